@@ -0,0 +1,475 @@
+package pe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mewmew/pe/internal/pe"
+	"github.com/pkg/errors"
+)
+
+// --- [ COFF archive ] --------------------------------------------------------
+
+// Archive is a COFF archive (static library or import library).
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#archive-library-file-format
+type Archive struct {
+	// File contents.
+	Content []byte
+	// Symbols of the first linker member, in order of appearance.
+	FirstLinkerSyms []ArchiveSymbol
+	// Member file offsets of the second linker member; nil if not present.
+	SecondLinkerMemberOffsets []uint32
+	// Symbols of the second linker member, in lexical order; nil if not
+	// present.
+	SecondLinkerSyms []ArchiveSymbol
+	// Contents of the longnames member; nil if not present.
+	LongNames []byte
+	// Archive members, excluding the linker members and the longnames member.
+	Members []ArchiveMember
+}
+
+// ArchiveSymbol is a public symbol of a COFF archive linker member.
+type ArchiveSymbol struct {
+	// Symbol name.
+	Name string
+	// File offset of the header of the archive member defining the symbol.
+	MemberOffset uint32
+}
+
+// ArchiveMember is a member of a COFF archive.
+type ArchiveMember struct {
+	// Member name; long names are resolved using the longnames member.
+	Name string
+	// Member creation time.
+	Date time.Time
+	// User ID.
+	UserID uint32
+	// Group ID.
+	GroupID uint32
+	// File mode.
+	Mode uint32
+	// File offset of the member header.
+	Offset uint32
+	// Member contents.
+	Data []byte
+	// Member contents parsed as a COFF object file or short import library
+	// member (see File.ImportHdr); nil for special members (e.g.
+	// "/<ECSYMBOLS>/") and for members which could not be parsed (e.g. LTO
+	// bitcode).
+	File *File
+	// Error encountered while parsing the member contents; nil if parsed
+	// successfully or not parsed (special members).
+	Err error
+}
+
+// Archive signature.
+var archiveSignature = []byte("!<arch>\n")
+
+// archiveMemberHeaderSize is the size in bytes of COFF archive member headers.
+const archiveMemberHeaderSize = 60
+
+// ParseArchiveFile parses the given COFF archive file.
+func ParseArchiveFile(path string) (*Archive, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ParseArchiveBytes(buf)
+}
+
+// ParseArchive parses the given COFF archive file, reading from r.
+func ParseArchive(r io.Reader) (*Archive, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ParseArchiveBytes(buf)
+}
+
+// ParseArchiveBytes parses the given COFF archive file, reading from content.
+func ParseArchiveBytes(content []byte) (*Archive, error) {
+	if !bytes.HasPrefix(content, archiveSignature) {
+		return nil, errors.Errorf("invalid archive signature; expected %q", archiveSignature)
+	}
+	arch := &Archive{
+		Content: content,
+	}
+	nlinkers := 0
+	for offset := uint64(len(archiveSignature)); offset < uint64(len(content)); {
+		member, err := arch.parseMember(uint32(offset))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse archive member at offset 0x%X", offset)
+		}
+		// Member data is aligned to 2 bytes.
+		offset += archiveMemberHeaderSize + uint64(len(member.Data))
+		offset += offset & 1
+		switch {
+		case member.Name == "/" && nlinkers == 0:
+			// First linker member.
+			nlinkers++
+			syms, err := parseFirstLinkerMember(member.Data)
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to parse first linker member")
+			}
+			arch.FirstLinkerSyms = syms
+		case member.Name == "/" && nlinkers == 1:
+			// Second linker member.
+			nlinkers++
+			memberOffsets, syms, err := parseSecondLinkerMember(member.Data)
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to parse second linker member")
+			}
+			arch.SecondLinkerMemberOffsets = memberOffsets
+			arch.SecondLinkerSyms = syms
+		case member.Name == "//":
+			// Longnames member.
+			arch.LongNames = member.Data
+		case strings.HasPrefix(member.Name, "/<"), member.Name == "/SYM64/":
+			// Special member (e.g. "/<ECSYMBOLS>/" or "/<HYBRIDMAP>/"), or 64-bit
+			// symbol table of archives created by GNU tools.
+			arch.Members = append(arch.Members, *member)
+		default:
+			// Members which cannot be parsed as COFF object files (e.g. LTO
+			// bitcode) are retained with their error recorded.
+			file, err := ParseBytes(member.Data)
+			if err != nil {
+				member.Err = errors.Wrapf(err, "unable to parse archive member %q", member.Name)
+			} else {
+				member.File = file
+			}
+			arch.Members = append(arch.Members, *member)
+		}
+	}
+	return arch, nil
+}
+
+// parseMember parses the archive member at the given file offset.
+func (arch *Archive) parseMember(offset uint32) (*ArchiveMember, error) {
+	start := uint64(offset)
+	end := start + archiveMemberHeaderSize
+	if end > uint64(len(arch.Content)) {
+		return nil, errors.Errorf("archive member header out of bounds; expected end <= %d, got %d", len(arch.Content), end)
+	}
+	var raw pe.RawArchiveMemberHeader
+	if err := binary.Read(bytes.NewReader(arch.Content[start:end]), binary.LittleEndian, &raw); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if string(raw.End[:]) != "`\n" {
+		return nil, errors.Errorf("invalid end of archive member header; expected %q, got %q", "`\n", raw.End[:])
+	}
+	date, err := parseArchiveNumber(raw.Date[:], 10)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse archive member date")
+	}
+	userID, err := parseArchiveNumber(raw.UserID[:], 10)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse archive member user ID")
+	}
+	groupID, err := parseArchiveNumber(raw.GroupID[:], 10)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse archive member group ID")
+	}
+	mode, err := parseArchiveNumber(raw.Mode[:], 8)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse archive member mode")
+	}
+	size, err := parseArchiveNumber(raw.Size[:], 10)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse archive member size")
+	}
+	dataEnd := end + uint64(size)
+	if dataEnd > uint64(len(arch.Content)) {
+		return nil, errors.Errorf("archive member data out of bounds; expected end <= %d, got %d", len(arch.Content), dataEnd)
+	}
+	name, err := arch.parseMemberName(raw.Name[:])
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	member := &ArchiveMember{
+		Name:    name,
+		Date:    parseDateFromEpoch(date),
+		UserID:  userID,
+		GroupID: groupID,
+		Mode:    mode,
+		Offset:  offset,
+		Data:    arch.Content[end:dataEnd],
+	}
+	return member, nil
+}
+
+// parseMemberName parses the given raw archive member name, resolving long
+// names ("/n") against the longnames member.
+func (arch *Archive) parseMemberName(raw []byte) (string, error) {
+	name := strings.TrimRight(string(raw), " ")
+	switch {
+	case name == "/", name == "//", strings.HasPrefix(name, "/<"), name == "/SYM64/":
+		// Special member names.
+		return name, nil
+	case strings.HasPrefix(name, "/"):
+		offset, err := strconv.ParseUint(name[1:], 10, 32)
+		if err != nil {
+			return "", errors.Errorf("invalid long name offset of archive member name %q", name)
+		}
+		if offset >= uint64(len(arch.LongNames)) {
+			return "", errors.Errorf("long name offset out of bounds; expected < %d, got %d", len(arch.LongNames), offset)
+		}
+		// Long names are NULL-terminated in archives created by Microsoft tools,
+		// and "/\n" terminated in archives created by GNU tools.
+		b := arch.LongNames[offset:]
+		if pos := bytes.IndexAny(b, "\x00\n"); pos != -1 {
+			b = b[:pos]
+		}
+		return strings.TrimSuffix(string(b), "/"), nil
+	default:
+		// Short names are terminated by '/'.
+		return strings.TrimSuffix(name, "/"), nil
+	}
+}
+
+// parseArchiveNumber parses the given space-padded ASCII number of an archive
+// member header in the given base. Blank fields are parsed as zero.
+func parseArchiveNumber(raw []byte, base int) (uint32, error) {
+	s := strings.TrimRight(string(raw), " ")
+	if len(s) == 0 {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(s, base, 32)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return uint32(v), nil
+}
+
+// parseFirstLinkerMember parses the contents of the first linker member.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#first-linker-member
+func parseFirstLinkerMember(buf []byte) ([]ArchiveSymbol, error) {
+	// Numbers of the first linker member are stored in big-endian byte order.
+	if len(buf) < 4 {
+		return nil, errors.Errorf("first linker member too short; expected length >= 4, got %d", len(buf))
+	}
+	nsyms := uint64(binary.BigEndian.Uint32(buf))
+	buf = buf[4:]
+	if nsyms*4 > uint64(len(buf)) {
+		return nil, errors.Errorf("symbol offsets out of bounds; expected end <= %d, got %d", len(buf), nsyms*4)
+	}
+	offsets := buf[:nsyms*4]
+	names, err := parseArchiveNames(buf[nsyms*4:], nsyms)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	syms := make([]ArchiveSymbol, nsyms)
+	for i := range syms {
+		syms[i] = ArchiveSymbol{
+			Name:         names[i],
+			MemberOffset: binary.BigEndian.Uint32(offsets[i*4:]),
+		}
+	}
+	return syms, nil
+}
+
+// parseSecondLinkerMember parses the contents of the second linker member,
+// returning the member file offsets and symbols.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#second-linker-member
+func parseSecondLinkerMember(buf []byte) ([]uint32, []ArchiveSymbol, error) {
+	// Numbers of the second linker member are stored in little-endian byte
+	// order.
+	if len(buf) < 4 {
+		return nil, nil, errors.Errorf("second linker member too short; expected length >= 4, got %d", len(buf))
+	}
+	nmembers := uint64(binary.LittleEndian.Uint32(buf))
+	buf = buf[4:]
+	if nmembers*4+4 > uint64(len(buf)) {
+		return nil, nil, errors.Errorf("member offsets out of bounds; expected end <= %d, got %d", len(buf), nmembers*4+4)
+	}
+	memberOffsets := make([]uint32, nmembers)
+	for i := range memberOffsets {
+		memberOffsets[i] = binary.LittleEndian.Uint32(buf[i*4:])
+	}
+	buf = buf[nmembers*4:]
+	nsyms := uint64(binary.LittleEndian.Uint32(buf))
+	buf = buf[4:]
+	if nsyms*2 > uint64(len(buf)) {
+		return nil, nil, errors.Errorf("symbol indices out of bounds; expected end <= %d, got %d", len(buf), nsyms*2)
+	}
+	indices := buf[:nsyms*2]
+	names, err := parseArchiveNames(buf[nsyms*2:], nsyms)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	syms := make([]ArchiveSymbol, nsyms)
+	for i := range syms {
+		// Symbol indices are 1-based indices into the member offsets.
+		index := binary.LittleEndian.Uint16(indices[i*2:])
+		if index < 1 || uint64(index) > nmembers {
+			return nil, nil, errors.Errorf("invalid member index of symbol %q; expected >= 1 and <= %d, got %d", names[i], nmembers, index)
+		}
+		syms[i] = ArchiveSymbol{
+			Name:         names[i],
+			MemberOffset: memberOffsets[index-1],
+		}
+	}
+	return memberOffsets, syms, nil
+}
+
+// parseArchiveNames parses n consecutive NULL-terminated strings of buf.
+func parseArchiveNames(buf []byte, n uint64) ([]string, error) {
+	names := make([]string, 0, n)
+	for i := uint64(0); i < n; i++ {
+		pos := bytes.IndexByte(buf, '\x00')
+		if pos == -1 {
+			return nil, errors.Errorf("unterminated symbol name %d of linker member", i)
+		}
+		names = append(names, string(buf[:pos]))
+		buf = buf[pos+1:]
+	}
+	return names, nil
+}
+
+// Imports returns the import object headers of the short import library
+// members of the archive.
+func (arch *Archive) Imports() []*ImportObjectHeader {
+	var imps []*ImportObjectHeader
+	for _, member := range arch.Members {
+		if member.File != nil && member.File.ImportHdr != nil {
+			imps = append(imps, member.File.ImportHdr)
+		}
+	}
+	return imps
+}
+
+// ~~~ [ Archive writer ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// archiveEntry is a member of a COFF archive to be written.
+type archiveEntry struct {
+	// Member name.
+	name string
+	// Member contents.
+	data []byte
+	// Public symbols defined by the member.
+	syms []string
+}
+
+// writeArchive writes a COFF archive of the given members to w, including the
+// first and second linker members, and a longnames member if required.
+func writeArchive(w io.Writer, entries []archiveEntry) error {
+	// Member indices of the second linker member are 16-bit and 1-based.
+	if len(entries) > math.MaxUint16 {
+		return errors.Errorf("number of archive members out of range; expected <= %d, got %d", math.MaxUint16, len(entries))
+	}
+	// Compute member names, using the longnames member for names not fitting
+	// in the member header.
+	var longNames []byte
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		if len(entry.name)+1 <= len(pe.RawArchiveMemberHeader{}.Name) {
+			names[i] = entry.name + "/"
+			continue
+		}
+		names[i] = "/" + strconv.Itoa(len(longNames))
+		longNames = append(longNames, entry.name...)
+		longNames = append(longNames, 0)
+	}
+	// Compute the size of the linker members, to determine member offsets.
+	nsyms := 0
+	symsSize := 0
+	for _, entry := range entries {
+		for _, sym := range entry.syms {
+			nsyms++
+			symsSize += len(sym) + 1
+		}
+	}
+	firstSize := 4 + 4*nsyms + symsSize
+	secondSize := 4 + 4*len(entries) + 4 + 2*nsyms + symsSize
+	offset := len(archiveSignature) + archiveMemberSize(firstSize) + archiveMemberSize(secondSize)
+	if len(longNames) > 0 {
+		offset += archiveMemberSize(len(longNames))
+	}
+	offsets := make([]uint32, len(entries))
+	for i, entry := range entries {
+		offsets[i] = uint32(offset)
+		offset += archiveMemberSize(len(entry.data))
+	}
+	// First linker member; symbols in order of member, with big-endian member
+	// offsets.
+	first := &bytes.Buffer{}
+	binary.Write(first, binary.BigEndian, uint32(nsyms))
+	for i, entry := range entries {
+		for range entry.syms {
+			binary.Write(first, binary.BigEndian, offsets[i])
+		}
+	}
+	for _, entry := range entries {
+		for _, sym := range entry.syms {
+			first.WriteString(sym)
+			first.WriteByte(0)
+		}
+	}
+	// Second linker member; symbols in lexical order, with little-endian
+	// 1-based member indices.
+	type symIndex struct {
+		name  string
+		index uint16
+	}
+	var syms []symIndex
+	for i, entry := range entries {
+		for _, sym := range entry.syms {
+			syms = append(syms, symIndex{name: sym, index: uint16(i + 1)})
+		}
+	}
+	sort.SliceStable(syms, func(i, j int) bool {
+		return syms[i].name < syms[j].name
+	})
+	second := &bytes.Buffer{}
+	binary.Write(second, binary.LittleEndian, uint32(len(entries)))
+	binary.Write(second, binary.LittleEndian, offsets)
+	binary.Write(second, binary.LittleEndian, uint32(nsyms))
+	for _, sym := range syms {
+		binary.Write(second, binary.LittleEndian, sym.index)
+	}
+	for _, sym := range syms {
+		second.WriteString(sym.name)
+		second.WriteByte(0)
+	}
+	// Write archive.
+	buf := &bytes.Buffer{}
+	buf.Write(archiveSignature)
+	writeArchiveMember(buf, "/", first.Bytes())
+	writeArchiveMember(buf, "/", second.Bytes())
+	if len(longNames) > 0 {
+		writeArchiveMember(buf, "//", longNames)
+	}
+	for i, entry := range entries {
+		writeArchiveMember(buf, names[i], entry.data)
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// archiveMemberSize returns the size in bytes of an archive member with the
+// given data size, including header and padding.
+func archiveMemberSize(dataSize int) int {
+	return archiveMemberHeaderSize + dataSize + dataSize&1
+}
+
+// writeArchiveMember writes the archive member header of the given raw member
+// name, followed by the member contents padded to 2 bytes.
+func writeArchiveMember(buf *bytes.Buffer, name string, data []byte) {
+	fmt.Fprintf(buf, "%-16s%-12d%-6s%-6s%-8d%-10d`\n", name, 0, "", "", 0, len(data))
+	buf.Write(data)
+	if len(data)&1 != 0 {
+		buf.WriteByte('\n')
+	}
+}
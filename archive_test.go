@@ -0,0 +1,84 @@
+package pe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/mewmew/pe/enum"
+)
+
+func TestParseArchiveUnparseableMembers(t *testing.T) {
+	lib := &importLib{dllName: "foo.dll", machine: enum.MachineTypeAMD64}
+	imp := lib.shortImport(libExport{Name: "bar", Ordinal: 1})
+	// GNU 64-bit symbol table: number of symbols, followed by 64-bit member
+	// offsets and symbol names.
+	sym64 := &bytes.Buffer{}
+	binary.Write(sym64, binary.BigEndian, uint64(0))
+	// LLVM bitcode of LTO objects.
+	bitcode := []byte("BC\xC0\xDE\x35\x14\x00\x00\x05\x00\x00\x00\x62\x0C\x30\x24")
+	buf := &bytes.Buffer{}
+	buf.Write(archiveSignature)
+	writeArchiveMember(buf, "/SYM64/", sym64.Bytes())
+	writeArchiveMember(buf, "lto.o/", bitcode)
+	writeArchiveMember(buf, "foo.dll/", imp.data)
+	arch, err := ParseArchiveBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unable to parse archive; %+v", err)
+	}
+	if len(arch.Members) != 3 {
+		t.Fatalf("number of members mismatch; expected 3, got %d", len(arch.Members))
+	}
+	golden := []struct {
+		name    string
+		wantErr bool
+		wantImp bool
+	}{
+		{name: "/SYM64/"},
+		{name: "lto.o", wantErr: true},
+		{name: "foo.dll", wantImp: true},
+	}
+	for i, g := range golden {
+		member := arch.Members[i]
+		if member.Name != g.name {
+			t.Errorf("member %d: name mismatch; expected %q, got %q", i, g.name, member.Name)
+		}
+		if (member.Err != nil) != g.wantErr {
+			t.Errorf("member %q: error mismatch; expected error %v, got %v", g.name, g.wantErr, member.Err)
+		}
+		if g.wantErr && member.File != nil {
+			t.Errorf("member %q: expected nil file of unparseable member", g.name)
+		}
+		if hasImp := member.File != nil && member.File.ImportHdr != nil; hasImp != g.wantImp {
+			t.Errorf("member %q: import header mismatch; expected %v, got %v", g.name, g.wantImp, hasImp)
+		}
+	}
+	if imps := arch.Imports(); len(imps) != 1 || imps[0].SymbolName != "bar" {
+		t.Errorf("imports mismatch; expected [bar], got %v", imps)
+	}
+}
+
+func TestWriteArchiveTooManyMembers(t *testing.T) {
+	entries := make([]archiveEntry, 65536)
+	for i := range entries {
+		entries[i] = archiveEntry{name: "foo.dll", syms: []string{"bar"}}
+	}
+	buf := &bytes.Buffer{}
+	if err := writeArchive(buf, entries); err == nil {
+		t.Fatalf("expected error for %d archive members", len(entries))
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output on error, got %d bytes", buf.Len())
+	}
+	// The maximum number of members is accepted.
+	if err := writeArchive(buf, entries[:65535]); err != nil {
+		t.Fatalf("unable to write archive of %d members; %+v", 65535, err)
+	}
+	arch, err := ParseArchiveBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unable to parse archive; %+v", err)
+	}
+	if len(arch.SecondLinkerMemberOffsets) != 65535 {
+		t.Errorf("number of member offsets mismatch; expected 65535, got %d", len(arch.SecondLinkerMemberOffsets))
+	}
+}
@@ -0,0 +1,13 @@
+package pe
+
+import "github.com/mewmew/pe/enum"
+
+// Certificate is an attribute certificate (e.g. an Authenticode signature).
+type Certificate struct {
+	// Certificate revision.
+	Revision enum.CertificateRevision
+	// Certificate type.
+	Type enum.CertificateType
+	// Certificate contents (e.g. PKCS#7 SignedData).
+	Data []byte
+}
@@ -0,0 +1,72 @@
+package pe
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// --- [ Image checksum ] ------------------------------------------------------
+
+// ComputeChecksum returns the image checksum of the PE file, as computed by
+// CheckSumMappedFile of the Windows image help library.
+//
+// The checksum is computed by summing the 16-bit words of the file (skipping
+// the checksum field of the optional header) into a 16-bit one's complement
+// sum, and adding the file size in bytes.
+func (file *File) ComputeChecksum() (uint32, error) {
+	if file.OptHdr == nil {
+		return 0, errors.New("unable to compute image checksum; missing optional header")
+	}
+	return computeChecksum(file.Content, file.checksumOffset()), nil
+}
+
+// ChecksumValid reports whether the checksum of the optional header matches the
+// computed image checksum of the PE file. A zero checksum (not set by the
+// linker) is not valid.
+func (file *File) ChecksumValid() bool {
+	checksum, err := file.ComputeChecksum()
+	if err != nil {
+		return false
+	}
+	return file.OptHdr.Checksum == checksum
+}
+
+// UpdateChecksum computes the image checksum of the given PE image contents and
+// stores it in the checksum field of the optional header. Writers of PE images
+// should update the checksum after all other contents are final.
+func UpdateChecksum(content []byte) error {
+	if !bytes.HasPrefix(content, dosSignature) || len(content) < dosHeaderSize {
+		return errors.New("unable to locate MS-DOS header of PE image")
+	}
+	// Offset of PE signature + size of PE signature + size of COFF file header +
+	// offset of checksum field in optional header.
+	offset := uint64(binary.LittleEndian.Uint32(content[0x3C:])) + 4 + 20 + 64
+	if offset+4 > uint64(len(content)) {
+		return errors.Errorf("checksum field of optional header out of bounds; expected end <= %d, got %d", len(content), offset+4)
+	}
+	checksum := computeChecksum(content, uint32(offset))
+	binary.LittleEndian.PutUint32(content[offset:], checksum)
+	return nil
+}
+
+// computeChecksum returns the image checksum of the given PE image contents,
+// skipping the checksum field at the given file offset.
+func computeChecksum(content []byte, checksumOffset uint32) uint32 {
+	var sum uint32
+	n := len(content)
+	for i := 0; i+1 < n; i += 2 {
+		if uint64(i) == uint64(checksumOffset) || uint64(i) == uint64(checksumOffset)+2 {
+			continue
+		}
+		sum += uint32(binary.LittleEndian.Uint16(content[i:]))
+		sum = sum&0xFFFF + sum>>16
+	}
+	if n%2 != 0 {
+		// Pad trailing byte with zero.
+		sum += uint32(content[n-1])
+		sum = sum&0xFFFF + sum>>16
+	}
+	return sum + uint32(n)
+}
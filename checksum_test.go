@@ -0,0 +1,100 @@
+package pe
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestComputeChecksum(t *testing.T) {
+	golden := []struct {
+		name           string
+		content        []byte
+		checksumOffset uint32
+		want           uint32
+	}{
+		{name: "empty", content: nil, checksumOffset: 100, want: 0},
+		// 0x0201 + 0x0003 (zero padded trailing byte) + 3 (size).
+		{name: "odd length", content: []byte{0x01, 0x02, 0x03}, checksumOffset: 100, want: 0x0207},
+		// 0xFFFF + 0xFFFF folds to 0xFFFF; + 0x0001 folds to 0x0001; + 5 (size).
+		{name: "carry", content: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x01}, checksumOffset: 100, want: 0x0006},
+		// The 4-byte checksum field is skipped; 0x0001 + 0x0002 + 8 (size).
+		{name: "skip checksum field", content: []byte{0x01, 0x00, 0xAA, 0xBB, 0xCC, 0xDD, 0x02, 0x00}, checksumOffset: 2, want: 0x000B},
+	}
+	for _, g := range golden {
+		got := computeChecksum(g.content, g.checksumOffset)
+		if got != g.want {
+			t.Errorf("%s: checksum mismatch; expected 0x%08X, got 0x%08X", g.name, g.want, got)
+		}
+	}
+}
+
+func TestChecksumValid(t *testing.T) {
+	// Checksum set by the linker of a signed Microsoft image.
+	const want = 0x0001AAEB
+	file, err := ParseFile("testdata/Microsoft.TestPlatform.PlatformAbstractions.dll")
+	if err != nil {
+		t.Fatalf("unable to parse file; %+v", err)
+	}
+	if file.OptHdr.Checksum != want {
+		t.Fatalf("checksum mismatch of optional header; expected 0x%08X, got 0x%08X", uint32(want), file.OptHdr.Checksum)
+	}
+	got, err := file.ComputeChecksum()
+	if err != nil {
+		t.Fatalf("unable to compute checksum; %+v", err)
+	}
+	if got != want {
+		t.Errorf("computed checksum mismatch; expected 0x%08X, got 0x%08X", uint32(want), got)
+	}
+	if !file.ChecksumValid() {
+		t.Error("expected valid checksum")
+	}
+}
+
+func TestUpdateChecksum(t *testing.T) {
+	orig, err := ioutil.ReadFile("testdata/Microsoft.TestPlatform.PlatformAbstractions.dll")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := []struct {
+		name    string
+		content []byte
+		// Expected checksum; or zero if unknown.
+		want uint32
+	}{
+		{name: "unmodified", content: append([]byte(nil), orig...), want: 0x0001AAEB},
+		// Odd-length file, with trailing byte.
+		{name: "odd length", content: append(append([]byte(nil), orig...), 0xCC)},
+	}
+	for _, g := range golden {
+		// Clear the checksum of the optional header.
+		file, err := ParseBytes(g.content)
+		if err != nil {
+			t.Fatalf("%s: unable to parse file; %+v", g.name, err)
+		}
+		offset := file.checksumOffset()
+		for i := offset; i < offset+4; i++ {
+			g.content[i] = 0
+		}
+		if file, err = ParseBytes(g.content); err != nil {
+			t.Fatalf("%s: unable to parse file; %+v", g.name, err)
+		}
+		if file.ChecksumValid() {
+			t.Errorf("%s: expected invalid zero checksum", g.name)
+		}
+		if err := UpdateChecksum(g.content); err != nil {
+			t.Fatalf("%s: unable to update checksum; %+v", g.name, err)
+		}
+		if file, err = ParseBytes(g.content); err != nil {
+			t.Fatalf("%s: unable to parse file; %+v", g.name, err)
+		}
+		if !file.ChecksumValid() {
+			t.Errorf("%s: expected valid checksum after update", g.name)
+		}
+		if g.want != 0 && file.OptHdr.Checksum != g.want {
+			t.Errorf("%s: checksum mismatch; expected 0x%08X, got 0x%08X", g.name, g.want, file.OptHdr.Checksum)
+		}
+	}
+	if err := UpdateChecksum([]byte("MZ")); err == nil {
+		t.Error("expected error for truncated image")
+	}
+}
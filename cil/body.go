@@ -0,0 +1,245 @@
+// Package cil provides access to CIL method bodies and instructions of managed
+// PE files.
+//
+// ref: ECMA-335, Partition II, 25.4 Common Intermediate Language physical
+// layout
+// ref: ECMA-335, Partition III, CIL instruction set
+package cil
+
+import (
+	"encoding/binary"
+
+	"github.com/mewmew/pe/metadata"
+	"github.com/pkg/errors"
+)
+
+// MethodBody is a CIL method body.
+type MethodBody struct {
+	// Specifies whether the method body uses the fat header format.
+	IsFat bool
+	// Method header flags.
+	Flags MethodFlag
+	// Size of method header in number of bytes.
+	HeaderSize uint32
+	// Maximum number of items on the operand stack.
+	MaxStack uint16
+	// Size of CIL code in number of bytes.
+	CodeSize uint32
+	// StandAloneSig token of local variable signature; nil if the method has
+	// no local variables.
+	LocalVarSigTok metadata.Token
+	// CIL code.
+	Code []byte
+	// Extra data sections following the CIL code.
+	Sects []Section
+}
+
+// MethodFlag is a bitfield of method header flags.
+type MethodFlag uint16
+
+// Method header flags.
+//
+// ref: ECMA-335, II.25.4.4 Flags for method headers
+const (
+	MethodFlagTinyFormat MethodFlag = 0x0002 // Method header uses the tiny format.
+	MethodFlagFatFormat  MethodFlag = 0x0003 // Method header uses the fat format.
+	MethodFlagMoreSects  MethodFlag = 0x0008 // More data sections follow the CIL code.
+	MethodFlagInitLocals MethodFlag = 0x0010 // Local variables are zero initialized.
+)
+
+// Section is an extra data section of a method body.
+type Section struct {
+	// Section kind.
+	Kind SectionKind
+	// Exception handling clauses; used if Kind has SectionKindEHTable set.
+	Clauses []ExceptionClause
+	// Raw contents of section, excluding section header.
+	Data []byte
+}
+
+// SectionKind is a bitfield of method data section kinds.
+type SectionKind uint8
+
+// Method data section kinds.
+//
+// ref: ECMA-335, II.25.4.5 Method data section
+const (
+	SectionKindEHTable    SectionKind = 0x01 // Exception handling data.
+	SectionKindOptILTable SectionKind = 0x02 // Reserved.
+	SectionKindFatFormat  SectionKind = 0x40 // Section uses the fat format.
+	SectionKindMoreSects  SectionKind = 0x80 // Another data section follows this section.
+)
+
+// ExceptionClause is an exception handling clause.
+type ExceptionClause struct {
+	// Clause kind.
+	Flags ClauseFlag
+	// Offset of try block (relative to start of CIL code).
+	TryOffset uint32
+	// Length of try block in number of bytes.
+	TryLength uint32
+	// Offset of handler block (relative to start of CIL code).
+	HandlerOffset uint32
+	// Length of handler block in number of bytes.
+	HandlerLength uint32
+	// TypeDef, TypeRef or TypeSpec of caught exception; used if Flags is
+	// ClauseFlagException.
+	ClassToken metadata.Token
+	// Offset of filter block (relative to start of CIL code); used if Flags is
+	// ClauseFlagFilter.
+	FilterOffset uint32
+}
+
+// ClauseFlag specifies the kind of an exception handling clause.
+type ClauseFlag uint32
+
+// Exception handling clause kinds.
+//
+// ref: ECMA-335, II.25.4.6 Exception handling clauses
+const (
+	ClauseFlagException ClauseFlag = 0x0000 // Typed exception clause.
+	ClauseFlagFilter    ClauseFlag = 0x0001 // Exception filter and handler clause.
+	ClauseFlagFinally   ClauseFlag = 0x0002 // Finally clause.
+	ClauseFlagFault     ClauseFlag = 0x0004 // Fault clause.
+)
+
+// Masks of method header format and data section kind.
+const (
+	formatMask      = 0x03
+	sectionKindMask = 0x3F
+)
+
+// ParseMethodBody parses the CIL method body at the start of buf.
+func ParseMethodBody(buf []byte) (*MethodBody, error) {
+	if len(buf) < 1 {
+		return nil, errors.New("empty method body")
+	}
+	body := &MethodBody{}
+	switch MethodFlag(buf[0] & formatMask) {
+	case MethodFlagTinyFormat:
+		// Tiny header; 6 bits of code size and 2 bits of format.
+		body.Flags = MethodFlagTinyFormat
+		body.HeaderSize = 1
+		body.MaxStack = 8
+		body.CodeSize = uint32(buf[0] >> 2)
+	case MethodFlagFatFormat:
+		// Fat header.
+		const minFatSize = 12
+		if len(buf) < minFatSize {
+			return nil, errors.Errorf("fat method header too short; expected >= %d bytes, got %d", minFatSize, len(buf))
+		}
+		v := binary.LittleEndian.Uint16(buf)
+		body.IsFat = true
+		// Flags : 12
+		body.Flags = MethodFlag(v & 0x0FFF)
+		// Size  : 4 (in number of 4-byte integers)
+		body.HeaderSize = uint32(v>>12) * 4
+		body.MaxStack = binary.LittleEndian.Uint16(buf[2:])
+		body.CodeSize = binary.LittleEndian.Uint32(buf[4:])
+		body.LocalVarSigTok = metadata.Token(binary.LittleEndian.Uint32(buf[8:]))
+		if body.HeaderSize < minFatSize {
+			return nil, errors.Errorf("invalid fat method header size; expected >= %d, got %d", minFatSize, body.HeaderSize)
+		}
+	default:
+		return nil, errors.Errorf("invalid method header format 0x%X", buf[0]&formatMask)
+	}
+	end := uint64(body.HeaderSize) + uint64(body.CodeSize)
+	if end > uint64(len(buf)) {
+		return nil, errors.Errorf("method body out of bounds; expected end <= %d, got %d", len(buf), end)
+	}
+	body.Code = buf[body.HeaderSize:end]
+	if !body.IsFat || body.Flags&MethodFlagMoreSects == 0 {
+		return body, nil
+	}
+	// Parse extra data sections, each aligned on a 4-byte boundary.
+	offset := (end + 3) &^ 3
+	for {
+		sect, size, err := parseSection(buf, offset)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		body.Sects = append(body.Sects, sect)
+		if sect.Kind&SectionKindMoreSects == 0 {
+			break
+		}
+		offset = (offset + size + 3) &^ 3
+	}
+	return body, nil
+}
+
+// parseSection parses the method data section at the given offset of buf,
+// returning the section and its size in bytes.
+func parseSection(buf []byte, offset uint64) (Section, uint64, error) {
+	const hdrSize = 4
+	if offset+hdrSize > uint64(len(buf)) {
+		return Section{}, 0, errors.Errorf("method data section header out of bounds; expected end <= %d, got %d", len(buf), offset+hdrSize)
+	}
+	kind := SectionKind(buf[offset])
+	var size uint64
+	if kind&SectionKindFatFormat != 0 {
+		// Fat section; 3 bytes of data size.
+		size = uint64(buf[offset+1]) | uint64(buf[offset+2])<<8 | uint64(buf[offset+3])<<16
+	} else {
+		// Small section; 1 byte of data size, followed by 2 bytes of padding.
+		size = uint64(buf[offset+1])
+	}
+	if size < hdrSize {
+		return Section{}, 0, errors.Errorf("invalid method data section size; expected >= %d, got %d", hdrSize, size)
+	}
+	if offset+size > uint64(len(buf)) {
+		return Section{}, 0, errors.Errorf("method data section out of bounds; expected end <= %d, got %d", len(buf), offset+size)
+	}
+	sect := Section{
+		Kind: kind,
+		Data: buf[offset+hdrSize : offset+size],
+	}
+	if kind&sectionKindMask&SectionKindEHTable != 0 {
+		sect.Clauses = parseClauses(sect.Data, kind&SectionKindFatFormat != 0)
+	}
+	return sect, size, nil
+}
+
+// parseClauses parses the exception handling clauses of the given method data
+// section contents.
+func parseClauses(data []byte, isFat bool) []ExceptionClause {
+	var clauses []ExceptionClause
+	if isFat {
+		const clauseSize = 24
+		for ; len(data) >= clauseSize; data = data[clauseSize:] {
+			clause := ExceptionClause{
+				Flags:         ClauseFlag(binary.LittleEndian.Uint32(data[0:])),
+				TryOffset:     binary.LittleEndian.Uint32(data[4:]),
+				TryLength:     binary.LittleEndian.Uint32(data[8:]),
+				HandlerOffset: binary.LittleEndian.Uint32(data[12:]),
+				HandlerLength: binary.LittleEndian.Uint32(data[16:]),
+			}
+			setClauseExtra(&clause, binary.LittleEndian.Uint32(data[20:]))
+			clauses = append(clauses, clause)
+		}
+		return clauses
+	}
+	const clauseSize = 12
+	for ; len(data) >= clauseSize; data = data[clauseSize:] {
+		clause := ExceptionClause{
+			Flags:         ClauseFlag(binary.LittleEndian.Uint16(data[0:])),
+			TryOffset:     uint32(binary.LittleEndian.Uint16(data[2:])),
+			TryLength:     uint32(data[4]),
+			HandlerOffset: uint32(binary.LittleEndian.Uint16(data[5:])),
+			HandlerLength: uint32(data[7]),
+		}
+		setClauseExtra(&clause, binary.LittleEndian.Uint32(data[8:]))
+		clauses = append(clauses, clause)
+	}
+	return clauses
+}
+
+// setClauseExtra sets the class token or filter offset of the exception
+// handling clause, based on its kind.
+func setClauseExtra(clause *ExceptionClause, v uint32) {
+	switch clause.Flags {
+	case ClauseFlagException:
+		clause.ClassToken = metadata.Token(v)
+	case ClauseFlagFilter:
+		clause.FilterOffset = v
+	}
+}
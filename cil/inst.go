@@ -0,0 +1,238 @@
+package cil
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mewmew/pe/metadata"
+	"github.com/pkg/errors"
+)
+
+// Inst is a CIL instruction.
+type Inst struct {
+	// Offset of instruction (relative to start of CIL code).
+	Offset uint32
+	// Size of instruction in number of bytes.
+	Size uint32
+	// Opcode of instruction.
+	Op *OpCode
+	// Operand of instruction. The Go type of the operand is determined by the
+	// operand type of the opcode.
+	//
+	//    OperandInlineNone           nil
+	//    OperandShortInlineVar       uint16
+	//    OperandInlineVar            uint16
+	//    OperandShortInlineI         int32
+	//    OperandInlineI              int32
+	//    OperandInlineI8             int64
+	//    OperandShortInlineR         float32
+	//    OperandInlineR              float64
+	//    OperandShortInlineBrTarget  uint32 (target offset)
+	//    OperandInlineBrTarget       uint32 (target offset)
+	//    OperandInlineSwitch         []uint32 (target offsets)
+	//    OperandInlineMethod         metadata.Token
+	//    OperandInlineField          metadata.Token
+	//    OperandInlineType           metadata.Token
+	//    OperandInlineTok            metadata.Token
+	//    OperandInlineString         metadata.Token
+	//    OperandInlineSig            metadata.Token
+	Arg interface{}
+}
+
+// Decode decodes the given CIL code into instructions.
+func Decode(code []byte) ([]Inst, error) {
+	var insts []Inst
+	for offset := 0; offset < len(code); {
+		inst, err := decodeInst(code, offset)
+		if err != nil {
+			return insts, errors.WithStack(err)
+		}
+		insts = append(insts, inst)
+		offset += int(inst.Size)
+	}
+	return insts, nil
+}
+
+// decodeInst decodes the CIL instruction at the given offset of code.
+func decodeInst(code []byte, offset int) (Inst, error) {
+	inst := Inst{
+		Offset: uint32(offset),
+	}
+	pos := offset
+	b := code[pos]
+	pos++
+	if b == twoBytePrefix {
+		if pos >= len(code) {
+			return inst, errors.Errorf("truncated two-byte opcode at offset 0x%04X", offset)
+		}
+		inst.Op = twoByteOpCodes[code[pos]]
+		if inst.Op == nil {
+			return inst, errors.Errorf("invalid opcode 0xFE%02X at offset 0x%04X", code[pos], offset)
+		}
+		pos++
+	} else {
+		inst.Op = oneByteOpCodes[b]
+		if inst.Op == nil {
+			return inst, errors.Errorf("invalid opcode 0x%02X at offset 0x%04X", b, offset)
+		}
+	}
+	// read returns the next n bytes of the operand.
+	read := func(n int) ([]byte, error) {
+		if pos+n > len(code) {
+			return nil, errors.Errorf("truncated operand of %q at offset 0x%04X", inst.Op.Name, offset)
+		}
+		b := code[pos : pos+n]
+		pos += n
+		return b, nil
+	}
+	switch inst.Op.Operand {
+	case OperandInlineNone:
+		// no operand.
+	case OperandShortInlineVar:
+		b, err := read(1)
+		if err != nil {
+			return inst, err
+		}
+		inst.Arg = uint16(b[0])
+	case OperandInlineVar:
+		b, err := read(2)
+		if err != nil {
+			return inst, err
+		}
+		inst.Arg = binary.LittleEndian.Uint16(b)
+	case OperandShortInlineI:
+		b, err := read(1)
+		if err != nil {
+			return inst, err
+		}
+		inst.Arg = int32(int8(b[0]))
+	case OperandInlineI:
+		b, err := read(4)
+		if err != nil {
+			return inst, err
+		}
+		inst.Arg = int32(binary.LittleEndian.Uint32(b))
+	case OperandInlineI8:
+		b, err := read(8)
+		if err != nil {
+			return inst, err
+		}
+		inst.Arg = int64(binary.LittleEndian.Uint64(b))
+	case OperandShortInlineR:
+		b, err := read(4)
+		if err != nil {
+			return inst, err
+		}
+		inst.Arg = math.Float32frombits(binary.LittleEndian.Uint32(b))
+	case OperandInlineR:
+		b, err := read(8)
+		if err != nil {
+			return inst, err
+		}
+		inst.Arg = math.Float64frombits(binary.LittleEndian.Uint64(b))
+	case OperandShortInlineBrTarget:
+		b, err := read(1)
+		if err != nil {
+			return inst, err
+		}
+		// Branch offsets are relative to the start of the next instruction.
+		inst.Arg = uint32(pos + int(int8(b[0])))
+	case OperandInlineBrTarget:
+		b, err := read(4)
+		if err != nil {
+			return inst, err
+		}
+		inst.Arg = uint32(pos + int(int32(binary.LittleEndian.Uint32(b))))
+	case OperandInlineSwitch:
+		b, err := read(4)
+		if err != nil {
+			return inst, err
+		}
+		n := binary.LittleEndian.Uint32(b)
+		if uint64(n)*4 > uint64(len(code)-pos) {
+			return inst, errors.Errorf("truncated operand of %q at offset 0x%04X", inst.Op.Name, offset)
+		}
+		next := pos + int(n)*4
+		targets := make([]uint32, n)
+		for i := range targets {
+			b, _ := read(4)
+			targets[i] = uint32(next + int(int32(binary.LittleEndian.Uint32(b))))
+		}
+		inst.Arg = targets
+	case OperandInlineMethod, OperandInlineField, OperandInlineType, OperandInlineTok, OperandInlineString, OperandInlineSig:
+		b, err := read(4)
+		if err != nil {
+			return inst, err
+		}
+		inst.Arg = metadata.Token(binary.LittleEndian.Uint32(b))
+	default:
+		panic(fmt.Errorf("support for operand type %v not yet implemented", inst.Op.Operand))
+	}
+	inst.Size = uint32(pos - offset)
+	return inst, nil
+}
+
+// String returns the ILAsm representation of the instruction, without
+// resolving metadata tokens.
+func (inst Inst) String() string {
+	return inst.Format(nil)
+}
+
+// Format returns the ILAsm representation of the instruction, resolving
+// metadata tokens using the given metadata (may be nil).
+func (inst Inst) Format(md *metadata.Metadata) string {
+	label := Label(inst.Offset)
+	if inst.Arg == nil {
+		return fmt.Sprintf("%s:  %s", label, inst.Op.Name)
+	}
+	var arg string
+	switch v := inst.Arg.(type) {
+	case uint16:
+		if inst.Op.LocalVar() {
+			arg = fmt.Sprintf("V_%d", v)
+		} else {
+			arg = strconv.Itoa(int(v))
+		}
+	case int32:
+		arg = strconv.Itoa(int(v))
+	case int64:
+		arg = strconv.FormatInt(v, 10)
+	case float32:
+		arg = strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		arg = strconv.FormatFloat(v, 'g', -1, 64)
+	case uint32:
+		arg = Label(v)
+	case []uint32:
+		labels := make([]string, len(v))
+		for i, target := range v {
+			labels[i] = Label(target)
+		}
+		arg = "(" + strings.Join(labels, ", ") + ")"
+	case metadata.Token:
+		switch {
+		case md == nil:
+			arg = fmt.Sprintf("0x%08X", uint32(v))
+		case inst.Op.Operand == OperandInlineSig:
+			arg = md.TokenName(v)
+		case inst.Op.Operand == OperandInlineTok && v.Table() == metadata.TableMethodDef,
+			inst.Op.Operand == OperandInlineTok && v.Table() == metadata.TableMethodSpec:
+			arg = "method " + md.TokenName(v)
+		case inst.Op.Operand == OperandInlineTok && v.Table() == metadata.TableField:
+			arg = "field " + md.TokenName(v)
+		default:
+			arg = md.TokenName(v)
+		}
+	default:
+		panic(fmt.Errorf("support for operand type %T not yet implemented", v))
+	}
+	return fmt.Sprintf("%s:  %-10s %s", label, inst.Op.Name, arg)
+}
+
+// Label returns the ILAsm label of the given instruction offset.
+func Label(offset uint32) string {
+	return fmt.Sprintf("IL_%04x", offset)
+}
@@ -0,0 +1,33 @@
+package cil
+
+import "testing"
+
+func TestInstFormatVar(t *testing.T) {
+	golden := []struct {
+		code []byte
+		want string
+	}{
+		{code: []byte{0x0E, 0x05}, want: "IL_0000:  ldarg.s    5"},
+		{code: []byte{0x10, 0x02}, want: "IL_0000:  starg.s    2"},
+		{code: []byte{0x11, 0x05}, want: "IL_0000:  ldloc.s    V_5"},
+		{code: []byte{0x12, 0x01}, want: "IL_0000:  ldloca.s   V_1"},
+		{code: []byte{0xFE, 0x09, 0x03, 0x01}, want: "IL_0000:  ldarg      259"},
+		{code: []byte{0xFE, 0x0A, 0x03, 0x00}, want: "IL_0000:  ldarga     3"},
+		{code: []byte{0xFE, 0x0E, 0x03, 0x01}, want: "IL_0000:  stloc      V_259"},
+	}
+	for _, g := range golden {
+		insts, err := Decode(g.code)
+		if err != nil {
+			t.Errorf("% X: unable to decode instruction; %v", g.code, err)
+			continue
+		}
+		if len(insts) != 1 {
+			t.Errorf("% X: number of instructions mismatch; expected 1, got %d", g.code, len(insts))
+			continue
+		}
+		got := insts[0].String()
+		if got != g.want {
+			t.Errorf("% X: instruction mismatch; expected %q, got %q", g.code, g.want, got)
+		}
+	}
+}
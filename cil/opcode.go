@@ -0,0 +1,309 @@
+package cil
+
+//go:generate stringer -trimprefix Operand -type OperandType
+
+// OperandType specifies the type of an instruction operand.
+type OperandType uint8
+
+// Operand types.
+//
+// ref: ECMA-335, Partition VI, Annex C.2 CIL opcode descriptions
+const (
+	OperandInlineNone          OperandType = iota // No operand.
+	OperandShortInlineVar                         // 1-byte argument or local variable index.
+	OperandInlineVar                              // 2-byte argument or local variable index.
+	OperandShortInlineI                           // 1-byte signed integer.
+	OperandInlineI                                // 4-byte signed integer.
+	OperandInlineI8                               // 8-byte signed integer.
+	OperandShortInlineR                           // 4-byte floating-point number.
+	OperandInlineR                                // 8-byte floating-point number.
+	OperandShortInlineBrTarget                    // 1-byte signed branch offset.
+	OperandInlineBrTarget                         // 4-byte signed branch offset.
+	OperandInlineSwitch                           // Number of targets followed by 4-byte signed branch offsets.
+	OperandInlineMethod                           // MethodDef, MemberRef or MethodSpec token.
+	OperandInlineField                            // Field or MemberRef token.
+	OperandInlineType                             // TypeDef, TypeRef or TypeSpec token.
+	OperandInlineTok                              // Type, method or field token.
+	OperandInlineString                           // User string token.
+	OperandInlineSig                              // StandAloneSig token.
+)
+
+// OpCode is a CIL opcode.
+type OpCode struct {
+	// Opcode value; two-byte opcodes are prefixed by 0xFE.
+	Value uint16
+	// Opcode mnemonic.
+	Name string
+	// Operand type.
+	Operand OperandType
+}
+
+// Size returns the size of the opcode in number of bytes.
+func (op *OpCode) Size() int {
+	if op.Value > 0xFF {
+		return 2
+	}
+	return 1
+}
+
+// String returns the mnemonic of the opcode.
+func (op *OpCode) String() string {
+	return op.Name
+}
+
+// LocalVar reports whether the operand of the opcode is a local variable index,
+// as opposed to an argument index.
+func (op *OpCode) LocalVar() bool {
+	switch op.Value {
+	case 0x11, 0x12, 0x13: // ldloc.s, ldloca.s, stloc.s
+		return true
+	case 0xFE0C, 0xFE0D, 0xFE0E: // ldloc, ldloca, stloc
+		return true
+	}
+	return false
+}
+
+// Opcode lookup tables, indexed by the (second) byte of the opcode.
+var (
+	oneByteOpCodes [256]*OpCode
+	twoByteOpCodes [256]*OpCode
+)
+
+// twoBytePrefix is the first byte of two-byte opcodes.
+const twoBytePrefix = 0xFE
+
+func init() {
+	for i := range opCodes {
+		op := &opCodes[i]
+		if op.Value > 0xFF {
+			twoByteOpCodes[op.Value&0xFF] = op
+		} else {
+			oneByteOpCodes[op.Value] = op
+		}
+	}
+}
+
+// opCodes lists the CIL opcodes.
+//
+// ref: ECMA-335, Partition III, CIL instruction set
+var opCodes = []OpCode{
+	{Value: 0x00, Name: "nop", Operand: OperandInlineNone},
+	{Value: 0x01, Name: "break", Operand: OperandInlineNone},
+	{Value: 0x02, Name: "ldarg.0", Operand: OperandInlineNone},
+	{Value: 0x03, Name: "ldarg.1", Operand: OperandInlineNone},
+	{Value: 0x04, Name: "ldarg.2", Operand: OperandInlineNone},
+	{Value: 0x05, Name: "ldarg.3", Operand: OperandInlineNone},
+	{Value: 0x06, Name: "ldloc.0", Operand: OperandInlineNone},
+	{Value: 0x07, Name: "ldloc.1", Operand: OperandInlineNone},
+	{Value: 0x08, Name: "ldloc.2", Operand: OperandInlineNone},
+	{Value: 0x09, Name: "ldloc.3", Operand: OperandInlineNone},
+	{Value: 0x0A, Name: "stloc.0", Operand: OperandInlineNone},
+	{Value: 0x0B, Name: "stloc.1", Operand: OperandInlineNone},
+	{Value: 0x0C, Name: "stloc.2", Operand: OperandInlineNone},
+	{Value: 0x0D, Name: "stloc.3", Operand: OperandInlineNone},
+	{Value: 0x0E, Name: "ldarg.s", Operand: OperandShortInlineVar},
+	{Value: 0x0F, Name: "ldarga.s", Operand: OperandShortInlineVar},
+	{Value: 0x10, Name: "starg.s", Operand: OperandShortInlineVar},
+	{Value: 0x11, Name: "ldloc.s", Operand: OperandShortInlineVar},
+	{Value: 0x12, Name: "ldloca.s", Operand: OperandShortInlineVar},
+	{Value: 0x13, Name: "stloc.s", Operand: OperandShortInlineVar},
+	{Value: 0x14, Name: "ldnull", Operand: OperandInlineNone},
+	{Value: 0x15, Name: "ldc.i4.m1", Operand: OperandInlineNone},
+	{Value: 0x16, Name: "ldc.i4.0", Operand: OperandInlineNone},
+	{Value: 0x17, Name: "ldc.i4.1", Operand: OperandInlineNone},
+	{Value: 0x18, Name: "ldc.i4.2", Operand: OperandInlineNone},
+	{Value: 0x19, Name: "ldc.i4.3", Operand: OperandInlineNone},
+	{Value: 0x1A, Name: "ldc.i4.4", Operand: OperandInlineNone},
+	{Value: 0x1B, Name: "ldc.i4.5", Operand: OperandInlineNone},
+	{Value: 0x1C, Name: "ldc.i4.6", Operand: OperandInlineNone},
+	{Value: 0x1D, Name: "ldc.i4.7", Operand: OperandInlineNone},
+	{Value: 0x1E, Name: "ldc.i4.8", Operand: OperandInlineNone},
+	{Value: 0x1F, Name: "ldc.i4.s", Operand: OperandShortInlineI},
+	{Value: 0x20, Name: "ldc.i4", Operand: OperandInlineI},
+	{Value: 0x21, Name: "ldc.i8", Operand: OperandInlineI8},
+	{Value: 0x22, Name: "ldc.r4", Operand: OperandShortInlineR},
+	{Value: 0x23, Name: "ldc.r8", Operand: OperandInlineR},
+	{Value: 0x25, Name: "dup", Operand: OperandInlineNone},
+	{Value: 0x26, Name: "pop", Operand: OperandInlineNone},
+	{Value: 0x27, Name: "jmp", Operand: OperandInlineMethod},
+	{Value: 0x28, Name: "call", Operand: OperandInlineMethod},
+	{Value: 0x29, Name: "calli", Operand: OperandInlineSig},
+	{Value: 0x2A, Name: "ret", Operand: OperandInlineNone},
+	{Value: 0x2B, Name: "br.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x2C, Name: "brfalse.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x2D, Name: "brtrue.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x2E, Name: "beq.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x2F, Name: "bge.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x30, Name: "bgt.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x31, Name: "ble.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x32, Name: "blt.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x33, Name: "bne.un.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x34, Name: "bge.un.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x35, Name: "bgt.un.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x36, Name: "ble.un.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x37, Name: "blt.un.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0x38, Name: "br", Operand: OperandInlineBrTarget},
+	{Value: 0x39, Name: "brfalse", Operand: OperandInlineBrTarget},
+	{Value: 0x3A, Name: "brtrue", Operand: OperandInlineBrTarget},
+	{Value: 0x3B, Name: "beq", Operand: OperandInlineBrTarget},
+	{Value: 0x3C, Name: "bge", Operand: OperandInlineBrTarget},
+	{Value: 0x3D, Name: "bgt", Operand: OperandInlineBrTarget},
+	{Value: 0x3E, Name: "ble", Operand: OperandInlineBrTarget},
+	{Value: 0x3F, Name: "blt", Operand: OperandInlineBrTarget},
+	{Value: 0x40, Name: "bne.un", Operand: OperandInlineBrTarget},
+	{Value: 0x41, Name: "bge.un", Operand: OperandInlineBrTarget},
+	{Value: 0x42, Name: "bgt.un", Operand: OperandInlineBrTarget},
+	{Value: 0x43, Name: "ble.un", Operand: OperandInlineBrTarget},
+	{Value: 0x44, Name: "blt.un", Operand: OperandInlineBrTarget},
+	{Value: 0x45, Name: "switch", Operand: OperandInlineSwitch},
+	{Value: 0x46, Name: "ldind.i1", Operand: OperandInlineNone},
+	{Value: 0x47, Name: "ldind.u1", Operand: OperandInlineNone},
+	{Value: 0x48, Name: "ldind.i2", Operand: OperandInlineNone},
+	{Value: 0x49, Name: "ldind.u2", Operand: OperandInlineNone},
+	{Value: 0x4A, Name: "ldind.i4", Operand: OperandInlineNone},
+	{Value: 0x4B, Name: "ldind.u4", Operand: OperandInlineNone},
+	{Value: 0x4C, Name: "ldind.i8", Operand: OperandInlineNone},
+	{Value: 0x4D, Name: "ldind.i", Operand: OperandInlineNone},
+	{Value: 0x4E, Name: "ldind.r4", Operand: OperandInlineNone},
+	{Value: 0x4F, Name: "ldind.r8", Operand: OperandInlineNone},
+	{Value: 0x50, Name: "ldind.ref", Operand: OperandInlineNone},
+	{Value: 0x51, Name: "stind.ref", Operand: OperandInlineNone},
+	{Value: 0x52, Name: "stind.i1", Operand: OperandInlineNone},
+	{Value: 0x53, Name: "stind.i2", Operand: OperandInlineNone},
+	{Value: 0x54, Name: "stind.i4", Operand: OperandInlineNone},
+	{Value: 0x55, Name: "stind.i8", Operand: OperandInlineNone},
+	{Value: 0x56, Name: "stind.r4", Operand: OperandInlineNone},
+	{Value: 0x57, Name: "stind.r8", Operand: OperandInlineNone},
+	{Value: 0x58, Name: "add", Operand: OperandInlineNone},
+	{Value: 0x59, Name: "sub", Operand: OperandInlineNone},
+	{Value: 0x5A, Name: "mul", Operand: OperandInlineNone},
+	{Value: 0x5B, Name: "div", Operand: OperandInlineNone},
+	{Value: 0x5C, Name: "div.un", Operand: OperandInlineNone},
+	{Value: 0x5D, Name: "rem", Operand: OperandInlineNone},
+	{Value: 0x5E, Name: "rem.un", Operand: OperandInlineNone},
+	{Value: 0x5F, Name: "and", Operand: OperandInlineNone},
+	{Value: 0x60, Name: "or", Operand: OperandInlineNone},
+	{Value: 0x61, Name: "xor", Operand: OperandInlineNone},
+	{Value: 0x62, Name: "shl", Operand: OperandInlineNone},
+	{Value: 0x63, Name: "shr", Operand: OperandInlineNone},
+	{Value: 0x64, Name: "shr.un", Operand: OperandInlineNone},
+	{Value: 0x65, Name: "neg", Operand: OperandInlineNone},
+	{Value: 0x66, Name: "not", Operand: OperandInlineNone},
+	{Value: 0x67, Name: "conv.i1", Operand: OperandInlineNone},
+	{Value: 0x68, Name: "conv.i2", Operand: OperandInlineNone},
+	{Value: 0x69, Name: "conv.i4", Operand: OperandInlineNone},
+	{Value: 0x6A, Name: "conv.i8", Operand: OperandInlineNone},
+	{Value: 0x6B, Name: "conv.r4", Operand: OperandInlineNone},
+	{Value: 0x6C, Name: "conv.r8", Operand: OperandInlineNone},
+	{Value: 0x6D, Name: "conv.u4", Operand: OperandInlineNone},
+	{Value: 0x6E, Name: "conv.u8", Operand: OperandInlineNone},
+	{Value: 0x6F, Name: "callvirt", Operand: OperandInlineMethod},
+	{Value: 0x70, Name: "cpobj", Operand: OperandInlineType},
+	{Value: 0x71, Name: "ldobj", Operand: OperandInlineType},
+	{Value: 0x72, Name: "ldstr", Operand: OperandInlineString},
+	{Value: 0x73, Name: "newobj", Operand: OperandInlineMethod},
+	{Value: 0x74, Name: "castclass", Operand: OperandInlineType},
+	{Value: 0x75, Name: "isinst", Operand: OperandInlineType},
+	{Value: 0x76, Name: "conv.r.un", Operand: OperandInlineNone},
+	{Value: 0x79, Name: "unbox", Operand: OperandInlineType},
+	{Value: 0x7A, Name: "throw", Operand: OperandInlineNone},
+	{Value: 0x7B, Name: "ldfld", Operand: OperandInlineField},
+	{Value: 0x7C, Name: "ldflda", Operand: OperandInlineField},
+	{Value: 0x7D, Name: "stfld", Operand: OperandInlineField},
+	{Value: 0x7E, Name: "ldsfld", Operand: OperandInlineField},
+	{Value: 0x7F, Name: "ldsflda", Operand: OperandInlineField},
+	{Value: 0x80, Name: "stsfld", Operand: OperandInlineField},
+	{Value: 0x81, Name: "stobj", Operand: OperandInlineType},
+	{Value: 0x82, Name: "conv.ovf.i1.un", Operand: OperandInlineNone},
+	{Value: 0x83, Name: "conv.ovf.i2.un", Operand: OperandInlineNone},
+	{Value: 0x84, Name: "conv.ovf.i4.un", Operand: OperandInlineNone},
+	{Value: 0x85, Name: "conv.ovf.i8.un", Operand: OperandInlineNone},
+	{Value: 0x86, Name: "conv.ovf.u1.un", Operand: OperandInlineNone},
+	{Value: 0x87, Name: "conv.ovf.u2.un", Operand: OperandInlineNone},
+	{Value: 0x88, Name: "conv.ovf.u4.un", Operand: OperandInlineNone},
+	{Value: 0x89, Name: "conv.ovf.u8.un", Operand: OperandInlineNone},
+	{Value: 0x8A, Name: "conv.ovf.i.un", Operand: OperandInlineNone},
+	{Value: 0x8B, Name: "conv.ovf.u.un", Operand: OperandInlineNone},
+	{Value: 0x8C, Name: "box", Operand: OperandInlineType},
+	{Value: 0x8D, Name: "newarr", Operand: OperandInlineType},
+	{Value: 0x8E, Name: "ldlen", Operand: OperandInlineNone},
+	{Value: 0x8F, Name: "ldelema", Operand: OperandInlineType},
+	{Value: 0x90, Name: "ldelem.i1", Operand: OperandInlineNone},
+	{Value: 0x91, Name: "ldelem.u1", Operand: OperandInlineNone},
+	{Value: 0x92, Name: "ldelem.i2", Operand: OperandInlineNone},
+	{Value: 0x93, Name: "ldelem.u2", Operand: OperandInlineNone},
+	{Value: 0x94, Name: "ldelem.i4", Operand: OperandInlineNone},
+	{Value: 0x95, Name: "ldelem.u4", Operand: OperandInlineNone},
+	{Value: 0x96, Name: "ldelem.i8", Operand: OperandInlineNone},
+	{Value: 0x97, Name: "ldelem.i", Operand: OperandInlineNone},
+	{Value: 0x98, Name: "ldelem.r4", Operand: OperandInlineNone},
+	{Value: 0x99, Name: "ldelem.r8", Operand: OperandInlineNone},
+	{Value: 0x9A, Name: "ldelem.ref", Operand: OperandInlineNone},
+	{Value: 0x9B, Name: "stelem.i", Operand: OperandInlineNone},
+	{Value: 0x9C, Name: "stelem.i1", Operand: OperandInlineNone},
+	{Value: 0x9D, Name: "stelem.i2", Operand: OperandInlineNone},
+	{Value: 0x9E, Name: "stelem.i4", Operand: OperandInlineNone},
+	{Value: 0x9F, Name: "stelem.i8", Operand: OperandInlineNone},
+	{Value: 0xA0, Name: "stelem.r4", Operand: OperandInlineNone},
+	{Value: 0xA1, Name: "stelem.r8", Operand: OperandInlineNone},
+	{Value: 0xA2, Name: "stelem.ref", Operand: OperandInlineNone},
+	{Value: 0xA3, Name: "ldelem", Operand: OperandInlineType},
+	{Value: 0xA4, Name: "stelem", Operand: OperandInlineType},
+	{Value: 0xA5, Name: "unbox.any", Operand: OperandInlineType},
+	{Value: 0xB3, Name: "conv.ovf.i1", Operand: OperandInlineNone},
+	{Value: 0xB4, Name: "conv.ovf.u1", Operand: OperandInlineNone},
+	{Value: 0xB5, Name: "conv.ovf.i2", Operand: OperandInlineNone},
+	{Value: 0xB6, Name: "conv.ovf.u2", Operand: OperandInlineNone},
+	{Value: 0xB7, Name: "conv.ovf.i4", Operand: OperandInlineNone},
+	{Value: 0xB8, Name: "conv.ovf.u4", Operand: OperandInlineNone},
+	{Value: 0xB9, Name: "conv.ovf.i8", Operand: OperandInlineNone},
+	{Value: 0xBA, Name: "conv.ovf.u8", Operand: OperandInlineNone},
+	{Value: 0xC2, Name: "refanyval", Operand: OperandInlineType},
+	{Value: 0xC3, Name: "ckfinite", Operand: OperandInlineNone},
+	{Value: 0xC6, Name: "mkrefany", Operand: OperandInlineType},
+	{Value: 0xD0, Name: "ldtoken", Operand: OperandInlineTok},
+	{Value: 0xD1, Name: "conv.u2", Operand: OperandInlineNone},
+	{Value: 0xD2, Name: "conv.u1", Operand: OperandInlineNone},
+	{Value: 0xD3, Name: "conv.i", Operand: OperandInlineNone},
+	{Value: 0xD4, Name: "conv.ovf.i", Operand: OperandInlineNone},
+	{Value: 0xD5, Name: "conv.ovf.u", Operand: OperandInlineNone},
+	{Value: 0xD6, Name: "add.ovf", Operand: OperandInlineNone},
+	{Value: 0xD7, Name: "add.ovf.un", Operand: OperandInlineNone},
+	{Value: 0xD8, Name: "mul.ovf", Operand: OperandInlineNone},
+	{Value: 0xD9, Name: "mul.ovf.un", Operand: OperandInlineNone},
+	{Value: 0xDA, Name: "sub.ovf", Operand: OperandInlineNone},
+	{Value: 0xDB, Name: "sub.ovf.un", Operand: OperandInlineNone},
+	{Value: 0xDC, Name: "endfinally", Operand: OperandInlineNone},
+	{Value: 0xDD, Name: "leave", Operand: OperandInlineBrTarget},
+	{Value: 0xDE, Name: "leave.s", Operand: OperandShortInlineBrTarget},
+	{Value: 0xDF, Name: "stind.i", Operand: OperandInlineNone},
+	{Value: 0xE0, Name: "conv.u", Operand: OperandInlineNone},
+	{Value: 0xFE00, Name: "arglist", Operand: OperandInlineNone},
+	{Value: 0xFE01, Name: "ceq", Operand: OperandInlineNone},
+	{Value: 0xFE02, Name: "cgt", Operand: OperandInlineNone},
+	{Value: 0xFE03, Name: "cgt.un", Operand: OperandInlineNone},
+	{Value: 0xFE04, Name: "clt", Operand: OperandInlineNone},
+	{Value: 0xFE05, Name: "clt.un", Operand: OperandInlineNone},
+	{Value: 0xFE06, Name: "ldftn", Operand: OperandInlineMethod},
+	{Value: 0xFE07, Name: "ldvirtftn", Operand: OperandInlineMethod},
+	{Value: 0xFE09, Name: "ldarg", Operand: OperandInlineVar},
+	{Value: 0xFE0A, Name: "ldarga", Operand: OperandInlineVar},
+	{Value: 0xFE0B, Name: "starg", Operand: OperandInlineVar},
+	{Value: 0xFE0C, Name: "ldloc", Operand: OperandInlineVar},
+	{Value: 0xFE0D, Name: "ldloca", Operand: OperandInlineVar},
+	{Value: 0xFE0E, Name: "stloc", Operand: OperandInlineVar},
+	{Value: 0xFE0F, Name: "localloc", Operand: OperandInlineNone},
+	{Value: 0xFE11, Name: "endfilter", Operand: OperandInlineNone},
+	{Value: 0xFE12, Name: "unaligned.", Operand: OperandShortInlineI},
+	{Value: 0xFE13, Name: "volatile.", Operand: OperandInlineNone},
+	{Value: 0xFE14, Name: "tail.", Operand: OperandInlineNone},
+	{Value: 0xFE15, Name: "initobj", Operand: OperandInlineType},
+	{Value: 0xFE16, Name: "constrained.", Operand: OperandInlineType},
+	{Value: 0xFE17, Name: "cpblk", Operand: OperandInlineNone},
+	{Value: 0xFE18, Name: "initblk", Operand: OperandInlineNone},
+	{Value: 0xFE19, Name: "no.", Operand: OperandShortInlineI},
+	{Value: 0xFE1A, Name: "rethrow", Operand: OperandInlineNone},
+	{Value: 0xFE1C, Name: "sizeof", Operand: OperandInlineType},
+	{Value: 0xFE1D, Name: "refanytype", Operand: OperandInlineNone},
+	{Value: 0xFE1E, Name: "readonly.", Operand: OperandInlineNone},
+}
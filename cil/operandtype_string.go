@@ -0,0 +1,40 @@
+// Code generated by "stringer -trimprefix Operand -type OperandType"; DO NOT EDIT.
+
+package cil
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[OperandInlineNone-0]
+	_ = x[OperandShortInlineVar-1]
+	_ = x[OperandInlineVar-2]
+	_ = x[OperandShortInlineI-3]
+	_ = x[OperandInlineI-4]
+	_ = x[OperandInlineI8-5]
+	_ = x[OperandShortInlineR-6]
+	_ = x[OperandInlineR-7]
+	_ = x[OperandShortInlineBrTarget-8]
+	_ = x[OperandInlineBrTarget-9]
+	_ = x[OperandInlineSwitch-10]
+	_ = x[OperandInlineMethod-11]
+	_ = x[OperandInlineField-12]
+	_ = x[OperandInlineType-13]
+	_ = x[OperandInlineTok-14]
+	_ = x[OperandInlineString-15]
+	_ = x[OperandInlineSig-16]
+}
+
+const _OperandType_name = "InlineNoneShortInlineVarInlineVarShortInlineIInlineIInlineI8ShortInlineRInlineRShortInlineBrTargetInlineBrTargetInlineSwitchInlineMethodInlineFieldInlineTypeInlineTokInlineStringInlineSig"
+
+var _OperandType_index = [...]uint8{0, 10, 24, 33, 45, 52, 60, 72, 79, 98, 112, 124, 136, 147, 157, 166, 178, 187}
+
+func (i OperandType) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_OperandType_index)-1 {
+		return "OperandType(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _OperandType_name[_OperandType_index[idx]:_OperandType_index[idx+1]]
+}
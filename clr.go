@@ -0,0 +1,66 @@
+package pe
+
+import (
+	"github.com/mewmew/pe/cil"
+	"github.com/mewmew/pe/enum"
+	"github.com/mewmew/pe/metadata"
+	"github.com/pkg/errors"
+)
+
+// --- [ CLR Header ] ----------------------------------------------------------
+
+// CLRHeader is a CLR runtime header, present in managed (.NET) PE files.
+type CLRHeader struct {
+	// Size of header in number of bytes.
+	Size uint32
+	// Major runtime version.
+	MajorRuntimeVer uint16
+	// Minor runtime version.
+	MinorRuntimeVer uint16
+	// Relative address and size of metadata.
+	Metadata DataDirectory
+	// Runtime flags.
+	Flags enum.CLRFlag
+	// Metadata token of entry point MethodDef or File; or relative address of
+	// native entry point if CLRFlagNativeEntryPoint is set.
+	EntryPointToken uint32
+	// Relative address and size of managed resources.
+	Resources DataDirectory
+	// Relative address and size of strong name signature.
+	StrongNameSignature DataDirectory
+	// Reserved.
+	CodeManagerTable DataDirectory
+	// Relative address and size of VTable fixups.
+	VTableFixups DataDirectory
+	// Reserved.
+	ExportAddressTableJumps DataDirectory
+	// Relative address and size of native header (NGen or ReadyToRun); zero
+	// for IL-only images.
+	ManagedNativeHeader DataDirectory
+}
+
+// MethodBody returns the CIL method body at the given relative address
+// (relative to image base), as specified by the RelAddr of a MethodDef.
+func (file *File) MethodBody(relAddr uint32) (*cil.MethodBody, error) {
+	buf, err := file.readFrom(relAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	body, err := cil.ParseMethodBody(buf)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse method body at relative address 0x%08X", relAddr)
+	}
+	return body, nil
+}
+
+// parseMetadata parses the CLR metadata of the managed PE file.
+func (file *File) parseMetadata() (*metadata.Metadata, error) {
+	dataDir := file.CLRHdr.Metadata
+	addr := file.OptHdr.ImageBase + uint64(dataDir.RelAddr)
+	buf := file.ReadData(addr, int64(dataDir.Size))
+	md, err := metadata.Parse(buf)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return md, nil
+}
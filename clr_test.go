@@ -0,0 +1,128 @@
+package pe
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mewmew/pe/cil"
+	"github.com/mewmew/pe/metadata"
+)
+
+func TestMethodBody(t *testing.T) {
+	file, err := ParseFile("testdata/Microsoft.TestPlatform.PlatformAbstractions.dll")
+	if err != nil {
+		t.Fatalf("unable to parse file; %+v", err)
+	}
+	md := file.Metadata
+	if md == nil {
+		t.Fatal("missing CLR metadata")
+	}
+	golden := []struct {
+		// MethodDef RID.
+		rid      uint32
+		name     string
+		isFat    bool
+		maxStack uint16
+		locals   metadata.Token
+		clauses  []cil.ExceptionClause
+		insts    []string
+	}{
+		// Tiny method header.
+		{
+			rid:      3,
+			name:     "instance void System.Runtime.CompilerServices.NullableAttribute::.ctor(uint8[])",
+			maxStack: 8,
+			insts: []string{
+				"IL_0000:  ldarg.0",
+				"IL_0001:  call       instance void [mscorlib]System.Attribute::.ctor()",
+				"IL_0006:  ldarg.0",
+				"IL_0007:  ldarg.1",
+				"IL_0008:  stfld      uint8[] System.Runtime.CompilerServices.NullableAttribute::NullableFlags",
+				"IL_000d:  ret",
+			},
+		},
+		// Fat method header with local variables and a finally clause.
+		{
+			rid:      43,
+			name:     "class [System]System.Diagnostics.TraceSource Microsoft.VisualStudio.TestPlatform.ObjectModel.PlatformEqtTrace::get_Source()",
+			isFat:    true,
+			maxStack: 2,
+			locals:   metadata.NewToken(metadata.TableStandAloneSig, 2),
+			clauses: []cil.ExceptionClause{
+				{Flags: cil.ClauseFlagFinally, TryOffset: 0x0F, TryLength: 0x21, HandlerOffset: 0x30, HandlerLength: 0x0A},
+			},
+			insts: []string{
+				"IL_0000:  ldsfld     class [System]System.Diagnostics.TraceSource Microsoft.VisualStudio.TestPlatform.ObjectModel.PlatformEqtTrace::s_traceSource",
+				"IL_0005:  brtrue.s   IL_003a",
+				"IL_0007:  ldsfld     object Microsoft.VisualStudio.TestPlatform.ObjectModel.PlatformEqtTrace::LockObject",
+				"IL_000c:  stloc.0",
+				"IL_000d:  ldc.i4.0",
+				"IL_000e:  stloc.1",
+				"IL_000f:  ldloc.0",
+				"IL_0010:  ldloca.s   V_1",
+				"IL_0012:  call       void [mscorlib]System.Threading.Monitor::Enter(object, bool&)",
+				"IL_0017:  ldsfld     class [System]System.Diagnostics.TraceSource Microsoft.VisualStudio.TestPlatform.ObjectModel.PlatformEqtTrace::s_traceSource",
+				"IL_001c:  brtrue.s   IL_002e",
+				`IL_001e:  ldstr      "TpTrace"`,
+				"IL_0023:  ldc.i4.0",
+				"IL_0024:  newobj     instance void [System]System.Diagnostics.TraceSource::.ctor(string, valuetype [System]System.Diagnostics.SourceLevels)",
+				"IL_0029:  stsfld     class [System]System.Diagnostics.TraceSource Microsoft.VisualStudio.TestPlatform.ObjectModel.PlatformEqtTrace::s_traceSource",
+				"IL_002e:  leave.s    IL_003a",
+				"IL_0030:  ldloc.1",
+				"IL_0031:  brfalse.s  IL_0039",
+				"IL_0033:  ldloc.0",
+				"IL_0034:  call       void [mscorlib]System.Threading.Monitor::Exit(object)",
+				"IL_0039:  endfinally",
+				"IL_003a:  ldsfld     class [System]System.Diagnostics.TraceSource Microsoft.VisualStudio.TestPlatform.ObjectModel.PlatformEqtTrace::s_traceSource",
+				"IL_003f:  ret",
+			},
+		},
+	}
+	for _, g := range golden {
+		tok := metadata.NewToken(metadata.TableMethodDef, g.rid)
+		if name := md.TokenName(tok); name != g.name {
+			t.Errorf("MethodDef %d: name mismatch; expected %q, got %q", g.rid, g.name, name)
+		}
+		method := md.Tables.MethodDef[g.rid-1]
+		body, err := file.MethodBody(method.RelAddr)
+		if err != nil {
+			t.Errorf("%q: unable to parse method body; %+v", g.name, err)
+			continue
+		}
+		if body.IsFat != g.isFat {
+			t.Errorf("%q: header format mismatch; expected fat %v, got %v", g.name, g.isFat, body.IsFat)
+		}
+		if body.MaxStack != g.maxStack {
+			t.Errorf("%q: max stack mismatch; expected %d, got %d", g.name, g.maxStack, body.MaxStack)
+		}
+		if body.LocalVarSigTok != g.locals {
+			t.Errorf("%q: local variable signature token mismatch; expected 0x%08X, got 0x%08X", g.name, uint32(g.locals), uint32(body.LocalVarSigTok))
+		}
+		var clauses []cil.ExceptionClause
+		for _, sect := range body.Sects {
+			clauses = append(clauses, sect.Clauses...)
+		}
+		if len(clauses) != len(g.clauses) {
+			t.Errorf("%q: number of exception clauses mismatch; expected %d, got %d", g.name, len(g.clauses), len(clauses))
+		} else {
+			for i := range clauses {
+				if clauses[i] != g.clauses[i] {
+					t.Errorf("%q: exception clause %d mismatch; expected %+v, got %+v", g.name, i, g.clauses[i], clauses[i])
+				}
+			}
+		}
+		insts, err := cil.Decode(body.Code)
+		if err != nil {
+			t.Errorf("%q: unable to decode method body; %+v", g.name, err)
+			continue
+		}
+		var got []string
+		for _, inst := range insts {
+			got = append(got, inst.Format(md))
+		}
+		want := strings.Join(g.insts, "\n")
+		if s := strings.Join(got, "\n"); s != want {
+			t.Errorf("%q: instructions mismatch; expected\n%s\ngot\n%s", g.name, want, s)
+		}
+	}
+}
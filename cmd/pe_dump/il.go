@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mewmew/pe"
+	"github.com/mewmew/pe/cil"
+	"github.com/mewmew/pe/metadata"
+	"github.com/pkg/errors"
+)
+
+// dumpIL prints an ildasm-like CIL listing of the managed methods of the given
+// PE file.
+func dumpIL(w io.Writer, file *pe.File) error {
+	md := file.Metadata
+	if md == nil || md.Tables == nil {
+		return errors.New("unable to locate CLR metadata; not a managed PE file")
+	}
+	for i, method := range md.Tables.MethodDef {
+		tok := metadata.NewToken(metadata.TableMethodDef, uint32(i+1))
+		fmt.Fprintf(w, ".method %s\n", md.TokenName(tok))
+		if method.RelAddr == 0 {
+			// abstract, runtime or P/Invoke method without method body.
+			fmt.Fprintln(w, "{")
+			fmt.Fprintln(w, "  // no method body")
+			fmt.Fprintln(w, "}")
+			fmt.Fprintln(w)
+			continue
+		}
+		body, err := file.MethodBody(method.RelAddr)
+		if err != nil {
+			return errors.Wrapf(err, "unable to parse method body of %v", tok)
+		}
+		if err := dumpMethodBody(w, md, body); err != nil {
+			return errors.Wrapf(err, "unable to disassemble method body of %v", tok)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// dumpMethodBody prints an ildasm-like CIL listing of the given method body.
+func dumpMethodBody(w io.Writer, md *metadata.Metadata, body *cil.MethodBody) error {
+	fmt.Fprintln(w, "{")
+	fmt.Fprintf(w, "  // Code size %d (0x%X)\n", body.CodeSize, body.CodeSize)
+	fmt.Fprintf(w, "  .maxstack %d\n", body.MaxStack)
+	if !body.LocalVarSigTok.IsNil() {
+		locals, err := md.LocalVarSig(body.LocalVarSigTok)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for i := range locals {
+			locals[i] = fmt.Sprintf("%s V_%d", locals[i], i)
+		}
+		init := ""
+		if body.Flags&cil.MethodFlagInitLocals != 0 {
+			init = "init "
+		}
+		fmt.Fprintf(w, "  .locals %s(%s)\n", init, strings.Join(locals, ", "))
+	}
+	insts, err := cil.Decode(body.Code)
+	for _, inst := range insts {
+		fmt.Fprintf(w, "  %s\n", inst.Format(md))
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, sect := range body.Sects {
+		for _, clause := range sect.Clauses {
+			fmt.Fprintf(w, "  %s\n", formatClause(md, clause))
+		}
+	}
+	fmt.Fprintln(w, "}")
+	return nil
+}
+
+// formatClause returns the ILAsm representation of the given exception
+// handling clause.
+func formatClause(md *metadata.Metadata, clause cil.ExceptionClause) string {
+	try := fmt.Sprintf(".try %s to %s", cil.Label(clause.TryOffset), cil.Label(clause.TryOffset+clause.TryLength))
+	handler := fmt.Sprintf("handler %s to %s", cil.Label(clause.HandlerOffset), cil.Label(clause.HandlerOffset+clause.HandlerLength))
+	switch clause.Flags {
+	case cil.ClauseFlagException:
+		return fmt.Sprintf("%s catch %s %s", try, md.TypeName(clause.ClassToken), handler)
+	case cil.ClauseFlagFilter:
+		return fmt.Sprintf("%s filter %s %s", try, cil.Label(clause.FilterOffset), handler)
+	case cil.ClauseFlagFinally:
+		return fmt.Sprintf("%s finally %s", try, handler)
+	case cil.ClauseFlagFault:
+		return fmt.Sprintf("%s fault %s", try, handler)
+	default:
+		return fmt.Sprintf("%s /* unknown clause kind 0x%X */ %s", try, uint32(clause.Flags), handler)
+	}
+}
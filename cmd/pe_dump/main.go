@@ -2,28 +2,85 @@ package main
 
 import (
 	"flag"
+	"io"
+	"io/ioutil"
 	"log"
+	"os"
 
 	"github.com/kr/pretty"
 	"github.com/mewmew/pe"
+	"github.com/mewmew/pe/upx"
 	"github.com/pkg/errors"
 )
 
 func main() {
+	var (
+		// Print CIL disassembly of managed methods.
+		il bool
+		// Output path of overlay data.
+		overlayPath string
+		// Output path of unpacked UPX packed file.
+		unpackPath string
+	)
+	flag.BoolVar(&il, "il", false, "print CIL disassembly of managed methods")
+	flag.StringVar(&overlayPath, "overlay", "", "write overlay data (appended after the last section) to the given file")
+	flag.StringVar(&unpackPath, "upx", "", "write unpacked UPX packed file to the given file")
 	flag.Parse()
 	for _, pePath := range flag.Args() {
-		if err := parse(pePath); err != nil {
+		if err := parse(pePath, il, overlayPath, unpackPath); err != nil {
 			log.Fatalf("%+v", err)
 		}
 	}
 }
 
-func parse(pePath string) error {
+func parse(pePath string, il bool, overlayPath, unpackPath string) error {
 	file, err := pe.ParseFile(pePath)
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	if len(overlayPath) > 0 {
+		return writeOverlay(overlayPath, file)
+	}
+	if len(unpackPath) > 0 {
+		return writeUnpacked(unpackPath, file)
+	}
+	if il {
+		return dumpIL(os.Stdout, file)
+	}
 	file.Content = nil
 	pretty.Println(file)
 	return nil
 }
+
+// writeOverlay writes the overlay data of the given PE file to the given output
+// path.
+func writeOverlay(overlayPath string, file *pe.File) error {
+	offset, size, sr := file.Overlay()
+	if size == 0 {
+		return errors.New("unable to locate overlay; no data appended after the last section")
+	}
+	f, err := os.Create(overlayPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, sr); err != nil {
+		return errors.WithStack(err)
+	}
+	log.Printf("wrote overlay (offset 0x%X, size %d bytes) to %q", offset, size, overlayPath)
+	return nil
+}
+
+// writeUnpacked writes the unpacked PE image of the given UPX packed PE file to
+// the given output path.
+func writeUnpacked(unpackPath string, file *pe.File) error {
+	content, err := upx.UnpackBytes(file)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ioutil.WriteFile(unpackPath, content, 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	log.Printf("wrote unpacked file (%d bytes) to %q", len(content), unpackPath)
+	return nil
+}
@@ -0,0 +1,131 @@
+// The pe_ordinals tool generates Go source code of ordinal name tables from the
+// imports by ordinal of import libraries (e.g. mfc42.lib and mfc42u.lib), for
+// use as built-in ordinal name tables of the pe package.
+//
+// Usage:
+//
+//	pe_ordinals [OPTION]... FILE.lib...
+//
+// Flags:
+//
+//	-o string
+//	      output path (default standard output)
+//	-pkg string
+//	      package name of generated source code (default "pe")
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mewmew/pe"
+	"github.com/pkg/errors"
+)
+
+func main() {
+	var (
+		// Output path.
+		output string
+		// Package name of generated source code.
+		pkgName string
+	)
+	flag.StringVar(&output, "o", "", "output path (default standard output)")
+	flag.StringVar(&pkgName, "pkg", "pe", "package name of generated source code")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	src, err := genOrdinals(pkgName, flag.Args())
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+	if len(output) == 0 {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(output, src, 0644); err != nil {
+		log.Fatalf("%+v", errors.WithStack(err))
+	}
+}
+
+// genOrdinals returns Go source code of the ordinal name tables of the DLLs
+// imported by ordinal by the given import libraries.
+func genOrdinals(pkgName string, libPaths []string) ([]byte, error) {
+	// Ordinal names, indexed by normalized DLL name.
+	dlls := make(map[string]map[uint16]string)
+	for _, libPath := range libPaths {
+		arch, err := pe.ParseArchiveFile(libPath)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for dllName, names := range pe.OrdinalNamesFromImportLib(arch) {
+			key := dllKey(dllName)
+			if dlls[key] == nil {
+				dlls[key] = make(map[uint16]string)
+			}
+			for ordinal, name := range names {
+				dlls[key][ordinal] = name
+			}
+		}
+	}
+	if len(dlls) == 0 {
+		return nil, errors.New("no imports by ordinal in import libraries")
+	}
+	var keys []string
+	for key := range dlls {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by pe_ordinals %s; DO NOT EDIT.\n\n", strings.Join(baseNames(libPaths), " "))
+	fmt.Fprintf(buf, "package %s\n\n", pkgName)
+	buf.WriteString("func init() {\n")
+	for _, key := range keys {
+		fmt.Fprintf(buf, "\tordinalNames[%q] = %sOrdinals\n", key, key)
+	}
+	buf.WriteString("}\n")
+	for _, key := range keys {
+		names := dlls[key]
+		var ordinals []int
+		for ordinal := range names {
+			ordinals = append(ordinals, int(ordinal))
+		}
+		sort.Ints(ordinals)
+		fmt.Fprintf(buf, "\n// %sOrdinals maps from ordinal to export name of %s.dll.\n", key, key)
+		fmt.Fprintf(buf, "var %sOrdinals = map[uint16]string{\n", key)
+		for _, ordinal := range ordinals {
+			fmt.Fprintf(buf, "\t%d: %q,\n", ordinal, names[uint16(ordinal)])
+		}
+		buf.WriteString("}\n")
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return src, nil
+}
+
+// dllKey returns the normalized DLL name used to index ordinal name tables; in
+// lowercase and without file extension (e.g. "mfc42u").
+func dllKey(dllName string) string {
+	name := strings.ToLower(dllName)
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// baseNames returns the base names of the given file paths.
+func baseNames(paths []string) []string {
+	var names []string
+	for _, p := range paths {
+		names = append(names, path.Base(strings.Replace(p, `\`, "/", -1)))
+	}
+	return names
+}
@@ -1,9 +1,18 @@
 package pe
 
 import (
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"io"
+	"io/ioutil"
 	"time"
 
 	"github.com/mewmew/pe/enum"
+	"github.com/mewmew/pe/metadata"
+	"github.com/pkg/errors"
 )
 
 // --- [ Debug ] ---------------------------------------------------------------
@@ -15,6 +24,14 @@ import (
 //    *DebugCodeView
 //    *DebugFPO
 //    *DebugMisc
+//    *DebugOMap
+//    *DebugVCFeature
+//    *DebugPOGO
+//    *DebugRepro
+//    *DebugEmbeddedPortablePDB
+//    *DebugPDBChecksum
+//    *DebugExDLLCharacteristics
+//    *DebugRaw
 type DebugData interface {
 	// DebugDir returns the debug data directory of the debug data.
 	DebugDir() DebugDirectory
@@ -47,7 +64,7 @@ type DebugCodeView struct {
 	// Debug data directory.
 	DbgDir DebugDirectory
 	// CodeView debug information.
-	CodeViewInfo
+	Info CodeViewInfo
 }
 
 // DebugDir returns the debug data directory of the CodeView debug data.
@@ -55,13 +72,37 @@ func (dbg *DebugCodeView) DebugDir() DebugDirectory {
 	return dbg.DbgDir
 }
 
-// CodeViewInfo contains CodeView debug information.
-type CodeViewInfo struct {
-	// CodeView signature ("NB10").
-	Signature uint32
+// CodeViewInfo is CodeView debug information, as identified by its CodeView
+// signature.
+//
+// CodeViewInfo is one of the following types.
+//
+//    *CodeViewNB09
+//    *CodeViewNB10
+//    *CodeViewNB11
+//    *CodeViewRSDS
+//    *CodeViewMTOC
+//    *CodeViewRaw
+type CodeViewInfo interface {
+	// isCodeViewInfo ensures that only CodeView debug information can be
+	// assigned to the CodeViewInfo interface.
+	isCodeViewInfo()
+}
+
+// CodeViewNB09 is CodeView 4.10 debug information ("NB09") stored in the
+// image.
+type CodeViewNB09 struct {
+	// Offset to CodeView subsection directory, relative to the start of the
+	// CodeView debug information.
+	DirOffset uint32
+}
+
+// CodeViewNB10 is CodeView debug information ("NB10") stored in a separate PDB
+// 2.0 file.
+type CodeViewNB10 struct {
 	// CodeView offset (set to 0 since debug info is stored in a separate file).
 	Offset uint32
-	// CodeView debug data creation time.
+	// PDB signature; creation time of the PDB file.
 	Date time.Time
 	// Incremental number, initially set to 1 and incremented for each partial
 	// write to the PDB file.
@@ -70,6 +111,51 @@ type CodeViewInfo struct {
 	PDBPath string
 }
 
+// CodeViewNB11 is CodeView 5.0 debug information ("NB11") stored in the image.
+type CodeViewNB11 struct {
+	// Offset to CodeView subsection directory, relative to the start of the
+	// CodeView debug information.
+	DirOffset uint32
+}
+
+// CodeViewRSDS is CodeView debug information ("RSDS") stored in a separate PDB
+// 7.0 file.
+type CodeViewRSDS struct {
+	// PDB signature; matches the GUID of the PDB file.
+	GUID GUID
+	// Incremental number, initially set to 1 and incremented for each partial
+	// write to the PDB file.
+	Age uint32
+	// Path to PDB file (UTF-8 encoded).
+	PDBPath string
+}
+
+// CodeViewMTOC is CodeView debug information ("MTOC") of an image converted
+// from Mach-O, as produced by the mtoc tool of EFI toolchains.
+type CodeViewMTOC struct {
+	// UUID of the original Mach-O file (in big-endian byte order).
+	UUID [16]byte
+	// Path to debug file (UTF-8 encoded).
+	PDBPath string
+}
+
+// CodeViewRaw is CodeView debug information of unknown format.
+type CodeViewRaw struct {
+	// CodeView signature.
+	Signature [4]byte
+	// Raw contents of CodeView debug information, including signature.
+	Data []byte
+}
+
+// isCodeViewInfo ensures that only CodeView debug information can be assigned
+// to the CodeViewInfo interface.
+func (*CodeViewNB09) isCodeViewInfo() {}
+func (*CodeViewNB10) isCodeViewInfo() {}
+func (*CodeViewNB11) isCodeViewInfo() {}
+func (*CodeViewRSDS) isCodeViewInfo() {}
+func (*CodeViewMTOC) isCodeViewInfo() {}
+func (*CodeViewRaw) isCodeViewInfo()  {}
+
 // ~~~ [ FPO ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // DebugFPO contains the contents of a FPO debug data directory.
@@ -125,3 +211,227 @@ type DebugMisc struct {
 func (dbg *DebugMisc) DebugDir() DebugDirectory {
 	return dbg.DbgDir
 }
+
+// ~~~ [ OMAP ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// DebugOMap contains the contents of an OMAP to source or OMAP from source
+// debug data directory.
+type DebugOMap struct {
+	// Debug data directory.
+	DbgDir DebugDirectory
+	// OMAP entries, sorted by relative address.
+	Entries []OMapEntry
+}
+
+// DebugDir returns the debug data directory of the OMAP debug data.
+func (dbg *DebugOMap) DebugDir() DebugDirectory {
+	return dbg.DbgDir
+}
+
+// OMapEntry maps a relative address of one image to the corresponding relative
+// address of another image.
+//
+// OMAP to source maps from the image to the source image (before rearrangement
+// by a post-link optimizer), and OMAP from source maps from the source image to
+// the image.
+type OMapEntry struct {
+	// Relative address in the source image.
+	RelAddr uint32
+	// Relative address in the destination image; zero if not mapped.
+	RelAddrTo uint32
+}
+
+// ~~~ [ VC_FEATURE ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// DebugVCFeature contains the contents of a Visual C++ feature debug data
+// directory.
+type DebugVCFeature struct {
+	// Debug data directory.
+	DbgDir DebugDirectory
+	// Number of object files built by compilers prior to Visual C++ 11.
+	PreVC11 uint32
+	// Number of object files built by the C/C++ compiler.
+	CCpp uint32
+	// Number of object files built with buffer security checks (/GS).
+	GS uint32
+	// Number of object files built with additional security checks (/sdl).
+	SDL uint32
+	// Number of object files built with /guardN.
+	GuardN uint32
+}
+
+// DebugDir returns the debug data directory of the Visual C++ feature debug
+// data.
+func (dbg *DebugVCFeature) DebugDir() DebugDirectory {
+	return dbg.DbgDir
+}
+
+// ~~~ [ POGO ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// DebugPOGO contains the contents of a profile guided optimization (POGO) debug
+// data directory.
+type DebugPOGO struct {
+	// Debug data directory.
+	DbgDir DebugDirectory
+	// POGO signature.
+	Signature enum.POGOType
+	// Section contributions.
+	Entries []POGOEntry
+}
+
+// DebugDir returns the debug data directory of the POGO debug data.
+func (dbg *DebugPOGO) DebugDir() DebugDirectory {
+	return dbg.DbgDir
+}
+
+// POGOEntry is a POGO section contribution (e.g. ".text$mn" or ".rdata$zz").
+type POGOEntry struct {
+	// Relative address of section contribution.
+	RelAddr uint32
+	// Size of section contribution in number of bytes.
+	Size uint32
+	// Name of section contribution.
+	Name string
+}
+
+// ~~~ [ REPRO ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// DebugRepro contains the contents of a PE reproducibility debug data
+// directory. The presence of the debug data indicates a deterministic build, in
+// which case the date stamp of the file header is derived from the hash.
+type DebugRepro struct {
+	// Debug data directory.
+	DbgDir DebugDirectory
+	// Hash of the image contents; nil if not present.
+	Hash []byte
+}
+
+// DebugDir returns the debug data directory of the reproducibility debug data.
+func (dbg *DebugRepro) DebugDir() DebugDirectory {
+	return dbg.DbgDir
+}
+
+// ~~~ [ EMBEDDED_PORTABLE_PDB ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// DebugEmbeddedPortablePDB contains the contents of an embedded portable PDB
+// debug data directory.
+type DebugEmbeddedPortablePDB struct {
+	// Debug data directory.
+	DbgDir DebugDirectory
+	// Size in bytes of the decompressed portable PDB.
+	Size uint32
+	// Deflate compressed contents of the portable PDB.
+	Data []byte
+}
+
+// DebugDir returns the debug data directory of the embedded portable PDB debug
+// data.
+func (dbg *DebugEmbeddedPortablePDB) DebugDir() DebugDirectory {
+	return dbg.DbgDir
+}
+
+// PDB returns the decompressed contents of the embedded portable PDB.
+func (dbg *DebugEmbeddedPortablePDB) PDB() ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(dbg.Data))
+	defer r.Close()
+	// The recorded size is not trusted for preallocation; the buffer grows with
+	// the decompressed data, read up to one byte past the recorded size to
+	// detect oversized data.
+	buf, err := ioutil.ReadAll(io.LimitReader(r, int64(dbg.Size)+1))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if uint64(len(buf)) != uint64(dbg.Size) {
+		return nil, errors.Errorf("size mismatch of embedded portable PDB; expected %d bytes, got %d", dbg.Size, len(buf))
+	}
+	return buf, nil
+}
+
+// ~~~ [ PDBCHECKSUM ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// DebugPDBChecksum contains the contents of a PDB checksum debug data
+// directory.
+type DebugPDBChecksum struct {
+	// Debug data directory.
+	DbgDir DebugDirectory
+	// Name of hash algorithm (e.g. "SHA256").
+	Algorithm string
+	// Checksum of the PDB file.
+	Checksum []byte
+}
+
+// DebugDir returns the debug data directory of the PDB checksum debug data.
+func (dbg *DebugPDBChecksum) DebugDir() DebugDirectory {
+	return dbg.DbgDir
+}
+
+// Verify reports whether the contents of the given portable PDB file match the
+// PDB checksum.
+//
+// The checksum is calculated over the entire PDB file, with the 20-byte PDB ID
+// at the start of the #Pdb stream set to zero.
+func (dbg *DebugPDBChecksum) Verify(pdb []byte) (bool, error) {
+	var h hash.Hash
+	switch dbg.Algorithm {
+	case "SHA256":
+		h = sha256.New()
+	case "SHA384":
+		h = sha512.New384()
+	case "SHA512":
+		h = sha512.New()
+	default:
+		return false, errors.Errorf("support for PDB checksum algorithm %q not yet implemented", dbg.Algorithm)
+	}
+	root, err := metadata.ParseRoot(pdb)
+	if err != nil {
+		return false, errors.Wrap(err, "unable to parse metadata root of portable PDB")
+	}
+	const pdbIDSize = 20
+	for _, streamHdr := range root.StreamHdrs {
+		if streamHdr.Name != "#Pdb" {
+			continue
+		}
+		start := uint64(streamHdr.Offset)
+		end := start + pdbIDSize
+		if streamHdr.Size < pdbIDSize || end > uint64(len(pdb)) {
+			return false, errors.Errorf("PDB ID out of bounds; expected end <= %d, got %d", len(pdb), end)
+		}
+		h.Write(pdb[:start])
+		h.Write(make([]byte, pdbIDSize))
+		h.Write(pdb[end:])
+		return bytes.Equal(h.Sum(nil), dbg.Checksum), nil
+	}
+	return false, errors.New("unable to locate #Pdb stream of portable PDB")
+}
+
+// ~~~ [ EX_DLLCHARACTERISTICS ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// DebugExDLLCharacteristics contains the contents of an extended DLL
+// characteristics debug data directory.
+type DebugExDLLCharacteristics struct {
+	// Debug data directory.
+	DbgDir DebugDirectory
+	// Extended DLL characteristics.
+	Characteristics enum.ExDLLCharacteristic
+}
+
+// DebugDir returns the debug data directory of the extended DLL
+// characteristics debug data.
+func (dbg *DebugExDLLCharacteristics) DebugDir() DebugDirectory {
+	return dbg.DbgDir
+}
+
+// ~~~ [ Raw ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// DebugRaw contains the contents of a debug data directory of unknown format.
+type DebugRaw struct {
+	// Debug data directory.
+	DbgDir DebugDirectory
+	// Raw contents of debug data directory.
+	Content []byte
+}
+
+// DebugDir returns the debug data directory of the raw debug data.
+func (dbg *DebugRaw) DebugDir() DebugDirectory {
+	return dbg.DbgDir
+}
@@ -0,0 +1,178 @@
+package pe
+
+import (
+	"bytes"
+	"compress/flate"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDebugEmbeddedPortablePDB(t *testing.T) {
+	file, err := ParseFile("testdata/Microsoft.TestPlatform.PlatformAbstractions.dll")
+	if err != nil {
+		t.Fatalf("unable to parse file; %+v", err)
+	}
+	var embedded *DebugEmbeddedPortablePDB
+	var checksum *DebugPDBChecksum
+	for _, dbg := range file.DbgData {
+		switch dbg := dbg.(type) {
+		case *DebugEmbeddedPortablePDB:
+			embedded = dbg
+		case *DebugPDBChecksum:
+			checksum = dbg
+		}
+	}
+	if embedded == nil {
+		t.Fatal("unable to locate embedded portable PDB debug data")
+	}
+	pdb, err := embedded.PDB()
+	if err != nil {
+		t.Fatalf("unable to decompress embedded portable PDB; %+v", err)
+	}
+	if len(pdb) != int(embedded.Size) {
+		t.Errorf("size mismatch of embedded portable PDB; expected %d, got %d", embedded.Size, len(pdb))
+	}
+	// Portable PDB files start with the metadata signature.
+	if !bytes.HasPrefix(pdb, []byte("BSJB")) {
+		t.Errorf("invalid signature of embedded portable PDB; expected %q, got %q", "BSJB", pdb[:4])
+	}
+	if checksum == nil {
+		t.Fatal("unable to locate PDB checksum debug data")
+	}
+	ok, err := checksum.Verify(pdb)
+	if err != nil {
+		t.Fatalf("unable to verify PDB checksum; %+v", err)
+	}
+	if !ok {
+		t.Error("PDB checksum mismatch of embedded portable PDB")
+	}
+}
+
+func TestDebugEmbeddedPortablePDBSizeMismatch(t *testing.T) {
+	data := &bytes.Buffer{}
+	w, err := flate.NewWriter(data, flate.BestCompression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("BSJB0123456789"))
+	w.Close()
+	golden := []struct {
+		size    uint32
+		wantErr bool
+	}{
+		{size: 14},
+		// Truncated data.
+		{size: 15, wantErr: true},
+		// Oversized data.
+		{size: 13, wantErr: true},
+		// Size far exceeding the compressed data must not be preallocated.
+		{size: 0xFFFFFFFF, wantErr: true},
+	}
+	for _, g := range golden {
+		dbg := &DebugEmbeddedPortablePDB{Size: g.size, Data: data.Bytes()}
+		_, err := dbg.PDB()
+		if (err != nil) != g.wantErr {
+			t.Errorf("size %d: error mismatch; expected error %v, got %v", g.size, g.wantErr, err)
+		}
+	}
+}
+
+func TestDebugCodeView(t *testing.T) {
+	const path = "testdata/Microsoft.TestPlatform.PlatformAbstractions.dll"
+	file, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	var codeView *DebugCodeView
+	for _, dbg := range file.DbgData {
+		if dbg, ok := dbg.(*DebugCodeView); ok {
+			codeView = dbg
+		}
+	}
+	if codeView == nil {
+		t.Fatalf("%q: unable to locate CodeView debug data", path)
+	}
+	if codeView.DbgDir.Size != 72 || codeView.DbgDir.Offset != 0x76CC {
+		t.Errorf("%q: debug data directory mismatch; expected size 72 at offset 0x76CC, got size %d at offset 0x%X", path, codeView.DbgDir.Size, codeView.DbgDir.Offset)
+	}
+	want := &CodeViewRSDS{
+		GUID:    GUID{0x34, 0xF3, 0x54, 0xA1, 0xB6, 0x66, 0xAF, 0x48, 0xBC, 0x67, 0x7B, 0x6B, 0x88, 0xC1, 0x45, 0xFA},
+		Age:     1,
+		PDBPath: "Microsoft.TestPlatform.PlatformAbstractions.pdb",
+	}
+	if !reflect.DeepEqual(codeView.Info, want) {
+		t.Errorf("%q: CodeView debug information mismatch; expected %#v, got %#v", path, want, codeView.Info)
+	}
+}
+
+func TestParseCodeViewInfo(t *testing.T) {
+	golden := []struct {
+		name    string
+		buf     string
+		want    CodeViewInfo
+		wantErr bool
+	}{
+		{
+			name: "NB09",
+			buf:  "NB09\x08\x00\x00\x00",
+			want: &CodeViewNB09{DirOffset: 8},
+		},
+		{
+			name: "NB10",
+			buf:  "NB10\x00\x00\x00\x00\x2E\x8B\x3A\x5C\x02\x00\x00\x00foo.pdb\x00",
+			want: &CodeViewNB10{Date: time.Unix(0x5C3A8B2E, 0), Age: 2, PDBPath: "foo.pdb"},
+		},
+		{
+			name: "NB11",
+			buf:  "NB11\x10\x20\x00\x00",
+			want: &CodeViewNB11{DirOffset: 0x2010},
+		},
+		{
+			name: "RSDS",
+			buf:  "RSDS\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0A\x0B\x0C\x0D\x0E\x0F\x03\x00\x00\x00C:\\foo\\bar.pdb\x00",
+			want: &CodeViewRSDS{GUID: GUID{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F}, Age: 3, PDBPath: `C:\foo\bar.pdb`},
+		},
+		{
+			name: "MTOC",
+			buf:  "MTOC\x0F\x0E\x0D\x0C\x0B\x0A\x09\x08\x07\x06\x05\x04\x03\x02\x01\x00/tmp/foo.dll\x00",
+			want: &CodeViewMTOC{UUID: [16]byte{0x0F, 0x0E, 0x0D, 0x0C, 0x0B, 0x0A, 0x09, 0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, 0x00}, PDBPath: "/tmp/foo.dll"},
+		},
+		{
+			name: "unknown signature",
+			buf:  "ABCD\x01\x02",
+			want: &CodeViewRaw{Signature: [4]byte{'A', 'B', 'C', 'D'}, Data: []byte("ABCD\x01\x02")},
+		},
+		{
+			name:    "truncated signature",
+			buf:     "RSD",
+			wantErr: true,
+		},
+		{
+			name:    "truncated RSDS",
+			buf:     "RSDS\x00\x01\x02\x03",
+			wantErr: true,
+		},
+		{
+			name:    "truncated NB09",
+			buf:     "NB09\x08",
+			wantErr: true,
+		},
+	}
+	for _, g := range golden {
+		got, err := parseCodeViewInfo([]byte(g.buf))
+		if g.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", g.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unable to parse CodeView debug information; %+v", g.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("%q: CodeView debug information mismatch; expected %#v, got %#v", g.name, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package pe
+
+import (
+	"bytes"
+	"compress/zlib"
+	"debug/dwarf"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// --- [ DWARF debug information ] ---------------------------------------------
+
+// DWARF returns the DWARF debug information of the file, as stored in the
+// ".debug_*" sections by GCC and Clang when targeting Windows. Section names
+// longer than 8 characters are resolved through the COFF string table.
+// Compressed ".zdebug_*" sections are decompressed.
+func (file *File) DWARF() (*dwarf.Data, error) {
+	// Contents of DWARF sections, indexed by section name without the
+	// ".debug_" prefix (e.g. "info").
+	sects := make(map[string][]byte)
+	// Contents of ".debug_types" sections, of which multiple may be present
+	// (e.g. one per COMDAT group).
+	var types [][]byte
+	for _, sectHdr := range file.SectHdrs {
+		var suffix string
+		switch {
+		case strings.HasPrefix(sectHdr.Name, ".debug_"):
+			suffix = sectHdr.Name[len(".debug_"):]
+		case strings.HasPrefix(sectHdr.Name, ".zdebug_"):
+			suffix = sectHdr.Name[len(".zdebug_"):]
+		default:
+			continue
+		}
+		data, err := file.dwarfSectionData(sectHdr)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if suffix == "types" {
+			types = append(types, data)
+			continue
+		}
+		sects[suffix] = data
+	}
+	d, err := dwarf.New(sects["abbrev"], sects["aranges"], sects["frame"], sects["info"], sects["line"], sects["pubnames"], sects["ranges"], sects["str"])
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i, data := range types {
+		if err := d.AddTypes(fmt.Sprintf("types-%d", i), data); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	// DWARF 5 sections.
+	for _, suffix := range []string{"addr", "line_str", "loclists", "rnglists", "str_offsets"} {
+		data, ok := sects[suffix]
+		if !ok {
+			continue
+		}
+		if err := d.AddSection(".debug_"+suffix, data); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return d, nil
+}
+
+// maxDeflateRatio is the maximum compression ratio of deflate compressed data.
+const maxDeflateRatio = 1032
+
+// dwarfSectionData returns the contents of the given DWARF section. Compressed
+// ".zdebug_*" sections start with a "ZLIB" signature followed by the
+// uncompressed size (8 bytes, big-endian) and zlib compressed data.
+func (file *File) dwarfSectionData(sectHdr SectionHeader) ([]byte, error) {
+	data, err := file.SectionData(sectHdr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// The raw data of sections in images is padded to the file alignment; trim
+	// to the virtual size if present.
+	if 0 < sectHdr.VirtualSize && int64(sectHdr.VirtualSize) < int64(len(data)) {
+		data = data[:sectHdr.VirtualSize]
+	}
+	if !strings.HasPrefix(sectHdr.Name, ".zdebug_") {
+		return data, nil
+	}
+	if len(data) < 12 || !bytes.HasPrefix(data, []byte("ZLIB")) {
+		return nil, errors.Errorf("invalid compressed DWARF section %q; missing ZLIB header", sectHdr.Name)
+	}
+	size := binary.BigEndian.Uint64(data[4:12])
+	// The compression ratio of deflate is at most 1032:1.
+	if size > uint64(len(data)-12)*maxDeflateRatio {
+		return nil, errors.Errorf("invalid uncompressed size %d of DWARF section %q", size, sectHdr.Name)
+	}
+	r, err := zlib.NewReader(bytes.NewReader(data[12:]))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to decompress DWARF section %q", sectHdr.Name)
+	}
+	defer r.Close()
+	// The buffer grows with the decompressed data, read up to one byte past
+	// the recorded size to detect oversized data.
+	buf, err := ioutil.ReadAll(io.LimitReader(r, int64(size)+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to decompress DWARF section %q", sectHdr.Name)
+	}
+	if uint64(len(buf)) != size {
+		return nil, errors.Errorf("size mismatch of decompressed DWARF section %q; expected %d bytes, got %d", sectHdr.Name, size, len(buf))
+	}
+	return buf, nil
+}
@@ -0,0 +1,84 @@
+package pe
+
+import (
+	"debug/dwarf"
+	"encoding/binary"
+	"testing"
+)
+
+func TestDWARF(t *testing.T) {
+	golden := []struct {
+		path string
+	}{
+		{path: "testdata/dwarf.o"},
+		// Same object file with compressed .zdebug_* sections.
+		{path: "testdata/dwarf_zlib.o"},
+	}
+	for _, g := range golden {
+		file, err := ParseFile(g.path)
+		if err != nil {
+			t.Errorf("%q: unable to parse file; %+v", g.path, err)
+			continue
+		}
+		d, err := file.DWARF()
+		if err != nil {
+			t.Errorf("%q: unable to parse DWARF debug information; %+v", g.path, err)
+			continue
+		}
+		var names []string
+		r := d.Reader()
+		for {
+			entry, err := r.Next()
+			if err != nil {
+				t.Errorf("%q: unable to read DWARF entry; %+v", g.path, err)
+				break
+			}
+			if entry == nil {
+				break
+			}
+			switch entry.Tag {
+			case dwarf.TagCompileUnit, dwarf.TagSubprogram:
+				name, _ := entry.Val(dwarf.AttrName).(string)
+				names = append(names, name)
+			}
+		}
+		if len(names) != 2 || names[0] != "t.c" || names[1] != "add" {
+			t.Errorf("%q: DWARF entries mismatch; expected [t.c add], got %v", g.path, names)
+		}
+	}
+}
+
+func TestDWARFCompressedSize(t *testing.T) {
+	golden := []struct {
+		name string
+		// Adjust uncompressed size of .zdebug_info, given the actual size.
+		size func(size uint64) uint64
+	}{
+		{name: "huge", size: func(uint64) uint64 { return 1 << 40 }},
+		{name: "max", size: func(uint64) uint64 { return 0xFFFFFFFFFFFFFFFF }},
+		{name: "truncated", size: func(size uint64) uint64 { return size - 1 }},
+		{name: "oversized", size: func(size uint64) uint64 { return size + 1 }},
+	}
+	for _, g := range golden {
+		file, err := ParseFile("testdata/dwarf_zlib.o")
+		if err != nil {
+			t.Fatalf("unable to parse file; %+v", err)
+		}
+		found := false
+		for _, sectHdr := range file.SectHdrs {
+			if sectHdr.Name != ".zdebug_info" {
+				continue
+			}
+			found = true
+			// Uncompressed size follows the "ZLIB" signature.
+			buf := file.Content[sectHdr.DataOffset+4:]
+			binary.BigEndian.PutUint64(buf, g.size(binary.BigEndian.Uint64(buf)))
+		}
+		if !found {
+			t.Fatal("unable to locate .zdebug_info section")
+		}
+		if _, err := file.DWARF(); err == nil {
+			t.Errorf("%s: expected error for invalid uncompressed size", g.name)
+		}
+	}
+}
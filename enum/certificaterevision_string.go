@@ -0,0 +1,29 @@
+// Code generated by "stringer -trimprefix CertificateRevision -type CertificateRevision"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[CertificateRevision1-256]
+	_ = x[CertificateRevision2-512]
+}
+
+const (
+	_CertificateRevision_name_0 = "1"
+	_CertificateRevision_name_1 = "2"
+)
+
+func (i CertificateRevision) String() string {
+	switch {
+	case i == 256:
+		return _CertificateRevision_name_0
+	case i == 512:
+		return _CertificateRevision_name_1
+	default:
+		return "CertificateRevision(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
@@ -0,0 +1,27 @@
+// Code generated by "stringer -trimprefix CertificateType -type CertificateType"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[CertificateTypeX509-1]
+	_ = x[CertificateTypePKCSSignedData-2]
+	_ = x[CertificateTypeReserved1-3]
+	_ = x[CertificateTypeTSStackSigned-4]
+}
+
+const _CertificateType_name = "X509PKCSSignedDataReserved1TSStackSigned"
+
+var _CertificateType_index = [...]uint8{0, 4, 18, 27, 40}
+
+func (i CertificateType) String() string {
+	idx := int(i) - 1
+	if i < 1 || idx >= len(_CertificateType_index)-1 {
+		return "CertificateType(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _CertificateType_name[_CertificateType_index[idx]:_CertificateType_index[idx+1]]
+}
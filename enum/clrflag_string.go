@@ -0,0 +1,51 @@
+// Code generated by "stringer -trimprefix CLRFlag -type CLRFlag"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[CLRFlagILOnly-1]
+	_ = x[CLRFlag32BitRequired-2]
+	_ = x[CLRFlagILLibrary-4]
+	_ = x[CLRFlagStrongNameSigned-8]
+	_ = x[CLRFlagNativeEntryPoint-16]
+	_ = x[CLRFlagTrackDebugData-65536]
+	_ = x[CLRFlag32BitPreferred-131072]
+}
+
+const (
+	_CLRFlag_name_0 = "ILOnly32BitRequired"
+	_CLRFlag_name_1 = "ILLibrary"
+	_CLRFlag_name_2 = "StrongNameSigned"
+	_CLRFlag_name_3 = "NativeEntryPoint"
+	_CLRFlag_name_4 = "TrackDebugData"
+	_CLRFlag_name_5 = "32BitPreferred"
+)
+
+var (
+	_CLRFlag_index_0 = [...]uint8{0, 6, 19}
+)
+
+func (i CLRFlag) String() string {
+	switch {
+	case 1 <= i && i <= 2:
+		i -= 1
+		return _CLRFlag_name_0[_CLRFlag_index_0[i]:_CLRFlag_index_0[i+1]]
+	case i == 4:
+		return _CLRFlag_name_1
+	case i == 8:
+		return _CLRFlag_name_2
+	case i == 16:
+		return _CLRFlag_name_3
+	case i == 65536:
+		return _CLRFlag_name_4
+	case i == 131072:
+		return _CLRFlag_name_5
+	default:
+		return "CLRFlag(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
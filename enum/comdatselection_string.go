@@ -0,0 +1,30 @@
+// Code generated by "stringer -trimprefix ComdatSelection -type ComdatSelection"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[ComdatSelectionNone-0]
+	_ = x[ComdatSelectionNoDuplicates-1]
+	_ = x[ComdatSelectionAny-2]
+	_ = x[ComdatSelectionSameSize-3]
+	_ = x[ComdatSelectionExactMatch-4]
+	_ = x[ComdatSelectionAssociative-5]
+	_ = x[ComdatSelectionLargest-6]
+}
+
+const _ComdatSelection_name = "NoneNoDuplicatesAnySameSizeExactMatchAssociativeLargest"
+
+var _ComdatSelection_index = [...]uint8{0, 4, 16, 19, 27, 37, 48, 55}
+
+func (i ComdatSelection) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_ComdatSelection_index)-1 {
+		return "ComdatSelection(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _ComdatSelection_name[_ComdatSelection_index[idx]:_ComdatSelection_index[idx+1]]
+}
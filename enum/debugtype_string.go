@@ -4,22 +4,42 @@ package enum
 
 import "strconv"
 
-const (
-	_DebugType_name_0 = "UnknownCOFFCodeViewFPOMiscExceptionFixupOMapToSrcOMapFromSrcBorlandReserved10CLSID"
-	_DebugType_name_1 = "Repro"
-)
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[DebugTypeUnknown-0]
+	_ = x[DebugTypeCOFF-1]
+	_ = x[DebugTypeCodeView-2]
+	_ = x[DebugTypeFPO-3]
+	_ = x[DebugTypeMisc-4]
+	_ = x[DebugTypeException-5]
+	_ = x[DebugTypeFixup-6]
+	_ = x[DebugTypeOMapToSrc-7]
+	_ = x[DebugTypeOMapFromSrc-8]
+	_ = x[DebugTypeBorland-9]
+	_ = x[DebugTypeReserved10-10]
+	_ = x[DebugTypeCLSID-11]
+	_ = x[DebugTypeVCFeature-12]
+	_ = x[DebugTypePOGO-13]
+	_ = x[DebugTypeILTCG-14]
+	_ = x[DebugTypeMPX-15]
+	_ = x[DebugTypeRepro-16]
+	_ = x[DebugTypeEmbeddedPortablePDB-17]
+	_ = x[DebugTypeSPGO-18]
+	_ = x[DebugTypePDBChecksum-19]
+	_ = x[DebugTypeExDLLCharacteristics-20]
+	_ = x[DebugTypeR2RPerfMap-21]
+}
+
+const _DebugType_name = "UnknownCOFFCodeViewFPOMiscExceptionFixupOMapToSrcOMapFromSrcBorlandReserved10CLSIDVCFeaturePOGOILTCGMPXReproEmbeddedPortablePDBSPGOPDBChecksumExDLLCharacteristicsR2RPerfMap"
 
-var (
-	_DebugType_index_0 = [...]uint8{0, 7, 11, 19, 22, 26, 35, 40, 49, 60, 67, 77, 82}
-)
+var _DebugType_index = [...]uint8{0, 7, 11, 19, 22, 26, 35, 40, 49, 60, 67, 77, 82, 91, 95, 100, 103, 108, 127, 131, 142, 162, 172}
 
 func (i DebugType) String() string {
-	switch {
-	case 0 <= i && i <= 11:
-		return _DebugType_name_0[_DebugType_index_0[i]:_DebugType_index_0[i+1]]
-	case i == 16:
-		return _DebugType_name_1
-	default:
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_DebugType_index)-1 {
 		return "DebugType(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
+	return _DebugType_name[_DebugType_index[idx]:_DebugType_index[idx+1]]
 }
@@ -1,7 +1,10 @@
 // Package enum defines enumerate types of the PE file format.
 package enum
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 //go:generate stringer -trimprefix MachineType -type MachineType
 
@@ -210,8 +213,328 @@ func SectionFlagString(flags SectionFlag) string {
 	return strings.Join(ss, " | ")
 }
 
+// --- [ COFF relocations ] ----------------------------------------------------
+
+// RelocType is a machine-specific COFF relocation type.
+//
+// RelocType is one of the following types.
+//
+//    RelocTypeAMD64
+//    RelocTypeI386
+//    RelocTypeARM
+//    RelocTypeARM64
+//    RelocTypeUnknown
+type RelocType interface {
+	fmt.Stringer
+	// isRelocType ensures that only COFF relocation types can be assigned to
+	// the RelocType interface.
+	isRelocType()
+}
+
+// isRelocType ensures that only COFF relocation types can be assigned to the
+// RelocType interface.
+func (RelocTypeAMD64) isRelocType()   {}
+func (RelocTypeI386) isRelocType()    {}
+func (RelocTypeARM) isRelocType()     {}
+func (RelocTypeARM64) isRelocType()   {}
+func (RelocTypeUnknown) isRelocType() {}
+
+// RelocTypeUnknown is a COFF relocation type of an unsupported machine type.
+type RelocTypeUnknown uint16
+
+// String returns the string representation of the relocation type.
+func (typ RelocTypeUnknown) String() string {
+	return fmt.Sprintf("RelocTypeUnknown(0x%04X)", uint16(typ))
+}
+
+//go:generate stringer -trimprefix RelocTypeAMD64 -type RelocTypeAMD64
+
+// RelocTypeAMD64 is a COFF relocation type of x64 processors.
+type RelocTypeAMD64 uint16
+
+// x64 relocation types.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#x64-processors
+const (
+	RelocTypeAMD64Absolute RelocTypeAMD64 = 0x0000 // The relocation is ignored.
+	RelocTypeAMD64Addr64   RelocTypeAMD64 = 0x0001 // The 64-bit VA of the relocation target.
+	RelocTypeAMD64Addr32   RelocTypeAMD64 = 0x0002 // The 32-bit VA of the relocation target.
+	RelocTypeAMD64Addr32NB RelocTypeAMD64 = 0x0003 // The 32-bit address without an image base (RVA).
+	RelocTypeAMD64Rel32    RelocTypeAMD64 = 0x0004 // The 32-bit relative address from the byte following the relocation.
+	RelocTypeAMD64Rel32_1  RelocTypeAMD64 = 0x0005 // The 32-bit address relative to byte distance 1 from the relocation.
+	RelocTypeAMD64Rel32_2  RelocTypeAMD64 = 0x0006 // The 32-bit address relative to byte distance 2 from the relocation.
+	RelocTypeAMD64Rel32_3  RelocTypeAMD64 = 0x0007 // The 32-bit address relative to byte distance 3 from the relocation.
+	RelocTypeAMD64Rel32_4  RelocTypeAMD64 = 0x0008 // The 32-bit address relative to byte distance 4 from the relocation.
+	RelocTypeAMD64Rel32_5  RelocTypeAMD64 = 0x0009 // The 32-bit address relative to byte distance 5 from the relocation.
+	RelocTypeAMD64Section  RelocTypeAMD64 = 0x000A // The 16-bit section index of the section that contains the target. This is used to support debugging information.
+	RelocTypeAMD64SecRel   RelocTypeAMD64 = 0x000B // The 32-bit offset of the target from the beginning of its section. This is used to support debugging information and static thread local storage.
+	RelocTypeAMD64SecRel7  RelocTypeAMD64 = 0x000C // A 7-bit unsigned offset from the base of the section that contains the target.
+	RelocTypeAMD64Token    RelocTypeAMD64 = 0x000D // CLR tokens.
+	RelocTypeAMD64SRel32   RelocTypeAMD64 = 0x000E // A 32-bit signed span-dependent value emitted into the object.
+	RelocTypeAMD64Pair     RelocTypeAMD64 = 0x000F // A pair that must immediately follow every span-dependent value.
+	RelocTypeAMD64SSpan32  RelocTypeAMD64 = 0x0010 // A 32-bit signed span-dependent value that is applied at link time.
+)
+
+//go:generate stringer -trimprefix RelocTypeI386 -type RelocTypeI386
+
+// RelocTypeI386 is a COFF relocation type of Intel 386 processors.
+type RelocTypeI386 uint16
+
+// Intel 386 relocation types.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#intel-386-processors
+const (
+	RelocTypeI386Absolute RelocTypeI386 = 0x0000 // The relocation is ignored.
+	RelocTypeI386Dir16    RelocTypeI386 = 0x0001 // Not supported.
+	RelocTypeI386Rel16    RelocTypeI386 = 0x0002 // Not supported.
+	RelocTypeI386Dir32    RelocTypeI386 = 0x0006 // The target's 32-bit VA.
+	RelocTypeI386Dir32NB  RelocTypeI386 = 0x0007 // The target's 32-bit RVA.
+	RelocTypeI386Seg12    RelocTypeI386 = 0x0009 // Not supported.
+	RelocTypeI386Section  RelocTypeI386 = 0x000A // The 16-bit section index of the section that contains the target. This is used to support debugging information.
+	RelocTypeI386SecRel   RelocTypeI386 = 0x000B // The 32-bit offset of the target from the beginning of its section. This is used to support debugging information and static thread local storage.
+	RelocTypeI386Token    RelocTypeI386 = 0x000C // The CLR token.
+	RelocTypeI386SecRel7  RelocTypeI386 = 0x000D // A 7-bit offset from the base of the section that contains the target.
+	RelocTypeI386Rel32    RelocTypeI386 = 0x0014 // The 32-bit relative displacement to the target.
+)
+
+//go:generate stringer -trimprefix RelocTypeARM -type RelocTypeARM
+
+// RelocTypeARM is a COFF relocation type of ARM processors.
+type RelocTypeARM uint16
+
+// ARM relocation types.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#arm-processors
+const (
+	RelocTypeARMAbsolute      RelocTypeARM = 0x0000 // The relocation is ignored.
+	RelocTypeARMAddr32        RelocTypeARM = 0x0001 // The 32-bit VA of the target.
+	RelocTypeARMAddr32NB      RelocTypeARM = 0x0002 // The 32-bit RVA of the target.
+	RelocTypeARMBranch24      RelocTypeARM = 0x0003 // The 24-bit relative displacement to the target.
+	RelocTypeARMBranch11      RelocTypeARM = 0x0004 // The reference to a subroutine call. The reference consists of two 16-bit instructions with 11-bit offsets.
+	RelocTypeARMToken         RelocTypeARM = 0x0005 // The CLR token.
+	RelocTypeARMBLX24         RelocTypeARM = 0x0008 // The 24-bit relative displacement to the target, for a BLX instruction.
+	RelocTypeARMBLX11         RelocTypeARM = 0x0009 // The reference to a subroutine call, for a BLX instruction. The reference consists of two 16-bit instructions with 11-bit offsets.
+	RelocTypeARMRel32         RelocTypeARM = 0x000A // The 32-bit relative address from the byte following the relocation.
+	RelocTypeARMSection       RelocTypeARM = 0x000E // The 16-bit section index of the section that contains the target. This is used to support debugging information.
+	RelocTypeARMSecRel        RelocTypeARM = 0x000F // The 32-bit offset of the target from the beginning of its section. This is used to support debugging information and static thread local storage.
+	RelocTypeARMMov32         RelocTypeARM = 0x0010 // The 32-bit VA of the target. This relocation is applied using a MOVW instruction for the low 16 bits followed by a MOVT for the high 16 bits.
+	RelocTypeARMThumbMov32    RelocTypeARM = 0x0011 // The 32-bit VA of the target. This relocation is applied using a MOVW instruction for the low 16 bits followed by a MOVT for the high 16 bits.
+	RelocTypeARMThumbBranch20 RelocTypeARM = 0x0012 // The instruction is fixed up with the 21-bit relative displacement to the 2-byte aligned target.
+	RelocTypeARMUnused        RelocTypeARM = 0x0013 // Unused.
+	RelocTypeARMThumbBranch24 RelocTypeARM = 0x0014 // The instruction is fixed up with the 25-bit relative displacement to the 2-byte aligned target.
+	RelocTypeARMThumbBLX23    RelocTypeARM = 0x0015 // The instruction is fixed up with the 25-bit relative displacement to the 4-byte aligned target.
+	RelocTypeARMPair          RelocTypeARM = 0x0016 // The relocation is valid only when it immediately follows a ARM_REFHI or THUMB_REFHI.
+)
+
+//go:generate stringer -trimprefix RelocTypeARM64 -type RelocTypeARM64
+
+// RelocTypeARM64 is a COFF relocation type of ARM64 processors.
+type RelocTypeARM64 uint16
+
+// ARM64 relocation types.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#arm64-processors
+const (
+	RelocTypeARM64Absolute      RelocTypeARM64 = 0x0000 // The relocation is ignored.
+	RelocTypeARM64Addr32        RelocTypeARM64 = 0x0001 // The 32-bit VA of the target.
+	RelocTypeARM64Addr32NB      RelocTypeARM64 = 0x0002 // The 32-bit RVA of the target.
+	RelocTypeARM64Branch26      RelocTypeARM64 = 0x0003 // The 26-bit relative displacement to the target, for B and BL instructions.
+	RelocTypeARM64PageBaseRel21 RelocTypeARM64 = 0x0004 // The page base of the target, for ADRP instruction.
+	RelocTypeARM64Rel21         RelocTypeARM64 = 0x0005 // The 12-bit relative displacement to the target, for instruction ADR.
+	RelocTypeARM64PageOffset12A RelocTypeARM64 = 0x0006 // The 12-bit page offset of the target, for instructions ADD/ADDS (immediate) with zero shift.
+	RelocTypeARM64PageOffset12L RelocTypeARM64 = 0x0007 // The 12-bit page offset of the target, for instruction LDR (indexed, unsigned immediate).
+	RelocTypeARM64SecRel        RelocTypeARM64 = 0x0008 // The 32-bit offset of the target from the beginning of its section. This is used to support debugging information and static thread local storage.
+	RelocTypeARM64SecRelLow12A  RelocTypeARM64 = 0x0009 // Bit 0:11 of section offset of the target, for instructions ADD/ADDS (immediate) with zero shift.
+	RelocTypeARM64SecRelHigh12A RelocTypeARM64 = 0x000A // Bit 12:23 of section offset of the target, for instructions ADD/ADDS (immediate) with zero shift.
+	RelocTypeARM64SecRelLow12L  RelocTypeARM64 = 0x000B // Bit 0:11 of section offset of the target, for instruction LDR (indexed, unsigned immediate).
+	RelocTypeARM64Token         RelocTypeARM64 = 0x000C // CLR token.
+	RelocTypeARM64Section       RelocTypeARM64 = 0x000D // The 16-bit section index of the section that contains the target. This is used to support debugging information.
+	RelocTypeARM64Addr64        RelocTypeARM64 = 0x000E // The 64-bit VA of the relocation target.
+	RelocTypeARM64Branch19      RelocTypeARM64 = 0x000F // The 19-bit offset to the relocation target, for conditional B instruction.
+	RelocTypeARM64Branch14      RelocTypeARM64 = 0x0010 // The 14-bit offset to the relocation target, for instructions TBZ and TBNZ.
+	RelocTypeARM64Rel32         RelocTypeARM64 = 0x0011 // The 32-bit relative address from the byte following the relocation.
+)
+
+// --- [ COFF symbol table ] ---------------------------------------------------
+
+//go:generate stringer -trimprefix StorageClass -type StorageClass
+
+// StorageClass specifies the storage class of a COFF symbol.
+type StorageClass uint8
+
+// Storage classes.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#storage-class
+const (
+	StorageClassEndOfFunction   StorageClass = 0xFF // A special symbol that represents the end of function, for debugging purposes.
+	StorageClassNull            StorageClass = 0    // No assigned storage class.
+	StorageClassAutomatic       StorageClass = 1    // The automatic (stack) variable. The Value field specifies the stack frame offset.
+	StorageClassExternal        StorageClass = 2    // A value that Microsoft tools use for external symbols.
+	StorageClassStatic          StorageClass = 3    // The offset of the symbol within the section. If the Value field is zero, then the symbol represents a section name.
+	StorageClassRegister        StorageClass = 4    // A register variable. The Value field specifies the register number.
+	StorageClassExternalDef     StorageClass = 5    // A symbol that is defined externally.
+	StorageClassLabel           StorageClass = 6    // A code label that is defined within the module.
+	StorageClassUndefinedLabel  StorageClass = 7    // A reference to a code label that is not defined.
+	StorageClassMemberOfStruct  StorageClass = 8    // The structure member. The Value field specifies the n th member.
+	StorageClassArgument        StorageClass = 9    // A formal argument (parameter) of a function.
+	StorageClassStructTag       StorageClass = 10   // The structure tag-name entry.
+	StorageClassMemberOfUnion   StorageClass = 11   // A union member.
+	StorageClassUnionTag        StorageClass = 12   // The Union tag-name entry.
+	StorageClassTypeDefinition  StorageClass = 13   // A Typedef entry.
+	StorageClassUndefinedStatic StorageClass = 14   // A static data declaration.
+	StorageClassEnumTag         StorageClass = 15   // An enumerated type tagname entry.
+	StorageClassMemberOfEnum    StorageClass = 16   // A member of an enumeration.
+	StorageClassRegisterParam   StorageClass = 17   // A register parameter.
+	StorageClassBitField        StorageClass = 18   // A bit-field reference.
+	StorageClassBlock           StorageClass = 100  // A .bb (beginning of block) or .eb (end of block) record.
+	StorageClassFunction        StorageClass = 101  // A .bf (beginning of function), .ef (end of function) or .lf (lines in function) record.
+	StorageClassEndOfStruct     StorageClass = 102  // An end-of-structure entry.
+	StorageClassFile            StorageClass = 103  // The source-file symbol record, followed by auxiliary records that name the file.
+	StorageClassSection         StorageClass = 104  // A definition of a section (Microsoft tools use STATIC storage class instead).
+	StorageClassWeakExternal    StorageClass = 105  // A weak external.
+	StorageClassCLRToken        StorageClass = 107  // A CLR token symbol.
+)
+
+//go:generate stringer -trimprefix SymbolBaseType -type SymbolBaseType
+
+// SymbolBaseType specifies the base type of a COFF symbol.
+type SymbolBaseType uint8
+
+// Symbol base types.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#type-representation
+const (
+	SymbolBaseTypeNull   SymbolBaseType = 0  // No type information or unknown base type.
+	SymbolBaseTypeVoid   SymbolBaseType = 1  // No valid type; used with void pointers and functions.
+	SymbolBaseTypeChar   SymbolBaseType = 2  // A character (signed byte).
+	SymbolBaseTypeShort  SymbolBaseType = 3  // A 2-byte signed integer.
+	SymbolBaseTypeInt    SymbolBaseType = 4  // A natural integer type (normally 4 bytes in Windows).
+	SymbolBaseTypeLong   SymbolBaseType = 5  // A 4-byte signed integer.
+	SymbolBaseTypeFloat  SymbolBaseType = 6  // A 4-byte floating-point number.
+	SymbolBaseTypeDouble SymbolBaseType = 7  // An 8-byte floating-point number.
+	SymbolBaseTypeStruct SymbolBaseType = 8  // A structure.
+	SymbolBaseTypeUnion  SymbolBaseType = 9  // A union.
+	SymbolBaseTypeEnum   SymbolBaseType = 10 // An enumerated type.
+	SymbolBaseTypeMOE    SymbolBaseType = 11 // A member of enumeration (a specific value).
+	SymbolBaseTypeByte   SymbolBaseType = 12 // A byte; unsigned 1-byte integer.
+	SymbolBaseTypeWord   SymbolBaseType = 13 // A word; unsigned 2-byte integer.
+	SymbolBaseTypeUint   SymbolBaseType = 14 // An unsigned integer of natural size (normally, 4 bytes).
+	SymbolBaseTypeDword  SymbolBaseType = 15 // An unsigned 4-byte integer.
+)
+
+//go:generate stringer -trimprefix SymbolComplexType -type SymbolComplexType
+
+// SymbolComplexType specifies the complex type of a COFF symbol.
+type SymbolComplexType uint8
+
+// Symbol complex types.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#type-representation
+const (
+	SymbolComplexTypeNull     SymbolComplexType = 0 // No derived type; the symbol is a simple scalar variable.
+	SymbolComplexTypePointer  SymbolComplexType = 1 // The symbol is a pointer to base type.
+	SymbolComplexTypeFunction SymbolComplexType = 2 // The symbol is a function that returns a base type.
+	SymbolComplexTypeArray    SymbolComplexType = 3 // The symbol is an array of base type.
+)
+
+//go:generate stringer -trimprefix WeakExternalFlag -type WeakExternalFlag
+
+// WeakExternalFlag specifies the library search behaviour of a weak external.
+type WeakExternalFlag uint32
+
+// Weak external characteristics.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#auxiliary-format-3-weak-externals
+const (
+	WeakExternalFlagSearchNoLibrary WeakExternalFlag = 1 // No library search for the symbol should be performed.
+	WeakExternalFlagSearchLibrary   WeakExternalFlag = 2 // A library search for the symbol should be performed.
+	WeakExternalFlagSearchAlias     WeakExternalFlag = 3 // The symbol is an alias for the tag symbol.
+	WeakExternalFlagAntiDependency  WeakExternalFlag = 4 // The symbol is an anti-dependency alias.
+)
+
+//go:generate stringer -trimprefix ComdatSelection -type ComdatSelection
+
+// ComdatSelection specifies how the linker resolves duplicate COMDAT sections.
+type ComdatSelection uint8
+
+// COMDAT selection numbers.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#comdat-sections-object-only
+const (
+	ComdatSelectionNone         ComdatSelection = 0 // Not a COMDAT section.
+	ComdatSelectionNoDuplicates ComdatSelection = 1 // Multiple definitions of the symbol are an error.
+	ComdatSelectionAny          ComdatSelection = 2 // Any section that defines the same COMDAT symbol can be linked.
+	ComdatSelectionSameSize     ComdatSelection = 3 // The linker chooses an arbitrary section among the definitions; duplicates must have the same size.
+	ComdatSelectionExactMatch   ComdatSelection = 4 // The linker chooses an arbitrary section among the definitions; duplicates must match exactly.
+	ComdatSelectionAssociative  ComdatSelection = 5 // The section is linked if a certain other COMDAT section is linked.
+	ComdatSelectionLargest      ComdatSelection = 6 // The linker chooses the largest definition from among all of the definitions.
+)
+
+// --- [ Import object header ] ------------------------------------------------
+
+//go:generate stringer -trimprefix ImportType -type ImportType
+
+// ImportType specifies the type of an import of a short import library member.
+type ImportType uint8
+
+// Import types.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#import-type
+const (
+	ImportTypeCode  ImportType = 0 // Executable code.
+	ImportTypeData  ImportType = 1 // Data.
+	ImportTypeConst ImportType = 2 // Specified as CONST in the .def file.
+)
+
+//go:generate stringer -trimprefix ImportNameType -type ImportNameType
+
+// ImportNameType specifies how the import name of a short import library member
+// is derived from its symbol name.
+type ImportNameType uint8
+
+// Import name types.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#import-name-type
+const (
+	ImportNameTypeOrdinal        ImportNameType = 0 // The import is by ordinal.
+	ImportNameTypeName           ImportNameType = 1 // The import name is identical to the public symbol name.
+	ImportNameTypeNameNoPrefix   ImportNameType = 2 // The import name is the public symbol name, skipping the leading ?, @, or optionally _.
+	ImportNameTypeNameUndecorate ImportNameType = 3 // The import name is the public symbol name, skipping the leading ?, @, or optionally _, and truncating at the first @.
+	ImportNameTypeNameExportAs   ImportNameType = 4 // The import name is specified explicitly after the DLL name.
+)
+
 // --- [ Data directories ] ----------------------------------------------------
 
+// ~~~ [ Certificate Table ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+//go:generate stringer -trimprefix CertificateRevision -type CertificateRevision
+
+// CertificateRevision specifies the revision of an attribute certificate.
+type CertificateRevision uint16
+
+// Attribute certificate revisions.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#the-attribute-certificate-table-image-only
+const (
+	CertificateRevision1 CertificateRevision = 0x0100 // Legacy version of WIN_CERTIFICATE.
+	CertificateRevision2 CertificateRevision = 0x0200 // Current version of WIN_CERTIFICATE.
+)
+
+//go:generate stringer -trimprefix CertificateType -type CertificateType
+
+// CertificateType specifies the type of an attribute certificate.
+type CertificateType uint16
+
+// Attribute certificate types.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#the-attribute-certificate-table-image-only
+const (
+	CertificateTypeX509           CertificateType = 0x0001 // X.509 certificate.
+	CertificateTypePKCSSignedData CertificateType = 0x0002 // PKCS#7 SignedData structure (Authenticode).
+	CertificateTypeReserved1      CertificateType = 0x0003 // Reserved.
+	CertificateTypeTSStackSigned  CertificateType = 0x0004 // Terminal Server Protocol Stack certificate signing.
+)
+
 // ~~~ [ Base Relocation Table ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 //go:generate stringer -trimprefix BaseRelocType -type BaseRelocType
@@ -251,19 +574,76 @@ type DebugType uint32
 //
 // ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#debug-type
 const (
-	DebugTypeUnknown     DebugType = 0  // An unknown value that is ignored by all tools.
-	DebugTypeCOFF        DebugType = 1  // The COFF debug information (line numbers, symbol table, and string table). This type of debug information is also pointed to by fields in the file headers.
-	DebugTypeCodeView    DebugType = 2  // The Visual C++ debug information. The format of the data block is described by the CodeView 4.0 specification.
-	DebugTypeFPO         DebugType = 3  // The frame pointer omission (FPO) information. This information tells the debugger how to interpret nonstandard stack frames, which use the EBP register for a purpose other than as a frame pointer.
-	DebugTypeMisc        DebugType = 4  // The location of DBG file.
-	DebugTypeException   DebugType = 5  // Exception information. A copy of .pdata section.
-	DebugTypeFixup       DebugType = 6  // Reserved. Fixup information.
-	DebugTypeOMapToSrc   DebugType = 7  // The mapping from an RVA in image to an RVA in source image.
-	DebugTypeOMapFromSrc DebugType = 8  // The mapping from an RVA in source image to an RVA in image.
-	DebugTypeBorland     DebugType = 9  // Reserved for Borland.
-	DebugTypeReserved10  DebugType = 10 // Reserved.
-	DebugTypeCLSID       DebugType = 11 // Reserved.
-	DebugTypeRepro       DebugType = 16 // PE determinism or reproducibility.
+	DebugTypeUnknown              DebugType = 0  // An unknown value that is ignored by all tools.
+	DebugTypeCOFF                 DebugType = 1  // The COFF debug information (line numbers, symbol table, and string table). This type of debug information is also pointed to by fields in the file headers.
+	DebugTypeCodeView             DebugType = 2  // The Visual C++ debug information. The format of the data block is described by the CodeView 4.0 specification.
+	DebugTypeFPO                  DebugType = 3  // The frame pointer omission (FPO) information. This information tells the debugger how to interpret nonstandard stack frames, which use the EBP register for a purpose other than as a frame pointer.
+	DebugTypeMisc                 DebugType = 4  // The location of DBG file.
+	DebugTypeException            DebugType = 5  // Exception information. A copy of .pdata section.
+	DebugTypeFixup                DebugType = 6  // Reserved. Fixup information.
+	DebugTypeOMapToSrc            DebugType = 7  // The mapping from an RVA in image to an RVA in source image.
+	DebugTypeOMapFromSrc          DebugType = 8  // The mapping from an RVA in source image to an RVA in image.
+	DebugTypeBorland              DebugType = 9  // Reserved for Borland.
+	DebugTypeReserved10           DebugType = 10 // Reserved.
+	DebugTypeCLSID                DebugType = 11 // Reserved.
+	DebugTypeVCFeature            DebugType = 12 // Visual C++ feature counters.
+	DebugTypePOGO                 DebugType = 13 // Profile guided optimization (POGO) section contributions.
+	DebugTypeILTCG                DebugType = 14 // Image was built with link-time code generation (LTCG) of compiler intermediate language.
+	DebugTypeMPX                  DebugType = 15 // Intel Memory Protection Extensions (MPX).
+	DebugTypeRepro                DebugType = 16 // PE determinism or reproducibility.
+	DebugTypeEmbeddedPortablePDB  DebugType = 17 // Embedded portable PDB.
+	DebugTypeSPGO                 DebugType = 18 // Sample profile guided optimization.
+	DebugTypePDBChecksum          DebugType = 19 // Cryptographic hash of the PDB.
+	DebugTypeExDLLCharacteristics DebugType = 20 // Extended DLL characteristics.
+	DebugTypeR2RPerfMap           DebugType = 21 // ReadyToRun performance map (perfmap) signature.
+)
+
+//go:generate stringer -trimprefix ExDLLCharacteristic -type ExDLLCharacteristic
+
+// ExDLLCharacteristic is a bitfield of extended DLL characteristics.
+type ExDLLCharacteristic uint32
+
+// Extended DLL characteristics.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#extended-dll-characteristics
+const (
+	ExDLLCharacteristicCETCompat                            ExDLLCharacteristic = 0x0001 // Image is Control-flow Enforcement Technology (CET) shadow stack compatible.
+	ExDLLCharacteristicCETCompatStrictMode                  ExDLLCharacteristic = 0x0002 // CET shadow stack is enforced in strict mode.
+	ExDLLCharacteristicCETSetContextIPValidationRelaxedMode ExDLLCharacteristic = 0x0004 // Relaxed mode for context IP validation under CET is allowed.
+	ExDLLCharacteristicCETDynamicAPIsAllowInProc            ExDLLCharacteristic = 0x0008 // Use of dynamic APIs is restricted to processes that are not in-process.
+	ExDLLCharacteristicCETReserved1                         ExDLLCharacteristic = 0x0010 // Reserved for future use.
+	ExDLLCharacteristicCETReserved2                         ExDLLCharacteristic = 0x0020 // Reserved for future use.
+	ExDLLCharacteristicForwardCFICompat                     ExDLLCharacteristic = 0x0040 // Image is forward control-flow integrity (CFI) compatible.
+	ExDLLCharacteristicHotPatchCompatible                   ExDLLCharacteristic = 0x0080 // Image is hot patch compatible.
+)
+
+// ExDLLCharacteristicString returns the string representation of the extended
+// DLL characteristics.
+func ExDLLCharacteristicString(c ExDLLCharacteristic) string {
+	var ss []string
+	for mask := uint64(1); mask <= 0xFFFFFFFF; mask <<= 1 {
+		m := ExDLLCharacteristic(mask)
+		if c&m != 0 {
+			s := m.String()
+			ss = append(ss, s)
+		}
+	}
+	return strings.Join(ss, " | ")
+}
+
+//go:generate stringer -trimprefix POGOType -type POGOType
+
+// POGOType specifies the signature of profile guided optimization (POGO) debug
+// data.
+type POGOType uint32
+
+// POGO signatures.
+const (
+	POGOTypeZero POGOType = 0x00000000 // No profile data.
+	POGOTypeLTCG POGOType = 0x4C544347 // Link-time code generation ("LTCG").
+	POGOTypePGI  POGOType = 0x50474900 // Profile guided instrumentation ("PGI").
+	POGOTypePGO  POGOType = 0x50474F00 // Profile guided optimization ("PGO").
+	POGOTypePGU  POGOType = 0x50475500 // Profile guided update ("PGU").
 )
 
 //go:generate stringer -trimprefix FrameType -type FrameType
@@ -284,3 +664,270 @@ const (
 	// TSS frame
 	FrameTypeTSS FrameType = 2
 )
+
+// ~~~ [ CLR Header ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+//go:generate stringer -trimprefix CLRFlag -type CLRFlag
+
+// CLRFlag is a bitfield of CLR runtime flags.
+type CLRFlag uint32
+
+// CLR runtime flags.
+//
+// ref: ECMA-335, II.25.3.3.1 Runtime flags
+const (
+	CLRFlagILOnly           CLRFlag = 0x00000001 // Image contains only IL code.
+	CLRFlag32BitRequired    CLRFlag = 0x00000002 // Image may only be loaded into a 32-bit process.
+	CLRFlagILLibrary        CLRFlag = 0x00000004 // Image is a native image (NGen).
+	CLRFlagStrongNameSigned CLRFlag = 0x00000008 // Image has a strong name signature.
+	CLRFlagNativeEntryPoint CLRFlag = 0x00000010 // Entry point is a relative address of native code, not a metadata token.
+	CLRFlagTrackDebugData   CLRFlag = 0x00010000 // Runtime should track debug data.
+	CLRFlag32BitPreferred   CLRFlag = 0x00020000 // Image should be loaded into a 32-bit process if possible.
+)
+
+// CLRFlagString returns the string representation of the CLR runtime flags.
+func CLRFlagString(flags CLRFlag) string {
+	var ss []string
+	for mask := uint64(1); mask < 0xFFFFFFFF; mask <<= 1 {
+		m := CLRFlag(mask)
+		if flags&m != 0 {
+			s := m.String()
+			ss = append(ss, s)
+		}
+	}
+	return strings.Join(ss, " | ")
+}
+
+//go:generate stringer -trimprefix StrongNameStatus -type StrongNameStatus
+
+// StrongNameStatus specifies the result of strong name signature verification.
+type StrongNameStatus uint8
+
+// Strong name signature verification results.
+const (
+	// Assembly is not strong-named.
+	StrongNameStatusNotSigned StrongNameStatus = iota
+	// Space is reserved for the strong name signature, but the assembly has not
+	// yet been signed.
+	StrongNameStatusDelaySigned
+	// Signature verified using a test public key rather than the public key of
+	// the assembly.
+	StrongNameStatusTestSigned
+	// Signature verified using the public key of the assembly.
+	StrongNameStatusValid
+	// Signature does not match the contents of the assembly.
+	StrongNameStatusInvalid
+)
+
+// ~~~ [ ReadyToRun ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+//go:generate stringer -trimprefix R2RFlag -type R2RFlag
+
+// R2RFlag is a bitfield of ReadyToRun image flags.
+type R2RFlag uint32
+
+// ReadyToRun image flags.
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/inc/readytorun.h
+const (
+	R2RFlagPlatformNeutralSource    R2RFlag = 0x00000001 // Set if the original IL image was platform neutral.
+	R2RFlagSkipTypeValidation       R2RFlag = 0x00000002 // Set if the runtime may skip type layout validation.
+	R2RFlagPartial                  R2RFlag = 0x00000004 // Set if only a subset of methods was compiled.
+	R2RFlagNonSharedPInvokeStubs    R2RFlag = 0x00000008 // PInvoke stubs compiled into image are non-shareable.
+	R2RFlagEmbeddedMSIL             R2RFlag = 0x00000010 // Input MSIL is embedded in the R2R image.
+	R2RFlagComponent                R2RFlag = 0x00000020 // Component assembly of a composite image.
+	R2RFlagMultiModuleVersionBubble R2RFlag = 0x00000040 // Image belongs to a version bubble of more than one module.
+	R2RFlagUnrelatedR2RCode         R2RFlag = 0x00000080 // Image contains code for methods not belonging to its version bubble.
+)
+
+// R2RFlagString returns the string representation of the ReadyToRun image
+// flags.
+func R2RFlagString(flags R2RFlag) string {
+	var ss []string
+	for mask := uint64(1); mask < 0xFFFFFFFF; mask <<= 1 {
+		m := R2RFlag(mask)
+		if flags&m != 0 {
+			s := m.String()
+			ss = append(ss, s)
+		}
+	}
+	return strings.Join(ss, " | ")
+}
+
+//go:generate stringer -trimprefix R2RSectionType -type R2RSectionType
+
+// R2RSectionType specifies the type of a ReadyToRun section.
+type R2RSectionType uint32
+
+// ReadyToRun section types.
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/inc/readytorun.h
+const (
+	R2RSectionTypeCompilerIdentifier        R2RSectionType = 100
+	R2RSectionTypeImportSections            R2RSectionType = 101
+	R2RSectionTypeRuntimeFunctions          R2RSectionType = 102
+	R2RSectionTypeMethodDefEntryPoints      R2RSectionType = 103
+	R2RSectionTypeExceptionInfo             R2RSectionType = 104
+	R2RSectionTypeDebugInfo                 R2RSectionType = 105
+	R2RSectionTypeDelayLoadMethodCallThunks R2RSectionType = 106
+	R2RSectionTypeAvailableTypesOld         R2RSectionType = 107 // obsolete
+	R2RSectionTypeAvailableTypes            R2RSectionType = 108
+	R2RSectionTypeInstanceMethodEntryPoints R2RSectionType = 109
+	R2RSectionTypeInliningInfo              R2RSectionType = 110 // added in v2.1, deprecated in v4.1
+	R2RSectionTypeProfileDataInfo           R2RSectionType = 111 // added in v2.2
+	R2RSectionTypeManifestMetadata          R2RSectionType = 112 // added in v2.3
+	R2RSectionTypeAttributePresence         R2RSectionType = 113 // added in v3.1
+	R2RSectionTypeInliningInfo2             R2RSectionType = 114 // added in v4.1
+	R2RSectionTypeComponentAssemblies       R2RSectionType = 115 // added in v4.1
+	R2RSectionTypeOwnerCompositeExecutable  R2RSectionType = 116 // added in v4.1
+	R2RSectionTypePgoInstrumentationData    R2RSectionType = 117 // added in v5.2
+	R2RSectionTypeManifestAssemblyMvids     R2RSectionType = 118 // added in v5.3
+	R2RSectionTypeCrossModuleInlineInfo     R2RSectionType = 119 // added in v6.2
+	R2RSectionTypeHotColdMap                R2RSectionType = 120 // added in v8.0
+	R2RSectionTypeMethodIsGenericMap        R2RSectionType = 121 // added in v9.0
+	R2RSectionTypeEnclosingTypeMap          R2RSectionType = 122 // added in v9.0
+	R2RSectionTypeTypeGenericInfoMap        R2RSectionType = 123 // added in v9.0
+)
+
+//go:generate stringer -trimprefix R2RImportSectionFlag -type R2RImportSectionFlag
+
+// R2RImportSectionFlag is a bitfield of ReadyToRun import section flags.
+type R2RImportSectionFlag uint16
+
+// ReadyToRun import section flags.
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/inc/readytorun.h
+const (
+	R2RImportSectionFlagEager R2RImportSectionFlag = 0x0001 // Section is resolved eagerly at module load time.
+	R2RImportSectionFlagPCode R2RImportSectionFlag = 0x0004 // Section contains pointers to code.
+)
+
+//go:generate stringer -trimprefix R2RImportSectionType -type R2RImportSectionType
+
+// R2RImportSectionType specifies the type of a ReadyToRun import section.
+type R2RImportSectionType uint8
+
+// ReadyToRun import section types.
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/inc/readytorun.h
+const (
+	R2RImportSectionTypeUnknown      R2RImportSectionType = 0
+	R2RImportSectionTypeStubDispatch R2RImportSectionType = 2
+	R2RImportSectionTypeStringHandle R2RImportSectionType = 3
+	R2RImportSectionTypeILBodyFixups R2RImportSectionType = 7
+)
+
+//go:generate stringer -trimprefix R2RFixupKind -type R2RFixupKind
+
+// R2RFixupKind specifies the kind of a ReadyToRun import fixup.
+type R2RFixupKind uint8
+
+// ReadyToRun fixup kinds.
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/inc/readytorun.h
+const (
+	R2RFixupKindThisObjDictionaryLookup       R2RFixupKind = 0x07
+	R2RFixupKindTypeDictionaryLookup          R2RFixupKind = 0x08
+	R2RFixupKindMethodDictionaryLookup        R2RFixupKind = 0x09
+	R2RFixupKindTypeHandle                    R2RFixupKind = 0x10
+	R2RFixupKindMethodHandle                  R2RFixupKind = 0x11
+	R2RFixupKindFieldHandle                   R2RFixupKind = 0x12
+	R2RFixupKindMethodEntry                   R2RFixupKind = 0x13
+	R2RFixupKindMethodEntryDefToken           R2RFixupKind = 0x14
+	R2RFixupKindMethodEntryRefToken           R2RFixupKind = 0x15
+	R2RFixupKindVirtualEntry                  R2RFixupKind = 0x16
+	R2RFixupKindVirtualEntryDefToken          R2RFixupKind = 0x17
+	R2RFixupKindVirtualEntryRefToken          R2RFixupKind = 0x18
+	R2RFixupKindVirtualEntrySlot              R2RFixupKind = 0x19
+	R2RFixupKindHelper                        R2RFixupKind = 0x1A
+	R2RFixupKindStringHandle                  R2RFixupKind = 0x1B
+	R2RFixupKindNewObject                     R2RFixupKind = 0x1C
+	R2RFixupKindNewArray                      R2RFixupKind = 0x1D
+	R2RFixupKindIsInstanceOf                  R2RFixupKind = 0x1E
+	R2RFixupKindChkCast                       R2RFixupKind = 0x1F
+	R2RFixupKindFieldAddress                  R2RFixupKind = 0x20
+	R2RFixupKindCctorTrigger                  R2RFixupKind = 0x21
+	R2RFixupKindStaticBaseNonGC               R2RFixupKind = 0x22
+	R2RFixupKindStaticBaseGC                  R2RFixupKind = 0x23
+	R2RFixupKindThreadStaticBaseNonGC         R2RFixupKind = 0x24
+	R2RFixupKindThreadStaticBaseGC            R2RFixupKind = 0x25
+	R2RFixupKindFieldBaseOffset               R2RFixupKind = 0x26
+	R2RFixupKindFieldOffset                   R2RFixupKind = 0x27
+	R2RFixupKindTypeDictionary                R2RFixupKind = 0x28
+	R2RFixupKindMethodDictionary              R2RFixupKind = 0x29
+	R2RFixupKindCheckTypeLayout               R2RFixupKind = 0x2A
+	R2RFixupKindCheckFieldOffset              R2RFixupKind = 0x2B
+	R2RFixupKindDelegateCtor                  R2RFixupKind = 0x2C
+	R2RFixupKindDeclaringTypeHandle           R2RFixupKind = 0x2D
+	R2RFixupKindIndirectPInvokeTarget         R2RFixupKind = 0x2E
+	R2RFixupKindPInvokeTarget                 R2RFixupKind = 0x2F
+	R2RFixupKindCheckInstructionSetSupport    R2RFixupKind = 0x30
+	R2RFixupKindVerifyFieldOffset             R2RFixupKind = 0x31
+	R2RFixupKindVerifyTypeLayout              R2RFixupKind = 0x32
+	R2RFixupKindCheckVirtualFunctionOverride  R2RFixupKind = 0x33
+	R2RFixupKindVerifyVirtualFunctionOverride R2RFixupKind = 0x34
+	R2RFixupKindCheckILBody                   R2RFixupKind = 0x35
+	R2RFixupKindVerifyILBody                  R2RFixupKind = 0x36
+	// Flag set if the fixup signature is prefixed by a module override.
+	R2RFixupKindModuleOverride R2RFixupKind = 0x80
+)
+
+//go:generate stringer -trimprefix R2ROS -type R2ROS
+
+// R2ROS specifies the target operating system of a ReadyToRun image, as
+// encoded by XOR-ing the machine type of the COFF file header.
+type R2ROS uint16
+
+// ReadyToRun target operating systems.
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/tools/aot/ILCompiler.Reflection.ReadyToRun/ReadyToRunReader.cs
+const (
+	R2ROSWindows R2ROS = 0x0000
+	R2ROSLinux   R2ROS = 0x7B79
+	R2ROSApple   R2ROS = 0x4644
+	R2ROSFreeBSD R2ROS = 0xADC4
+	R2ROSNetBSD  R2ROS = 0x1993
+	R2ROSSunOS   R2ROS = 0x1992
+)
+
+// ~~~ [ Rich header ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+//go:generate stringer -trimprefix RichToolKind -type RichToolKind
+
+// RichToolKind specifies the kind of tool which produced the objects recorded
+// by an entry of the Rich header.
+type RichToolKind uint8
+
+// Rich header tool kinds.
+const (
+	// Unknown tool.
+	RichToolKindUnknown RichToolKind = iota
+	// Imported functions (number of imports from import libraries).
+	RichToolKindImport
+	// C compiler.
+	RichToolKindC
+	// C++ compiler.
+	RichToolKindCPP
+	// C/C++ compiler targeting MSIL (/clr).
+	RichToolKindMSIL
+	// Linker.
+	RichToolKindLinker
+	// Microsoft Macro Assembler.
+	RichToolKindMASM
+	// Resource converter (cvtres).
+	RichToolKindResource
+	// Export file (.exp) produced by the library manager.
+	RichToolKindExport
+	// Import library produced by the library manager.
+	RichToolKindImplib
+	// OMF to COFF converter (cvtomf).
+	RichToolKindCVTOMF
+	// Profile-guided optimization database converter (cvtpgd).
+	RichToolKindCVTPGD
+	// Alias object.
+	RichToolKindAliasObj
+	// Visual Basic compiler.
+	RichToolKindBasic
+	// IL assembler.
+	RichToolKindILAsm
+)
@@ -0,0 +1,55 @@
+// Code generated by "stringer -trimprefix ExDLLCharacteristic -type ExDLLCharacteristic"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[ExDLLCharacteristicCETCompat-1]
+	_ = x[ExDLLCharacteristicCETCompatStrictMode-2]
+	_ = x[ExDLLCharacteristicCETSetContextIPValidationRelaxedMode-4]
+	_ = x[ExDLLCharacteristicCETDynamicAPIsAllowInProc-8]
+	_ = x[ExDLLCharacteristicCETReserved1-16]
+	_ = x[ExDLLCharacteristicCETReserved2-32]
+	_ = x[ExDLLCharacteristicForwardCFICompat-64]
+	_ = x[ExDLLCharacteristicHotPatchCompatible-128]
+}
+
+const (
+	_ExDLLCharacteristic_name_0 = "CETCompatCETCompatStrictMode"
+	_ExDLLCharacteristic_name_1 = "CETSetContextIPValidationRelaxedMode"
+	_ExDLLCharacteristic_name_2 = "CETDynamicAPIsAllowInProc"
+	_ExDLLCharacteristic_name_3 = "CETReserved1"
+	_ExDLLCharacteristic_name_4 = "CETReserved2"
+	_ExDLLCharacteristic_name_5 = "ForwardCFICompat"
+	_ExDLLCharacteristic_name_6 = "HotPatchCompatible"
+)
+
+var (
+	_ExDLLCharacteristic_index_0 = [...]uint8{0, 9, 28}
+)
+
+func (i ExDLLCharacteristic) String() string {
+	switch {
+	case 1 <= i && i <= 2:
+		i -= 1
+		return _ExDLLCharacteristic_name_0[_ExDLLCharacteristic_index_0[i]:_ExDLLCharacteristic_index_0[i+1]]
+	case i == 4:
+		return _ExDLLCharacteristic_name_1
+	case i == 8:
+		return _ExDLLCharacteristic_name_2
+	case i == 16:
+		return _ExDLLCharacteristic_name_3
+	case i == 32:
+		return _ExDLLCharacteristic_name_4
+	case i == 64:
+		return _ExDLLCharacteristic_name_5
+	case i == 128:
+		return _ExDLLCharacteristic_name_6
+	default:
+		return "ExDLLCharacteristic(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
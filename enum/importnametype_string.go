@@ -0,0 +1,28 @@
+// Code generated by "stringer -trimprefix ImportNameType -type ImportNameType"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[ImportNameTypeOrdinal-0]
+	_ = x[ImportNameTypeName-1]
+	_ = x[ImportNameTypeNameNoPrefix-2]
+	_ = x[ImportNameTypeNameUndecorate-3]
+	_ = x[ImportNameTypeNameExportAs-4]
+}
+
+const _ImportNameType_name = "OrdinalNameNameNoPrefixNameUndecorateNameExportAs"
+
+var _ImportNameType_index = [...]uint8{0, 7, 11, 23, 37, 49}
+
+func (i ImportNameType) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_ImportNameType_index)-1 {
+		return "ImportNameType(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _ImportNameType_name[_ImportNameType_index[idx]:_ImportNameType_index[idx+1]]
+}
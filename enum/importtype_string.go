@@ -0,0 +1,26 @@
+// Code generated by "stringer -trimprefix ImportType -type ImportType"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[ImportTypeCode-0]
+	_ = x[ImportTypeData-1]
+	_ = x[ImportTypeConst-2]
+}
+
+const _ImportType_name = "CodeDataConst"
+
+var _ImportType_index = [...]uint8{0, 4, 8, 13}
+
+func (i ImportType) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_ImportType_index)-1 {
+		return "ImportType(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _ImportType_name[_ImportType_index[idx]:_ImportType_index[idx+1]]
+}
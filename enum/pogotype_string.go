@@ -0,0 +1,41 @@
+// Code generated by "stringer -trimprefix POGOType -type POGOType"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[POGOTypeZero-0]
+	_ = x[POGOTypeLTCG-1280590663]
+	_ = x[POGOTypePGI-1346849024]
+	_ = x[POGOTypePGO-1346850560]
+	_ = x[POGOTypePGU-1346852096]
+}
+
+const (
+	_POGOType_name_0 = "Zero"
+	_POGOType_name_1 = "LTCG"
+	_POGOType_name_2 = "PGI"
+	_POGOType_name_3 = "PGO"
+	_POGOType_name_4 = "PGU"
+)
+
+func (i POGOType) String() string {
+	switch {
+	case i == 0:
+		return _POGOType_name_0
+	case i == 1280590663:
+		return _POGOType_name_1
+	case i == 1346849024:
+		return _POGOType_name_2
+	case i == 1346850560:
+		return _POGOType_name_3
+	case i == 1346852096:
+		return _POGOType_name_4
+	default:
+		return "POGOType(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
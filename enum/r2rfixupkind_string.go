@@ -0,0 +1,80 @@
+// Code generated by "stringer -trimprefix R2RFixupKind -type R2RFixupKind"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[R2RFixupKindThisObjDictionaryLookup-7]
+	_ = x[R2RFixupKindTypeDictionaryLookup-8]
+	_ = x[R2RFixupKindMethodDictionaryLookup-9]
+	_ = x[R2RFixupKindTypeHandle-16]
+	_ = x[R2RFixupKindMethodHandle-17]
+	_ = x[R2RFixupKindFieldHandle-18]
+	_ = x[R2RFixupKindMethodEntry-19]
+	_ = x[R2RFixupKindMethodEntryDefToken-20]
+	_ = x[R2RFixupKindMethodEntryRefToken-21]
+	_ = x[R2RFixupKindVirtualEntry-22]
+	_ = x[R2RFixupKindVirtualEntryDefToken-23]
+	_ = x[R2RFixupKindVirtualEntryRefToken-24]
+	_ = x[R2RFixupKindVirtualEntrySlot-25]
+	_ = x[R2RFixupKindHelper-26]
+	_ = x[R2RFixupKindStringHandle-27]
+	_ = x[R2RFixupKindNewObject-28]
+	_ = x[R2RFixupKindNewArray-29]
+	_ = x[R2RFixupKindIsInstanceOf-30]
+	_ = x[R2RFixupKindChkCast-31]
+	_ = x[R2RFixupKindFieldAddress-32]
+	_ = x[R2RFixupKindCctorTrigger-33]
+	_ = x[R2RFixupKindStaticBaseNonGC-34]
+	_ = x[R2RFixupKindStaticBaseGC-35]
+	_ = x[R2RFixupKindThreadStaticBaseNonGC-36]
+	_ = x[R2RFixupKindThreadStaticBaseGC-37]
+	_ = x[R2RFixupKindFieldBaseOffset-38]
+	_ = x[R2RFixupKindFieldOffset-39]
+	_ = x[R2RFixupKindTypeDictionary-40]
+	_ = x[R2RFixupKindMethodDictionary-41]
+	_ = x[R2RFixupKindCheckTypeLayout-42]
+	_ = x[R2RFixupKindCheckFieldOffset-43]
+	_ = x[R2RFixupKindDelegateCtor-44]
+	_ = x[R2RFixupKindDeclaringTypeHandle-45]
+	_ = x[R2RFixupKindIndirectPInvokeTarget-46]
+	_ = x[R2RFixupKindPInvokeTarget-47]
+	_ = x[R2RFixupKindCheckInstructionSetSupport-48]
+	_ = x[R2RFixupKindVerifyFieldOffset-49]
+	_ = x[R2RFixupKindVerifyTypeLayout-50]
+	_ = x[R2RFixupKindCheckVirtualFunctionOverride-51]
+	_ = x[R2RFixupKindVerifyVirtualFunctionOverride-52]
+	_ = x[R2RFixupKindCheckILBody-53]
+	_ = x[R2RFixupKindVerifyILBody-54]
+	_ = x[R2RFixupKindModuleOverride-128]
+}
+
+const (
+	_R2RFixupKind_name_0 = "ThisObjDictionaryLookupTypeDictionaryLookupMethodDictionaryLookup"
+	_R2RFixupKind_name_1 = "TypeHandleMethodHandleFieldHandleMethodEntryMethodEntryDefTokenMethodEntryRefTokenVirtualEntryVirtualEntryDefTokenVirtualEntryRefTokenVirtualEntrySlotHelperStringHandleNewObjectNewArrayIsInstanceOfChkCastFieldAddressCctorTriggerStaticBaseNonGCStaticBaseGCThreadStaticBaseNonGCThreadStaticBaseGCFieldBaseOffsetFieldOffsetTypeDictionaryMethodDictionaryCheckTypeLayoutCheckFieldOffsetDelegateCtorDeclaringTypeHandleIndirectPInvokeTargetPInvokeTargetCheckInstructionSetSupportVerifyFieldOffsetVerifyTypeLayoutCheckVirtualFunctionOverrideVerifyVirtualFunctionOverrideCheckILBodyVerifyILBody"
+	_R2RFixupKind_name_2 = "ModuleOverride"
+)
+
+var (
+	_R2RFixupKind_index_0 = [...]uint8{0, 23, 43, 65}
+	_R2RFixupKind_index_1 = [...]uint16{0, 10, 22, 33, 44, 63, 82, 94, 114, 134, 150, 156, 168, 177, 185, 197, 204, 216, 228, 243, 255, 276, 294, 309, 320, 334, 350, 365, 381, 393, 412, 433, 446, 472, 489, 505, 533, 562, 573, 585}
+)
+
+func (i R2RFixupKind) String() string {
+	switch {
+	case 7 <= i && i <= 9:
+		i -= 7
+		return _R2RFixupKind_name_0[_R2RFixupKind_index_0[i]:_R2RFixupKind_index_0[i+1]]
+	case 16 <= i && i <= 54:
+		i -= 16
+		return _R2RFixupKind_name_1[_R2RFixupKind_index_1[i]:_R2RFixupKind_index_1[i+1]]
+	case i == 128:
+		return _R2RFixupKind_name_2
+	default:
+		return "R2RFixupKind(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
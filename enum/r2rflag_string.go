@@ -0,0 +1,55 @@
+// Code generated by "stringer -trimprefix R2RFlag -type R2RFlag"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[R2RFlagPlatformNeutralSource-1]
+	_ = x[R2RFlagSkipTypeValidation-2]
+	_ = x[R2RFlagPartial-4]
+	_ = x[R2RFlagNonSharedPInvokeStubs-8]
+	_ = x[R2RFlagEmbeddedMSIL-16]
+	_ = x[R2RFlagComponent-32]
+	_ = x[R2RFlagMultiModuleVersionBubble-64]
+	_ = x[R2RFlagUnrelatedR2RCode-128]
+}
+
+const (
+	_R2RFlag_name_0 = "PlatformNeutralSourceSkipTypeValidation"
+	_R2RFlag_name_1 = "Partial"
+	_R2RFlag_name_2 = "NonSharedPInvokeStubs"
+	_R2RFlag_name_3 = "EmbeddedMSIL"
+	_R2RFlag_name_4 = "Component"
+	_R2RFlag_name_5 = "MultiModuleVersionBubble"
+	_R2RFlag_name_6 = "UnrelatedR2RCode"
+)
+
+var (
+	_R2RFlag_index_0 = [...]uint8{0, 21, 39}
+)
+
+func (i R2RFlag) String() string {
+	switch {
+	case 1 <= i && i <= 2:
+		i -= 1
+		return _R2RFlag_name_0[_R2RFlag_index_0[i]:_R2RFlag_index_0[i+1]]
+	case i == 4:
+		return _R2RFlag_name_1
+	case i == 8:
+		return _R2RFlag_name_2
+	case i == 16:
+		return _R2RFlag_name_3
+	case i == 32:
+		return _R2RFlag_name_4
+	case i == 64:
+		return _R2RFlag_name_5
+	case i == 128:
+		return _R2RFlag_name_6
+	default:
+		return "R2RFlag(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
@@ -0,0 +1,29 @@
+// Code generated by "stringer -trimprefix R2RImportSectionFlag -type R2RImportSectionFlag"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[R2RImportSectionFlagEager-1]
+	_ = x[R2RImportSectionFlagPCode-4]
+}
+
+const (
+	_R2RImportSectionFlag_name_0 = "Eager"
+	_R2RImportSectionFlag_name_1 = "PCode"
+)
+
+func (i R2RImportSectionFlag) String() string {
+	switch {
+	case i == 1:
+		return _R2RImportSectionFlag_name_0
+	case i == 4:
+		return _R2RImportSectionFlag_name_1
+	default:
+		return "R2RImportSectionFlag(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
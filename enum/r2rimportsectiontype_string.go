@@ -0,0 +1,39 @@
+// Code generated by "stringer -trimprefix R2RImportSectionType -type R2RImportSectionType"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[R2RImportSectionTypeUnknown-0]
+	_ = x[R2RImportSectionTypeStubDispatch-2]
+	_ = x[R2RImportSectionTypeStringHandle-3]
+	_ = x[R2RImportSectionTypeILBodyFixups-7]
+}
+
+const (
+	_R2RImportSectionType_name_0 = "Unknown"
+	_R2RImportSectionType_name_1 = "StubDispatchStringHandle"
+	_R2RImportSectionType_name_2 = "ILBodyFixups"
+)
+
+var (
+	_R2RImportSectionType_index_1 = [...]uint8{0, 12, 24}
+)
+
+func (i R2RImportSectionType) String() string {
+	switch {
+	case i == 0:
+		return _R2RImportSectionType_name_0
+	case 2 <= i && i <= 3:
+		i -= 2
+		return _R2RImportSectionType_name_1[_R2RImportSectionType_index_1[i]:_R2RImportSectionType_index_1[i+1]]
+	case i == 7:
+		return _R2RImportSectionType_name_2
+	default:
+		return "R2RImportSectionType(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
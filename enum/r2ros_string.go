@@ -0,0 +1,47 @@
+// Code generated by "stringer -trimprefix R2ROS -type R2ROS"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[R2ROSWindows-0]
+	_ = x[R2ROSLinux-31609]
+	_ = x[R2ROSApple-17988]
+	_ = x[R2ROSFreeBSD-44484]
+	_ = x[R2ROSNetBSD-6547]
+	_ = x[R2ROSSunOS-6546]
+}
+
+const (
+	_R2ROS_name_0 = "Windows"
+	_R2ROS_name_1 = "SunOSNetBSD"
+	_R2ROS_name_2 = "Apple"
+	_R2ROS_name_3 = "Linux"
+	_R2ROS_name_4 = "FreeBSD"
+)
+
+var (
+	_R2ROS_index_1 = [...]uint8{0, 5, 11}
+)
+
+func (i R2ROS) String() string {
+	switch {
+	case i == 0:
+		return _R2ROS_name_0
+	case 6546 <= i && i <= 6547:
+		i -= 6546
+		return _R2ROS_name_1[_R2ROS_index_1[i]:_R2ROS_index_1[i+1]]
+	case i == 17988:
+		return _R2ROS_name_2
+	case i == 31609:
+		return _R2ROS_name_3
+	case i == 44484:
+		return _R2ROS_name_4
+	default:
+		return "R2ROS(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
@@ -0,0 +1,47 @@
+// Code generated by "stringer -trimprefix R2RSectionType -type R2RSectionType"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[R2RSectionTypeCompilerIdentifier-100]
+	_ = x[R2RSectionTypeImportSections-101]
+	_ = x[R2RSectionTypeRuntimeFunctions-102]
+	_ = x[R2RSectionTypeMethodDefEntryPoints-103]
+	_ = x[R2RSectionTypeExceptionInfo-104]
+	_ = x[R2RSectionTypeDebugInfo-105]
+	_ = x[R2RSectionTypeDelayLoadMethodCallThunks-106]
+	_ = x[R2RSectionTypeAvailableTypesOld-107]
+	_ = x[R2RSectionTypeAvailableTypes-108]
+	_ = x[R2RSectionTypeInstanceMethodEntryPoints-109]
+	_ = x[R2RSectionTypeInliningInfo-110]
+	_ = x[R2RSectionTypeProfileDataInfo-111]
+	_ = x[R2RSectionTypeManifestMetadata-112]
+	_ = x[R2RSectionTypeAttributePresence-113]
+	_ = x[R2RSectionTypeInliningInfo2-114]
+	_ = x[R2RSectionTypeComponentAssemblies-115]
+	_ = x[R2RSectionTypeOwnerCompositeExecutable-116]
+	_ = x[R2RSectionTypePgoInstrumentationData-117]
+	_ = x[R2RSectionTypeManifestAssemblyMvids-118]
+	_ = x[R2RSectionTypeCrossModuleInlineInfo-119]
+	_ = x[R2RSectionTypeHotColdMap-120]
+	_ = x[R2RSectionTypeMethodIsGenericMap-121]
+	_ = x[R2RSectionTypeEnclosingTypeMap-122]
+	_ = x[R2RSectionTypeTypeGenericInfoMap-123]
+}
+
+const _R2RSectionType_name = "CompilerIdentifierImportSectionsRuntimeFunctionsMethodDefEntryPointsExceptionInfoDebugInfoDelayLoadMethodCallThunksAvailableTypesOldAvailableTypesInstanceMethodEntryPointsInliningInfoProfileDataInfoManifestMetadataAttributePresenceInliningInfo2ComponentAssembliesOwnerCompositeExecutablePgoInstrumentationDataManifestAssemblyMvidsCrossModuleInlineInfoHotColdMapMethodIsGenericMapEnclosingTypeMapTypeGenericInfoMap"
+
+var _R2RSectionType_index = [...]uint16{0, 18, 32, 48, 68, 81, 90, 115, 132, 146, 171, 183, 198, 214, 231, 244, 263, 287, 309, 330, 351, 361, 379, 395, 413}
+
+func (i R2RSectionType) String() string {
+	idx := int(i) - 100
+	if i < 100 || idx >= len(_R2RSectionType_index)-1 {
+		return "R2RSectionType(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _R2RSectionType_name[_R2RSectionType_index[idx]:_R2RSectionType_index[idx+1]]
+}
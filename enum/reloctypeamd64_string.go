@@ -0,0 +1,40 @@
+// Code generated by "stringer -trimprefix RelocTypeAMD64 -type RelocTypeAMD64"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[RelocTypeAMD64Absolute-0]
+	_ = x[RelocTypeAMD64Addr64-1]
+	_ = x[RelocTypeAMD64Addr32-2]
+	_ = x[RelocTypeAMD64Addr32NB-3]
+	_ = x[RelocTypeAMD64Rel32-4]
+	_ = x[RelocTypeAMD64Rel32_1-5]
+	_ = x[RelocTypeAMD64Rel32_2-6]
+	_ = x[RelocTypeAMD64Rel32_3-7]
+	_ = x[RelocTypeAMD64Rel32_4-8]
+	_ = x[RelocTypeAMD64Rel32_5-9]
+	_ = x[RelocTypeAMD64Section-10]
+	_ = x[RelocTypeAMD64SecRel-11]
+	_ = x[RelocTypeAMD64SecRel7-12]
+	_ = x[RelocTypeAMD64Token-13]
+	_ = x[RelocTypeAMD64SRel32-14]
+	_ = x[RelocTypeAMD64Pair-15]
+	_ = x[RelocTypeAMD64SSpan32-16]
+}
+
+const _RelocTypeAMD64_name = "AbsoluteAddr64Addr32Addr32NBRel32Rel32_1Rel32_2Rel32_3Rel32_4Rel32_5SectionSecRelSecRel7TokenSRel32PairSSpan32"
+
+var _RelocTypeAMD64_index = [...]uint8{0, 8, 14, 20, 28, 33, 40, 47, 54, 61, 68, 75, 81, 88, 93, 99, 103, 110}
+
+func (i RelocTypeAMD64) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_RelocTypeAMD64_index)-1 {
+		return "RelocTypeAMD64(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _RelocTypeAMD64_name[_RelocTypeAMD64_index[idx]:_RelocTypeAMD64_index[idx+1]]
+}
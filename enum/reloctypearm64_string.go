@@ -0,0 +1,41 @@
+// Code generated by "stringer -trimprefix RelocTypeARM64 -type RelocTypeARM64"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[RelocTypeARM64Absolute-0]
+	_ = x[RelocTypeARM64Addr32-1]
+	_ = x[RelocTypeARM64Addr32NB-2]
+	_ = x[RelocTypeARM64Branch26-3]
+	_ = x[RelocTypeARM64PageBaseRel21-4]
+	_ = x[RelocTypeARM64Rel21-5]
+	_ = x[RelocTypeARM64PageOffset12A-6]
+	_ = x[RelocTypeARM64PageOffset12L-7]
+	_ = x[RelocTypeARM64SecRel-8]
+	_ = x[RelocTypeARM64SecRelLow12A-9]
+	_ = x[RelocTypeARM64SecRelHigh12A-10]
+	_ = x[RelocTypeARM64SecRelLow12L-11]
+	_ = x[RelocTypeARM64Token-12]
+	_ = x[RelocTypeARM64Section-13]
+	_ = x[RelocTypeARM64Addr64-14]
+	_ = x[RelocTypeARM64Branch19-15]
+	_ = x[RelocTypeARM64Branch14-16]
+	_ = x[RelocTypeARM64Rel32-17]
+}
+
+const _RelocTypeARM64_name = "AbsoluteAddr32Addr32NBBranch26PageBaseRel21Rel21PageOffset12APageOffset12LSecRelSecRelLow12ASecRelHigh12ASecRelLow12LTokenSectionAddr64Branch19Branch14Rel32"
+
+var _RelocTypeARM64_index = [...]uint8{0, 8, 14, 22, 30, 43, 48, 61, 74, 80, 92, 105, 117, 122, 129, 135, 143, 151, 156}
+
+func (i RelocTypeARM64) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_RelocTypeARM64_index)-1 {
+		return "RelocTypeARM64(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _RelocTypeARM64_name[_RelocTypeARM64_index[idx]:_RelocTypeARM64_index[idx+1]]
+}
@@ -0,0 +1,56 @@
+// Code generated by "stringer -trimprefix RelocTypeARM -type RelocTypeARM"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[RelocTypeARMAbsolute-0]
+	_ = x[RelocTypeARMAddr32-1]
+	_ = x[RelocTypeARMAddr32NB-2]
+	_ = x[RelocTypeARMBranch24-3]
+	_ = x[RelocTypeARMBranch11-4]
+	_ = x[RelocTypeARMToken-5]
+	_ = x[RelocTypeARMBLX24-8]
+	_ = x[RelocTypeARMBLX11-9]
+	_ = x[RelocTypeARMRel32-10]
+	_ = x[RelocTypeARMSection-14]
+	_ = x[RelocTypeARMSecRel-15]
+	_ = x[RelocTypeARMMov32-16]
+	_ = x[RelocTypeARMThumbMov32-17]
+	_ = x[RelocTypeARMThumbBranch20-18]
+	_ = x[RelocTypeARMUnused-19]
+	_ = x[RelocTypeARMThumbBranch24-20]
+	_ = x[RelocTypeARMThumbBLX23-21]
+	_ = x[RelocTypeARMPair-22]
+}
+
+const (
+	_RelocTypeARM_name_0 = "AbsoluteAddr32Addr32NBBranch24Branch11Token"
+	_RelocTypeARM_name_1 = "BLX24BLX11Rel32"
+	_RelocTypeARM_name_2 = "SectionSecRelMov32ThumbMov32ThumbBranch20UnusedThumbBranch24ThumbBLX23Pair"
+)
+
+var (
+	_RelocTypeARM_index_0 = [...]uint8{0, 8, 14, 22, 30, 38, 43}
+	_RelocTypeARM_index_1 = [...]uint8{0, 5, 10, 15}
+	_RelocTypeARM_index_2 = [...]uint8{0, 7, 13, 18, 28, 41, 47, 60, 70, 74}
+)
+
+func (i RelocTypeARM) String() string {
+	switch {
+	case i <= 5:
+		return _RelocTypeARM_name_0[_RelocTypeARM_index_0[i]:_RelocTypeARM_index_0[i+1]]
+	case 8 <= i && i <= 10:
+		i -= 8
+		return _RelocTypeARM_name_1[_RelocTypeARM_index_1[i]:_RelocTypeARM_index_1[i+1]]
+	case 14 <= i && i <= 22:
+		i -= 14
+		return _RelocTypeARM_name_2[_RelocTypeARM_index_2[i]:_RelocTypeARM_index_2[i+1]]
+	default:
+		return "RelocTypeARM(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
@@ -0,0 +1,52 @@
+// Code generated by "stringer -trimprefix RelocTypeI386 -type RelocTypeI386"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[RelocTypeI386Absolute-0]
+	_ = x[RelocTypeI386Dir16-1]
+	_ = x[RelocTypeI386Rel16-2]
+	_ = x[RelocTypeI386Dir32-6]
+	_ = x[RelocTypeI386Dir32NB-7]
+	_ = x[RelocTypeI386Seg12-9]
+	_ = x[RelocTypeI386Section-10]
+	_ = x[RelocTypeI386SecRel-11]
+	_ = x[RelocTypeI386Token-12]
+	_ = x[RelocTypeI386SecRel7-13]
+	_ = x[RelocTypeI386Rel32-20]
+}
+
+const (
+	_RelocTypeI386_name_0 = "AbsoluteDir16Rel16"
+	_RelocTypeI386_name_1 = "Dir32Dir32NB"
+	_RelocTypeI386_name_2 = "Seg12SectionSecRelTokenSecRel7"
+	_RelocTypeI386_name_3 = "Rel32"
+)
+
+var (
+	_RelocTypeI386_index_0 = [...]uint8{0, 8, 13, 18}
+	_RelocTypeI386_index_1 = [...]uint8{0, 5, 12}
+	_RelocTypeI386_index_2 = [...]uint8{0, 5, 12, 18, 23, 30}
+)
+
+func (i RelocTypeI386) String() string {
+	switch {
+	case i <= 2:
+		return _RelocTypeI386_name_0[_RelocTypeI386_index_0[i]:_RelocTypeI386_index_0[i+1]]
+	case 6 <= i && i <= 7:
+		i -= 6
+		return _RelocTypeI386_name_1[_RelocTypeI386_index_1[i]:_RelocTypeI386_index_1[i+1]]
+	case 9 <= i && i <= 13:
+		i -= 9
+		return _RelocTypeI386_name_2[_RelocTypeI386_index_2[i]:_RelocTypeI386_index_2[i+1]]
+	case i == 20:
+		return _RelocTypeI386_name_3
+	default:
+		return "RelocTypeI386(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
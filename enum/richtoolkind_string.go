@@ -0,0 +1,38 @@
+// Code generated by "stringer -trimprefix RichToolKind -type RichToolKind"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[RichToolKindUnknown-0]
+	_ = x[RichToolKindImport-1]
+	_ = x[RichToolKindC-2]
+	_ = x[RichToolKindCPP-3]
+	_ = x[RichToolKindMSIL-4]
+	_ = x[RichToolKindLinker-5]
+	_ = x[RichToolKindMASM-6]
+	_ = x[RichToolKindResource-7]
+	_ = x[RichToolKindExport-8]
+	_ = x[RichToolKindImplib-9]
+	_ = x[RichToolKindCVTOMF-10]
+	_ = x[RichToolKindCVTPGD-11]
+	_ = x[RichToolKindAliasObj-12]
+	_ = x[RichToolKindBasic-13]
+	_ = x[RichToolKindILAsm-14]
+}
+
+const _RichToolKind_name = "UnknownImportCCPPMSILLinkerMASMResourceExportImplibCVTOMFCVTPGDAliasObjBasicILAsm"
+
+var _RichToolKind_index = [...]uint8{0, 7, 13, 14, 17, 21, 27, 31, 39, 45, 51, 57, 63, 71, 76, 81}
+
+func (i RichToolKind) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_RichToolKind_index)-1 {
+		return "RichToolKind(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _RichToolKind_name[_RichToolKind_index[idx]:_RichToolKind_index[idx+1]]
+}
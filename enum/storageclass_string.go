@@ -0,0 +1,66 @@
+// Code generated by "stringer -trimprefix StorageClass -type StorageClass"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[StorageClassEndOfFunction-255]
+	_ = x[StorageClassNull-0]
+	_ = x[StorageClassAutomatic-1]
+	_ = x[StorageClassExternal-2]
+	_ = x[StorageClassStatic-3]
+	_ = x[StorageClassRegister-4]
+	_ = x[StorageClassExternalDef-5]
+	_ = x[StorageClassLabel-6]
+	_ = x[StorageClassUndefinedLabel-7]
+	_ = x[StorageClassMemberOfStruct-8]
+	_ = x[StorageClassArgument-9]
+	_ = x[StorageClassStructTag-10]
+	_ = x[StorageClassMemberOfUnion-11]
+	_ = x[StorageClassUnionTag-12]
+	_ = x[StorageClassTypeDefinition-13]
+	_ = x[StorageClassUndefinedStatic-14]
+	_ = x[StorageClassEnumTag-15]
+	_ = x[StorageClassMemberOfEnum-16]
+	_ = x[StorageClassRegisterParam-17]
+	_ = x[StorageClassBitField-18]
+	_ = x[StorageClassBlock-100]
+	_ = x[StorageClassFunction-101]
+	_ = x[StorageClassEndOfStruct-102]
+	_ = x[StorageClassFile-103]
+	_ = x[StorageClassSection-104]
+	_ = x[StorageClassWeakExternal-105]
+	_ = x[StorageClassCLRToken-107]
+}
+
+const (
+	_StorageClass_name_0 = "NullAutomaticExternalStaticRegisterExternalDefLabelUndefinedLabelMemberOfStructArgumentStructTagMemberOfUnionUnionTagTypeDefinitionUndefinedStaticEnumTagMemberOfEnumRegisterParamBitField"
+	_StorageClass_name_1 = "BlockFunctionEndOfStructFileSectionWeakExternal"
+	_StorageClass_name_2 = "CLRToken"
+	_StorageClass_name_3 = "EndOfFunction"
+)
+
+var (
+	_StorageClass_index_0 = [...]uint8{0, 4, 13, 21, 27, 35, 46, 51, 65, 79, 87, 96, 109, 117, 131, 146, 153, 165, 178, 186}
+	_StorageClass_index_1 = [...]uint8{0, 5, 13, 24, 28, 35, 47}
+)
+
+func (i StorageClass) String() string {
+	switch {
+	case i <= 18:
+		return _StorageClass_name_0[_StorageClass_index_0[i]:_StorageClass_index_0[i+1]]
+	case 100 <= i && i <= 105:
+		i -= 100
+		return _StorageClass_name_1[_StorageClass_index_1[i]:_StorageClass_index_1[i+1]]
+	case i == 107:
+		return _StorageClass_name_2
+	case i == 255:
+		return _StorageClass_name_3
+	default:
+		return "StorageClass(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
@@ -0,0 +1,28 @@
+// Code generated by "stringer -trimprefix StrongNameStatus -type StrongNameStatus"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[StrongNameStatusNotSigned-0]
+	_ = x[StrongNameStatusDelaySigned-1]
+	_ = x[StrongNameStatusTestSigned-2]
+	_ = x[StrongNameStatusValid-3]
+	_ = x[StrongNameStatusInvalid-4]
+}
+
+const _StrongNameStatus_name = "NotSignedDelaySignedTestSignedValidInvalid"
+
+var _StrongNameStatus_index = [...]uint8{0, 9, 20, 30, 35, 42}
+
+func (i StrongNameStatus) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_StrongNameStatus_index)-1 {
+		return "StrongNameStatus(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _StrongNameStatus_name[_StrongNameStatus_index[idx]:_StrongNameStatus_index[idx+1]]
+}
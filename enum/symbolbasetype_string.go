@@ -0,0 +1,39 @@
+// Code generated by "stringer -trimprefix SymbolBaseType -type SymbolBaseType"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[SymbolBaseTypeNull-0]
+	_ = x[SymbolBaseTypeVoid-1]
+	_ = x[SymbolBaseTypeChar-2]
+	_ = x[SymbolBaseTypeShort-3]
+	_ = x[SymbolBaseTypeInt-4]
+	_ = x[SymbolBaseTypeLong-5]
+	_ = x[SymbolBaseTypeFloat-6]
+	_ = x[SymbolBaseTypeDouble-7]
+	_ = x[SymbolBaseTypeStruct-8]
+	_ = x[SymbolBaseTypeUnion-9]
+	_ = x[SymbolBaseTypeEnum-10]
+	_ = x[SymbolBaseTypeMOE-11]
+	_ = x[SymbolBaseTypeByte-12]
+	_ = x[SymbolBaseTypeWord-13]
+	_ = x[SymbolBaseTypeUint-14]
+	_ = x[SymbolBaseTypeDword-15]
+}
+
+const _SymbolBaseType_name = "NullVoidCharShortIntLongFloatDoubleStructUnionEnumMOEByteWordUintDword"
+
+var _SymbolBaseType_index = [...]uint8{0, 4, 8, 12, 17, 20, 24, 29, 35, 41, 46, 50, 53, 57, 61, 65, 70}
+
+func (i SymbolBaseType) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_SymbolBaseType_index)-1 {
+		return "SymbolBaseType(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _SymbolBaseType_name[_SymbolBaseType_index[idx]:_SymbolBaseType_index[idx+1]]
+}
@@ -0,0 +1,27 @@
+// Code generated by "stringer -trimprefix SymbolComplexType -type SymbolComplexType"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[SymbolComplexTypeNull-0]
+	_ = x[SymbolComplexTypePointer-1]
+	_ = x[SymbolComplexTypeFunction-2]
+	_ = x[SymbolComplexTypeArray-3]
+}
+
+const _SymbolComplexType_name = "NullPointerFunctionArray"
+
+var _SymbolComplexType_index = [...]uint8{0, 4, 11, 19, 24}
+
+func (i SymbolComplexType) String() string {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_SymbolComplexType_index)-1 {
+		return "SymbolComplexType(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _SymbolComplexType_name[_SymbolComplexType_index[idx]:_SymbolComplexType_index[idx+1]]
+}
@@ -0,0 +1,27 @@
+// Code generated by "stringer -trimprefix WeakExternalFlag -type WeakExternalFlag"; DO NOT EDIT.
+
+package enum
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[WeakExternalFlagSearchNoLibrary-1]
+	_ = x[WeakExternalFlagSearchLibrary-2]
+	_ = x[WeakExternalFlagSearchAlias-3]
+	_ = x[WeakExternalFlagAntiDependency-4]
+}
+
+const _WeakExternalFlag_name = "SearchNoLibrarySearchLibrarySearchAliasAntiDependency"
+
+var _WeakExternalFlag_index = [...]uint8{0, 15, 28, 39, 53}
+
+func (i WeakExternalFlag) String() string {
+	idx := int(i) - 1
+	if i < 1 || idx >= len(_WeakExternalFlag_index)-1 {
+		return "WeakExternalFlag(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _WeakExternalFlag_name[_WeakExternalFlag_index[idx]:_WeakExternalFlag_index[idx+1]]
+}
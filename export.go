@@ -0,0 +1,45 @@
+package pe
+
+import "time"
+
+// ExportDirectory is an export data directory.
+type ExportDirectory struct {
+	// Reserved; must be zero.
+	Characteristics uint32
+	// Export data creation time.
+	Date time.Time
+	// Major version number.
+	MajorVer uint16
+	// Minor version number.
+	MinorVer uint16
+	// DLL name.
+	Name string
+	// Starting ordinal number of exports.
+	OrdinalBase uint32
+	// Number of entries in the export address table (EAT).
+	NFuncs uint32
+	// Number of entries in the export name pointer table and ordinal table.
+	NNames uint32
+	// Relative address of export address table (EAT).
+	EATRelAddr uint32
+	// Relative address of export name pointer table.
+	NamePtrsRelAddr uint32
+	// Relative address of export ordinal table.
+	OrdinalsRelAddr uint32
+}
+
+// Export is an exported symbol of a PE file.
+type Export struct {
+	// Ordinal number of export.
+	Ordinal uint32
+	// Relative address of the exported symbol; or of the forwarder string if
+	// Forwarder is set.
+	RelAddr uint32
+	// Export name; empty if exported by ordinal only (NONAME).
+	Name string
+	// Index into the export name pointer table (used if Name is set).
+	Hint uint16
+	// Forwarder string ("DLL.name" or "DLL.#ordinal") of forwarded exports;
+	// empty otherwise.
+	Forwarder string
+}
@@ -1,32 +1,63 @@
 package pe
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	"github.com/mewmew/pe/enum"
+	"github.com/mewmew/pe/metadata"
+	"github.com/pkg/errors"
 )
 
 // File is a Portable Executable (PE) file.
 type File struct {
 	// File contents.
 	Content []byte
-	// COFF file header.
+	// MS-DOS header of PE images; nil for COFF object files.
+	DOSHdr *DOSHeader
+	// MS-DOS stub program of PE images, located between the MS-DOS header and
+	// the Rich header (or PE signature if not present); nil for COFF object
+	// files.
+	DOSStub []byte
+	// Rich header of images linked by Microsoft linkers; nil if not present.
+	RichHdr *RichHeader
+	// COFF file header; nil for import objects and anonymous objects other
+	// than big object files.
 	FileHdr *FileHeader
-	// Optional header.
+	// Anonymous object header of big object files and other anonymous objects
+	// (e.g. LTCG objects); nil otherwise.
+	AnonHdr *AnonObjectHeader
+	// Import object header of short import library members; nil otherwise.
+	ImportHdr *ImportObjectHeader
+	// Optional header; nil for COFF object files.
 	OptHdr *OptHeader
 	// Data directories.
 	DataDirs []DataDirectory
 	// Section headers.
 	SectHdrs []SectionHeader
+	// COFF symbol table.
+	Symbols []Symbol
+	// COFF string table.
+	StrTable StringTable
+	// Error encountered while parsing the COFF symbol table or string table of
+	// a PE image, in which case Symbols and StrTable are nil. Invalid symbol
+	// tables of COFF object files are reported by the parse functions instead.
+	SymbolTableErr error
 	// Data directory contents.
 	//
 	// 0 - Export Table
+	ExpDir *ExportDirectory
+	Exps   []Export
 	// 1 - Import Table
 	Imps []ImportEntry
 	// 2 - Resource Table
 	// 3 - Exception Table
 	// 4 - Certificate Table
+	Certs []Certificate
 	// 5 - Base Relocation Table
 	BaseRelocBlocks []BaseRelocBlock
 	// 6 - Debug data
@@ -39,7 +70,29 @@ type File struct {
 	// 12 - Import Address Table
 	// 13 - Delay Import Descriptor
 	// 14 - CLR Header
+	CLRHdr *CLRHeader
+	// CLR metadata (of the CLR header).
+	Metadata *metadata.Metadata
+	// ReadyToRun header (of the CLR header); nil if not a ReadyToRun image.
+	R2R *ReadyToRun
 	// 15 - Reserved
+
+	// Functions with COFF line numbers of each section, sorted by relative
+	// address, and any error encountered while building the table; lazily
+	// populated by LineNumAt.
+	lineFuncsOnce sync.Once
+	lineFuncs     map[int][]FuncLineNums
+	lineFuncsErr  error
+}
+
+// WriteTo writes the contents of the PE file to w. It implements the
+// io.WriterTo interface.
+func (file *File) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(file.Content)
+	if err != nil {
+		return int64(n), errors.WithStack(err)
+	}
+	return int64(n), nil
 }
 
 // ReadData reads the data with the specified address and length from the
@@ -48,7 +101,7 @@ func (file *File) ReadData(addr uint64, n int64) []byte {
 	for _, sectHdr := range file.SectHdrs {
 		sectStartAddr := file.OptHdr.ImageBase + uint64(sectHdr.RelAddr)
 		sectEndAddr := sectStartAddr + uint64(sectHdr.DataSize)
-		if !(sectStartAddr <= addr && addr+uint64(n) < sectEndAddr) {
+		if !(sectStartAddr <= addr && addr+uint64(n) <= sectEndAddr) {
 			continue
 		}
 		offset := addr - sectStartAddr
@@ -59,12 +112,149 @@ func (file *File) ReadData(addr uint64, n int64) []byte {
 	panic(fmt.Errorf("unable to locate data at address 0x%08X (%d bytes)", addr, n))
 }
 
+// readFrom returns the data of the section containing the given relative
+// address (relative to image base), starting at the relative address and
+// extending to the end of the section data.
+func (file *File) readFrom(relAddr uint32) ([]byte, error) {
+	buf, sectRelAddr, err := file.sectionData(relAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buf[relAddr-sectRelAddr:], nil
+}
+
+// sectionData returns the data of the section containing the given relative
+// address (relative to image base), and the relative address of the section.
+func (file *File) sectionData(relAddr uint32) ([]byte, uint32, error) {
+	for _, sectHdr := range file.SectHdrs {
+		if !(sectHdr.RelAddr <= relAddr && uint64(relAddr) < uint64(sectHdr.RelAddr)+uint64(sectHdr.DataSize)) {
+			continue
+		}
+		start := uint64(sectHdr.DataOffset)
+		end := start + uint64(sectHdr.DataSize)
+		if end > uint64(len(file.Content)) {
+			end = uint64(len(file.Content))
+		}
+		if start+uint64(relAddr-sectHdr.RelAddr) >= end {
+			break
+		}
+		return file.Content[start:end], sectHdr.RelAddr, nil
+	}
+	return nil, 0, errors.Errorf("unable to locate data at relative address 0x%08X", relAddr)
+}
+
+// SectionData returns the raw contents of the given section, as stored in the
+// file. Sections of uninitialized data have no contents.
+func (file *File) SectionData(sectHdr SectionHeader) ([]byte, error) {
+	if sectHdr.Flags&enum.SectionFlagContainsUninitializedData != 0 || sectHdr.DataOffset == 0 {
+		return nil, nil
+	}
+	start := uint64(sectHdr.DataOffset)
+	end := start + uint64(sectHdr.DataSize)
+	if end > uint64(len(file.Content)) {
+		return nil, errors.Errorf("contents of section %q out of bounds; expected end <= %d, got %d", sectHdr.Name, len(file.Content), end)
+	}
+	return file.Content[start:end], nil
+}
+
+// fileOffset returns the file offset of the given relative address (relative
+// to image base).
+func (file *File) fileOffset(relAddr uint32) (uint32, error) {
+	for _, sectHdr := range file.SectHdrs {
+		if sectHdr.RelAddr <= relAddr && uint64(relAddr) < uint64(sectHdr.RelAddr)+uint64(sectHdr.DataSize) {
+			return sectHdr.DataOffset + (relAddr - sectHdr.RelAddr), nil
+		}
+	}
+	return 0, errors.Errorf("unable to locate file offset of relative address 0x%08X", relAddr)
+}
+
+// standardDOSStub is the MS-DOS stub program emitted by Microsoft linkers (and
+// compatible linkers), which prints "This program cannot be run in DOS mode."
+// and exits.
+var standardDOSStub = []byte("\x0E\x1F\xBA\x0E\x00\xB4\x09\xCD\x21\xB8\x01\x4C\xCD\x21This program cannot be run in DOS mode.\r\r\n$")
+
+// HasStandardDOSStub reports whether the MS-DOS stub of the PE image is the
+// standard stub emitted by Microsoft linkers, ignoring trailing zero padding.
+func (file *File) HasStandardDOSStub() bool {
+	stub := bytes.TrimRight(file.DOSStub, "\x00")
+	return bytes.Equal(stub, standardDOSStub)
+}
+
+// optHdrOffset returns the file offset of the optional header.
+func (file *File) optHdrOffset() uint32 {
+	// Offset of PE signature + size of PE signature + size of COFF file header.
+	return binary.LittleEndian.Uint32(file.Content[0x3C:]) + 4 + 20
+}
+
+// checksumOffset returns the file offset of the checksum field of the optional
+// header.
+func (file *File) checksumOffset() uint32 {
+	return file.optHdrOffset() + 64
+}
+
+// dataDirOffset returns the file offset of the data directory with the given
+// index.
+func (file *File) dataDirOffset(idx int) uint32 {
+	offset := file.optHdrOffset() + 96
+	if file.OptHdr.Magic == magic64 {
+		offset = file.optHdrOffset() + 112
+	}
+	return offset + uint32(idx)*8
+}
+
+// sectHdrsEnd returns the file offset of the end of the section headers.
+func (file *File) sectHdrsEnd() uint32 {
+	return file.optHdrOffset() + uint32(file.FileHdr.OptHdrSize) + uint32(file.FileHdr.NSections)*40
+}
+
+// DOSHeader is the MS-DOS header of a PE image.
+type DOSHeader struct {
+	// Magic number ("MZ").
+	Magic [2]byte
+	// Number of bytes used in the last page of the file.
+	LastPageSize uint16
+	// Number of pages (512 bytes) in the file.
+	NPages uint16
+	// Number of relocation entries.
+	NRelocs uint16
+	// Size of header in number of paragraphs (16 bytes).
+	HeaderParagraphs uint16
+	// Minimum number of extra paragraphs needed.
+	MinAlloc uint16
+	// Maximum number of extra paragraphs needed.
+	MaxAlloc uint16
+	// Initial (relative) value of the SS register.
+	SS uint16
+	// Initial value of the SP register.
+	SP uint16
+	// Checksum.
+	Checksum uint16
+	// Initial value of the IP register.
+	IP uint16
+	// Initial (relative) value of the CS register.
+	CS uint16
+	// File offset of relocation table.
+	RelocTableOffset uint16
+	// Overlay number.
+	OverlayNum uint16
+	// Reserved.
+	Reserved1 [4]uint16
+	// OEM identifier (for OEMInfo).
+	OEMID uint16
+	// OEM information (specific to OEMID).
+	OEMInfo uint16
+	// Reserved.
+	Reserved2 [10]uint16
+	// File offset of PE signature.
+	PEOffset uint32
+}
+
 // FileHeader is a COFF file header.
 type FileHeader struct {
 	// Target CPU type.
 	Machine enum.MachineType
 	// Number of sections.
-	NSections uint16
+	NSections uint32
 	// File creation time.
 	Date time.Time
 	// File offset of COFF symbol table.
@@ -153,8 +343,11 @@ type DataDirectory struct {
 
 // SectionHeader is a section header.
 type SectionHeader struct {
-	// Section name.
+	// Section name; long section names are resolved using the string table.
 	Name string
+	// Raw section name (NULL-padded); either the section name, or a "/n" or
+	// "//base64" encoded string table offset of a long section name.
+	RawName [8]byte
 	// Size of section when loaded into memory.
 	VirtualSize uint32
 	// Relative address of section (relative to image base).
@@ -173,4 +366,8 @@ type SectionHeader struct {
 	NLineNums uint16
 	// Section flags.
 	Flags enum.SectionFlag
+	// COFF relocation entries of the section (object files).
+	Relocs []Reloc
+	// COFF line number entries of the section.
+	LineNums []LineNum
 }
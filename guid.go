@@ -0,0 +1,16 @@
+package pe
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// GUID is a globally unique identifier, stored in on-disk byte order (the first
+// three groups are little-endian).
+type GUID [16]byte
+
+// String returns the registry format representation of the GUID (e.g.
+// "D1BAA1C7-BAEE-4BA9-AF20-FAF66AA4DCB8").
+func (guid GUID) String() string {
+	return fmt.Sprintf("%08X-%04X-%04X-%X-%X", binary.LittleEndian.Uint32(guid[0:4]), binary.LittleEndian.Uint16(guid[4:6]), binary.LittleEndian.Uint16(guid[6:8]), guid[8:10], guid[10:16])
+}
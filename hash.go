@@ -0,0 +1,82 @@
+package pe
+
+import (
+	"crypto/md5"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// --- [ Import and export hashes ] --------------------------------------------
+
+// imphashOrdinals maps from DLL name (lowercase) to ordinal names used by the
+// import hash; as defined by pefile for compatibility.
+var imphashOrdinals = map[string]map[uint16]string{
+	"ws2_32.dll":   ws2_32Ordinals,
+	"wsock32.dll":  ws2_32Ordinals,
+	"oleaut32.dll": oleaut32Ordinals,
+}
+
+// ImpHash returns the import hash (imphash) of the PE file, as computed by
+// pefile; or an empty string if the file has no imports.
+//
+// The import hash is the MD5 hash (in lowercase hexadecimal) of the
+// comma-separated list of imports, in order of occurrence, each formatted as
+// "dll.func"; where dll is the lowercase DLL name with ".dll", ".ocx" and
+// ".sys" extensions removed, and func is the lowercase function name. Imports
+// by ordinal are named through the ordinal tables of ws2_32.dll, wsock32.dll
+// and oleaut32.dll, or "ord" followed by the ordinal (e.g. "ord123").
+//
+// ref: https://www.mandiant.com/resources/blog/tracking-malware-import-hashing
+func (file *File) ImpHash() string {
+	var imps []string
+	for _, imp := range file.Imps {
+		dllName := strings.ToLower(imp.ImpDir.Name)
+		libName := dllName
+		if pos := strings.LastIndex(libName, "."); pos != -1 {
+			switch libName[pos+1:] {
+			case "dll", "ocx", "sys":
+				libName = libName[:pos]
+			}
+		}
+		for _, entry := range imp.entries() {
+			funcName := entry.NameEntry.Name
+			if entry.IsOrdinal {
+				name, ok := imphashOrdinals[dllName][entry.Ordinal]
+				if !ok {
+					name = fmt.Sprintf("ord%d", entry.Ordinal)
+				}
+				funcName = name
+			}
+			if len(funcName) == 0 {
+				continue
+			}
+			imps = append(imps, libName+"."+strings.ToLower(funcName))
+		}
+	}
+	if len(imps) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(imps, ","))))
+}
+
+// ExpHash returns the export hash of the PE file; or an empty string if the
+// file has no named exports.
+//
+// The export hash is the MD5 hash (in lowercase hexadecimal) of the
+// comma-separated list of export names, in lowercase and sorted in ascending
+// order. Exports by ordinal only are excluded.
+func (file *File) ExpHash() string {
+	var names []string
+	for _, exp := range file.Exps {
+		if len(exp.Name) == 0 {
+			continue
+		}
+		names = append(names, strings.ToLower(exp.Name))
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(names, ","))))
+}
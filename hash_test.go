@@ -0,0 +1,58 @@
+package pe
+
+import "testing"
+
+func TestImpHash(t *testing.T) {
+	golden := []struct {
+		path string
+		want string
+	}{
+		// Imports, as formatted by pefile:
+		//
+		//    ws2_32.wsastartup,ws2_32.closesocket,ws2_32.ord999,ws2_32.select,
+		//    wsock32.closesocket,kernel32.exitprocess,oleaut32.sysallocstring,
+		//    oleaut32.sysfreestring,foo.ord7,bar.barfunc,baz.drv.ord1
+		//
+		// Ordinals 115 and 3 of ws2_32.dll and wsock32.dll, and ordinals 2 and 6
+		// of oleaut32.dll are resolved by name; other imports by ordinal use the
+		// "ord" fallback. Only ".dll", ".ocx" and ".sys" extensions are removed.
+		{path: "testdata/imphash.exe", want: "aae21ed00a1ead784f4c83a3b692954e"},
+		// No imports.
+		{path: "testdata/exports.dll", want: ""},
+	}
+	for _, g := range golden {
+		file, err := ParseFile(g.path)
+		if err != nil {
+			t.Errorf("%q: unable to parse file; %+v", g.path, err)
+			continue
+		}
+		got := file.ImpHash()
+		if got != g.want {
+			t.Errorf("%q: imphash mismatch; expected %q, got %q", g.path, g.want, got)
+		}
+	}
+}
+
+func TestExpHash(t *testing.T) {
+	golden := []struct {
+		path string
+		want string
+	}{
+		// Named exports "bar", "myalloc" (forwarded) and "qux"; the NONAME export
+		// of ordinal 5 is excluded.
+		{path: "testdata/exports.dll", want: "af8979598bf241e11319e03e57bbf01e"},
+		// No exports.
+		{path: "testdata/imphash.exe", want: ""},
+	}
+	for _, g := range golden {
+		file, err := ParseFile(g.path)
+		if err != nil {
+			t.Errorf("%q: unable to parse file; %+v", g.path, err)
+			continue
+		}
+		got := file.ExpHash()
+		if got != g.want {
+			t.Errorf("%q: exphash mismatch; expected %q, got %q", g.path, g.want, got)
+		}
+	}
+}
@@ -39,3 +39,29 @@ func (file *File) parseCString(addr uint64) string {
 	}
 	return string(buf)
 }
+
+// reverse returns a copy of b in reverse byte order.
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i, v := range b {
+		r[len(b)-1-i] = v
+	}
+	return r
+}
+
+// isZero reports whether all bytes of b are zero.
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// zero sets all bytes of b to zero.
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
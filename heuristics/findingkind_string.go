@@ -0,0 +1,28 @@
+// Code generated by "stringer -trimprefix Finding -type FindingKind"; DO NOT EDIT.
+
+package heuristics
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[FindingHighEntropy-1]
+	_ = x[FindingWritableExecutable-2]
+	_ = x[FindingEntryPointOutsideCode-3]
+	_ = x[FindingTinyImports-4]
+	_ = x[FindingPackerSignature-5]
+}
+
+const _FindingKind_name = "HighEntropyWritableExecutableEntryPointOutsideCodeTinyImportsPackerSignature"
+
+var _FindingKind_index = [...]uint8{0, 11, 29, 50, 61, 76}
+
+func (i FindingKind) String() string {
+	idx := int(i) - 1
+	if i < 1 || idx >= len(_FindingKind_index)-1 {
+		return "FindingKind(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+	return _FindingKind_name[_FindingKind_index[idx]:_FindingKind_index[idx+1]]
+}
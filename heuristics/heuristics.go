@@ -0,0 +1,204 @@
+// Package heuristics implements heuristics for triage of PE files, such as
+// section entropy statistics and detection of common packers.
+package heuristics
+
+import (
+	"fmt"
+
+	"github.com/mewmew/pe"
+	"github.com/mewmew/pe/enum"
+)
+
+// Thresholds of heuristics.
+const (
+	// Minimum entropy (in bits per byte) of high-entropy sections.
+	HighEntropy = 7.0
+	// Maximum number of imported functions of tiny import tables.
+	TinyImports = 10
+)
+
+// Report is the result of analyzing a PE file.
+type Report struct {
+	// Statistics of sections, in order of occurrence.
+	Sections []SectionStats
+	// Suspicious characteristics of the file.
+	Findings []Finding
+	// Packers detected, in order of decreasing confidence.
+	Packers []PackerMatch
+	// Overall verdict.
+	Verdict Verdict
+}
+
+// SectionStats is the statistics of the raw data of a section.
+type SectionStats struct {
+	// Section name.
+	Name string
+	// Size of raw data in bytes.
+	Size int
+	// Shannon entropy in bits per byte (between 0 and 8).
+	Entropy float64
+	// Chi-squared statistic of byte distribution (compared to a uniform
+	// distribution).
+	ChiSquared float64
+}
+
+//go:generate stringer -trimprefix Finding -type FindingKind
+
+// FindingKind specifies the kind of a finding.
+type FindingKind uint8
+
+// Kinds of findings.
+const (
+	// Executable section with high entropy (compressed or encrypted code).
+	FindingHighEntropy FindingKind = iota + 1
+	// Section which is both writable and executable.
+	FindingWritableExecutable
+	// Entry point located outside of the first code section.
+	FindingEntryPointOutsideCode
+	// Import table with few imported functions.
+	FindingTinyImports
+	// Signature of a known packer.
+	FindingPackerSignature
+)
+
+// Finding is a suspicious characteristic of a PE file.
+type Finding struct {
+	// Finding kind.
+	Kind FindingKind
+	// Index into the section headers of the section concerned; -1 if not
+	// section specific.
+	SectIndex int
+	// Description of finding.
+	Desc string
+}
+
+//go:generate stringer -trimprefix Verdict -type Verdict
+
+// Verdict specifies the overall verdict of analyzing a PE file.
+type Verdict uint8
+
+// Verdicts.
+const (
+	// No suspicious characteristics.
+	VerdictClean Verdict = iota
+	// Suspicious characteristics, without evidence of a known packer.
+	VerdictSuspicious
+	// Packed (or encrypted) by a known packer, or by an unknown packer with
+	// compressed code and a tiny import table.
+	VerdictPacked
+)
+
+// Analyze analyzes the given PE file, reporting section statistics, suspicious
+// characteristics and known packers.
+func Analyze(file *pe.File) *Report {
+	report := &Report{}
+	highEntropy := false
+	for i, sectHdr := range file.SectHdrs {
+		data, err := file.SectionData(sectHdr)
+		if err != nil {
+			// Truncate sections extending past the end of the file.
+			data = nil
+			if uint64(sectHdr.DataOffset) < uint64(len(file.Content)) {
+				data = file.Content[sectHdr.DataOffset:]
+			}
+		}
+		stats := SectionStats{
+			Name:       sectHdr.Name,
+			Size:       len(data),
+			Entropy:    Entropy(data),
+			ChiSquared: ChiSquared(data),
+		}
+		report.Sections = append(report.Sections, stats)
+		exec := sectHdr.Flags&(enum.SectionFlagMemExecute|enum.SectionFlagContainsCode) != 0
+		if exec && stats.Entropy >= HighEntropy {
+			highEntropy = true
+			report.addFinding(FindingHighEntropy, i, "executable section %q has high entropy (%.2f)", sectHdr.Name, stats.Entropy)
+		}
+		if sectHdr.Flags&enum.SectionFlagMemWrite != 0 && sectHdr.Flags&enum.SectionFlagMemExecute != 0 {
+			report.addFinding(FindingWritableExecutable, i, "section %q is writable and executable", sectHdr.Name)
+		}
+	}
+	report.checkEntryPoint(file)
+	tinyImports := report.checkImports(file)
+	report.Packers = detectPackers(file)
+	for _, packer := range report.Packers {
+		for _, evidence := range packer.Evidence {
+			report.addFinding(FindingPackerSignature, -1, "%s: %s", packer.Name, evidence)
+		}
+	}
+	switch {
+	case len(report.Packers) > 0 && report.Packers[0].Confidence >= 0.5:
+		report.Verdict = VerdictPacked
+	case highEntropy && tinyImports:
+		report.Verdict = VerdictPacked
+	case len(report.Findings) > 0:
+		report.Verdict = VerdictSuspicious
+	default:
+		report.Verdict = VerdictClean
+	}
+	return report
+}
+
+// checkEntryPoint checks whether the entry point of the given PE image is
+// located outside of the first code section.
+func (report *Report) checkEntryPoint(file *pe.File) {
+	if file.OptHdr == nil || file.OptHdr.EntryRelAddr == 0 {
+		// No entry point (e.g. resource-only DLL).
+		return
+	}
+	entry := file.OptHdr.EntryRelAddr
+	for i, sectHdr := range file.SectHdrs {
+		if sectHdr.Flags&(enum.SectionFlagContainsCode|enum.SectionFlagMemExecute) == 0 {
+			continue
+		}
+		if !contains(sectHdr, entry) {
+			report.addFinding(FindingEntryPointOutsideCode, i, "entry point 0x%08X outside of first code section %q", entry, sectHdr.Name)
+		}
+		return
+	}
+	report.addFinding(FindingEntryPointOutsideCode, -1, "entry point 0x%08X present but no code section", entry)
+}
+
+// checkImports checks whether the given PE image has a tiny import table. The
+// boolean return value indicates whether the import table is tiny.
+func (report *Report) checkImports(file *pe.File) bool {
+	if file.OptHdr == nil || file.CLRHdr != nil {
+		// Managed images import a single function (_CorExeMain or
+		// _CorDllMain).
+		return false
+	}
+	n := 0
+	for _, imp := range file.Imps {
+		n += len(imp.Names())
+	}
+	if n > TinyImports {
+		return false
+	}
+	if n == 0 && file.OptHdr.EntryRelAddr == 0 {
+		// Resource-only DLL.
+		return false
+	}
+	report.addFinding(FindingTinyImports, -1, "import table has only %d imported functions from %d DLLs", n, len(file.Imps))
+	return true
+}
+
+// addFinding adds a finding of the given kind and section index, with a
+// description according to the given format specifier.
+func (report *Report) addFinding(kind FindingKind, sectIndex int, format string, a ...interface{}) {
+	finding := Finding{
+		Kind:      kind,
+		SectIndex: sectIndex,
+		Desc:      fmt.Sprintf(format, a...),
+	}
+	report.Findings = append(report.Findings, finding)
+}
+
+// contains reports whether the given section contains the given relative
+// address (relative to image base).
+func contains(sectHdr pe.SectionHeader, relAddr uint32) bool {
+	size := sectHdr.VirtualSize
+	if size == 0 {
+		size = sectHdr.DataSize
+	}
+	return sectHdr.RelAddr <= relAddr && uint64(relAddr) < uint64(sectHdr.RelAddr)+uint64(size)
+}
@@ -0,0 +1,284 @@
+package heuristics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mewmew/pe"
+	"github.com/mewmew/pe/enum"
+)
+
+// testSection is a section of a PE file constructed for testing.
+type testSection struct {
+	// Section name.
+	name string
+	// Section flags.
+	flags enum.SectionFlag
+	// Section contents.
+	data []byte
+}
+
+// Section flags of test sections.
+const (
+	code = enum.SectionFlagContainsCode | enum.SectionFlagMemExecute | enum.SectionFlagMemRead
+	data = enum.SectionFlagContainsInitializedData | enum.SectionFlagMemRead | enum.SectionFlagMemWrite
+)
+
+// newFile returns a PE file with the given sections, with the entry point at
+// the start of the section with the given index, and the given number of
+// imported functions. The headers contain the given data.
+func newFile(sects []testSection, entrySect, nimps int, hdrData []byte) *pe.File {
+	const (
+		hdrSize   = 0x400
+		sectAlign = 0x1000
+	)
+	content := make([]byte, hdrSize)
+	copy(content[0x200:], hdrData)
+	file := &pe.File{
+		OptHdr: &pe.OptHeader{},
+	}
+	for i, sect := range sects {
+		sectHdr := pe.SectionHeader{
+			Name:        sect.name,
+			VirtualSize: uint32(len(sect.data)),
+			RelAddr:     uint32(sectAlign * (i + 1)),
+			DataSize:    uint32(len(sect.data)),
+			DataOffset:  uint32(len(content)),
+			Flags:       sect.flags,
+		}
+		content = append(content, sect.data...)
+		file.SectHdrs = append(file.SectHdrs, sectHdr)
+	}
+	if entrySect >= 0 {
+		file.OptHdr.EntryRelAddr = file.SectHdrs[entrySect].RelAddr
+	}
+	if nimps > 0 {
+		imp := pe.ImportEntry{
+			ImpDir: pe.ImportDirectory{Name: "kernel32.dll"},
+			INTs:   make([]pe.INTEntry, nimps),
+		}
+		file.Imps = append(file.Imps, imp)
+	}
+	file.Content = content
+	return file
+}
+
+func TestDetectPackers(t *testing.T) {
+	plain := bytes.Repeat([]byte{0x90}, 0x200)
+	golden := []struct {
+		name  string
+		file  *pe.File
+		want  string
+		conf  float64
+		nsigs int
+	}{
+		{
+			name: "none",
+			file: newFile([]testSection{{name: ".text", flags: code, data: plain}}, 0, 20, nil),
+		},
+		{
+			name:  "UPX0",
+			file:  newFile([]testSection{{name: "UPX0", flags: code, data: plain}}, 0, 20, nil),
+			want:  "UPX",
+			conf:  0.4,
+			nsigs: 1,
+		},
+		{
+			// 1 - (1-0.4)*(1-0.4)
+			name: "UPX0 and UPX1",
+			file: newFile([]testSection{
+				{name: "UPX0", flags: code, data: plain},
+				{name: "UPX1", flags: code, data: plain},
+			}, 0, 20, nil),
+			want:  "UPX",
+			conf:  0.64,
+			nsigs: 2,
+		},
+		{
+			// 1 - (1-0.4)*(1-0.4)*(1-0.6)
+			name: "UPX0, UPX1 and UPX! header",
+			file: newFile([]testSection{
+				{name: "UPX0", flags: code, data: plain},
+				{name: "UPX1", flags: code, data: plain},
+			}, 0, 20, []byte("3.96\x00UPX!")),
+			want:  "UPX",
+			conf:  0.856,
+			nsigs: 3,
+		},
+		{
+			// Renamed sections; x86 entry point stub only.
+			name:  "UPX entry point stub",
+			file:  newFile([]testSection{{name: ".text", flags: code, data: append([]byte{0x60, 0xBE, 0, 0x10, 0, 0, 0x8D, 0xBE, 0, 0xF0, 0xFF, 0xFF}, plain...)}}, 0, 20, nil),
+			want:  "UPX",
+			conf:  0.5,
+			nsigs: 1,
+		},
+		{
+			// 1 - (1-0.8)*(1-0.4)
+			name: "Themida",
+			file: newFile([]testSection{
+				{name: "", flags: code, data: plain},
+				{name: "  ", flags: data, data: plain},
+				{name: ".themida", flags: code, data: plain},
+			}, 0, 20, nil),
+			want:  "Themida",
+			conf:  0.88,
+			nsigs: 2,
+		},
+	}
+	for _, g := range golden {
+		packers := detectPackers(g.file)
+		if len(g.want) == 0 {
+			if len(packers) != 0 {
+				t.Errorf("%s: unexpected packers %v", g.name, packers)
+			}
+			continue
+		}
+		if len(packers) == 0 {
+			t.Errorf("%s: expected packer %q, got none", g.name, g.want)
+			continue
+		}
+		got := packers[0]
+		if got.Name != g.want {
+			t.Errorf("%s: packer mismatch; expected %q, got %q", g.name, g.want, got.Name)
+		}
+		if !approxEqual(got.Confidence, g.conf) {
+			t.Errorf("%s: confidence mismatch; expected %v, got %v", g.name, g.conf, got.Confidence)
+		}
+		if len(got.Evidence) != g.nsigs {
+			t.Errorf("%s: number of matching signatures mismatch; expected %d, got %d (%v)", g.name, g.nsigs, len(got.Evidence), got.Evidence)
+		}
+	}
+}
+
+func TestAnalyzeVerdict(t *testing.T) {
+	// Low entropy code.
+	plain := bytes.Repeat([]byte{0x90}, 0x200)
+	// High entropy (compressed) code.
+	packed := uniform(2)
+	golden := []struct {
+		name  string
+		file  *pe.File
+		want  Verdict
+		kinds []FindingKind
+	}{
+		{
+			name: "clean",
+			file: newFile([]testSection{
+				{name: ".text", flags: code, data: plain},
+				{name: ".data", flags: data, data: plain},
+			}, 0, 20, nil),
+			want: VerdictClean,
+		},
+		{
+			name: "writable and executable",
+			file: newFile([]testSection{
+				{name: ".text", flags: code | enum.SectionFlagMemWrite, data: plain},
+			}, 0, 20, nil),
+			want:  VerdictSuspicious,
+			kinds: []FindingKind{FindingWritableExecutable},
+		},
+		{
+			name: "entry point outside first code section",
+			file: newFile([]testSection{
+				{name: ".text", flags: code, data: plain},
+				{name: ".text2", flags: code, data: plain},
+			}, 1, 20, nil),
+			want:  VerdictSuspicious,
+			kinds: []FindingKind{FindingEntryPointOutsideCode},
+		},
+		{
+			// High entropy code alone is not sufficient.
+			name: "high entropy",
+			file: newFile([]testSection{
+				{name: ".text", flags: code, data: packed},
+			}, 0, 20, nil),
+			want:  VerdictSuspicious,
+			kinds: []FindingKind{FindingHighEntropy},
+		},
+		{
+			// Tiny import table alone is not sufficient.
+			name: "tiny imports",
+			file: newFile([]testSection{
+				{name: ".text", flags: code, data: plain},
+			}, 0, 3, nil),
+			want:  VerdictSuspicious,
+			kinds: []FindingKind{FindingTinyImports},
+		},
+		{
+			// Unknown packer; high entropy code and tiny import table.
+			name: "high entropy and tiny imports",
+			file: newFile([]testSection{
+				{name: ".text", flags: code, data: packed},
+			}, 0, 3, nil),
+			want:  VerdictPacked,
+			kinds: []FindingKind{FindingHighEntropy, FindingTinyImports},
+		},
+		{
+			// Signature below the confidence threshold of 0.5.
+			name: "UPX0 section name",
+			file: newFile([]testSection{
+				{name: "UPX0", flags: code, data: plain},
+			}, 0, 20, nil),
+			want:  VerdictSuspicious,
+			kinds: []FindingKind{FindingPackerSignature},
+		},
+		{
+			name: "UPX section names",
+			file: newFile([]testSection{
+				{name: "UPX0", flags: code | enum.SectionFlagMemWrite, data: nil},
+				{name: "UPX1", flags: code | enum.SectionFlagMemWrite, data: packed},
+			}, 1, 3, []byte("UPX!")),
+			want: VerdictPacked,
+			kinds: []FindingKind{
+				// UPX0.
+				FindingWritableExecutable,
+				// UPX1.
+				FindingHighEntropy,
+				FindingWritableExecutable,
+				FindingEntryPointOutsideCode,
+				FindingTinyImports,
+				FindingPackerSignature,
+				FindingPackerSignature,
+				FindingPackerSignature,
+			},
+		},
+		{
+			// Resource-only DLL; no entry point and no imports.
+			name: "resource-only DLL",
+			file: newFile([]testSection{
+				{name: ".rsrc", flags: data &^ enum.SectionFlagMemWrite, data: plain},
+			}, -1, 0, nil),
+			want: VerdictClean,
+		},
+	}
+	for _, g := range golden {
+		report := Analyze(g.file)
+		if report.Verdict != g.want {
+			t.Errorf("%s: verdict mismatch; expected %v, got %v (findings: %v)", g.name, g.want, report.Verdict, report.Findings)
+		}
+		var kinds []FindingKind
+		for _, finding := range report.Findings {
+			kinds = append(kinds, finding.Kind)
+		}
+		if !equalKinds(kinds, g.kinds) {
+			t.Errorf("%s: findings mismatch; expected %v, got %v", g.name, g.kinds, kinds)
+		}
+		if len(report.Sections) != len(g.file.SectHdrs) {
+			t.Errorf("%s: number of section statistics mismatch; expected %d, got %d", g.name, len(g.file.SectHdrs), len(report.Sections))
+		}
+	}
+}
+
+// equalKinds reports whether the given lists of finding kinds are equal.
+func equalKinds(a, b []FindingKind) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
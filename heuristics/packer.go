@@ -0,0 +1,175 @@
+package heuristics
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mewmew/pe"
+)
+
+// --- [ Packer signatures ] ---------------------------------------------------
+
+// PackerMatch is a known packer detected in a PE file.
+type PackerMatch struct {
+	// Packer name.
+	Name string
+	// Confidence of detection, between 0 and 1; combined from the confidence
+	// of each matching signature.
+	Confidence float64
+	// Descriptions of matching signatures.
+	Evidence []string
+}
+
+// packerSig is a signature of a packer.
+type packerSig struct {
+	// Packer name.
+	packer string
+	// Description of signature.
+	desc string
+	// Confidence of signature, between 0 and 1.
+	confidence float64
+	// match reports whether the signature matches the given PE file.
+	match func(file *pe.File) bool
+}
+
+// packerSigs specifies the signatures of known packers.
+var packerSigs = []packerSig{
+	// UPX.
+	{packer: "UPX", desc: `section named "UPX0"`, confidence: 0.4, match: hasSection("UPX0")},
+	{packer: "UPX", desc: `section named "UPX1"`, confidence: 0.4, match: hasSection("UPX1")},
+	{packer: "UPX", desc: `"UPX!" header`, confidence: 0.6, match: headersContain([]byte("UPX!"))},
+	{packer: "UPX", desc: "x86 entry point stub", confidence: 0.5, match: entryPointMatches("60 BE ?? ?? ?? ?? 8D BE ?? ?? ?? ??")},
+	{packer: "UPX", desc: "x64 entry point stub", confidence: 0.5, match: entryPointMatches("53 56 57 55 48 8D 35 ?? ?? ?? ?? 48 8D BE")},
+	// MPRESS.
+	{packer: "MPRESS", desc: `section named ".MPRESS1"`, confidence: 0.6, match: hasSection(".MPRESS1")},
+	{packer: "MPRESS", desc: `section named ".MPRESS2"`, confidence: 0.5, match: hasSection(".MPRESS2")},
+	{packer: "MPRESS", desc: "x86 entry point stub", confidence: 0.3, match: entryPointMatches("60 E8 00 00 00 00 58 05")},
+	// ASPack.
+	{packer: "ASPack", desc: `section named ".aspack"`, confidence: 0.7, match: hasSection(".aspack")},
+	{packer: "ASPack", desc: `section named ".adata"`, confidence: 0.3, match: hasSection(".adata")},
+	{packer: "ASPack", desc: "entry point stub", confidence: 0.6, match: entryPointMatches("60 E8 03 00 00 00 E9 EB")},
+	// Themida and WinLicense.
+	{packer: "Themida", desc: `section named ".themida"`, confidence: 0.8, match: hasSection(".themida")},
+	{packer: "Themida", desc: `section named ".winlice"`, confidence: 0.8, match: hasSection(".winlice")},
+	{packer: "Themida", desc: "multiple sections with blank names", confidence: 0.4, match: hasBlankSections(2)},
+}
+
+// detectPackers returns the known packers detected in the given PE file, in
+// order of decreasing confidence.
+func detectPackers(file *pe.File) []PackerMatch {
+	var packers []PackerMatch
+	index := make(map[string]int)
+	for _, sig := range packerSigs {
+		if !sig.match(file) {
+			continue
+		}
+		i, ok := index[sig.packer]
+		if !ok {
+			i = len(packers)
+			index[sig.packer] = i
+			packers = append(packers, PackerMatch{Name: sig.packer})
+		}
+		p := &packers[i]
+		// Combine confidence of independent signatures.
+		p.Confidence = 1 - (1-p.Confidence)*(1-sig.confidence)
+		p.Evidence = append(p.Evidence, sig.desc)
+	}
+	sort.SliceStable(packers, func(i, j int) bool {
+		return packers[i].Confidence > packers[j].Confidence
+	})
+	return packers
+}
+
+// hasSection returns a function which reports whether the PE file has a section
+// with the given name.
+func hasSection(name string) func(file *pe.File) bool {
+	return func(file *pe.File) bool {
+		for _, sectHdr := range file.SectHdrs {
+			if sectHdr.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// hasBlankSections returns a function which reports whether the PE file has at
+// least n sections with blank (empty or all space) names.
+func hasBlankSections(n int) func(file *pe.File) bool {
+	return func(file *pe.File) bool {
+		count := 0
+		for _, sectHdr := range file.SectHdrs {
+			if len(strings.TrimSpace(sectHdr.Name)) == 0 {
+				count++
+			}
+		}
+		return count >= n
+	}
+}
+
+// headersContain returns a function which reports whether the headers of the PE
+// file (i.e. the contents preceding the raw data of the first section) contain
+// the given byte sequence.
+func headersContain(s []byte) func(file *pe.File) bool {
+	return func(file *pe.File) bool {
+		end := uint64(len(file.Content))
+		for _, sectHdr := range file.SectHdrs {
+			if sectHdr.DataOffset != 0 && uint64(sectHdr.DataOffset) < end {
+				end = uint64(sectHdr.DataOffset)
+			}
+		}
+		return bytes.Contains(file.Content[:end], s)
+	}
+}
+
+// entryPointMatches returns a function which reports whether the code at the
+// entry point of the PE image matches the given byte pattern; specified as
+// space-separated hexadecimal bytes, with "??" denoting any byte.
+func entryPointMatches(pattern string) func(file *pe.File) bool {
+	var pat []int
+	for _, s := range strings.Fields(pattern) {
+		if s == "??" {
+			pat = append(pat, -1)
+			continue
+		}
+		b, err := hex.DecodeString(s)
+		if err != nil || len(b) != 1 {
+			panic(fmt.Errorf("invalid byte %q of pattern %q", s, pattern))
+		}
+		pat = append(pat, int(b[0]))
+	}
+	return func(file *pe.File) bool {
+		code := entryPointCode(file)
+		if len(code) < len(pat) {
+			return false
+		}
+		for i, b := range pat {
+			if b != -1 && int(code[i]) != b {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// entryPointCode returns the raw data of the PE image starting at the entry
+// point; or nil if not present.
+func entryPointCode(file *pe.File) []byte {
+	if file.OptHdr == nil || file.OptHdr.EntryRelAddr == 0 {
+		return nil
+	}
+	entry := file.OptHdr.EntryRelAddr
+	for _, sectHdr := range file.SectHdrs {
+		if sectHdr.RelAddr <= entry && uint64(entry) < uint64(sectHdr.RelAddr)+uint64(sectHdr.DataSize) {
+			data, err := file.SectionData(sectHdr)
+			if err != nil || uint64(entry-sectHdr.RelAddr) >= uint64(len(data)) {
+				return nil
+			}
+			return data[entry-sectHdr.RelAddr:]
+		}
+	}
+	return nil
+}
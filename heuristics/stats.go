@@ -0,0 +1,53 @@
+package heuristics
+
+import "math"
+
+// --- [ Byte statistics ] -----------------------------------------------------
+
+// Entropy returns the Shannon entropy of the given data, in bits per byte
+// (between 0 and 8). Compressed and encrypted data has an entropy close to 8.
+func Entropy(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+	freqs := byteFreqs(data)
+	n := float64(len(data))
+	var entropy float64
+	for _, freq := range freqs {
+		if freq == 0 {
+			continue
+		}
+		p := float64(freq) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// ChiSquared returns the chi-squared statistic of the byte distribution of the
+// given data, compared to a uniform distribution. Encrypted data has a low
+// chi-squared value (close to 255 degrees of freedom), while compressed data
+// has a somewhat higher value and plain code and data a considerably higher
+// value.
+func ChiSquared(data []byte) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+	freqs := byteFreqs(data)
+	expected := float64(len(data)) / 256
+	var chi2 float64
+	for _, freq := range freqs {
+		d := float64(freq) - expected
+		chi2 += d * d / expected
+	}
+	return chi2
+}
+
+// byteFreqs returns the number of occurrences of each byte value in the given
+// data.
+func byteFreqs(data []byte) [256]int {
+	var freqs [256]int
+	for _, b := range data {
+		freqs[b]++
+	}
+	return freqs
+}
@@ -0,0 +1,64 @@
+package heuristics
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestEntropy(t *testing.T) {
+	golden := []struct {
+		name       string
+		data       []byte
+		entropy    float64
+		chiSquared float64
+	}{
+		{name: "empty", data: nil, entropy: 0, chiSquared: 0},
+		// Constant data; all 1024 bytes in a single bin, with an expected
+		// count of 4 per bin: (1024-4)^2/4 + 255*4^2/4 = 1024*255.
+		{name: "constant", data: bytes.Repeat([]byte{0x90}, 1024), entropy: 0, chiSquared: 1024 * 255},
+		// Uniform distribution of byte values.
+		{name: "uniform", data: uniform(4), entropy: 8, chiSquared: 0},
+		// Two alternating byte values: 2*508^2/4 + 254*4^2/4.
+		{name: "two values", data: bytes.Repeat([]byte{0x00, 0xFF}, 512), entropy: 1, chiSquared: 130048},
+		// Four byte values with probabilities 1/2, 1/4, 1/8 and 1/8, with an
+		// expected count of 2 per bin: (254^2 + 126^2 + 2*62^2 + 252*2^2)/2.
+		{name: "skewed", data: skewed(), entropy: 1.75, chiSquared: 44544},
+	}
+	for _, g := range golden {
+		if got := Entropy(g.data); !approxEqual(got, g.entropy) {
+			t.Errorf("%s: entropy mismatch; expected %v, got %v", g.name, g.entropy, got)
+		}
+		if got := ChiSquared(g.data); !approxEqual(got, g.chiSquared) {
+			t.Errorf("%s: chi-squared mismatch; expected %v, got %v", g.name, g.chiSquared, got)
+		}
+	}
+}
+
+// uniform returns data containing each byte value n times.
+func uniform(n int) []byte {
+	var data []byte
+	for i := 0; i < n; i++ {
+		for b := 0; b < 256; b++ {
+			data = append(data, byte(b))
+		}
+	}
+	return data
+}
+
+// skewed returns 512 bytes of four byte values with probabilities 1/2, 1/4,
+// 1/8 and 1/8.
+func skewed() []byte {
+	var data []byte
+	data = append(data, bytes.Repeat([]byte{'a'}, 256)...)
+	data = append(data, bytes.Repeat([]byte{'b'}, 128)...)
+	data = append(data, bytes.Repeat([]byte{'c'}, 64)...)
+	data = append(data, bytes.Repeat([]byte{'d'}, 64)...)
+	return data
+}
+
+// approxEqual reports whether the given floating-point values are
+// approximately equal.
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
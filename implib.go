@@ -0,0 +1,432 @@
+package pe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/mewmew/pe/enum"
+	"github.com/mewmew/pe/internal/pe"
+	"github.com/pkg/errors"
+)
+
+// --- [ Import library ] ------------------------------------------------------
+
+// libExport is an export of a DLL, as described by module-definition files and
+// import libraries.
+type libExport struct {
+	// Export name; synthesized for exports without a name (see noNameFormat).
+	Name string
+	// Ordinal number of export.
+	Ordinal uint16
+	// Exported by ordinal only.
+	NoName bool
+	// Data export.
+	Data bool
+	// Forwarder string of forwarded exports; empty otherwise.
+	Forwarder string
+}
+
+// noNameFormat is the format of names synthesized for exports without a name
+// (NONAME), given the ordinal number of the export.
+const noNameFormat = "Ordinal%d"
+
+// libExports returns the exports of the DLL, in order of increasing ordinal.
+//
+// Exports within sections that are not executable are considered data exports.
+func (file *File) libExports() ([]libExport, error) {
+	if file.ExpDir == nil {
+		return nil, errors.New("unable to locate export table")
+	}
+	var exps []libExport
+	for _, exp := range file.Exps {
+		if exp.Ordinal > math.MaxUint16 {
+			return nil, errors.Errorf("invalid ordinal of export %q; expected <= %d, got %d", exp.Name, math.MaxUint16, exp.Ordinal)
+		}
+		e := libExport{
+			Name:      exp.Name,
+			Ordinal:   uint16(exp.Ordinal),
+			Forwarder: exp.Forwarder,
+		}
+		if len(e.Name) == 0 {
+			e.Name = fmt.Sprintf(noNameFormat, exp.Ordinal)
+			e.NoName = true
+		}
+		if len(e.Forwarder) == 0 {
+			for _, sectHdr := range file.SectHdrs {
+				if sectHdr.RelAddr <= exp.RelAddr && uint64(exp.RelAddr) < uint64(sectHdr.RelAddr)+uint64(sectHdr.VirtualSize) {
+					e.Data = sectHdr.Flags&enum.SectionFlagMemExecute == 0
+					break
+				}
+			}
+		}
+		exps = append(exps, e)
+	}
+	return exps, nil
+}
+
+// WriteDef writes a module-definition (.def) file describing the exports of the
+// DLL to w, as accepted by lib.exe /def and dlltool.
+//
+// Exports without a name are given a synthesized name and marked NONAME, and
+// exports within sections that are not executable are marked DATA.
+func (file *File) WriteDef(w io.Writer) error {
+	exps, err := file.libExports()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "LIBRARY \"%s\"\n", file.ExpDir.Name)
+	fmt.Fprintln(buf, "EXPORTS")
+	for _, exp := range exps {
+		fmt.Fprintf(buf, "    %s", exp.Name)
+		if len(exp.Forwarder) > 0 {
+			fmt.Fprintf(buf, " = %s", exp.Forwarder)
+		}
+		fmt.Fprintf(buf, " @%d", exp.Ordinal)
+		if exp.NoName {
+			fmt.Fprint(buf, " NONAME")
+		}
+		if exp.Data {
+			fmt.Fprint(buf, " DATA")
+		}
+		fmt.Fprintln(buf)
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// WriteImportLib writes a COFF import library for the exports of the DLL to w,
+// as produced by lib.exe /def; i.e. an import descriptor, a NULL import
+// descriptor and a NULL thunk member, followed by one short import library
+// member per export.
+func (file *File) WriteImportLib(w io.Writer) error {
+	exps, err := file.libExports()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	lib := &importLib{
+		dllName: file.ExpDir.Name,
+		machine: file.FileHdr.Machine,
+	}
+	members := []archiveEntry{
+		lib.importDescriptor(),
+		lib.nullImportDescriptor(),
+		lib.nullThunk(),
+	}
+	for _, exp := range exps {
+		members = append(members, lib.shortImport(exp))
+	}
+	if err := writeArchive(w, members); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// importLib is an import library writer of a given DLL.
+type importLib struct {
+	// DLL name.
+	dllName string
+	// Target CPU type.
+	machine enum.MachineType
+}
+
+// libName returns the DLL name without extension.
+func (lib *importLib) libName() string {
+	return strings.TrimSuffix(lib.dllName, path.Ext(lib.dllName))
+}
+
+// is32bit reports whether the target CPU type is a 32-bit architecture.
+func (lib *importLib) is32bit() bool {
+	switch lib.machine {
+	case enum.MachineTypeI386, enum.MachineTypeARMNT:
+		return true
+	default:
+		return false
+	}
+}
+
+// characteristics returns the COFF file header characteristics of import
+// library objects.
+func (lib *importLib) characteristics() enum.Characteristic {
+	if lib.is32bit() {
+		return enum.Characteristic32bitMachine
+	}
+	return 0
+}
+
+// imageRelReloc returns the relocation type of 32-bit relative addresses
+// (relative to image base).
+func (lib *importLib) imageRelReloc() uint16 {
+	switch lib.machine {
+	case enum.MachineTypeI386:
+		return uint16(enum.RelocTypeI386Dir32NB)
+	case enum.MachineTypeARMNT:
+		return uint16(enum.RelocTypeARMAddr32NB)
+	case enum.MachineTypeARM64:
+		return uint16(enum.RelocTypeARM64Addr32NB)
+	default:
+		return uint16(enum.RelocTypeAMD64Addr32NB)
+	}
+}
+
+// Symbol names of import library objects.
+const (
+	importDescriptorPrefix     = "__IMPORT_DESCRIPTOR_"
+	nullImportDescriptorSymbol = "__NULL_IMPORT_DESCRIPTOR"
+	nullThunkDataSuffix        = "_NULL_THUNK_DATA"
+	importPrefix               = "__imp_"
+)
+
+// importDescriptor returns the import descriptor member of the import library,
+// containing the import directory entry (.idata$2) and DLL name (.idata$6).
+func (lib *importLib) importDescriptor() archiveEntry {
+	const (
+		nsects  = 2
+		nrelocs = 3
+		nsyms   = 7
+	)
+	importDescriptorSymbol := importDescriptorPrefix + lib.libName()
+	nullThunkSymbol := "\x7F" + lib.libName() + nullThunkDataSuffix
+	obj := &objWriter{}
+	impDirSize := uint32(binary.Size(pe.RawImportDirectory{}))
+	impDirOffset := uint32(fileHeaderSize + nsects*sectionHeaderSize)
+	relocsOffset := impDirOffset + impDirSize
+	nameOffset := relocsOffset + nrelocs*relocSize
+	nameSize := uint32(len(lib.dllName) + 1)
+	obj.write(pe.RawFileHeader{
+		Machine:           lib.machine,
+		NSections:         nsects,
+		SymbolTableOffset: nameOffset + nameSize,
+		NSymbols:          nsyms,
+		Characteristics:   lib.characteristics(),
+	})
+	obj.write(pe.RawSectionHeader{
+		Name:         sectionName(".idata$2"),
+		DataSize:     impDirSize,
+		DataOffset:   impDirOffset,
+		RelocsOffset: relocsOffset,
+		NRelocs:      nrelocs,
+		Flags:        enum.SectionFlagAlign4 | enum.SectionFlagContainsInitializedData | enum.SectionFlagMemRead | enum.SectionFlagMemWrite,
+	})
+	obj.write(pe.RawSectionHeader{
+		Name:       sectionName(".idata$6"),
+		DataSize:   nameSize,
+		DataOffset: nameOffset,
+		Flags:      enum.SectionFlagAlign2 | enum.SectionFlagContainsInitializedData | enum.SectionFlagMemRead | enum.SectionFlagMemWrite,
+	})
+	// .idata$2
+	obj.write(pe.RawImportDirectory{})
+	// Relocations of the DLL name, INT and IAT of the import directory entry;
+	// referring to the .idata$6, .idata$4 and .idata$5 symbols.
+	obj.write(pe.RawReloc{Offset: 0x0C, SymbolIndex: 2, Type: lib.imageRelReloc()})
+	obj.write(pe.RawReloc{Offset: 0x00, SymbolIndex: 3, Type: lib.imageRelReloc()})
+	obj.write(pe.RawReloc{Offset: 0x10, SymbolIndex: 4, Type: lib.imageRelReloc()})
+	// .idata$6
+	obj.writeString(lib.dllName)
+	obj.writeSymbol(importDescriptorSymbol, 1, enum.StorageClassExternal)
+	obj.writeSymbol(".idata$2", 1, enum.StorageClassSection)
+	obj.writeSymbol(".idata$6", 2, enum.StorageClassStatic)
+	obj.writeSymbol(".idata$4", 0, enum.StorageClassSection)
+	obj.writeSymbol(".idata$5", 0, enum.StorageClassSection)
+	obj.writeSymbol(nullImportDescriptorSymbol, 0, enum.StorageClassExternal)
+	obj.writeSymbol(nullThunkSymbol, 0, enum.StorageClassExternal)
+	return archiveEntry{
+		name: lib.dllName,
+		data: obj.bytes(),
+		syms: []string{importDescriptorSymbol},
+	}
+}
+
+// nullImportDescriptor returns the NULL import descriptor member of the import
+// library, containing the terminating import directory entry (.idata$3).
+func (lib *importLib) nullImportDescriptor() archiveEntry {
+	const nsects = 1
+	obj := &objWriter{}
+	impDirSize := uint32(binary.Size(pe.RawImportDirectory{}))
+	impDirOffset := uint32(fileHeaderSize + nsects*sectionHeaderSize)
+	obj.write(pe.RawFileHeader{
+		Machine:           lib.machine,
+		NSections:         nsects,
+		SymbolTableOffset: impDirOffset + impDirSize,
+		NSymbols:          1,
+		Characteristics:   lib.characteristics(),
+	})
+	obj.write(pe.RawSectionHeader{
+		Name:       sectionName(".idata$3"),
+		DataSize:   impDirSize,
+		DataOffset: impDirOffset,
+		Flags:      enum.SectionFlagAlign4 | enum.SectionFlagContainsInitializedData | enum.SectionFlagMemRead | enum.SectionFlagMemWrite,
+	})
+	// .idata$3
+	obj.write(pe.RawImportDirectory{})
+	obj.writeSymbol(nullImportDescriptorSymbol, 1, enum.StorageClassExternal)
+	return archiveEntry{
+		name: lib.dllName,
+		data: obj.bytes(),
+		syms: []string{nullImportDescriptorSymbol},
+	}
+}
+
+// nullThunk returns the NULL thunk member of the import library, containing the
+// terminating IAT (.idata$5) and INT (.idata$4) entries.
+func (lib *importLib) nullThunk() archiveEntry {
+	const nsects = 2
+	nullThunkSymbol := "\x7F" + lib.libName() + nullThunkDataSuffix
+	obj := &objWriter{}
+	thunkSize := uint32(8)
+	flags := enum.SectionFlagAlign8
+	if lib.is32bit() {
+		thunkSize = 4
+		flags = enum.SectionFlagAlign4
+	}
+	flags |= enum.SectionFlagContainsInitializedData | enum.SectionFlagMemRead | enum.SectionFlagMemWrite
+	iatOffset := uint32(fileHeaderSize + nsects*sectionHeaderSize)
+	intOffset := iatOffset + thunkSize
+	obj.write(pe.RawFileHeader{
+		Machine:           lib.machine,
+		NSections:         nsects,
+		SymbolTableOffset: intOffset + thunkSize,
+		NSymbols:          1,
+		Characteristics:   lib.characteristics(),
+	})
+	obj.write(pe.RawSectionHeader{
+		Name:       sectionName(".idata$5"),
+		DataSize:   thunkSize,
+		DataOffset: iatOffset,
+		Flags:      flags,
+	})
+	obj.write(pe.RawSectionHeader{
+		Name:       sectionName(".idata$4"),
+		DataSize:   thunkSize,
+		DataOffset: intOffset,
+		Flags:      flags,
+	})
+	// .idata$5 and .idata$4
+	obj.write(make([]byte, 2*thunkSize))
+	obj.writeSymbol(nullThunkSymbol, 1, enum.StorageClassExternal)
+	return archiveEntry{
+		name: lib.dllName,
+		data: obj.bytes(),
+		syms: []string{nullThunkSymbol},
+	}
+}
+
+// shortImport returns the short import library member of the given export.
+func (lib *importLib) shortImport(exp libExport) archiveEntry {
+	sym := exp.Name
+	nameType := enum.ImportNameTypeName
+	// C symbols of 32-bit x86 are decorated with a leading underscore; C++
+	// symbols are already decorated.
+	if lib.machine == enum.MachineTypeI386 && !strings.HasPrefix(sym, "?") {
+		sym = "_" + sym
+		nameType = enum.ImportNameTypeNameUndecorate
+	}
+	if exp.NoName {
+		nameType = enum.ImportNameTypeOrdinal
+	}
+	typ := enum.ImportTypeCode
+	if exp.Data {
+		typ = enum.ImportTypeData
+	}
+	buf := &bytes.Buffer{}
+	dataSize := uint32(len(sym) + 1 + len(lib.dllName) + 1)
+	raw := pe.RawImportObjectHeader{
+		Sig1:          uint16(enum.MachineTypeUnknown),
+		Sig2:          0xFFFF,
+		Machine:       lib.machine,
+		DataSize:      dataSize,
+		OrdinalOrHint: exp.Ordinal,
+		Types:         uint16(nameType)<<2 | uint16(typ),
+	}
+	binary.Write(buf, binary.LittleEndian, raw)
+	buf.WriteString(sym)
+	buf.WriteByte(0)
+	buf.WriteString(lib.dllName)
+	buf.WriteByte(0)
+	// Data imports are only accessible through the import address table.
+	syms := []string{importPrefix + sym}
+	if !exp.Data {
+		syms = append(syms, sym)
+	}
+	return archiveEntry{
+		name: lib.dllName,
+		data: buf.Bytes(),
+		syms: syms,
+	}
+}
+
+// ~~~ [ COFF object writer ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// Sizes in bytes of COFF headers.
+const (
+	fileHeaderSize    = 20
+	sectionHeaderSize = 40
+)
+
+// objWriter is a writer of COFF object files. The symbol table is buffered and
+// written after the section contents.
+type objWriter struct {
+	// Object file contents, excluding symbol table and string table.
+	buf bytes.Buffer
+	// Symbol table.
+	syms bytes.Buffer
+	// String table, excluding the leading 4-byte size field.
+	strs bytes.Buffer
+}
+
+// write writes the given raw structure to the object file.
+func (obj *objWriter) write(v interface{}) {
+	// Writes to bytes.Buffer never fail.
+	binary.Write(&obj.buf, binary.LittleEndian, v)
+}
+
+// writeString writes the given NULL-terminated string to the object file.
+func (obj *objWriter) writeString(s string) {
+	obj.buf.WriteString(s)
+	obj.buf.WriteByte(0)
+}
+
+// writeSymbol adds a symbol with the given name, 1-based section index and
+// storage class to the symbol table.
+func (obj *objWriter) writeSymbol(name string, sectNum int16, storageClass enum.StorageClass) {
+	raw := pe.RawSymbol{
+		SectNum:      sectNum,
+		StorageClass: storageClass,
+	}
+	if len(name) <= len(raw.Name) {
+		copy(raw.Name[:], name)
+	} else {
+		// Long names are stored as four zero bytes followed by a string table
+		// offset.
+		offset := uint32(4 + obj.strs.Len())
+		binary.LittleEndian.PutUint32(raw.Name[4:], offset)
+		obj.strs.WriteString(name)
+		obj.strs.WriteByte(0)
+	}
+	binary.Write(&obj.syms, binary.LittleEndian, raw)
+}
+
+// bytes returns the contents of the object file, including symbol table and
+// string table.
+func (obj *objWriter) bytes() []byte {
+	buf := &bytes.Buffer{}
+	buf.Write(obj.buf.Bytes())
+	buf.Write(obj.syms.Bytes())
+	binary.Write(buf, binary.LittleEndian, uint32(4+obj.strs.Len()))
+	buf.Write(obj.strs.Bytes())
+	return buf.Bytes()
+}
+
+// sectionName returns the raw section name of the given short section name.
+func sectionName(name string) [8]byte {
+	var raw [8]byte
+	copy(raw[:], name)
+	return raw
+}
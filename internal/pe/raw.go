@@ -3,6 +3,89 @@ package pe
 
 import "github.com/mewmew/pe/enum"
 
+// RawDOSHeader is an MS-DOS header (in raw format), as located at the start of
+// PE images (IMAGE_DOS_HEADER).
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#ms-dos-stub-image-only
+type RawDOSHeader struct {
+	// Magic number ("MZ").
+	//
+	// offset: 0x0000 (2 bytes)
+	Magic [2]byte
+	// Number of bytes used in the last page of the file.
+	//
+	// offset: 0x0002 (2 bytes)
+	LastPageSize uint16
+	// Number of pages (512 bytes) in the file.
+	//
+	// offset: 0x0004 (2 bytes)
+	NPages uint16
+	// Number of relocation entries.
+	//
+	// offset: 0x0006 (2 bytes)
+	NRelocs uint16
+	// Size of header in number of paragraphs (16 bytes).
+	//
+	// offset: 0x0008 (2 bytes)
+	HeaderParagraphs uint16
+	// Minimum number of extra paragraphs needed.
+	//
+	// offset: 0x000A (2 bytes)
+	MinAlloc uint16
+	// Maximum number of extra paragraphs needed.
+	//
+	// offset: 0x000C (2 bytes)
+	MaxAlloc uint16
+	// Initial (relative) value of the SS register.
+	//
+	// offset: 0x000E (2 bytes)
+	SS uint16
+	// Initial value of the SP register.
+	//
+	// offset: 0x0010 (2 bytes)
+	SP uint16
+	// Checksum.
+	//
+	// offset: 0x0012 (2 bytes)
+	Checksum uint16
+	// Initial value of the IP register.
+	//
+	// offset: 0x0014 (2 bytes)
+	IP uint16
+	// Initial (relative) value of the CS register.
+	//
+	// offset: 0x0016 (2 bytes)
+	CS uint16
+	// File offset of relocation table.
+	//
+	// offset: 0x0018 (2 bytes)
+	RelocTableOffset uint16
+	// Overlay number.
+	//
+	// offset: 0x001A (2 bytes)
+	OverlayNum uint16
+	// Reserved.
+	//
+	// offset: 0x001C (8 bytes)
+	Reserved1 [4]uint16
+	// OEM identifier (for OEMInfo).
+	//
+	// offset: 0x0024 (2 bytes)
+	OEMID uint16
+	// OEM information (specific to OEMID).
+	//
+	// offset: 0x0026 (2 bytes)
+	OEMInfo uint16
+	// Reserved.
+	//
+	// offset: 0x0028 (20 bytes)
+	Reserved2 [10]uint16
+	// File offset of PE signature.
+	//
+	// offset: 0x003C (4 bytes)
+	PEOffset uint32
+}
+
 // RawFileHeader is a COFF file header (in raw format).
 //
 // ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#coff-file-header-object-and-image
@@ -37,6 +120,137 @@ type RawFileHeader struct {
 	Characteristics enum.Characteristic
 }
 
+// --- [ Anonymous object header ] ---------------------------------------------
+
+// RawAnonObjectHeader is the header of an anonymous object file (in raw
+// format); version 1.
+//
+// ref: https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-anon_object_header
+type RawAnonObjectHeader struct {
+	// Signature; must be 0 (IMAGE_FILE_MACHINE_UNKNOWN).
+	//
+	// offset: 0x0000 (2 bytes)
+	Sig1 uint16
+	// Signature; must be 0xFFFF.
+	//
+	// offset: 0x0002 (2 bytes)
+	Sig2 uint16
+	// Header version; at least 1.
+	//
+	// offset: 0x0004 (2 bytes)
+	Version uint16
+	// Target CPU type.
+	//
+	// offset: 0x0006 (2 bytes)
+	Machine enum.MachineType
+	// File creation time, measured in number of seconds since Epoch.
+	//
+	// offset: 0x0008 (4 bytes)
+	Date uint32
+	// Class ID identifying the object format.
+	//
+	// offset: 0x000C (16 bytes)
+	ClassID [16]byte
+	// Size in bytes of data following the header.
+	//
+	// offset: 0x001C (4 bytes)
+	DataSize uint32
+}
+
+// RawAnonObjectHeaderV2 is the header of an anonymous object file (in raw
+// format); version 2.
+//
+// ref: https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-anon_object_header_v2
+type RawAnonObjectHeaderV2 struct {
+	// Version 1 header.
+	//
+	// offset: 0x0000 (32 bytes)
+	RawAnonObjectHeader
+	// Object flags.
+	//
+	// offset: 0x0020 (4 bytes)
+	Flags uint32
+	// Size in bytes of CLR metadata.
+	//
+	// offset: 0x0024 (4 bytes)
+	MetadataSize uint32
+	// File offset of CLR metadata.
+	//
+	// offset: 0x0028 (4 bytes)
+	MetadataOffset uint32
+}
+
+// RawBigObjHeader is the header of a big object file (in raw format); as
+// produced by /bigobj.
+//
+// ref: https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-anon_object_header_bigobj
+type RawBigObjHeader struct {
+	// Version 2 header.
+	//
+	// offset: 0x0000 (44 bytes)
+	RawAnonObjectHeaderV2
+	// Number of sections.
+	//
+	// offset: 0x002C (4 bytes)
+	NSections uint32
+	// File offset of COFF symbol table.
+	//
+	// offset: 0x0030 (4 bytes)
+	SymbolTableOffset uint32
+	// Number of entries in symbol table.
+	//
+	// offset: 0x0034 (4 bytes)
+	NSymbols uint32
+}
+
+// RawImportObjectHeader is the header of a short import library member (in raw
+// format); followed by the NULL-terminated import name and DLL name.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#import-header
+type RawImportObjectHeader struct {
+	// Signature; must be 0 (IMAGE_FILE_MACHINE_UNKNOWN).
+	//
+	// offset: 0x0000 (2 bytes)
+	Sig1 uint16
+	// Signature; must be 0xFFFF.
+	//
+	// offset: 0x0002 (2 bytes)
+	Sig2 uint16
+	// Structure version; must be 0.
+	//
+	// offset: 0x0004 (2 bytes)
+	Version uint16
+	// Target CPU type.
+	//
+	// offset: 0x0006 (2 bytes)
+	Machine enum.MachineType
+	// File creation time, measured in number of seconds since Epoch.
+	//
+	// offset: 0x0008 (4 bytes)
+	Date uint32
+	// Size in bytes of the strings following the header.
+	//
+	// offset: 0x000C (4 bytes)
+	DataSize uint32
+	// Ordinal or hint of the import, as determined by the import name type.
+	//
+	// offset: 0x0010 (2 bytes)
+	OrdinalOrHint uint16
+	// Import type and import name type.
+	//
+	// Bitfield of data:
+	//
+	//    // Import type.
+	//    Type     : 2
+	//    // Import name type.
+	//    NameType : 3
+	//    // Reserved.
+	//    _        : 11
+	//
+	// offset: 0x0012 (2 bytes)
+	Types uint16
+}
+
 // RawOptHeader32 is an optional header of a 32-bit PE file (in raw format).
 //
 // ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#optional-header-image-only
@@ -333,8 +547,328 @@ type RawSectionHeader struct {
 	Flags enum.SectionFlag
 }
 
+// --- [ COFF relocations ] ----------------------------------------------------
+
+// RawReloc is a COFF relocation entry (in raw format).
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#coff-relocations-object-only
+type RawReloc struct {
+	// Offset of the item to which relocation is applied (relative to start of
+	// section in object files).
+	//
+	// offset: 0x0000 (4 bytes)
+	Offset uint32
+	// Symbol table index of the relocation target.
+	//
+	// offset: 0x0004 (4 bytes)
+	SymbolIndex uint32
+	// Machine-specific relocation type.
+	//
+	// offset: 0x0008 (2 bytes)
+	Type uint16
+}
+
+// --- [ COFF line numbers ] ---------------------------------------------------
+
+// RawLineNum is a COFF line number entry (in raw format).
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#coff-line-numbers-deprecated
+type RawLineNum struct {
+	// Symbol table index of function symbol if LineNum is zero; otherwise
+	// relative address of the code corresponding to the source line.
+	//
+	// offset: 0x0000 (4 bytes)
+	Addr uint32
+	// Line number relative to the start of the function (1-based); zero
+	// indicates the start of a function.
+	//
+	// offset: 0x0004 (2 bytes)
+	LineNum uint16
+}
+
+// --- [ COFF symbol table ] ---------------------------------------------------
+
+// RawSymbol is a COFF symbol table entry (in raw format); followed by NAux
+// auxiliary symbol records of the same size.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#coff-symbol-table
+type RawSymbol struct {
+	// Symbol name; either a NULL-padded short name, or four zero bytes followed
+	// by an offset into the string table.
+	//
+	// offset: 0x0000 (8 bytes)
+	Name [8]byte
+	// Symbol value; interpretation depends on section number and storage
+	// class.
+	//
+	// offset: 0x0008 (4 bytes)
+	Value uint32
+	// 1-based section index, or special section number.
+	//
+	// offset: 0x000C (2 bytes)
+	SectNum int16
+	// Symbol type.
+	//
+	// Bitfield of data:
+	//
+	//    // Base type.
+	//    BaseType    : 4
+	//    // Complex type.
+	//    ComplexType : 4
+	//    // Unused.
+	//    _           : 8
+	//
+	// offset: 0x000E (2 bytes)
+	Type uint16
+	// Storage class.
+	//
+	// offset: 0x0010 (1 bytes)
+	StorageClass enum.StorageClass
+	// Number of auxiliary symbol records following the symbol.
+	//
+	// offset: 0x0011 (1 bytes)
+	NAux uint8
+}
+
+// RawSymbolEx is a COFF symbol table entry of a big object file (in raw
+// format); followed by NAux auxiliary symbol records of the same size.
+//
+// ref: https://docs.microsoft.com/en-us/windows/win32/api/winnt/ns-winnt-image_symbol_ex
+type RawSymbolEx struct {
+	// Symbol name; either a NULL-padded short name, or four zero bytes followed
+	// by an offset into the string table.
+	//
+	// offset: 0x0000 (8 bytes)
+	Name [8]byte
+	// Symbol value; interpretation depends on section number and storage
+	// class.
+	//
+	// offset: 0x0008 (4 bytes)
+	Value uint32
+	// 1-based section index, or special section number.
+	//
+	// offset: 0x000C (4 bytes)
+	SectNum int32
+	// Symbol type (see RawSymbol.Type).
+	//
+	// offset: 0x0010 (2 bytes)
+	Type uint16
+	// Storage class.
+	//
+	// offset: 0x0012 (1 byte)
+	StorageClass enum.StorageClass
+	// Number of auxiliary symbol records following the symbol.
+	//
+	// offset: 0x0013 (1 byte)
+	NAux uint8
+}
+
+// ~~~ [ Auxiliary symbol records ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// RawAuxFuncDef is a function definition auxiliary symbol record (in raw
+// format).
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#auxiliary-format-1-function-definitions
+type RawAuxFuncDef struct {
+	// Symbol table index of the corresponding .bf symbol.
+	//
+	// offset: 0x0000 (4 bytes)
+	TagIndex uint32
+	// Size of function code in number of bytes.
+	//
+	// offset: 0x0004 (4 bytes)
+	TotalSize uint32
+	// File offset of first COFF line number entry of the function; zero if
+	// none.
+	//
+	// offset: 0x0008 (4 bytes)
+	LineNumsOffset uint32
+	// Symbol table index of the next function symbol; zero if last.
+	//
+	// offset: 0x000C (4 bytes)
+	NextFunc uint32
+	// Unused.
+	//
+	// offset: 0x0010 (2 bytes)
+	Unused [2]byte
+}
+
+// RawAuxBfEf is a .bf or .ef auxiliary symbol record (in raw format).
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#auxiliary-format-2-bf-and-ef-symbols
+type RawAuxBfEf struct {
+	// Unused.
+	//
+	// offset: 0x0000 (4 bytes)
+	Unused1 [4]byte
+	// Actual ordinal line number (1-based) within the source file.
+	//
+	// offset: 0x0004 (2 bytes)
+	LineNum uint16
+	// Unused.
+	//
+	// offset: 0x0006 (6 bytes)
+	Unused2 [6]byte
+	// Symbol table index of the next .bf symbol; zero if last (only used by
+	// .bf symbols).
+	//
+	// offset: 0x000C (4 bytes)
+	NextFunc uint32
+	// Unused.
+	//
+	// offset: 0x0010 (2 bytes)
+	Unused3 [2]byte
+}
+
+// RawAuxWeakExternal is a weak external auxiliary symbol record (in raw
+// format).
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#auxiliary-format-3-weak-externals
+type RawAuxWeakExternal struct {
+	// Symbol table index of the symbol to be linked if the weak external is not
+	// found.
+	//
+	// offset: 0x0000 (4 bytes)
+	TagIndex uint32
+	// Library search characteristics.
+	//
+	// offset: 0x0004 (4 bytes)
+	Characteristics enum.WeakExternalFlag
+	// Unused.
+	//
+	// offset: 0x0008 (10 bytes)
+	Unused [10]byte
+}
+
+// RawAuxFile is a file name auxiliary symbol record (in raw format); long file
+// names span several consecutive records.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#auxiliary-format-4-files
+type RawAuxFile struct {
+	// Source file name (NULL-padded).
+	//
+	// offset: 0x0000 (18 bytes)
+	Name [18]byte
+}
+
+// RawAuxSectionDef is a section definition auxiliary symbol record (in raw
+// format).
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#auxiliary-format-5-section-definitions
+type RawAuxSectionDef struct {
+	// Size of section data in number of bytes.
+	//
+	// offset: 0x0000 (4 bytes)
+	Length uint32
+	// Number of relocation entries of the section.
+	//
+	// offset: 0x0004 (2 bytes)
+	NRelocs uint16
+	// Number of line number entries of the section.
+	//
+	// offset: 0x0006 (2 bytes)
+	NLineNums uint16
+	// Checksum of section data (used by COMDAT sections).
+	//
+	// offset: 0x0008 (4 bytes)
+	Checksum uint32
+	// 1-based section index of associated section (used by associative COMDAT
+	// sections); low 16 bits.
+	//
+	// offset: 0x000C (2 bytes)
+	Number uint16
+	// COMDAT selection number.
+	//
+	// offset: 0x000E (1 bytes)
+	Selection enum.ComdatSelection
+	// Unused.
+	//
+	// offset: 0x000F (1 bytes)
+	Unused uint8
+	// 1-based section index of associated section; high 16 bits (only used by
+	// bigobj files).
+	//
+	// offset: 0x0010 (2 bytes)
+	NumberHigh uint16
+}
+
+// RawAuxCLRToken is a CLR token definition auxiliary symbol record (in raw
+// format).
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#clr-token-definition-object-only
+type RawAuxCLRToken struct {
+	// Auxiliary type (IMAGE_AUX_SYMBOL_TYPE_TOKEN_DEF).
+	//
+	// offset: 0x0000 (1 bytes)
+	AuxType uint8
+	// Reserved.
+	//
+	// offset: 0x0001 (1 bytes)
+	Reserved1 uint8
+	// Symbol table index of the symbol defined by the CLR token.
+	//
+	// offset: 0x0002 (4 bytes)
+	SymbolIndex uint32
+	// Reserved.
+	//
+	// offset: 0x0006 (12 bytes)
+	Reserved2 [12]byte
+}
+
 // --- [ Data directories ] ----------------------------------------------------
 
+// ~~~ [ 0 - Export Table ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// RawExportDirectory is an export data directory (in raw format).
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#export-directory-table
+type RawExportDirectory struct {
+	// Reserved; must be zero.
+	//
+	// offset: 0x0000 (4 bytes)
+	Characteristics uint32
+	// Export data creation time, measured in number of seconds since Epoch.
+	//
+	// offset: 0x0004 (4 bytes)
+	Date uint32
+	// Major version number.
+	//
+	// offset: 0x0008 (2 bytes)
+	MajorVer uint16
+	// Minor version number.
+	//
+	// offset: 0x000A (2 bytes)
+	MinorVer uint16
+	// Relative address of the DLL name (relative to image base).
+	//
+	// offset: 0x000C (4 bytes)
+	NameRelAddr uint32
+	// Starting ordinal number of exports.
+	//
+	// offset: 0x0010 (4 bytes)
+	OrdinalBase uint32
+	// Number of entries in the export address table (EAT).
+	//
+	// offset: 0x0014 (4 bytes)
+	NFuncs uint32
+	// Number of entries in the export name pointer table and ordinal table.
+	//
+	// offset: 0x0018 (4 bytes)
+	NNames uint32
+	// Relative address of export address table (EAT).
+	//
+	// offset: 0x001C (4 bytes)
+	EATRelAddr uint32
+	// Relative address of export name pointer table.
+	//
+	// offset: 0x0020 (4 bytes)
+	NamePtrsRelAddr uint32
+	// Relative address of export ordinal table.
+	//
+	// offset: 0x0024 (4 bytes)
+	OrdinalsRelAddr uint32
+}
+
 // ~~~ [ 1 - Import Table ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // RawImportDirectory is an import data directory (in raw format). The last
@@ -432,6 +966,27 @@ type RawNameEntry struct {
 	// Zero or one bytes of padding, to make the name entry 2-byte aligned.
 }
 
+// ~~~ [ 4 - Certificate Table ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// RawCertificateHeader is an attribute certificate header (in raw format);
+// followed by the certificate contents.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#the-attribute-certificate-table-image-only
+type RawCertificateHeader struct {
+	// Size of attribute certificate in number of bytes, including header.
+	//
+	// offset: 0x0000 (4 bytes)
+	Length uint32
+	// Certificate revision.
+	//
+	// offset: 0x0004 (2 bytes)
+	Revision enum.CertificateRevision
+	// Certificate type.
+	//
+	// offset: 0x0006 (2 bytes)
+	Type enum.CertificateType
+}
+
 // ~~~ [ 5 - Base Relocation Table ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // RawBaseRelocBlock is a base relocation block descriptor (in raw format).
@@ -502,15 +1057,32 @@ type RawDebugDirectory struct {
 	Offset uint32
 }
 
-// RawCodeViewInfo contains CodeView debug information (in raw format).
+// RawCodeViewNB09 contains CodeView debug information stored in the image, as
+// identified by the "NB09" and "NB11" signatures (in raw format).
+//
+// ref: Visual C++ 5.0 Symbolic Debug Information Specification
+type RawCodeViewNB09 struct {
+	// CodeView signature ("NB09" or "NB11").
+	//
+	// offset: 0x0000 (4 bytes)
+	Signature [4]byte
+	// Offset to CodeView subsection directory, relative to the start of the
+	// CodeView debug information.
+	//
+	// offset: 0x0004 (4 bytes)
+	DirOffset uint32
+}
+
+// RawCodeViewNB10 contains CodeView debug information stored in a separate PDB
+// 2.0 file (in raw format).
 //
 // ref: Visual C++ 5.0 Symbolic Debug Information Specification
 // ref: https://github.com/Microsoft/microsoft-pdb/blob/master/include/cvinfo.h
-type RawCodeViewInfo struct {
+type RawCodeViewNB10 struct {
 	// CodeView signature ("NB10").
 	//
 	// offset: 0x0000 (4 bytes)
-	Signature uint32
+	Signature [4]byte
 	// CodeView offset.
 	//
 	// offset: 0x0004 (4 bytes)
@@ -525,6 +1097,44 @@ type RawCodeViewInfo struct {
 	//
 	// offset: 0x000C (4 bytes)
 	Age uint32
+	// NULL-terminated path to PDB file follows.
+}
+
+// RawCodeViewRSDS contains CodeView debug information stored in a separate PDB
+// 7.0 file (in raw format).
+//
+// ref: https://github.com/dotnet/runtime/blob/main/docs/design/specs/PE-COFF.md#codeview-debug-directory-entry-type-2
+type RawCodeViewRSDS struct {
+	// CodeView signature ("RSDS").
+	//
+	// offset: 0x0000 (4 bytes)
+	Signature [4]byte
+	// PDB signature GUID.
+	//
+	// offset: 0x0004 (16 bytes)
+	GUID [16]byte
+	// Incremental number, initially set to 1 and incremented for each partial
+	// write to the PDB file.
+	//
+	// offset: 0x0014 (4 bytes)
+	Age uint32
+	// NULL-terminated UTF-8 encoded path to PDB file follows.
+}
+
+// RawCodeViewMTOC contains CodeView debug information of an image converted
+// from Mach-O (in raw format).
+//
+// ref: https://github.com/tianocore/edk2/blob/master/MdePkg/Include/IndustryStandard/PeImage.h
+type RawCodeViewMTOC struct {
+	// CodeView signature ("MTOC").
+	//
+	// offset: 0x0000 (4 bytes)
+	Signature [4]byte
+	// UUID of Mach-O file.
+	//
+	// offset: 0x0004 (16 bytes)
+	UUID [16]byte
+	// NULL-terminated UTF-8 encoded path to debug file follows.
 }
 
 // RawFPOData represents the stack frame layout for a function on an x86
@@ -570,3 +1180,338 @@ type RawFPOData struct {
 	// offset: 0x000F (1 bytes)
 	Bitfield uint8
 }
+
+// RawOMapEntry is an OMAP entry, mapping a relative address of one image to
+// the corresponding relative address of another image (in raw format).
+//
+// ref: https://docs.microsoft.com/en-us/windows/win32/debug/dbghelp-structures
+type RawOMapEntry struct {
+	// Relative address in the source image.
+	//
+	// offset: 0x0000 (4 bytes)
+	RelAddr uint32
+	// Relative address in the destination image; zero if not mapped.
+	//
+	// offset: 0x0004 (4 bytes)
+	RelAddrTo uint32
+}
+
+// RawVCFeature contains Visual C++ feature counters (in raw format).
+type RawVCFeature struct {
+	// Number of object files built by compilers prior to Visual C++ 11.
+	//
+	// offset: 0x0000 (4 bytes)
+	PreVC11 uint32
+	// Number of object files built by the C/C++ compiler.
+	//
+	// offset: 0x0004 (4 bytes)
+	CCpp uint32
+	// Number of object files built with buffer security checks (/GS).
+	//
+	// offset: 0x0008 (4 bytes)
+	GS uint32
+	// Number of object files built with additional security checks (/sdl).
+	//
+	// offset: 0x000C (4 bytes)
+	SDL uint32
+	// Number of object files built with /guardN.
+	//
+	// offset: 0x0010 (4 bytes)
+	GuardN uint32
+}
+
+// RawPOGOEntry is a POGO section contribution entry (in raw format); followed
+// by the NULL-terminated name of the contribution, padded to 4-byte alignment.
+type RawPOGOEntry struct {
+	// Relative address of section contribution.
+	//
+	// offset: 0x0000 (4 bytes)
+	RelAddr uint32
+	// Size of section contribution in number of bytes.
+	//
+	// offset: 0x0004 (4 bytes)
+	Size uint32
+}
+
+// RawEmbeddedPortablePDB is the header of an embedded portable PDB debug data
+// directory (in raw format). The header is followed by the deflate compressed
+// portable PDB.
+//
+// ref: https://github.com/dotnet/runtime/blob/main/docs/design/specs/PE-COFF.md#embedded-portable-pdb-debug-directory-entry-type-17
+type RawEmbeddedPortablePDB struct {
+	// Signature ("MPDB").
+	//
+	// offset: 0x0000 (4 bytes)
+	Signature [4]byte
+	// Size in bytes of the decompressed portable PDB.
+	//
+	// offset: 0x0004 (4 bytes)
+	Size uint32
+}
+
+// ~~~ [ 14 - CLR Header ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// RawCLRHeader is a CLR runtime header (in raw format).
+//
+// ref: ECMA-335, II.25.3.3 CLI header
+// ref: https://github.com/dotnet/runtime/blob/master/src/coreclr/inc/corhdr.h
+type RawCLRHeader struct {
+	// Size of header in number of bytes.
+	//
+	// offset: 0x0000 (4 bytes)
+	Size uint32
+	// Major runtime version.
+	//
+	// offset: 0x0004 (2 bytes)
+	MajorRuntimeVer uint16
+	// Minor runtime version.
+	//
+	// offset: 0x0006 (2 bytes)
+	MinorRuntimeVer uint16
+	// Relative address and size of metadata.
+	//
+	// offset: 0x0008 (8 bytes)
+	Metadata RawDataDirectory
+	// Runtime flags.
+	//
+	// offset: 0x0010 (4 bytes)
+	Flags enum.CLRFlag
+	// Metadata token of entry point MethodDef or File; or relative address of
+	// native entry point if CLRFlagNativeEntryPoint is set.
+	//
+	// offset: 0x0014 (4 bytes)
+	EntryPointToken uint32
+	// Relative address and size of managed resources.
+	//
+	// offset: 0x0018 (8 bytes)
+	Resources RawDataDirectory
+	// Relative address and size of strong name signature.
+	//
+	// offset: 0x0020 (8 bytes)
+	StrongNameSignature RawDataDirectory
+	// Reserved.
+	//
+	// offset: 0x0028 (8 bytes)
+	CodeManagerTable RawDataDirectory
+	// Relative address and size of VTable fixups.
+	//
+	// offset: 0x0030 (8 bytes)
+	VTableFixups RawDataDirectory
+	// Reserved.
+	//
+	// offset: 0x0038 (8 bytes)
+	ExportAddressTableJumps RawDataDirectory
+	// Relative address and size of native header (NGen or ReadyToRun); zero
+	// for IL-only images.
+	//
+	// offset: 0x0040 (8 bytes)
+	ManagedNativeHeader RawDataDirectory
+}
+
+// RawDataDirectory is a data directory (in raw format).
+type RawDataDirectory struct {
+	// Relative address to table.
+	//
+	// offset: 0x0000 (4 bytes)
+	RelAddr uint32
+	// Size of table in bytes.
+	//
+	// offset: 0x0004 (4 bytes)
+	Size uint32
+}
+
+// ~~~ [ Strong Name ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// RawStrongNameKey is a strong name public key header (in raw format), as
+// stored in the PublicKey of the Assembly metadata table; followed by KeySize
+// bytes of CryptoAPI public key blob.
+//
+// ref: ECMA-335, II.6.2.1.3 PublicKeyBlob
+type RawStrongNameKey struct {
+	// Signature algorithm identifier (e.g. CALG_RSA_SIGN).
+	//
+	// offset: 0x0000 (4 bytes)
+	SigAlgID uint32
+	// Hash algorithm identifier (e.g. CALG_SHA1).
+	//
+	// offset: 0x0004 (4 bytes)
+	HashAlgID uint32
+	// Size of public key blob in number of bytes.
+	//
+	// offset: 0x0008 (4 bytes)
+	KeySize uint32
+}
+
+// RawRSAPublicKey is a CryptoAPI RSA public key blob header (in raw format);
+// followed by BitLen/8 bytes of modulus in little-endian byte order.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/seccrypto/base-provider-key-blobs#public-key-blobs
+type RawRSAPublicKey struct {
+	// Key blob type (PUBLICKEYBLOB).
+	//
+	// offset: 0x0000 (1 bytes)
+	Type uint8
+	// Key blob version.
+	//
+	// offset: 0x0001 (1 bytes)
+	Version uint8
+	// Reserved.
+	//
+	// offset: 0x0002 (2 bytes)
+	Reserved uint16
+	// Key algorithm identifier (e.g. CALG_RSA_SIGN).
+	//
+	// offset: 0x0004 (4 bytes)
+	KeyAlg uint32
+	// RSA public key magic ("RSA1").
+	//
+	// offset: 0x0008 (4 bytes)
+	Magic uint32
+	// Size of modulus in number of bits.
+	//
+	// offset: 0x000C (4 bytes)
+	BitLen uint32
+	// Public exponent.
+	//
+	// offset: 0x0010 (4 bytes)
+	PubExp uint32
+}
+
+// ~~~ [ ReadyToRun ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// RawR2RHeader is a ReadyToRun header (in raw format), as referred to by the
+// managed native header of the CLR header.
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/inc/readytorun.h
+type RawR2RHeader struct {
+	// ReadyToRun signature ("RTR\x00").
+	//
+	// offset: 0x0000 (4 bytes)
+	Signature uint32
+	// Major version of ReadyToRun format.
+	//
+	// offset: 0x0004 (2 bytes)
+	MajorVer uint16
+	// Minor version of ReadyToRun format.
+	//
+	// offset: 0x0006 (2 bytes)
+	MinorVer uint16
+	// Core header.
+	//
+	// offset: 0x0008 (8 bytes)
+	RawR2RCoreHeader
+}
+
+// RawR2RCoreHeader is a ReadyToRun core header (in raw format); followed by
+// NSections sections.
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/inc/readytorun.h
+type RawR2RCoreHeader struct {
+	// ReadyToRun flags.
+	//
+	// offset: 0x0000 (4 bytes)
+	Flags enum.R2RFlag
+	// Number of sections.
+	//
+	// offset: 0x0004 (4 bytes)
+	NSections uint32
+}
+
+// RawR2RSection is a ReadyToRun section (in raw format).
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/inc/readytorun.h
+type RawR2RSection struct {
+	// Section type.
+	//
+	// offset: 0x0000 (4 bytes)
+	Type enum.R2RSectionType
+	// Relative address and size of section contents.
+	//
+	// offset: 0x0004 (8 bytes)
+	Section RawDataDirectory
+}
+
+// RawR2RImportSection is a ReadyToRun import section (in raw format).
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/inc/readytorun.h
+type RawR2RImportSection struct {
+	// Relative address and size of import cells.
+	//
+	// offset: 0x0000 (8 bytes)
+	Section RawDataDirectory
+	// Import section flags.
+	//
+	// offset: 0x0008 (2 bytes)
+	Flags enum.R2RImportSectionFlag
+	// Import section type.
+	//
+	// offset: 0x000A (1 bytes)
+	Type enum.R2RImportSectionType
+	// Size of import cell in number of bytes.
+	//
+	// offset: 0x000B (1 bytes)
+	EntrySize uint8
+	// Relative address of fixup signature relative addresses (one per import
+	// cell); zero if not present.
+	//
+	// offset: 0x000C (4 bytes)
+	Signatures uint32
+	// Relative address of auxiliary data; zero if not present.
+	//
+	// offset: 0x0010 (4 bytes)
+	AuxiliaryData uint32
+}
+
+// RawR2RComponentAssembly is a component assembly entry of a composite
+// ReadyToRun image (in raw format).
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/inc/readytorun.h
+type RawR2RComponentAssembly struct {
+	// Relative address and size of the CLR header of the component assembly.
+	//
+	// offset: 0x0000 (8 bytes)
+	CLRHeader RawDataDirectory
+	// Relative address and size of the ReadyToRun core header of the component
+	// assembly.
+	//
+	// offset: 0x0008 (8 bytes)
+	CoreHeader RawDataDirectory
+}
+
+// --- [ COFF archive ] --------------------------------------------------------
+
+// RawArchiveMemberHeader is the header of a COFF archive member (in raw
+// format); fields are ASCII text, padded with spaces.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#archive-member-headers
+type RawArchiveMemberHeader struct {
+	// Member name, terminated by '/'; or "/n" for long names, where n is the
+	// decimal offset into the longnames member.
+	//
+	// offset: 0x0000 (16 bytes)
+	Name [16]byte
+	// Member creation time, measured in decimal number of seconds since Epoch.
+	//
+	// offset: 0x0010 (12 bytes)
+	Date [12]byte
+	// Decimal user ID; blank in Microsoft tools.
+	//
+	// offset: 0x001C (6 bytes)
+	UserID [6]byte
+	// Decimal group ID; blank in Microsoft tools.
+	//
+	// offset: 0x0022 (6 bytes)
+	GroupID [6]byte
+	// Octal file mode.
+	//
+	// offset: 0x0028 (8 bytes)
+	Mode [8]byte
+	// Decimal size in bytes of member data, excluding header.
+	//
+	// offset: 0x0030 (10 bytes)
+	Size [10]byte
+	// End of header; must be "`\n".
+	//
+	// offset: 0x003A (2 bytes)
+	End [2]byte
+}
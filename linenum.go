@@ -0,0 +1,194 @@
+package pe
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// --- [ COFF line numbers ] ---------------------------------------------------
+
+// LineNum is a COFF line number entry.
+//
+// Each function is introduced by an entry with a zero line number referring to
+// the function symbol, followed by entries mapping code addresses to line
+// numbers relative to the start of the function.
+type LineNum struct {
+	// Symbol table index of function symbol (only used if LineNum is zero).
+	SymbolIndex uint32
+	// Relative address of the code corresponding to the source line (only used
+	// if LineNum is non-zero); relative to image base in PE images and to the
+	// section address in object files.
+	RelAddr uint32
+	// Line number relative to the start of the function (1-based); zero
+	// indicates the start of a function.
+	LineNum uint16
+}
+
+// FuncLineNums is the COFF line number information of a function.
+type FuncLineNums struct {
+	// Function symbol; nil if not present in the symbol table.
+	Func *Symbol
+	// Index into the section headers of the section containing the line number
+	// entries.
+	SectIndex int
+	// Relative address of function start; same address space as
+	// LineNum.RelAddr.
+	RelAddr uint32
+	// Source line number of function start (from the .bf symbol); zero if
+	// unknown.
+	BaseLineNum uint16
+	// Source lines of the function, in order of occurrence.
+	Lines []SourceLine
+}
+
+// SourceLine maps the address of a code location to its source line number.
+type SourceLine struct {
+	// Relative address of code; same address space as LineNum.RelAddr.
+	RelAddr uint32
+	// Source line number (1-based) within the source file.
+	LineNum int
+}
+
+// FuncLineNums returns the COFF line number information of each function,
+// grouped by owning function symbol.
+func (file *File) FuncLineNums() ([]FuncLineNums, error) {
+	var funcs []FuncLineNums
+	for i, sectHdr := range file.SectHdrs {
+		var cur *FuncLineNums
+		for _, lineNum := range sectHdr.LineNums {
+			if lineNum.LineNum == 0 {
+				f, err := file.funcLineNums(i, lineNum.SymbolIndex)
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+				funcs = append(funcs, f)
+				cur = &funcs[len(funcs)-1]
+				continue
+			}
+			if cur == nil {
+				return nil, errors.Errorf("line number entry at relative address 0x%08X of section %q precedes function start", lineNum.RelAddr, sectHdr.Name)
+			}
+			line := int(lineNum.LineNum)
+			// Line numbers are relative to the source line of the function start,
+			// where the first line of the function has line number 1.
+			if cur.BaseLineNum != 0 {
+				line += int(cur.BaseLineNum) - 1
+			}
+			cur.Lines = append(cur.Lines, SourceLine{RelAddr: lineNum.RelAddr, LineNum: line})
+		}
+	}
+	return funcs, nil
+}
+
+// funcLineNums returns the line number information of the function with the
+// given symbol table index, with line number entries in the given section.
+func (file *File) funcLineNums(sectIndex int, symIndex uint32) (FuncLineNums, error) {
+	f := FuncLineNums{
+		SectIndex: sectIndex,
+	}
+	sym, ok := file.Symbol(symIndex)
+	if !ok {
+		return f, nil
+	}
+	f.Func = sym
+	// Symbol values of functions are relative to the start of their section.
+	if sym.SectNum < 1 || int(sym.SectNum) > len(file.SectHdrs) {
+		return f, errors.Errorf("invalid section number %d of function symbol %q", sym.SectNum, sym.Name)
+	}
+	f.RelAddr = file.SectHdrs[sym.SectNum-1].RelAddr + sym.Value
+	// Locate the .bf symbol of the function, either through the function
+	// definition auxiliary symbol record, or as the symbol directly following
+	// the function symbol.
+	bfIndex := sym.Index + 1 + uint32(len(sym.Aux))
+	for _, aux := range sym.Aux {
+		if funcDef, ok := aux.(*AuxFuncDef); ok && funcDef.TagIndex != 0 {
+			bfIndex = funcDef.TagIndex
+		}
+	}
+	if bf, ok := file.Symbol(bfIndex); ok && bf.Name == ".bf" {
+		for _, aux := range bf.Aux {
+			if bfAux, ok := aux.(*AuxBfEf); ok {
+				f.BaseLineNum = bfAux.LineNum
+			}
+		}
+	}
+	return f, nil
+}
+
+// LineNumAt returns the function symbol and source line number of the code at
+// the given relative address, as recorded by the COFF line numbers of the
+// section with the given index into the section headers. The relative address
+// is relative to image base in PE images and to the section address in object
+// files. The boolean return value indicates success.
+//
+// The line number table is built on first use and cached on the file; it is
+// safe to call LineNumAt concurrently.
+func (file *File) LineNumAt(sectIndex int, relAddr uint32) (*Symbol, int, bool) {
+	file.lineFuncsOnce.Do(func() {
+		file.lineFuncs, file.lineFuncsErr = file.buildLineFuncs()
+	})
+	if file.lineFuncsErr != nil {
+		return nil, 0, false
+	}
+	funcs := file.lineFuncs[sectIndex]
+	// Locate the last function starting at or before the relative address.
+	i := sort.Search(len(funcs), func(i int) bool {
+		return funcs[i].RelAddr > relAddr
+	}) - 1
+	if i < 0 {
+		return nil, 0, false
+	}
+	f := funcs[i]
+	if size := f.size(); size != 0 && uint64(relAddr) >= uint64(f.RelAddr)+uint64(size) {
+		return nil, 0, false
+	}
+	// Locate the last source line at or before the relative address.
+	j := sort.Search(len(f.Lines), func(j int) bool {
+		return f.Lines[j].RelAddr > relAddr
+	}) - 1
+	if j < 0 {
+		if f.BaseLineNum == 0 {
+			return nil, 0, false
+		}
+		return f.Func, int(f.BaseLineNum), true
+	}
+	return f.Func, f.Lines[j].LineNum, true
+}
+
+// buildLineFuncs returns the functions with COFF line numbers of each section
+// (by index into the section headers), with functions and source lines sorted
+// by relative address.
+func (file *File) buildLineFuncs() (map[int][]FuncLineNums, error) {
+	funcs, err := file.FuncLineNums()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	lineFuncs := make(map[int][]FuncLineNums)
+	for _, f := range funcs {
+		sort.SliceStable(f.Lines, func(i, j int) bool {
+			return f.Lines[i].RelAddr < f.Lines[j].RelAddr
+		})
+		lineFuncs[f.SectIndex] = append(lineFuncs[f.SectIndex], f)
+	}
+	for _, fs := range lineFuncs {
+		sort.SliceStable(fs, func(i, j int) bool {
+			return fs[i].RelAddr < fs[j].RelAddr
+		})
+	}
+	return lineFuncs, nil
+}
+
+// size returns the size in bytes of the function code, as recorded by the
+// function definition auxiliary symbol record; or zero if unknown.
+func (f FuncLineNums) size() uint32 {
+	if f.Func == nil {
+		return 0
+	}
+	for _, aux := range f.Func.Aux {
+		if funcDef, ok := aux.(*AuxFuncDef); ok {
+			return funcDef.TotalSize
+		}
+	}
+	return 0
+}
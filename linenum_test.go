@@ -0,0 +1,99 @@
+package pe
+
+import (
+	"sync"
+	"testing"
+)
+
+// newLineNumFile returns a file with COFF line numbers of two functions.
+func newLineNumFile() *File {
+	return &File{
+		SectHdrs: []SectionHeader{
+			{
+				Name:    ".text",
+				RelAddr: 0x1000,
+				LineNums: []LineNum{
+					// bar, declared after foo but located before it.
+					{SymbolIndex: 4},
+					{RelAddr: 0x1003, LineNum: 2},
+					{RelAddr: 0x1008, LineNum: 3},
+					// foo.
+					{SymbolIndex: 0},
+					{RelAddr: 0x1023, LineNum: 2},
+					{RelAddr: 0x1030, LineNum: 4},
+				},
+			},
+		},
+		Symbols: []Symbol{
+			{Index: 0, Name: "foo", Value: 0x20, SectNum: 1, Aux: []SymbolAux{&AuxFuncDef{TagIndex: 2, TotalSize: 0x20}}},
+			{Index: 2, Name: ".bf", Aux: []SymbolAux{&AuxBfEf{LineNum: 10}}},
+			{Index: 4, Name: "bar", Value: 0x00, SectNum: 1, Aux: []SymbolAux{&AuxFuncDef{TagIndex: 6, TotalSize: 0x10}}},
+			{Index: 6, Name: ".bf", Aux: []SymbolAux{&AuxBfEf{LineNum: 20}}},
+		},
+	}
+}
+
+func TestLineNumAt(t *testing.T) {
+	file := newLineNumFile()
+	golden := []struct {
+		relAddr  uint32
+		wantFunc string
+		wantLine int
+		wantOK   bool
+	}{
+		{relAddr: 0x1000, wantFunc: "bar", wantLine: 20, wantOK: true},
+		{relAddr: 0x1004, wantFunc: "bar", wantLine: 21, wantOK: true},
+		{relAddr: 0x100F, wantFunc: "bar", wantLine: 22, wantOK: true},
+		// Gap between bar and foo.
+		{relAddr: 0x1010, wantOK: false},
+		{relAddr: 0x1020, wantFunc: "foo", wantLine: 10, wantOK: true},
+		{relAddr: 0x1023, wantFunc: "foo", wantLine: 11, wantOK: true},
+		{relAddr: 0x103F, wantFunc: "foo", wantLine: 13, wantOK: true},
+		// Past end of foo.
+		{relAddr: 0x1040, wantOK: false},
+		{relAddr: 0x0FFF, wantOK: false},
+	}
+	for _, g := range golden {
+		sym, line, ok := file.LineNumAt(0, g.relAddr)
+		if ok != g.wantOK {
+			t.Errorf("0x%08X: success mismatch; expected %v, got %v", g.relAddr, g.wantOK, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if sym.Name != g.wantFunc || line != g.wantLine {
+			t.Errorf("0x%08X: line mismatch; expected %s:%d, got %s:%d", g.relAddr, g.wantFunc, g.wantLine, sym.Name, line)
+		}
+	}
+}
+
+func TestLineNumAtConcurrent(t *testing.T) {
+	file := newLineNumFile()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sym, line, ok := file.LineNumAt(0, 0x1023)
+			if !ok || sym.Name != "foo" || line != 11 {
+				t.Errorf("0x%08X: line mismatch; expected foo:11, got %v:%d (ok %v)", 0x1023, sym, line, ok)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLineNumAtInvalid(t *testing.T) {
+	file := newLineNumFile()
+	// Function symbol with invalid section number.
+	file.Symbols[2].SectNum = 2
+	if _, _, ok := file.LineNumAt(0, 0x1004); ok {
+		t.Error("expected failure for function symbol with invalid section number")
+	}
+	// The error is cached with the line number table.
+	file.Symbols[2].SectNum = 1
+	if _, _, ok := file.LineNumAt(0, 0x1004); ok {
+		t.Error("expected cached failure for function symbol with invalid section number")
+	}
+}
@@ -0,0 +1,323 @@
+// Package metadata provides access to ECMA-335 CLI metadata, as stored in
+// managed PE files and portable PDB files.
+//
+// ref: ECMA-335, Partition II, 24 Metadata physical layout
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// Metadata is the CLI metadata of a managed module.
+type Metadata struct {
+	// Metadata root.
+	Root *Root
+	// #Strings heap.
+	Strings StringHeap
+	// #US heap.
+	US UserStringHeap
+	// #GUID heap.
+	GUID GUIDHeap
+	// #Blob heap.
+	Blob BlobHeap
+	// Metadata tables (#~ or #- stream).
+	Tables *Tables
+	// #Pdb stream; nil if not a portable PDB.
+	PDB *PDBStream
+
+	// Cached lookup information; lazily initialized.
+	cache *cache
+}
+
+// Root is a metadata root.
+type Root struct {
+	// Major version.
+	MajorVer uint16
+	// Minor version.
+	MinorVer uint16
+	// Version string (e.g. "v4.0.30319").
+	Version string
+	// Reserved.
+	Flags uint16
+	// Stream headers.
+	StreamHdrs []StreamHeader
+}
+
+// StreamHeader is a metadata stream header.
+type StreamHeader struct {
+	// Offset of stream contents (relative to metadata root).
+	Offset uint32
+	// Size of stream in bytes.
+	Size uint32
+	// Stream name (e.g. "#~").
+	Name string
+}
+
+// Metadata root signature ("BSJB").
+const signature = 0x424A5342
+
+// Parse parses the given CLI metadata, starting with the metadata root.
+func Parse(buf []byte) (*Metadata, error) {
+	root, err := ParseRoot(buf)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	md := &Metadata{
+		Root: root,
+	}
+	var tables, pdb []byte
+	for _, hdr := range root.StreamHdrs {
+		start := uint64(hdr.Offset)
+		end := start + uint64(hdr.Size)
+		if end > uint64(len(buf)) {
+			return nil, errors.Errorf("stream %q out of bounds; expected end <= %d, got %d", hdr.Name, len(buf), end)
+		}
+		data := buf[start:end]
+		switch hdr.Name {
+		case "#~", "#-":
+			tables = data
+		case "#Strings":
+			md.Strings = StringHeap(data)
+		case "#US":
+			md.US = UserStringHeap(data)
+		case "#GUID":
+			md.GUID = GUIDHeap(data)
+		case "#Blob":
+			md.Blob = BlobHeap(data)
+		case "#Pdb":
+			pdb = data
+		}
+	}
+	if pdb != nil {
+		p, err := parsePDBStream(pdb)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		md.PDB = p
+	}
+	if tables != nil {
+		t, err := parseTables(tables, md.PDB)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		md.Tables = t
+	}
+	return md, nil
+}
+
+// ParseRoot parses the metadata root, reading from buf.
+func ParseRoot(buf []byte) (*Root, error) {
+	r := bytes.NewReader(buf)
+	var hdr struct {
+		Signature uint32
+		MajorVer  uint16
+		MinorVer  uint16
+		Reserved  uint32
+		Length    uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &hdr); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if hdr.Signature != signature {
+		return nil, errors.Errorf("invalid metadata root signature; expected 0x%08X, got 0x%08X", signature, hdr.Signature)
+	}
+	version := make([]byte, hdr.Length)
+	if _, err := io.ReadFull(r, version); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var trailer struct {
+		Flags    uint16
+		NStreams uint16
+	}
+	if err := binary.Read(r, binary.LittleEndian, &trailer); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	root := &Root{
+		MajorVer: hdr.MajorVer,
+		MinorVer: hdr.MinorVer,
+		Version:  parseCString(version),
+		Flags:    trailer.Flags,
+	}
+	for i := 0; i < int(trailer.NStreams); i++ {
+		var streamHdr struct {
+			Offset uint32
+			Size   uint32
+		}
+		if err := binary.Read(r, binary.LittleEndian, &streamHdr); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		// Stream name is NULL-terminated and padded to a 4-byte boundary.
+		var name []byte
+		for {
+			b := make([]byte, 4)
+			if _, err := io.ReadFull(r, b); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			name = append(name, b...)
+			if bytes.IndexByte(b, 0) != -1 {
+				break
+			}
+		}
+		root.StreamHdrs = append(root.StreamHdrs, StreamHeader{
+			Offset: streamHdr.Offset,
+			Size:   streamHdr.Size,
+			Name:   parseCString(name),
+		})
+	}
+	return root, nil
+}
+
+// ### [ Heaps ] ###############################################################
+
+// StringIndex is an index into the #Strings heap.
+type StringIndex uint32
+
+// GUIDIndex is a 1-based index into the #GUID heap; zero denotes a null GUID.
+type GUIDIndex uint32
+
+// BlobIndex is an index into the #Blob heap.
+type BlobIndex uint32
+
+// StringHeap is the #Strings heap, containing NULL-terminated UTF-8 strings.
+type StringHeap []byte
+
+// Get returns the string at the given index of the heap.
+func (heap StringHeap) Get(index StringIndex) string {
+	if int(index) >= len(heap) {
+		return ""
+	}
+	return parseCString(heap[index:])
+}
+
+// UserStringHeap is the #US heap, containing length-prefixed UTF-16 strings.
+type UserStringHeap []byte
+
+// Get returns the user string at the given index of the heap.
+func (heap UserStringHeap) Get(index uint32) string {
+	if int(index) >= len(heap) {
+		return ""
+	}
+	n, size, err := decodeUint(heap[index:])
+	if err != nil {
+		return ""
+	}
+	start := int(index) + size
+	end := start + int(n)
+	if end > len(heap) {
+		return ""
+	}
+	// The last byte is a flag indicating whether any character requires
+	// special handling; it is not part of the string.
+	b := heap[start:end]
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(u))
+}
+
+// GUIDHeap is the #GUID heap, containing 16-byte GUIDs.
+type GUIDHeap []byte
+
+// Get returns the GUID at the given index of the heap.
+func (heap GUIDHeap) Get(index GUIDIndex) [16]byte {
+	var guid [16]byte
+	if index == 0 || int(index)*16 > len(heap) {
+		return guid
+	}
+	copy(guid[:], heap[(index-1)*16:])
+	return guid
+}
+
+// BlobHeap is the #Blob heap, containing length-prefixed binary blobs.
+type BlobHeap []byte
+
+// Get returns the blob at the given index of the heap.
+func (heap BlobHeap) Get(index BlobIndex) []byte {
+	if int(index) >= len(heap) {
+		return nil
+	}
+	n, size, err := decodeUint(heap[index:])
+	if err != nil {
+		return nil
+	}
+	start := int(index) + size
+	end := start + int(n)
+	if end > len(heap) {
+		return nil
+	}
+	return heap[start:end]
+}
+
+// ### [ Helper functions ] ####################################################
+
+// decodeUint decodes the compressed unsigned integer stored at the start of
+// buf, returning the value and its encoded size in bytes.
+//
+// ref: ECMA-335, II.23.2 Blobs and signatures
+func decodeUint(buf []byte) (uint32, int, error) {
+	if len(buf) < 1 {
+		return 0, 0, errors.WithStack(io.ErrUnexpectedEOF)
+	}
+	switch {
+	case buf[0]&0x80 == 0:
+		// 0xxxxxxx
+		return uint32(buf[0]), 1, nil
+	case buf[0]&0xC0 == 0x80:
+		// 10xxxxxx xxxxxxxx
+		if len(buf) < 2 {
+			return 0, 0, errors.WithStack(io.ErrUnexpectedEOF)
+		}
+		return uint32(buf[0]&0x3F)<<8 | uint32(buf[1]), 2, nil
+	case buf[0]&0xE0 == 0xC0:
+		// 110xxxxx xxxxxxxx xxxxxxxx xxxxxxxx
+		if len(buf) < 4 {
+			return 0, 0, errors.WithStack(io.ErrUnexpectedEOF)
+		}
+		return uint32(buf[0]&0x1F)<<24 | uint32(buf[1])<<16 | uint32(buf[2])<<8 | uint32(buf[3]), 4, nil
+	default:
+		return 0, 0, errors.Errorf("invalid compressed integer prefix 0x%02X", buf[0])
+	}
+}
+
+// decodeInt decodes the compressed signed integer stored at the start of buf,
+// returning the value and its encoded size in bytes.
+//
+// ref: ECMA-335, II.23.2 Blobs and signatures
+func decodeInt(buf []byte) (int32, int, error) {
+	v, n, err := decodeUint(buf)
+	if err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+	// The value is rotated left by one bit, storing the sign bit as the least
+	// significant bit of the 7-, 14- or 29-bit encoding.
+	var bits uint
+	switch n {
+	case 1:
+		bits = 7
+	case 2:
+		bits = 14
+	default:
+		bits = 29
+	}
+	x := int32(v >> 1)
+	if v&1 != 0 {
+		x -= 1 << (bits - 1)
+	}
+	return x, n, nil
+}
+
+// parseCString parses the given a NULL-terminated string into a corresponding
+// Go string.
+func parseCString(b []byte) string {
+	pos := bytes.IndexByte(b, '\x00')
+	if pos != -1 {
+		b = b[:pos]
+	}
+	return string(b)
+}
@@ -0,0 +1,240 @@
+package metadata
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// cache holds lookup information derived from the metadata tables.
+type cache struct {
+	// Enclosing TypeDef of each nested TypeDef, indexed by RID of nested type.
+	enclosing map[uint32]uint32
+}
+
+// getCache returns the lookup information of the metadata, initializing it on
+// first use.
+func (md *Metadata) getCache() *cache {
+	if md.cache != nil {
+		return md.cache
+	}
+	c := &cache{
+		enclosing: make(map[uint32]uint32),
+	}
+	if md.Tables != nil {
+		for _, nested := range md.Tables.NestedClass {
+			c.enclosing[nested.NestedClass] = nested.EnclosingClass
+		}
+	}
+	md.cache = c
+	return c
+}
+
+// DeclaringType returns the RID of the TypeDef owning the given MethodDef, and
+// a boolean indicating success.
+func (md *Metadata) DeclaringType(methodRID uint32) (uint32, bool) {
+	if md.Tables == nil {
+		return 0, false
+	}
+	return ownerOf(md.Tables.TypeDef, methodRID, func(t TypeDef) uint32 { return t.MethodList })
+}
+
+// FieldDeclaringType returns the RID of the TypeDef owning the given Field,
+// and a boolean indicating success.
+func (md *Metadata) FieldDeclaringType(fieldRID uint32) (uint32, bool) {
+	if md.Tables == nil {
+		return 0, false
+	}
+	return ownerOf(md.Tables.TypeDef, fieldRID, func(t TypeDef) uint32 { return t.FieldList })
+}
+
+// ownerOf returns the RID of the TypeDef whose run of members (as given by
+// list) contains the given member RID.
+func ownerOf(typeDefs []TypeDef, rid uint32, list func(TypeDef) uint32) (uint32, bool) {
+	// Runs of members are stored in ascending order; the owner is the last
+	// type whose run starts at or before rid (preceding types sharing the same
+	// start have empty runs).
+	i := sort.Search(len(typeDefs), func(i int) bool {
+		return list(typeDefs[i]) > rid
+	})
+	if i == 0 {
+		return 0, false
+	}
+	// The 0-based index i-1 corresponds to RID i.
+	return uint32(i), true
+}
+
+// maxDepth is the maximum nesting depth of type names, guarding against cyclic
+// references in malformed metadata.
+const maxDepth = 64
+
+// TypeName returns the ILAsm name of the given TypeDef, TypeRef or TypeSpec,
+// without class or valuetype prefix.
+func (md *Metadata) TypeName(tok Token) string {
+	return md.typeName(tok, 0)
+}
+
+// typeName returns the ILAsm name of the given TypeDef, TypeRef or TypeSpec,
+// at the given nesting depth.
+func (md *Metadata) typeName(tok Token, depth int) string {
+	if depth > maxDepth {
+		return tok.String()
+	}
+	t := md.Tables
+	rid := tok.RID()
+	switch tok.Table() {
+	case TableTypeDef:
+		if t == nil || rid < 1 || int(rid) > len(t.TypeDef) {
+			break
+		}
+		row := t.TypeDef[rid-1]
+		name := qualifiedName(md.Strings.Get(row.Namespace), md.Strings.Get(row.Name))
+		if enclosing, ok := md.getCache().enclosing[rid]; ok && enclosing != rid {
+			return md.typeName(NewToken(TableTypeDef, enclosing), depth+1) + "/" + name
+		}
+		return name
+	case TableTypeRef:
+		if t == nil || rid < 1 || int(rid) > len(t.TypeRef) {
+			break
+		}
+		row := t.TypeRef[rid-1]
+		name := qualifiedName(md.Strings.Get(row.Namespace), md.Strings.Get(row.Name))
+		scope := row.ResolutionScope
+		switch scope.Table() {
+		case TableTypeRef:
+			if scope.RID() != rid {
+				return md.typeName(scope, depth+1) + "/" + name
+			}
+		case TableAssemblyRef:
+			if int(scope.RID()) <= len(t.AssemblyRef) && scope.RID() > 0 {
+				return "[" + md.Strings.Get(t.AssemblyRef[scope.RID()-1].Name) + "]" + name
+			}
+		case TableModuleRef:
+			if int(scope.RID()) <= len(t.ModuleRef) && scope.RID() > 0 {
+				return "[.module " + md.Strings.Get(t.ModuleRef[scope.RID()-1].Name) + "]" + name
+			}
+		}
+		return name
+	case TableTypeSpec:
+		if t == nil || rid < 1 || int(rid) > len(t.TypeSpec) {
+			break
+		}
+		r := &sigReader{buf: md.Blob.Get(t.TypeSpec[rid-1].Signature), depth: depth + 1}
+		s := md.sigType(r)
+		if r.err != nil {
+			break
+		}
+		return s
+	}
+	return tok.String()
+}
+
+// TokenName returns the ILAsm representation of the entity identified by the
+// given metadata token, as used by operands of CIL instructions.
+func (md *Metadata) TokenName(tok Token) string {
+	t := md.Tables
+	rid := tok.RID()
+	switch tok.Table() {
+	case TableTypeDef, TableTypeRef, TableTypeSpec:
+		return md.TypeName(tok)
+	case TableString:
+		return strconv.Quote(md.US.Get(rid))
+	case TableMethodDef:
+		if t == nil || rid < 1 || int(rid) > len(t.MethodDef) {
+			break
+		}
+		row := t.MethodDef[rid-1]
+		name := quoteName(md.Strings.Get(row.Name))
+		if owner, ok := md.DeclaringType(rid); ok {
+			name = md.TypeName(NewToken(TableTypeDef, owner)) + "::" + name
+		}
+		if s, err := md.MethodSig(row.Signature, name); err == nil {
+			return s
+		}
+	case TableField:
+		if t == nil || rid < 1 || int(rid) > len(t.Field) {
+			break
+		}
+		row := t.Field[rid-1]
+		name := quoteName(md.Strings.Get(row.Name))
+		if owner, ok := md.FieldDeclaringType(rid); ok {
+			name = md.TypeName(NewToken(TableTypeDef, owner)) + "::" + name
+		}
+		if s, err := md.FieldSig(row.Signature, name); err == nil {
+			return s
+		}
+	case TableMemberRef:
+		if t == nil || rid < 1 || int(rid) > len(t.MemberRef) {
+			break
+		}
+		row := t.MemberRef[rid-1]
+		name := quoteName(md.Strings.Get(row.Name))
+		if !row.Class.IsNil() {
+			parent := md.TypeName(row.Class)
+			if row.Class.Table() == TableMethodDef {
+				parent = md.TokenName(row.Class)
+			}
+			name = parent + "::" + name
+		}
+		sig := md.Blob.Get(row.Signature)
+		if len(sig) > 0 && sig[0]&sigKindMask == sigField {
+			if s, err := md.FieldSig(row.Signature, name); err == nil {
+				return s
+			}
+			break
+		}
+		if s, err := md.MethodSig(row.Signature, name); err == nil {
+			return s
+		}
+	case TableMethodSpec:
+		if t == nil || rid < 1 || int(rid) > len(t.MethodSpec) {
+			break
+		}
+		row := t.MethodSpec[rid-1]
+		args, err := md.genericInstSig(row.Instantiation)
+		if err != nil {
+			break
+		}
+		return md.TokenName(row.Method) + " " + args
+	case TableStandAloneSig:
+		if t == nil || rid < 1 || int(rid) > len(t.StandAloneSig) {
+			break
+		}
+		if s, err := md.MethodSig(t.StandAloneSig[rid-1].Signature, ""); err == nil {
+			return s
+		}
+	case TableModuleRef:
+		if t == nil || rid < 1 || int(rid) > len(t.ModuleRef) {
+			break
+		}
+		return "[.module " + md.Strings.Get(t.ModuleRef[rid-1].Name) + "]"
+	}
+	return tok.String()
+}
+
+// qualifiedName returns the namespace qualified name of a type.
+func qualifiedName(namespace, name string) string {
+	if len(namespace) == 0 {
+		return quoteName(name)
+	}
+	return quoteName(namespace + "." + name)
+}
+
+// quoteName returns the given identifier, enclosed in single quotes if not a
+// valid ILAsm dotted name.
+func quoteName(name string) string {
+	if len(name) == 0 {
+		return "''"
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == '$', r == '@', r == '`', r == '?':
+		case r >= '0' && r <= '9' && i > 0:
+		case r == '.' && name != "." && name != "..":
+		case r > 0x7F:
+		default:
+			return fmt.Sprintf("'%s'", name)
+		}
+	}
+	return name
+}
@@ -0,0 +1,194 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PDBStream is the #Pdb stream of a portable PDB.
+//
+// ref: Portable PDB v1.0 Format Specification, #Pdb stream
+type PDBStream struct {
+	// PDB ID; the GUID (first 16 bytes) and timestamp (last 4 bytes) matching
+	// the CodeView debug information of the associated module.
+	ID [20]byte
+	// MethodDef of the entry point; nil if none.
+	EntryPoint Token
+	// Bitfield of type system tables referenced by the portable PDB.
+	ReferencedTables uint64
+	// Number of rows of each referenced type system table in the associated
+	// module, indexed by table identifier.
+	TypeSystemNRows [64]uint32
+}
+
+// parsePDBStream parses the given #Pdb stream.
+func parsePDBStream(buf []byte) (*PDBStream, error) {
+	if len(buf) < 32 {
+		return nil, errors.Errorf("#Pdb stream too short; expected >= 32 bytes, got %d", len(buf))
+	}
+	pdb := &PDBStream{
+		EntryPoint:       Token(binary.LittleEndian.Uint32(buf[20:])),
+		ReferencedTables: binary.LittleEndian.Uint64(buf[24:]),
+	}
+	copy(pdb.ID[:], buf)
+	pos := 32
+	for i := 0; i < 64; i++ {
+		if pdb.ReferencedTables&(1<<uint(i)) == 0 {
+			continue
+		}
+		if pos+4 > len(buf) {
+			return nil, errors.WithStack(io.ErrUnexpectedEOF)
+		}
+		pdb.TypeSystemNRows[i] = binary.LittleEndian.Uint32(buf[pos:])
+		pos += 4
+	}
+	return pdb, nil
+}
+
+// DocumentName returns the name of the document with the given 1-based row
+// index into the Document table.
+func (md *Metadata) DocumentName(rid uint32) (string, error) {
+	if md.Tables == nil || rid < 1 || int(rid) > len(md.Tables.Document) {
+		return "", errors.Errorf("invalid Document row index %d", rid)
+	}
+	// The document name blob contains a UTF-8 separator character (zero if
+	// none), followed by the blob indices of the UTF-8 encoded name parts.
+	blob := md.Blob.Get(md.Tables.Document[rid-1].Name)
+	if len(blob) < 1 {
+		return "", errors.Errorf("invalid name blob of document %d", rid)
+	}
+	var sep string
+	if blob[0] != 0 {
+		sep = string(blob[:1])
+	}
+	var parts []string
+	for pos := 1; pos < len(blob); {
+		index, n, err := decodeUint(blob[pos:])
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		pos += n
+		parts = append(parts, string(md.Blob.Get(BlobIndex(index))))
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// HiddenLine is the line number of hidden sequence points.
+const HiddenLine = 0xFEEFEE
+
+// SequencePoint maps an IL offset of a method to a source code location.
+type SequencePoint struct {
+	// IL offset of the sequence point.
+	ILOffset uint32
+	// Document containing the source code.
+	Document uint32
+	// Start line (1-based); HiddenLine if the sequence point is hidden.
+	StartLine uint32
+	// Start column (1-based); zero if the sequence point is hidden.
+	StartColumn uint32
+	// End line (1-based); HiddenLine if the sequence point is hidden.
+	EndLine uint32
+	// End column (1-based); zero if the sequence point is hidden.
+	EndColumn uint32
+}
+
+// IsHidden reports whether the sequence point is hidden.
+func (sp SequencePoint) IsHidden() bool {
+	return sp.StartLine == HiddenLine
+}
+
+// SequencePoints returns the sequence points of the method with the given
+// 1-based row index into the MethodDef table.
+//
+// ref: Portable PDB v1.0 Format Specification, Sequence Points Blob
+func (md *Metadata) SequencePoints(methodRID uint32) ([]SequencePoint, error) {
+	if md.Tables == nil || methodRID < 1 || int(methodRID) > len(md.Tables.MethodDebugInformation) {
+		return nil, errors.Errorf("invalid MethodDebugInformation row index %d", methodRID)
+	}
+	info := md.Tables.MethodDebugInformation[methodRID-1]
+	if info.SequencePoints == 0 {
+		return nil, nil
+	}
+	r := &sigReader{buf: md.Blob.Get(info.SequencePoints)}
+	// Header.
+	r.uint() // LocalSignature
+	doc := info.Document
+	if doc == 0 {
+		doc = r.uint()
+	}
+	var sps []SequencePoint
+	var ilOffset, startLine, startColumn uint32
+	first, firstVisible := true, true
+	for r.err == nil && r.pos < len(r.buf) {
+		delta := r.uint()
+		if !first && delta == 0 {
+			// Document record.
+			doc = r.uint()
+			continue
+		}
+		ilOffset += delta
+		first = false
+		deltaLines := r.uint()
+		var deltaColumns int32
+		if deltaLines == 0 {
+			deltaColumns = int32(r.uint())
+		} else {
+			deltaColumns = r.int()
+		}
+		sp := SequencePoint{
+			ILOffset: ilOffset,
+			Document: doc,
+		}
+		if deltaLines == 0 && deltaColumns == 0 {
+			// Hidden sequence point.
+			sp.StartLine = HiddenLine
+			sp.EndLine = HiddenLine
+			sps = append(sps, sp)
+			continue
+		}
+		if firstVisible {
+			startLine = r.uint()
+			startColumn = r.uint()
+			firstVisible = false
+		} else {
+			startLine = uint32(int32(startLine) + r.int())
+			startColumn = uint32(int32(startColumn) + r.int())
+		}
+		sp.StartLine = startLine
+		sp.StartColumn = startColumn
+		sp.EndLine = startLine + deltaLines
+		sp.EndColumn = uint32(int32(startColumn) + deltaColumns)
+		sps = append(sps, sp)
+	}
+	if r.err != nil {
+		return nil, errors.Wrapf(r.err, "unable to decode sequence points of method %d", methodRID)
+	}
+	return sps, nil
+}
+
+// SourceLocation returns the sequence point of the source code corresponding
+// to the given IL offset of the method with the given 1-based row index into
+// the MethodDef table; i.e. the last visible sequence point at or before the
+// IL offset. The boolean return value indicates success.
+func (md *Metadata) SourceLocation(methodRID, ilOffset uint32) (SequencePoint, bool, error) {
+	sps, err := md.SequencePoints(methodRID)
+	if err != nil {
+		return SequencePoint{}, false, errors.WithStack(err)
+	}
+	var loc SequencePoint
+	found := false
+	for _, sp := range sps {
+		if sp.ILOffset > ilOffset {
+			break
+		}
+		if sp.IsHidden() {
+			continue
+		}
+		loc = sp
+		found = true
+	}
+	return loc, found, nil
+}
@@ -0,0 +1,187 @@
+package metadata_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/pe"
+	"github.com/mewmew/pe/metadata"
+)
+
+// parseEmbeddedPDB parses the embedded portable PDB of the given managed PE
+// file.
+func parseEmbeddedPDB(t *testing.T, path string) *metadata.Metadata {
+	file, err := pe.ParseFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	for _, dbg := range file.DbgData {
+		embedded, ok := dbg.(*pe.DebugEmbeddedPortablePDB)
+		if !ok {
+			continue
+		}
+		buf, err := embedded.PDB()
+		if err != nil {
+			t.Fatalf("%q: unable to decompress embedded portable PDB; %+v", path, err)
+		}
+		md, err := metadata.Parse(buf)
+		if err != nil {
+			t.Fatalf("%q: unable to parse embedded portable PDB; %+v", path, err)
+		}
+		return md
+	}
+	t.Fatalf("%q: unable to locate embedded portable PDB debug data", path)
+	return nil
+}
+
+func TestDocumentName(t *testing.T) {
+	const path = "../testdata/Microsoft.TestPlatform.PlatformAbstractions.dll"
+	md := parseEmbeddedPDB(t, path)
+	if len(md.Tables.Document) != 40 {
+		t.Fatalf("%q: number of documents mismatch; expected 40, got %d", path, len(md.Tables.Document))
+	}
+	golden := []struct {
+		rid     uint32
+		want    string
+		wantErr bool
+	}{
+		{rid: 1, want: "/_/src/Microsoft.TestPlatform.PlatformAbstractions/common/IO/PlatformStream.cs"},
+		{rid: 3, want: "/_/src/Microsoft.TestPlatform.PlatformAbstractions/common/System/ProcessHelper.cs"},
+		{rid: 40, want: "/_/artifacts/obj/Microsoft.TestPlatform.PlatformAbstractions/Release/net462/Microsoft.TestPlatform.PlatformAbstractions.AssemblyInfo.cs"},
+		// Invalid row indices.
+		{rid: 0, wantErr: true},
+		{rid: 41, wantErr: true},
+	}
+	for _, g := range golden {
+		got, err := md.DocumentName(g.rid)
+		if g.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected error for document %d, got nil", path, g.rid)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unable to get name of document %d; %+v", path, g.rid, err)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("%q: name of document %d mismatch; expected %q, got %q", path, g.rid, g.want, got)
+		}
+	}
+}
+
+func TestSequencePoints(t *testing.T) {
+	const path = "../testdata/Microsoft.TestPlatform.PlatformAbstractions.dll"
+	md := parseEmbeddedPDB(t, path)
+	// PlatformEqtTrace::get_Source(), with hidden sequence points of the
+	// finally clause.
+	const methodRID = 43
+	hidden := metadata.SequencePoint{Document: 4, StartLine: metadata.HiddenLine, EndLine: metadata.HiddenLine}
+	hidden1, hidden2 := hidden, hidden
+	hidden1.ILOffset = 48
+	hidden2.ILOffset = 57
+	want := []metadata.SequencePoint{
+		{ILOffset: 0, Document: 4, StartLine: 151, StartColumn: 13, EndLine: 151, EndColumn: 39},
+		{ILOffset: 7, Document: 4, StartLine: 153, StartColumn: 17, EndLine: 153, EndColumn: 34},
+		{ILOffset: 23, Document: 4, StartLine: 155, StartColumn: 21, EndLine: 155, EndColumn: 84},
+		{ILOffset: 46, Document: 4, StartLine: 156, StartColumn: 17, EndLine: 156, EndColumn: 18},
+		hidden1,
+		hidden2,
+		{ILOffset: 58, Document: 4, StartLine: 159, StartColumn: 13, EndLine: 159, EndColumn: 34},
+	}
+	got, err := md.SequencePoints(methodRID)
+	if err != nil {
+		t.Fatalf("%q: unable to get sequence points of method %d; %+v", path, methodRID, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%q: sequence points of method %d mismatch; expected %+v, got %+v", path, methodRID, want, got)
+	}
+	for _, sp := range got {
+		if sp.IsHidden() != (sp.StartLine == metadata.HiddenLine) {
+			t.Errorf("%q: hidden mismatch of sequence point at IL offset 0x%X", path, sp.ILOffset)
+		}
+	}
+	// Invalid row indices.
+	for _, rid := range []uint32{0, uint32(len(md.Tables.MethodDebugInformation) + 1)} {
+		if _, err := md.SequencePoints(rid); err == nil {
+			t.Errorf("%q: expected error for method %d, got nil", path, rid)
+		}
+	}
+}
+
+func TestSequencePointsDocumentRecord(t *testing.T) {
+	// Sequence points blob of a method spanning two documents:
+	//
+	//    header          LocalSignature 0, InitialDocument 1
+	//    sequence point  IL 0, lines 0, columns 5, start line 10, start column 5
+	//    document record document 2
+	//    sequence point  IL +4, lines 1, columns +2, start line +3, start column -1
+	//    hidden point    IL +2
+	blob := []byte{0x00, 0x01, 0x00, 0x00, 0x05, 0x0A, 0x05, 0x00, 0x02, 0x04, 0x01, 0x04, 0x06, 0x7F, 0x02, 0x00, 0x00}
+	// Sequence points blob truncated after the delta lines of the first
+	// sequence point.
+	truncated := []byte{0x00, 0x01, 0x00}
+	heap := append([]byte{0x00, byte(len(blob))}, blob...)
+	truncatedIndex := metadata.BlobIndex(len(heap))
+	heap = append(heap, byte(len(truncated)))
+	heap = append(heap, truncated...)
+	md := &metadata.Metadata{
+		Blob: heap,
+		Tables: &metadata.Tables{
+			MethodDebugInformation: []metadata.MethodDebugInformation{
+				{SequencePoints: 1},
+				{Document: 1, SequencePoints: truncatedIndex},
+			},
+		},
+	}
+	want := []metadata.SequencePoint{
+		{ILOffset: 0, Document: 1, StartLine: 10, StartColumn: 5, EndLine: 10, EndColumn: 10},
+		{ILOffset: 4, Document: 2, StartLine: 13, StartColumn: 4, EndLine: 14, EndColumn: 6},
+		{ILOffset: 6, Document: 2, StartLine: metadata.HiddenLine, EndLine: metadata.HiddenLine},
+	}
+	got, err := md.SequencePoints(1)
+	if err != nil {
+		t.Fatalf("unable to get sequence points; %+v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sequence points mismatch; expected %+v, got %+v", want, got)
+	}
+	if _, err := md.SequencePoints(2); err == nil {
+		t.Error("expected error for truncated sequence points blob, got nil")
+	}
+}
+
+func TestSourceLocation(t *testing.T) {
+	const path = "../testdata/Microsoft.TestPlatform.PlatformAbstractions.dll"
+	md := parseEmbeddedPDB(t, path)
+	golden := []struct {
+		methodRID uint32
+		ilOffset  uint32
+		wantLine  uint32
+		wantOK    bool
+	}{
+		{methodRID: 43, ilOffset: 0x00, wantLine: 151, wantOK: true},
+		{methodRID: 43, ilOffset: 0x0A, wantLine: 153, wantOK: true},
+		// Hidden sequence points are skipped.
+		{methodRID: 43, ilOffset: 0x32, wantLine: 156, wantOK: true},
+		{methodRID: 43, ilOffset: 0x3A, wantLine: 159, wantOK: true},
+		// PlatformEqtTrace::ShouldTrace(), starting with a hidden sequence
+		// point.
+		{methodRID: 51, ilOffset: 0x0A, wantOK: false},
+		{methodRID: 51, ilOffset: 0x1C, wantLine: 265, wantOK: true},
+	}
+	for _, g := range golden {
+		sp, ok, err := md.SourceLocation(g.methodRID, g.ilOffset)
+		if err != nil {
+			t.Errorf("%q: unable to get source location of method %d at IL offset 0x%X; %+v", path, g.methodRID, g.ilOffset, err)
+			continue
+		}
+		if ok != g.wantOK {
+			t.Errorf("%q: success mismatch of method %d at IL offset 0x%X; expected %v, got %v", path, g.methodRID, g.ilOffset, g.wantOK, ok)
+			continue
+		}
+		if ok && (sp.StartLine != g.wantLine || sp.Document != 4) {
+			t.Errorf("%q: source location of method %d at IL offset 0x%X mismatch; expected document 4 line %d, got document %d line %d", path, g.methodRID, g.ilOffset, g.wantLine, sp.Document, sp.StartLine)
+		}
+	}
+}
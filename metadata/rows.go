@@ -0,0 +1,534 @@
+package metadata
+
+// ref: ECMA-335, II.22 Metadata logical format: tables
+
+// Module is a row of the Module table (0x00).
+type Module struct {
+	// Reserved.
+	Generation uint16
+	// Module name.
+	Name StringIndex
+	// Module version identifier.
+	Mvid GUIDIndex
+	// Reserved.
+	EncID GUIDIndex
+	// Reserved.
+	EncBaseID GUIDIndex
+}
+
+// TypeRef is a row of the TypeRef table (0x01).
+type TypeRef struct {
+	// Module, ModuleRef, AssemblyRef or TypeRef (enclosing type) of the type.
+	ResolutionScope Token
+	// Type name.
+	Name StringIndex
+	// Type namespace.
+	Namespace StringIndex
+}
+
+// TypeDef is a row of the TypeDef table (0x02).
+type TypeDef struct {
+	// Type attributes.
+	Flags uint32
+	// Type name.
+	Name StringIndex
+	// Type namespace.
+	Namespace StringIndex
+	// TypeDef, TypeRef or TypeSpec of base type; nil if none.
+	Extends Token
+	// First Field of the type's run of fields.
+	FieldList uint32
+	// First MethodDef of the type's run of methods.
+	MethodList uint32
+}
+
+// FieldPtr is a row of the FieldPtr table (0x03); only present in
+// unoptimized metadata.
+type FieldPtr struct {
+	// Field.
+	Field uint32
+}
+
+// Field is a row of the Field table (0x04).
+type Field struct {
+	// Field attributes.
+	Flags uint16
+	// Field name.
+	Name StringIndex
+	// Field signature.
+	Signature BlobIndex
+}
+
+// MethodPtr is a row of the MethodPtr table (0x05); only present in
+// unoptimized metadata.
+type MethodPtr struct {
+	// MethodDef.
+	Method uint32
+}
+
+// MethodDef is a row of the MethodDef table (0x06).
+type MethodDef struct {
+	// Relative address of method body (relative to image base); zero if the
+	// method has no body.
+	RelAddr uint32
+	// Method implementation attributes.
+	ImplFlags uint16
+	// Method attributes.
+	Flags uint16
+	// Method name.
+	Name StringIndex
+	// Method signature.
+	Signature BlobIndex
+	// First Param of the method's run of parameters.
+	ParamList uint32
+}
+
+// ParamPtr is a row of the ParamPtr table (0x07); only present in
+// unoptimized metadata.
+type ParamPtr struct {
+	// Param.
+	Param uint32
+}
+
+// Param is a row of the Param table (0x08).
+type Param struct {
+	// Parameter attributes.
+	Flags uint16
+	// Parameter sequence number; zero refers to the return value.
+	Sequence uint16
+	// Parameter name.
+	Name StringIndex
+}
+
+// InterfaceImpl is a row of the InterfaceImpl table (0x09).
+type InterfaceImpl struct {
+	// TypeDef implementing the interface.
+	Class uint32
+	// TypeDef, TypeRef or TypeSpec of interface.
+	Interface Token
+}
+
+// MemberRef is a row of the MemberRef table (0x0A).
+type MemberRef struct {
+	// TypeDef, TypeRef, ModuleRef, MethodDef or TypeSpec of parent.
+	Class Token
+	// Member name.
+	Name StringIndex
+	// Member signature.
+	Signature BlobIndex
+}
+
+// Constant is a row of the Constant table (0x0B).
+type Constant struct {
+	// Element type of constant.
+	Type uint8
+	// Field, Param or Property of constant.
+	Parent Token
+	// Constant value.
+	Value BlobIndex
+}
+
+// CustomAttribute is a row of the CustomAttribute table (0x0C).
+type CustomAttribute struct {
+	// Entity to which the attribute is attached.
+	Parent Token
+	// MethodDef or MemberRef of attribute constructor.
+	Type Token
+	// Attribute value.
+	Value BlobIndex
+}
+
+// FieldMarshal is a row of the FieldMarshal table (0x0D).
+type FieldMarshal struct {
+	// Field or Param.
+	Parent Token
+	// Marshalling signature.
+	NativeType BlobIndex
+}
+
+// DeclSecurity is a row of the DeclSecurity table (0x0E).
+type DeclSecurity struct {
+	// Security action.
+	Action uint16
+	// TypeDef, MethodDef or Assembly.
+	Parent Token
+	// Permission set.
+	PermissionSet BlobIndex
+}
+
+// ClassLayout is a row of the ClassLayout table (0x0F).
+type ClassLayout struct {
+	// Field alignment in bytes.
+	PackingSize uint16
+	// Class size in bytes.
+	ClassSize uint32
+	// TypeDef.
+	Parent uint32
+}
+
+// FieldLayout is a row of the FieldLayout table (0x10).
+type FieldLayout struct {
+	// Field offset in bytes.
+	Offset uint32
+	// Field.
+	Field uint32
+}
+
+// StandAloneSig is a row of the StandAloneSig table (0x11).
+type StandAloneSig struct {
+	// Signature (e.g. local variables of a method).
+	Signature BlobIndex
+}
+
+// EventMap is a row of the EventMap table (0x12).
+type EventMap struct {
+	// TypeDef.
+	Parent uint32
+	// First Event of the type's run of events.
+	EventList uint32
+}
+
+// EventPtr is a row of the EventPtr table (0x13); only present in
+// unoptimized metadata.
+type EventPtr struct {
+	// Event.
+	Event uint32
+}
+
+// Event is a row of the Event table (0x14).
+type Event struct {
+	// Event attributes.
+	Flags uint16
+	// Event name.
+	Name StringIndex
+	// TypeDef, TypeRef or TypeSpec of event type.
+	EventType Token
+}
+
+// PropertyMap is a row of the PropertyMap table (0x15).
+type PropertyMap struct {
+	// TypeDef.
+	Parent uint32
+	// First Property of the type's run of properties.
+	PropertyList uint32
+}
+
+// PropertyPtr is a row of the PropertyPtr table (0x16); only present in
+// unoptimized metadata.
+type PropertyPtr struct {
+	// Property.
+	Property uint32
+}
+
+// Property is a row of the Property table (0x17).
+type Property struct {
+	// Property attributes.
+	Flags uint16
+	// Property name.
+	Name StringIndex
+	// Property signature.
+	Signature BlobIndex
+}
+
+// MethodSemantics is a row of the MethodSemantics table (0x18).
+type MethodSemantics struct {
+	// Method semantics attributes.
+	Semantics uint16
+	// MethodDef.
+	Method uint32
+	// Event or Property.
+	Association Token
+}
+
+// MethodImpl is a row of the MethodImpl table (0x19).
+type MethodImpl struct {
+	// TypeDef.
+	Class uint32
+	// MethodDef or MemberRef of implementing method.
+	MethodBody Token
+	// MethodDef or MemberRef of implemented method.
+	MethodDeclaration Token
+}
+
+// ModuleRef is a row of the ModuleRef table (0x1A).
+type ModuleRef struct {
+	// Module name.
+	Name StringIndex
+}
+
+// TypeSpec is a row of the TypeSpec table (0x1B).
+type TypeSpec struct {
+	// Type signature.
+	Signature BlobIndex
+}
+
+// ImplMap is a row of the ImplMap table (0x1C).
+type ImplMap struct {
+	// P/Invoke attributes.
+	MappingFlags uint16
+	// Field or MethodDef.
+	MemberForwarded Token
+	// Name of imported function.
+	ImportName StringIndex
+	// ModuleRef of imported module.
+	ImportScope uint32
+}
+
+// FieldRVA is a row of the FieldRVA table (0x1D).
+type FieldRVA struct {
+	// Relative address of field data (relative to image base).
+	RelAddr uint32
+	// Field.
+	Field uint32
+}
+
+// ENCLog is a row of the ENCLog table (0x1E).
+type ENCLog struct {
+	// Token of edited entity.
+	Token Token
+	// Edit operation.
+	FuncCode uint32
+}
+
+// ENCMap is a row of the ENCMap table (0x1F).
+type ENCMap struct {
+	// Token of edited entity.
+	Token Token
+}
+
+// Assembly is a row of the Assembly table (0x20).
+type Assembly struct {
+	// Hash algorithm identifier.
+	HashAlgID uint32
+	// Major version.
+	MajorVer uint16
+	// Minor version.
+	MinorVer uint16
+	// Build number.
+	BuildNumber uint16
+	// Revision number.
+	RevisionNum uint16
+	// Assembly flags.
+	Flags uint32
+	// Public key of the assembly; empty if not strong-named.
+	PublicKey BlobIndex
+	// Assembly name.
+	Name StringIndex
+	// Assembly culture.
+	Culture StringIndex
+}
+
+// AssemblyProcessor is a row of the AssemblyProcessor table (0x21).
+type AssemblyProcessor struct {
+	// Processor.
+	Processor uint32
+}
+
+// AssemblyOS is a row of the AssemblyOS table (0x22).
+type AssemblyOS struct {
+	// Operating system platform identifier.
+	OSPlatformID uint32
+	// Major operating system version.
+	OSMajorVer uint32
+	// Minor operating system version.
+	OSMinorVer uint32
+}
+
+// AssemblyRef is a row of the AssemblyRef table (0x23).
+type AssemblyRef struct {
+	// Major version.
+	MajorVer uint16
+	// Minor version.
+	MinorVer uint16
+	// Build number.
+	BuildNumber uint16
+	// Revision number.
+	RevisionNum uint16
+	// Assembly flags.
+	Flags uint32
+	// Public key or public key token of referenced assembly.
+	PublicKeyOrToken BlobIndex
+	// Assembly name.
+	Name StringIndex
+	// Assembly culture.
+	Culture StringIndex
+	// Hash value.
+	HashValue BlobIndex
+}
+
+// AssemblyRefProcessor is a row of the AssemblyRefProcessor table (0x24).
+type AssemblyRefProcessor struct {
+	// Processor.
+	Processor uint32
+	// AssemblyRef.
+	AssemblyRef uint32
+}
+
+// AssemblyRefOS is a row of the AssemblyRefOS table (0x25).
+type AssemblyRefOS struct {
+	// Operating system platform identifier.
+	OSPlatformID uint32
+	// Major operating system version.
+	OSMajorVer uint32
+	// Minor operating system version.
+	OSMinorVer uint32
+	// AssemblyRef.
+	AssemblyRef uint32
+}
+
+// File is a row of the File table (0x26).
+type File struct {
+	// File attributes.
+	Flags uint32
+	// File name.
+	Name StringIndex
+	// Hash value of file contents.
+	HashValue BlobIndex
+}
+
+// ExportedType is a row of the ExportedType table (0x27).
+type ExportedType struct {
+	// Type attributes.
+	Flags uint32
+	// Hint of TypeDef row in the module defining the type.
+	TypeDefID uint32
+	// Type name.
+	Name StringIndex
+	// Type namespace.
+	Namespace StringIndex
+	// File, AssemblyRef or ExportedType (enclosing type) of the type.
+	Implementation Token
+}
+
+// ManifestResource is a row of the ManifestResource table (0x28).
+type ManifestResource struct {
+	// Offset of resource (relative to the resources of the CLR header).
+	Offset uint32
+	// Manifest resource attributes.
+	Flags uint32
+	// Resource name.
+	Name StringIndex
+	// File or AssemblyRef containing the resource; nil if in this file.
+	Implementation Token
+}
+
+// NestedClass is a row of the NestedClass table (0x29).
+type NestedClass struct {
+	// TypeDef of nested type.
+	NestedClass uint32
+	// TypeDef of enclosing type.
+	EnclosingClass uint32
+}
+
+// GenericParam is a row of the GenericParam table (0x2A).
+type GenericParam struct {
+	// Index of generic parameter, numbered from left to right, starting at 0.
+	Number uint16
+	// Generic parameter attributes.
+	Flags uint16
+	// TypeDef or MethodDef owning the generic parameter.
+	Owner Token
+	// Generic parameter name.
+	Name StringIndex
+}
+
+// MethodSpec is a row of the MethodSpec table (0x2B).
+type MethodSpec struct {
+	// MethodDef or MemberRef of generic method.
+	Method Token
+	// Instantiation signature.
+	Instantiation BlobIndex
+}
+
+// GenericParamConstraint is a row of the GenericParamConstraint table (0x2C).
+type GenericParamConstraint struct {
+	// GenericParam.
+	Owner uint32
+	// TypeDef, TypeRef or TypeSpec of constraint.
+	Constraint Token
+}
+
+// ref: Portable PDB v1.0 Format Specification, Metadata tables
+
+// Document is a row of the Document table (0x30).
+type Document struct {
+	// Document name; encoded as a document name blob.
+	Name BlobIndex
+	// Hash algorithm of the document hash (e.g. SHA-256).
+	HashAlgorithm GUIDIndex
+	// Hash of the document contents.
+	Hash BlobIndex
+	// Source language (e.g. C#).
+	Language GUIDIndex
+}
+
+// MethodDebugInformation is a row of the MethodDebugInformation table (0x31),
+// with rows corresponding one-to-one to the MethodDef table.
+type MethodDebugInformation struct {
+	// Document containing the method; nil if the method spans several
+	// documents.
+	Document uint32
+	// Sequence points of the method; encoded as a sequence points blob.
+	SequencePoints BlobIndex
+}
+
+// LocalScope is a row of the LocalScope table (0x32).
+type LocalScope struct {
+	// MethodDef containing the scope.
+	Method uint32
+	// ImportScope of the scope.
+	ImportScope uint32
+	// First LocalVariable of the scope.
+	VariableList uint32
+	// First LocalConstant of the scope.
+	ConstantList uint32
+	// IL offset of the start of the scope.
+	StartOffset uint32
+	// Length of the scope in number of bytes of IL.
+	Length uint32
+}
+
+// LocalVariable is a row of the LocalVariable table (0x33).
+type LocalVariable struct {
+	// Local variable attributes.
+	Attributes uint16
+	// Slot index of the local variable in the local signature of the method.
+	Index uint16
+	// Local variable name.
+	Name StringIndex
+}
+
+// LocalConstant is a row of the LocalConstant table (0x34).
+type LocalConstant struct {
+	// Local constant name.
+	Name StringIndex
+	// Type and value of the local constant.
+	Signature BlobIndex
+}
+
+// ImportScope is a row of the ImportScope table (0x35).
+type ImportScope struct {
+	// Enclosing ImportScope; nil if none.
+	Parent uint32
+	// Imports of the scope; encoded as an imports blob.
+	Imports BlobIndex
+}
+
+// StateMachineMethod is a row of the StateMachineMethod table (0x36).
+type StateMachineMethod struct {
+	// MethodDef of the MoveNext method of the state machine.
+	MoveNextMethod uint32
+	// MethodDef of the async or iterator method that creates the state
+	// machine.
+	KickoffMethod uint32
+}
+
+// CustomDebugInformation is a row of the CustomDebugInformation table (0x37).
+type CustomDebugInformation struct {
+	// Entity the information is associated with.
+	Parent Token
+	// Kind of custom debug information.
+	Kind GUIDIndex
+	// Custom debug information.
+	Value BlobIndex
+}
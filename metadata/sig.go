@@ -0,0 +1,397 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Element types of signatures.
+//
+// ref: ECMA-335, II.23.1.16 Element types used in signatures
+const (
+	elemVoid        = 0x01
+	elemBoolean     = 0x02
+	elemChar        = 0x03
+	elemI1          = 0x04
+	elemU1          = 0x05
+	elemI2          = 0x06
+	elemU2          = 0x07
+	elemI4          = 0x08
+	elemU4          = 0x09
+	elemI8          = 0x0A
+	elemU8          = 0x0B
+	elemR4          = 0x0C
+	elemR8          = 0x0D
+	elemString      = 0x0E
+	elemPtr         = 0x0F
+	elemByRef       = 0x10
+	elemValueType   = 0x11
+	elemClass       = 0x12
+	elemVar         = 0x13
+	elemArray       = 0x14
+	elemGenericInst = 0x15
+	elemTypedByRef  = 0x16
+	elemI           = 0x18
+	elemU           = 0x19
+	elemFnPtr       = 0x1B
+	elemObject      = 0x1C
+	elemSZArray     = 0x1D
+	elemMVar        = 0x1E
+	elemCModReqd    = 0x1F
+	elemCModOpt     = 0x20
+	elemSentinel    = 0x41
+	elemPinned      = 0x45
+)
+
+// Calling convention flags of signatures.
+//
+// ref: ECMA-335, II.23.2.1 MethodDefSig
+const (
+	sigKindMask     = 0x0F
+	sigVarArg       = 0x05
+	sigField        = 0x06
+	sigLocalVar     = 0x07
+	sigGenericInst  = 0x0A
+	sigGeneric      = 0x10
+	sigHasThis      = 0x20
+	sigExplicitThis = 0x40
+)
+
+// primitiveNames maps from primitive element types to their ILAsm names.
+var primitiveNames = map[byte]string{
+	elemVoid:       "void",
+	elemBoolean:    "bool",
+	elemChar:       "char",
+	elemI1:         "int8",
+	elemU1:         "uint8",
+	elemI2:         "int16",
+	elemU2:         "uint16",
+	elemI4:         "int32",
+	elemU4:         "uint32",
+	elemI8:         "int64",
+	elemU8:         "uint64",
+	elemR4:         "float32",
+	elemR8:         "float64",
+	elemString:     "string",
+	elemTypedByRef: "typedref",
+	elemI:          "native int",
+	elemU:          "native unsigned int",
+	elemObject:     "object",
+}
+
+// sigReader reads the contents of a signature blob. Errors are sticky; once an
+// error is encountered, all subsequent reads return zero.
+type sigReader struct {
+	// Signature blob.
+	buf []byte
+	// Current read position.
+	pos int
+	// Nesting depth of type names.
+	depth int
+	// First error encountered.
+	err error
+}
+
+// byte reads a single byte of the signature.
+func (r *sigReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if r.pos >= len(r.buf) {
+		r.err = errors.New("unexpected end of signature")
+		return 0
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b
+}
+
+// peek returns the next byte of the signature without consuming it.
+func (r *sigReader) peek() byte {
+	if r.err != nil || r.pos >= len(r.buf) {
+		return 0
+	}
+	return r.buf[r.pos]
+}
+
+// uint reads a compressed unsigned integer of the signature.
+func (r *sigReader) uint() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	v, n, err := decodeUint(r.buf[r.pos:])
+	if err != nil {
+		r.err = err
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+// count reads a compressed unsigned integer of the signature, denoting the
+// number of elements that follow.
+func (r *sigReader) count() uint32 {
+	n := r.uint()
+	// Each element occupies at least one byte.
+	if r.err == nil && int(n) > len(r.buf)-r.pos {
+		r.err = errors.Errorf("invalid element count %d of signature", n)
+		return 0
+	}
+	return n
+}
+
+// int reads a compressed signed integer of the signature.
+func (r *sigReader) int() int32 {
+	if r.err != nil {
+		return 0
+	}
+	v, n, err := decodeUint(r.buf[r.pos:])
+	if err != nil {
+		r.err = err
+		return 0
+	}
+	r.pos += n
+	// The value is rotated left by one bit, and sign extended based on its
+	// encoded width.
+	var bits uint
+	switch n {
+	case 1:
+		bits = 7
+	case 2:
+		bits = 14
+	default:
+		bits = 29
+	}
+	if v&1 == 0 {
+		return int32(v >> 1)
+	}
+	return int32(v>>1) - 1<<(bits-1)
+}
+
+// typeDefOrRefEncoded reads a TypeDefOrRefOrSpecEncoded token of the
+// signature.
+func (r *sigReader) typeDefOrRefEncoded() Token {
+	v := r.uint()
+	switch v & 0x3 {
+	case 0:
+		return NewToken(TableTypeDef, v>>2)
+	case 1:
+		return NewToken(TableTypeRef, v>>2)
+	case 2:
+		return NewToken(TableTypeSpec, v>>2)
+	default:
+		if r.err == nil {
+			r.err = errors.Errorf("invalid TypeDefOrRefOrSpecEncoded tag %d", v&0x3)
+		}
+		return 0
+	}
+}
+
+// sigType reads and formats a type of the signature.
+func (md *Metadata) sigType(r *sigReader) string {
+	elem := r.byte()
+	if name, ok := primitiveNames[elem]; ok {
+		return name
+	}
+	switch elem {
+	case elemPtr:
+		return md.sigType(r) + "*"
+	case elemByRef:
+		return md.sigType(r) + "&"
+	case elemPinned:
+		return md.sigType(r) + " pinned"
+	case elemValueType:
+		return "valuetype " + md.typeName(r.typeDefOrRefEncoded(), r.depth)
+	case elemClass:
+		return "class " + md.typeName(r.typeDefOrRefEncoded(), r.depth)
+	case elemVar:
+		return fmt.Sprintf("!%d", r.uint())
+	case elemMVar:
+		return fmt.Sprintf("!!%d", r.uint())
+	case elemSZArray:
+		return md.sigType(r) + "[]"
+	case elemArray:
+		elemType := md.sigType(r)
+		rank := r.uint()
+		// The runtime supports at most 32 array dimensions.
+		if rank > 32 && r.err == nil {
+			r.err = errors.Errorf("invalid array rank %d", rank)
+			return "?"
+		}
+		nsizes := r.count()
+		sizes := make([]uint32, nsizes)
+		for i := range sizes {
+			sizes[i] = r.uint()
+		}
+		nlobounds := r.count()
+		lobounds := make([]int32, nlobounds)
+		for i := range lobounds {
+			lobounds[i] = r.int()
+		}
+		dims := make([]string, rank)
+		for i := range dims {
+			switch {
+			case i < len(lobounds) && i < len(sizes):
+				dims[i] = fmt.Sprintf("%d...%d", lobounds[i], lobounds[i]+int32(sizes[i])-1)
+			case i < len(lobounds):
+				dims[i] = fmt.Sprintf("%d...", lobounds[i])
+			case i < len(sizes):
+				dims[i] = fmt.Sprintf("%d", sizes[i])
+			}
+		}
+		return fmt.Sprintf("%s[%s]", elemType, strings.Join(dims, ","))
+	case elemGenericInst:
+		kind := "class "
+		if r.byte() == elemValueType {
+			kind = "valuetype "
+		}
+		name := md.typeName(r.typeDefOrRefEncoded(), r.depth)
+		n := r.count()
+		args := make([]string, n)
+		for i := range args {
+			args[i] = md.sigType(r)
+		}
+		return fmt.Sprintf("%s%s<%s>", kind, name, strings.Join(args, ", "))
+	case elemFnPtr:
+		return "method " + md.methodSig(r, "*")
+	case elemCModReqd, elemCModOpt:
+		mod := "modopt"
+		if elem == elemCModReqd {
+			mod = "modreq"
+		}
+		tok := r.typeDefOrRefEncoded()
+		return fmt.Sprintf("%s %s(%s)", md.sigType(r), mod, md.typeName(tok, r.depth))
+	case elemSentinel:
+		return "..."
+	default:
+		if r.err == nil {
+			r.err = errors.Errorf("invalid element type 0x%02X", elem)
+		}
+		return "?"
+	}
+}
+
+// methodSig reads and formats a method signature, with the given name
+// inserted between return type and parameters.
+func (md *Metadata) methodSig(r *sigReader, name string) string {
+	conv := r.byte()
+	var genericArity uint32
+	if conv&sigGeneric != 0 {
+		genericArity = r.uint()
+		// Generic parameter numbers are stored as 2-byte constants.
+		if genericArity > 0xFFFF && r.err == nil {
+			r.err = errors.Errorf("invalid generic parameter count %d", genericArity)
+			return "?"
+		}
+	}
+	nparams := r.count()
+	ret := md.sigType(r)
+	params := make([]string, 0, nparams)
+	for i := uint32(0); i < nparams && r.err == nil; i++ {
+		if r.peek() == elemSentinel {
+			r.byte()
+			params = append(params, "...")
+		}
+		params = append(params, md.sigType(r))
+	}
+	var prefix string
+	if conv&sigHasThis != 0 {
+		prefix = "instance "
+	}
+	if conv&sigExplicitThis != 0 {
+		prefix += "explicit "
+	}
+	if conv&sigKindMask == sigVarArg {
+		prefix += "vararg "
+	}
+	if genericArity > 0 {
+		typeParams := make([]string, genericArity)
+		for i := range typeParams {
+			typeParams[i] = fmt.Sprintf("!!%d", i)
+		}
+		name += "<" + strings.Join(typeParams, ", ") + ">"
+	}
+	return fmt.Sprintf("%s%s %s(%s)", prefix, ret, name, strings.Join(params, ", "))
+}
+
+// MethodSig returns the ILAsm representation of the given method signature,
+// with the given method name inserted between return type and parameters.
+func (md *Metadata) MethodSig(sig BlobIndex, name string) (string, error) {
+	r := &sigReader{buf: md.Blob.Get(sig)}
+	s := md.methodSig(r, name)
+	if r.err != nil {
+		return "", errors.WithStack(r.err)
+	}
+	return s, nil
+}
+
+// FieldSig returns the ILAsm representation of the given field signature,
+// with the given field name appended to the field type.
+func (md *Metadata) FieldSig(sig BlobIndex, name string) (string, error) {
+	r := &sigReader{buf: md.Blob.Get(sig)}
+	if conv := r.byte(); conv&sigKindMask != sigField {
+		return "", errors.Errorf("invalid field signature; expected 0x%02X, got 0x%02X", sigField, conv)
+	}
+	// Skip custom modifiers.
+	for r.peek() == elemCModReqd || r.peek() == elemCModOpt {
+		r.byte()
+		r.typeDefOrRefEncoded()
+	}
+	typ := md.sigType(r)
+	if r.err != nil {
+		return "", errors.WithStack(r.err)
+	}
+	return typ + " " + name, nil
+}
+
+// LocalVarSig returns the ILAsm representation of the local variable types
+// of the given StandAloneSig token.
+func (md *Metadata) LocalVarSig(tok Token) ([]string, error) {
+	if tok.Table() != TableStandAloneSig || md.Tables == nil || tok.RID() < 1 || int(tok.RID()) > len(md.Tables.StandAloneSig) {
+		return nil, errors.Errorf("invalid local variable signature token %v", tok)
+	}
+	sig := md.Tables.StandAloneSig[tok.RID()-1].Signature
+	r := &sigReader{buf: md.Blob.Get(sig)}
+	if conv := r.byte(); conv != sigLocalVar {
+		return nil, errors.Errorf("invalid local variable signature; expected 0x%02X, got 0x%02X", sigLocalVar, conv)
+	}
+	n := r.count()
+	var locals []string
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		locals = append(locals, md.sigType(r))
+	}
+	if r.err != nil {
+		return nil, errors.WithStack(r.err)
+	}
+	return locals, nil
+}
+
+// TypeSpecSig returns the ILAsm representation of the given TypeSpec
+// signature.
+func (md *Metadata) TypeSpecSig(sig BlobIndex) (string, error) {
+	r := &sigReader{buf: md.Blob.Get(sig)}
+	typ := md.sigType(r)
+	if r.err != nil {
+		return "", errors.WithStack(r.err)
+	}
+	return typ, nil
+}
+
+// genericInstSig reads and formats a generic method instantiation signature.
+func (md *Metadata) genericInstSig(sig BlobIndex) (string, error) {
+	r := &sigReader{buf: md.Blob.Get(sig)}
+	if conv := r.byte(); conv != sigGenericInst {
+		return "", errors.Errorf("invalid generic instantiation signature; expected 0x%02X, got 0x%02X", sigGenericInst, conv)
+	}
+	n := r.count()
+	args := make([]string, n)
+	for i := range args {
+		args[i] = md.sigType(r)
+	}
+	if r.err != nil {
+		return "", errors.WithStack(r.err)
+	}
+	return "<" + strings.Join(args, ", ") + ">", nil
+}
@@ -0,0 +1,94 @@
+// Code generated by "stringer -trimprefix Table -type Table"; DO NOT EDIT.
+
+package metadata
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[TableModule-0]
+	_ = x[TableTypeRef-1]
+	_ = x[TableTypeDef-2]
+	_ = x[TableFieldPtr-3]
+	_ = x[TableField-4]
+	_ = x[TableMethodPtr-5]
+	_ = x[TableMethodDef-6]
+	_ = x[TableParamPtr-7]
+	_ = x[TableParam-8]
+	_ = x[TableInterfaceImpl-9]
+	_ = x[TableMemberRef-10]
+	_ = x[TableConstant-11]
+	_ = x[TableCustomAttribute-12]
+	_ = x[TableFieldMarshal-13]
+	_ = x[TableDeclSecurity-14]
+	_ = x[TableClassLayout-15]
+	_ = x[TableFieldLayout-16]
+	_ = x[TableStandAloneSig-17]
+	_ = x[TableEventMap-18]
+	_ = x[TableEventPtr-19]
+	_ = x[TableEvent-20]
+	_ = x[TablePropertyMap-21]
+	_ = x[TablePropertyPtr-22]
+	_ = x[TableProperty-23]
+	_ = x[TableMethodSemantics-24]
+	_ = x[TableMethodImpl-25]
+	_ = x[TableModuleRef-26]
+	_ = x[TableTypeSpec-27]
+	_ = x[TableImplMap-28]
+	_ = x[TableFieldRVA-29]
+	_ = x[TableENCLog-30]
+	_ = x[TableENCMap-31]
+	_ = x[TableAssembly-32]
+	_ = x[TableAssemblyProcessor-33]
+	_ = x[TableAssemblyOS-34]
+	_ = x[TableAssemblyRef-35]
+	_ = x[TableAssemblyRefProcessor-36]
+	_ = x[TableAssemblyRefOS-37]
+	_ = x[TableFile-38]
+	_ = x[TableExportedType-39]
+	_ = x[TableManifestResource-40]
+	_ = x[TableNestedClass-41]
+	_ = x[TableGenericParam-42]
+	_ = x[TableMethodSpec-43]
+	_ = x[TableGenericParamConstraint-44]
+	_ = x[TableDocument-48]
+	_ = x[TableMethodDebugInformation-49]
+	_ = x[TableLocalScope-50]
+	_ = x[TableLocalVariable-51]
+	_ = x[TableLocalConstant-52]
+	_ = x[TableImportScope-53]
+	_ = x[TableStateMachineMethod-54]
+	_ = x[TableCustomDebugInformation-55]
+	_ = x[TableString-112]
+	_ = x[tableNone-255]
+}
+
+const (
+	_Table_name_0 = "ModuleTypeRefTypeDefFieldPtrFieldMethodPtrMethodDefParamPtrParamInterfaceImplMemberRefConstantCustomAttributeFieldMarshalDeclSecurityClassLayoutFieldLayoutStandAloneSigEventMapEventPtrEventPropertyMapPropertyPtrPropertyMethodSemanticsMethodImplModuleRefTypeSpecImplMapFieldRVAENCLogENCMapAssemblyAssemblyProcessorAssemblyOSAssemblyRefAssemblyRefProcessorAssemblyRefOSFileExportedTypeManifestResourceNestedClassGenericParamMethodSpecGenericParamConstraint"
+	_Table_name_1 = "DocumentMethodDebugInformationLocalScopeLocalVariableLocalConstantImportScopeStateMachineMethodCustomDebugInformation"
+	_Table_name_2 = "String"
+	_Table_name_3 = "tableNone"
+)
+
+var (
+	_Table_index_0 = [...]uint16{0, 6, 13, 20, 28, 33, 42, 51, 59, 64, 77, 86, 94, 109, 121, 133, 144, 155, 168, 176, 184, 189, 200, 211, 219, 234, 244, 253, 261, 268, 276, 282, 288, 296, 313, 323, 334, 354, 367, 371, 383, 399, 410, 422, 432, 454}
+	_Table_index_1 = [...]uint8{0, 8, 30, 40, 53, 66, 77, 95, 117}
+)
+
+func (i Table) String() string {
+	switch {
+	case i <= 44:
+		return _Table_name_0[_Table_index_0[i]:_Table_index_0[i+1]]
+	case 48 <= i && i <= 55:
+		i -= 48
+		return _Table_name_1[_Table_index_1[i]:_Table_index_1[i+1]]
+	case i == 112:
+		return _Table_name_2
+	case i == 255:
+		return _Table_name_3
+	default:
+		return "Table(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
@@ -0,0 +1,676 @@
+package metadata
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Tables is the contents of the metadata tables stream (#~ or #-).
+//
+// Simple indices into other tables are stored as 1-based row indices (RIDs),
+// and coded indices are stored as metadata tokens.
+//
+// ref: ECMA-335, II.24.2.6 #~ stream
+type Tables struct {
+	// Major version of table schema.
+	MajorVer uint8
+	// Minor version of table schema.
+	MinorVer uint8
+	// Bitfield of heap index sizes.
+	HeapSizes uint8
+	// Bitfield of present tables.
+	Valid uint64
+	// Bitfield of sorted tables.
+	Sorted uint64
+	// Number of rows of each table, indexed by table identifier.
+	NRows [64]uint32
+
+	// Table contents.
+	Module                 []Module
+	TypeRef                []TypeRef
+	TypeDef                []TypeDef
+	FieldPtr               []FieldPtr
+	Field                  []Field
+	MethodPtr              []MethodPtr
+	MethodDef              []MethodDef
+	ParamPtr               []ParamPtr
+	Param                  []Param
+	InterfaceImpl          []InterfaceImpl
+	MemberRef              []MemberRef
+	Constant               []Constant
+	CustomAttribute        []CustomAttribute
+	FieldMarshal           []FieldMarshal
+	DeclSecurity           []DeclSecurity
+	ClassLayout            []ClassLayout
+	FieldLayout            []FieldLayout
+	StandAloneSig          []StandAloneSig
+	EventMap               []EventMap
+	EventPtr               []EventPtr
+	Event                  []Event
+	PropertyMap            []PropertyMap
+	PropertyPtr            []PropertyPtr
+	Property               []Property
+	MethodSemantics        []MethodSemantics
+	MethodImpl             []MethodImpl
+	ModuleRef              []ModuleRef
+	TypeSpec               []TypeSpec
+	ImplMap                []ImplMap
+	FieldRVA               []FieldRVA
+	ENCLog                 []ENCLog
+	ENCMap                 []ENCMap
+	Assembly               []Assembly
+	AssemblyProcessor      []AssemblyProcessor
+	AssemblyOS             []AssemblyOS
+	AssemblyRef            []AssemblyRef
+	AssemblyRefProcessor   []AssemblyRefProcessor
+	AssemblyRefOS          []AssemblyRefOS
+	File                   []File
+	ExportedType           []ExportedType
+	ManifestResource       []ManifestResource
+	NestedClass            []NestedClass
+	GenericParam           []GenericParam
+	MethodSpec             []MethodSpec
+	GenericParamConstraint []GenericParamConstraint
+	// Portable PDB table contents.
+	Document               []Document
+	MethodDebugInformation []MethodDebugInformation
+	LocalScope             []LocalScope
+	LocalVariable          []LocalVariable
+	LocalConstant          []LocalConstant
+	ImportScope            []ImportScope
+	StateMachineMethod     []StateMachineMethod
+	CustomDebugInformation []CustomDebugInformation
+}
+
+// Heap size flags of the tables stream header.
+const (
+	// Indices into #Strings heap are 4 bytes wide.
+	heapSizeStrings = 0x01
+	// Indices into #GUID heap are 4 bytes wide.
+	heapSizeGUID = 0x02
+	// Indices into #Blob heap are 4 bytes wide.
+	heapSizeBlob = 0x04
+	// Four bytes of extra data follow the row counts.
+	heapSizeExtraData = 0x40
+)
+
+// parseTables parses the given metadata tables stream. The row counts of type
+// system tables referenced by a portable PDB are taken from the given #Pdb
+// stream; pdb is nil for regular metadata.
+func parseTables(buf []byte, pdb *PDBStream) (*Tables, error) {
+	if len(buf) < 24 {
+		return nil, errors.Errorf("metadata tables stream too short; expected >= 24 bytes, got %d", len(buf))
+	}
+	t := &Tables{
+		MajorVer:  buf[4],
+		MinorVer:  buf[5],
+		HeapSizes: buf[6],
+		Valid:     binary.LittleEndian.Uint64(buf[8:]),
+		Sorted:    binary.LittleEndian.Uint64(buf[16:]),
+	}
+	d := &tableDecoder{
+		buf:       buf,
+		pos:       24,
+		heapSizes: t.HeapSizes,
+	}
+	for i := 0; i < 64; i++ {
+		if t.Valid&(1<<uint(i)) == 0 {
+			continue
+		}
+		t.NRows[i] = d.u32()
+	}
+	if t.HeapSizes&heapSizeExtraData != 0 {
+		d.u32()
+	}
+	if d.err != nil {
+		return nil, errors.WithStack(d.err)
+	}
+	d.nrows = t.NRows
+	if pdb != nil {
+		// Type system tables are stored in the associated module, but their
+		// row counts determine the index sizes of portable PDB tables.
+		for i := 0; i < 64; i++ {
+			if pdb.ReferencedTables&(1<<uint(i)) != 0 {
+				d.nrows[i] = pdb.TypeSystemNRows[i]
+			}
+		}
+	}
+	if err := d.decode(t); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return t, nil
+}
+
+// decode decodes the contents of each present table, in order of table
+// identifier.
+func (d *tableDecoder) decode(t *Tables) error {
+	for i := 0; i < 64; i++ {
+		if t.Valid&(1<<uint(i)) == 0 {
+			continue
+		}
+		n := int(t.NRows[i])
+		switch Table(i) {
+		case TableModule:
+			for j := 0; j < n; j++ {
+				t.Module = append(t.Module, Module{
+					Generation: d.u16(),
+					Name:       d.str(),
+					Mvid:       d.guid(),
+					EncID:      d.guid(),
+					EncBaseID:  d.guid(),
+				})
+			}
+		case TableTypeRef:
+			for j := 0; j < n; j++ {
+				t.TypeRef = append(t.TypeRef, TypeRef{
+					ResolutionScope: d.coded(resolutionScope),
+					Name:            d.str(),
+					Namespace:       d.str(),
+				})
+			}
+		case TableTypeDef:
+			for j := 0; j < n; j++ {
+				t.TypeDef = append(t.TypeDef, TypeDef{
+					Flags:      d.u32(),
+					Name:       d.str(),
+					Namespace:  d.str(),
+					Extends:    d.coded(typeDefOrRef),
+					FieldList:  d.index(TableField),
+					MethodList: d.index(TableMethodDef),
+				})
+			}
+		case TableFieldPtr:
+			for j := 0; j < n; j++ {
+				t.FieldPtr = append(t.FieldPtr, FieldPtr{
+					Field: d.index(TableField),
+				})
+			}
+		case TableField:
+			for j := 0; j < n; j++ {
+				t.Field = append(t.Field, Field{
+					Flags:     d.u16(),
+					Name:      d.str(),
+					Signature: d.blob(),
+				})
+			}
+		case TableMethodPtr:
+			for j := 0; j < n; j++ {
+				t.MethodPtr = append(t.MethodPtr, MethodPtr{
+					Method: d.index(TableMethodDef),
+				})
+			}
+		case TableMethodDef:
+			for j := 0; j < n; j++ {
+				t.MethodDef = append(t.MethodDef, MethodDef{
+					RelAddr:   d.u32(),
+					ImplFlags: d.u16(),
+					Flags:     d.u16(),
+					Name:      d.str(),
+					Signature: d.blob(),
+					ParamList: d.index(TableParam),
+				})
+			}
+		case TableParamPtr:
+			for j := 0; j < n; j++ {
+				t.ParamPtr = append(t.ParamPtr, ParamPtr{
+					Param: d.index(TableParam),
+				})
+			}
+		case TableParam:
+			for j := 0; j < n; j++ {
+				t.Param = append(t.Param, Param{
+					Flags:    d.u16(),
+					Sequence: d.u16(),
+					Name:     d.str(),
+				})
+			}
+		case TableInterfaceImpl:
+			for j := 0; j < n; j++ {
+				t.InterfaceImpl = append(t.InterfaceImpl, InterfaceImpl{
+					Class:     d.index(TableTypeDef),
+					Interface: d.coded(typeDefOrRef),
+				})
+			}
+		case TableMemberRef:
+			for j := 0; j < n; j++ {
+				t.MemberRef = append(t.MemberRef, MemberRef{
+					Class:     d.coded(memberRefParent),
+					Name:      d.str(),
+					Signature: d.blob(),
+				})
+			}
+		case TableConstant:
+			for j := 0; j < n; j++ {
+				t.Constant = append(t.Constant, Constant{
+					// Type is followed by a 1-byte padding.
+					Type:   uint8(d.u16()),
+					Parent: d.coded(hasConstant),
+					Value:  d.blob(),
+				})
+			}
+		case TableCustomAttribute:
+			for j := 0; j < n; j++ {
+				t.CustomAttribute = append(t.CustomAttribute, CustomAttribute{
+					Parent: d.coded(hasCustomAttribute),
+					Type:   d.coded(customAttributeType),
+					Value:  d.blob(),
+				})
+			}
+		case TableFieldMarshal:
+			for j := 0; j < n; j++ {
+				t.FieldMarshal = append(t.FieldMarshal, FieldMarshal{
+					Parent:     d.coded(hasFieldMarshal),
+					NativeType: d.blob(),
+				})
+			}
+		case TableDeclSecurity:
+			for j := 0; j < n; j++ {
+				t.DeclSecurity = append(t.DeclSecurity, DeclSecurity{
+					Action:        d.u16(),
+					Parent:        d.coded(hasDeclSecurity),
+					PermissionSet: d.blob(),
+				})
+			}
+		case TableClassLayout:
+			for j := 0; j < n; j++ {
+				t.ClassLayout = append(t.ClassLayout, ClassLayout{
+					PackingSize: d.u16(),
+					ClassSize:   d.u32(),
+					Parent:      d.index(TableTypeDef),
+				})
+			}
+		case TableFieldLayout:
+			for j := 0; j < n; j++ {
+				t.FieldLayout = append(t.FieldLayout, FieldLayout{
+					Offset: d.u32(),
+					Field:  d.index(TableField),
+				})
+			}
+		case TableStandAloneSig:
+			for j := 0; j < n; j++ {
+				t.StandAloneSig = append(t.StandAloneSig, StandAloneSig{
+					Signature: d.blob(),
+				})
+			}
+		case TableEventMap:
+			for j := 0; j < n; j++ {
+				t.EventMap = append(t.EventMap, EventMap{
+					Parent:    d.index(TableTypeDef),
+					EventList: d.index(TableEvent),
+				})
+			}
+		case TableEventPtr:
+			for j := 0; j < n; j++ {
+				t.EventPtr = append(t.EventPtr, EventPtr{
+					Event: d.index(TableEvent),
+				})
+			}
+		case TableEvent:
+			for j := 0; j < n; j++ {
+				t.Event = append(t.Event, Event{
+					Flags:     d.u16(),
+					Name:      d.str(),
+					EventType: d.coded(typeDefOrRef),
+				})
+			}
+		case TablePropertyMap:
+			for j := 0; j < n; j++ {
+				t.PropertyMap = append(t.PropertyMap, PropertyMap{
+					Parent:       d.index(TableTypeDef),
+					PropertyList: d.index(TableProperty),
+				})
+			}
+		case TablePropertyPtr:
+			for j := 0; j < n; j++ {
+				t.PropertyPtr = append(t.PropertyPtr, PropertyPtr{
+					Property: d.index(TableProperty),
+				})
+			}
+		case TableProperty:
+			for j := 0; j < n; j++ {
+				t.Property = append(t.Property, Property{
+					Flags:     d.u16(),
+					Name:      d.str(),
+					Signature: d.blob(),
+				})
+			}
+		case TableMethodSemantics:
+			for j := 0; j < n; j++ {
+				t.MethodSemantics = append(t.MethodSemantics, MethodSemantics{
+					Semantics:   d.u16(),
+					Method:      d.index(TableMethodDef),
+					Association: d.coded(hasSemantics),
+				})
+			}
+		case TableMethodImpl:
+			for j := 0; j < n; j++ {
+				t.MethodImpl = append(t.MethodImpl, MethodImpl{
+					Class:             d.index(TableTypeDef),
+					MethodBody:        d.coded(methodDefOrRef),
+					MethodDeclaration: d.coded(methodDefOrRef),
+				})
+			}
+		case TableModuleRef:
+			for j := 0; j < n; j++ {
+				t.ModuleRef = append(t.ModuleRef, ModuleRef{
+					Name: d.str(),
+				})
+			}
+		case TableTypeSpec:
+			for j := 0; j < n; j++ {
+				t.TypeSpec = append(t.TypeSpec, TypeSpec{
+					Signature: d.blob(),
+				})
+			}
+		case TableImplMap:
+			for j := 0; j < n; j++ {
+				t.ImplMap = append(t.ImplMap, ImplMap{
+					MappingFlags:    d.u16(),
+					MemberForwarded: d.coded(memberForwarded),
+					ImportName:      d.str(),
+					ImportScope:     d.index(TableModuleRef),
+				})
+			}
+		case TableFieldRVA:
+			for j := 0; j < n; j++ {
+				t.FieldRVA = append(t.FieldRVA, FieldRVA{
+					RelAddr: d.u32(),
+					Field:   d.index(TableField),
+				})
+			}
+		case TableENCLog:
+			for j := 0; j < n; j++ {
+				t.ENCLog = append(t.ENCLog, ENCLog{
+					Token:    Token(d.u32()),
+					FuncCode: d.u32(),
+				})
+			}
+		case TableENCMap:
+			for j := 0; j < n; j++ {
+				t.ENCMap = append(t.ENCMap, ENCMap{
+					Token: Token(d.u32()),
+				})
+			}
+		case TableAssembly:
+			for j := 0; j < n; j++ {
+				t.Assembly = append(t.Assembly, Assembly{
+					HashAlgID:   d.u32(),
+					MajorVer:    d.u16(),
+					MinorVer:    d.u16(),
+					BuildNumber: d.u16(),
+					RevisionNum: d.u16(),
+					Flags:       d.u32(),
+					PublicKey:   d.blob(),
+					Name:        d.str(),
+					Culture:     d.str(),
+				})
+			}
+		case TableAssemblyProcessor:
+			for j := 0; j < n; j++ {
+				t.AssemblyProcessor = append(t.AssemblyProcessor, AssemblyProcessor{
+					Processor: d.u32(),
+				})
+			}
+		case TableAssemblyOS:
+			for j := 0; j < n; j++ {
+				t.AssemblyOS = append(t.AssemblyOS, AssemblyOS{
+					OSPlatformID: d.u32(),
+					OSMajorVer:   d.u32(),
+					OSMinorVer:   d.u32(),
+				})
+			}
+		case TableAssemblyRef:
+			for j := 0; j < n; j++ {
+				t.AssemblyRef = append(t.AssemblyRef, AssemblyRef{
+					MajorVer:         d.u16(),
+					MinorVer:         d.u16(),
+					BuildNumber:      d.u16(),
+					RevisionNum:      d.u16(),
+					Flags:            d.u32(),
+					PublicKeyOrToken: d.blob(),
+					Name:             d.str(),
+					Culture:          d.str(),
+					HashValue:        d.blob(),
+				})
+			}
+		case TableAssemblyRefProcessor:
+			for j := 0; j < n; j++ {
+				t.AssemblyRefProcessor = append(t.AssemblyRefProcessor, AssemblyRefProcessor{
+					Processor:   d.u32(),
+					AssemblyRef: d.index(TableAssemblyRef),
+				})
+			}
+		case TableAssemblyRefOS:
+			for j := 0; j < n; j++ {
+				t.AssemblyRefOS = append(t.AssemblyRefOS, AssemblyRefOS{
+					OSPlatformID: d.u32(),
+					OSMajorVer:   d.u32(),
+					OSMinorVer:   d.u32(),
+					AssemblyRef:  d.index(TableAssemblyRef),
+				})
+			}
+		case TableFile:
+			for j := 0; j < n; j++ {
+				t.File = append(t.File, File{
+					Flags:     d.u32(),
+					Name:      d.str(),
+					HashValue: d.blob(),
+				})
+			}
+		case TableExportedType:
+			for j := 0; j < n; j++ {
+				t.ExportedType = append(t.ExportedType, ExportedType{
+					Flags:          d.u32(),
+					TypeDefID:      d.u32(),
+					Name:           d.str(),
+					Namespace:      d.str(),
+					Implementation: d.coded(implementation),
+				})
+			}
+		case TableManifestResource:
+			for j := 0; j < n; j++ {
+				t.ManifestResource = append(t.ManifestResource, ManifestResource{
+					Offset:         d.u32(),
+					Flags:          d.u32(),
+					Name:           d.str(),
+					Implementation: d.coded(implementation),
+				})
+			}
+		case TableNestedClass:
+			for j := 0; j < n; j++ {
+				t.NestedClass = append(t.NestedClass, NestedClass{
+					NestedClass:    d.index(TableTypeDef),
+					EnclosingClass: d.index(TableTypeDef),
+				})
+			}
+		case TableGenericParam:
+			for j := 0; j < n; j++ {
+				t.GenericParam = append(t.GenericParam, GenericParam{
+					Number: d.u16(),
+					Flags:  d.u16(),
+					Owner:  d.coded(typeOrMethodDef),
+					Name:   d.str(),
+				})
+			}
+		case TableMethodSpec:
+			for j := 0; j < n; j++ {
+				t.MethodSpec = append(t.MethodSpec, MethodSpec{
+					Method:        d.coded(methodDefOrRef),
+					Instantiation: d.blob(),
+				})
+			}
+		case TableGenericParamConstraint:
+			for j := 0; j < n; j++ {
+				t.GenericParamConstraint = append(t.GenericParamConstraint, GenericParamConstraint{
+					Owner:      d.index(TableGenericParam),
+					Constraint: d.coded(typeDefOrRef),
+				})
+			}
+		case TableDocument:
+			for j := 0; j < n; j++ {
+				t.Document = append(t.Document, Document{
+					Name:          d.blob(),
+					HashAlgorithm: d.guid(),
+					Hash:          d.blob(),
+					Language:      d.guid(),
+				})
+			}
+		case TableMethodDebugInformation:
+			for j := 0; j < n; j++ {
+				t.MethodDebugInformation = append(t.MethodDebugInformation, MethodDebugInformation{
+					Document:       d.index(TableDocument),
+					SequencePoints: d.blob(),
+				})
+			}
+		case TableLocalScope:
+			for j := 0; j < n; j++ {
+				t.LocalScope = append(t.LocalScope, LocalScope{
+					Method:       d.index(TableMethodDef),
+					ImportScope:  d.index(TableImportScope),
+					VariableList: d.index(TableLocalVariable),
+					ConstantList: d.index(TableLocalConstant),
+					StartOffset:  d.u32(),
+					Length:       d.u32(),
+				})
+			}
+		case TableLocalVariable:
+			for j := 0; j < n; j++ {
+				t.LocalVariable = append(t.LocalVariable, LocalVariable{
+					Attributes: d.u16(),
+					Index:      d.u16(),
+					Name:       d.str(),
+				})
+			}
+		case TableLocalConstant:
+			for j := 0; j < n; j++ {
+				t.LocalConstant = append(t.LocalConstant, LocalConstant{
+					Name:      d.str(),
+					Signature: d.blob(),
+				})
+			}
+		case TableImportScope:
+			for j := 0; j < n; j++ {
+				t.ImportScope = append(t.ImportScope, ImportScope{
+					Parent:  d.index(TableImportScope),
+					Imports: d.blob(),
+				})
+			}
+		case TableStateMachineMethod:
+			for j := 0; j < n; j++ {
+				t.StateMachineMethod = append(t.StateMachineMethod, StateMachineMethod{
+					MoveNextMethod: d.index(TableMethodDef),
+					KickoffMethod:  d.index(TableMethodDef),
+				})
+			}
+		case TableCustomDebugInformation:
+			for j := 0; j < n; j++ {
+				t.CustomDebugInformation = append(t.CustomDebugInformation, CustomDebugInformation{
+					Parent: d.coded(hasCustomDebugInformation),
+					Kind:   d.guid(),
+					Value:  d.blob(),
+				})
+			}
+		default:
+			return errors.Errorf("support for metadata table 0x%02X not yet implemented", i)
+		}
+		if d.err != nil {
+			return errors.Wrapf(d.err, "unable to decode %v table", Table(i))
+		}
+	}
+	return nil
+}
+
+// tableDecoder decodes rows of metadata tables. Errors are sticky; once an
+// error is encountered, all subsequent reads return zero.
+type tableDecoder struct {
+	// Contents of tables stream.
+	buf []byte
+	// Current read position.
+	pos int
+	// Bitfield of heap index sizes.
+	heapSizes uint8
+	// Number of rows of each table, indexed by table identifier.
+	nrows [64]uint32
+	// First error encountered.
+	err error
+}
+
+// read returns the next n bytes of the tables stream.
+func (d *tableDecoder) read(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if d.pos+n > len(d.buf) {
+		d.err = errors.WithStack(io.ErrUnexpectedEOF)
+		return nil
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+// u16 decodes a 2-byte constant.
+func (d *tableDecoder) u16() uint16 {
+	b := d.read(2)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(b)
+}
+
+// u32 decodes a 4-byte constant.
+func (d *tableDecoder) u32() uint32 {
+	b := d.read(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+// uint decodes a 2- or 4-byte index, based on wide.
+func (d *tableDecoder) uint(wide bool) uint32 {
+	if wide {
+		return d.u32()
+	}
+	return uint32(d.u16())
+}
+
+// str decodes an index into the #Strings heap.
+func (d *tableDecoder) str() StringIndex {
+	return StringIndex(d.uint(d.heapSizes&heapSizeStrings != 0))
+}
+
+// guid decodes an index into the #GUID heap.
+func (d *tableDecoder) guid() GUIDIndex {
+	return GUIDIndex(d.uint(d.heapSizes&heapSizeGUID != 0))
+}
+
+// blob decodes an index into the #Blob heap.
+func (d *tableDecoder) blob() BlobIndex {
+	return BlobIndex(d.uint(d.heapSizes&heapSizeBlob != 0))
+}
+
+// index decodes a simple index into the given table.
+func (d *tableDecoder) index(table Table) uint32 {
+	return d.uint(d.nrows[table] >= 1<<16)
+}
+
+// coded decodes a coded index of the given kind, returning the corresponding
+// metadata token.
+func (d *tableDecoder) coded(kind codedIndex) Token {
+	var max uint32
+	for _, table := range kind.tables {
+		if table != tableNone && d.nrows[table] > max {
+			max = d.nrows[table]
+		}
+	}
+	v := d.uint(max >= 1<<(16-kind.bits))
+	tag := v & (1<<kind.bits - 1)
+	rid := v >> kind.bits
+	if int(tag) >= len(kind.tables) || kind.tables[tag] == tableNone {
+		return 0
+	}
+	return NewToken(kind.tables[tag], rid)
+}
@@ -0,0 +1,195 @@
+package metadata
+
+import "fmt"
+
+//go:generate stringer -trimprefix Table -type Table
+
+// Table is a metadata table identifier.
+type Table uint8
+
+// Metadata tables.
+//
+// ref: ECMA-335, II.22 Metadata logical format: tables
+const (
+	TableModule                 Table = 0x00
+	TableTypeRef                Table = 0x01
+	TableTypeDef                Table = 0x02
+	TableFieldPtr               Table = 0x03
+	TableField                  Table = 0x04
+	TableMethodPtr              Table = 0x05
+	TableMethodDef              Table = 0x06
+	TableParamPtr               Table = 0x07
+	TableParam                  Table = 0x08
+	TableInterfaceImpl          Table = 0x09
+	TableMemberRef              Table = 0x0A
+	TableConstant               Table = 0x0B
+	TableCustomAttribute        Table = 0x0C
+	TableFieldMarshal           Table = 0x0D
+	TableDeclSecurity           Table = 0x0E
+	TableClassLayout            Table = 0x0F
+	TableFieldLayout            Table = 0x10
+	TableStandAloneSig          Table = 0x11
+	TableEventMap               Table = 0x12
+	TableEventPtr               Table = 0x13
+	TableEvent                  Table = 0x14
+	TablePropertyMap            Table = 0x15
+	TablePropertyPtr            Table = 0x16
+	TableProperty               Table = 0x17
+	TableMethodSemantics        Table = 0x18
+	TableMethodImpl             Table = 0x19
+	TableModuleRef              Table = 0x1A
+	TableTypeSpec               Table = 0x1B
+	TableImplMap                Table = 0x1C
+	TableFieldRVA               Table = 0x1D
+	TableENCLog                 Table = 0x1E
+	TableENCMap                 Table = 0x1F
+	TableAssembly               Table = 0x20
+	TableAssemblyProcessor      Table = 0x21
+	TableAssemblyOS             Table = 0x22
+	TableAssemblyRef            Table = 0x23
+	TableAssemblyRefProcessor   Table = 0x24
+	TableAssemblyRefOS          Table = 0x25
+	TableFile                   Table = 0x26
+	TableExportedType           Table = 0x27
+	TableManifestResource       Table = 0x28
+	TableNestedClass            Table = 0x29
+	TableGenericParam           Table = 0x2A
+	TableMethodSpec             Table = 0x2B
+	TableGenericParamConstraint Table = 0x2C
+	// Portable PDB tables.
+	//
+	// ref: Portable PDB v1.0 Format Specification, Metadata tables
+	TableDocument               Table = 0x30
+	TableMethodDebugInformation Table = 0x31
+	TableLocalScope             Table = 0x32
+	TableLocalVariable          Table = 0x33
+	TableLocalConstant          Table = 0x34
+	TableImportScope            Table = 0x35
+	TableStateMachineMethod     Table = 0x36
+	TableCustomDebugInformation Table = 0x37
+	// Pseudo-table of user string tokens (#US heap offsets).
+	TableString Table = 0x70
+)
+
+// Token is a metadata token, identifying a row of a metadata table.
+//
+//    // Table identifier.
+//    Table : 8
+//    // 1-based row index; zero denotes a null token.
+//    RID   : 24
+type Token uint32
+
+// NewToken returns a metadata token for the given row of the given table.
+func NewToken(table Table, rid uint32) Token {
+	return Token(uint32(table)<<24 | rid&0x00FFFFFF)
+}
+
+// Table returns the table identifier of the token.
+func (tok Token) Table() Table {
+	return Table(tok >> 24)
+}
+
+// RID returns the 1-based row index of the token.
+func (tok Token) RID() uint32 {
+	return uint32(tok & 0x00FFFFFF)
+}
+
+// IsNil reports whether the token refers to no row.
+func (tok Token) IsNil() bool {
+	return tok.RID() == 0
+}
+
+// String returns the string representation of the token.
+func (tok Token) String() string {
+	return fmt.Sprintf("%s(0x%08X)", tok.Table(), uint32(tok))
+}
+
+// codedIndex is a coded index kind, which refers to a row in one of several
+// tables.
+//
+// ref: ECMA-335, II.24.2.6 #~ stream
+type codedIndex struct {
+	// Number of low-order bits used to encode the table.
+	bits uint
+	// Tables indexed by the tag; tableNone denotes an unused tag.
+	tables []Table
+}
+
+// tableNone denotes an unused tag value of a coded index.
+const tableNone Table = 0xFF
+
+// Coded index kinds.
+var (
+	typeDefOrRef = codedIndex{
+		bits:   2,
+		tables: []Table{TableTypeDef, TableTypeRef, TableTypeSpec},
+	}
+	hasConstant = codedIndex{
+		bits:   2,
+		tables: []Table{TableField, TableParam, TableProperty},
+	}
+	hasCustomAttribute = codedIndex{
+		bits: 5,
+		tables: []Table{
+			TableMethodDef, TableField, TableTypeRef, TableTypeDef, TableParam,
+			TableInterfaceImpl, TableMemberRef, TableModule, TableDeclSecurity,
+			TableProperty, TableEvent, TableStandAloneSig, TableModuleRef,
+			TableTypeSpec, TableAssembly, TableAssemblyRef, TableFile,
+			TableExportedType, TableManifestResource, TableGenericParam,
+			TableGenericParamConstraint, TableMethodSpec,
+		},
+	}
+	hasFieldMarshal = codedIndex{
+		bits:   1,
+		tables: []Table{TableField, TableParam},
+	}
+	hasDeclSecurity = codedIndex{
+		bits:   2,
+		tables: []Table{TableTypeDef, TableMethodDef, TableAssembly},
+	}
+	memberRefParent = codedIndex{
+		bits:   3,
+		tables: []Table{TableTypeDef, TableTypeRef, TableModuleRef, TableMethodDef, TableTypeSpec},
+	}
+	hasSemantics = codedIndex{
+		bits:   1,
+		tables: []Table{TableEvent, TableProperty},
+	}
+	methodDefOrRef = codedIndex{
+		bits:   1,
+		tables: []Table{TableMethodDef, TableMemberRef},
+	}
+	memberForwarded = codedIndex{
+		bits:   1,
+		tables: []Table{TableField, TableMethodDef},
+	}
+	implementation = codedIndex{
+		bits:   2,
+		tables: []Table{TableFile, TableAssemblyRef, TableExportedType},
+	}
+	customAttributeType = codedIndex{
+		bits:   3,
+		tables: []Table{tableNone, tableNone, TableMethodDef, TableMemberRef, tableNone},
+	}
+	resolutionScope = codedIndex{
+		bits:   2,
+		tables: []Table{TableModule, TableModuleRef, TableAssemblyRef, TableTypeRef},
+	}
+	typeOrMethodDef = codedIndex{
+		bits:   1,
+		tables: []Table{TableTypeDef, TableMethodDef},
+	}
+	hasCustomDebugInformation = codedIndex{
+		bits: 5,
+		tables: []Table{
+			TableMethodDef, TableField, TableTypeRef, TableTypeDef, TableParam,
+			TableInterfaceImpl, TableMemberRef, TableModule, TableDeclSecurity,
+			TableProperty, TableEvent, TableStandAloneSig, TableModuleRef,
+			TableTypeSpec, TableAssembly, TableAssemblyRef, TableFile,
+			TableExportedType, TableManifestResource, TableGenericParam,
+			TableGenericParamConstraint, TableMethodSpec, TableDocument,
+			TableLocalScope, TableLocalVariable, TableLocalConstant,
+			TableImportScope,
+		},
+	}
+)
@@ -0,0 +1,281 @@
+package pe
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// --- [ NativeFormat ] --------------------------------------------------------
+
+// nativeReader decodes NativeFormat encoded data of ReadyToRun images, as
+// addressed by relative addresses (relative to image base).
+//
+// ref: https://github.com/dotnet/runtime/blob/main/src/coreclr/vm/nativeformatreader.h
+type nativeReader struct {
+	// Section data containing the encoded data.
+	buf []byte
+	// Relative address of the section data.
+	base uint32
+}
+
+// newNativeReader returns a new NativeFormat reader for the section containing
+// the given relative address.
+func (file *File) newNativeReader(relAddr uint32) (*nativeReader, error) {
+	buf, base, err := file.sectionData(relAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &nativeReader{buf: buf, base: base}, nil
+}
+
+// bytes returns n bytes at the given relative address.
+func (r *nativeReader) bytes(relAddr uint32, n uint32) ([]byte, error) {
+	if relAddr < r.base || uint64(relAddr-r.base)+uint64(n) > uint64(len(r.buf)) {
+		return nil, errors.Errorf("NativeFormat data at relative address 0x%08X (%d bytes) out of bounds", relAddr, n)
+	}
+	start := relAddr - r.base
+	return r.buf[start : start+n], nil
+}
+
+// uint8 decodes an unsigned 8-bit integer at the given relative address.
+func (r *nativeReader) uint8(relAddr uint32) (uint32, error) {
+	b, err := r.bytes(relAddr, 1)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0]), nil
+}
+
+// uint16 decodes an unsigned 16-bit integer at the given relative address.
+func (r *nativeReader) uint16(relAddr uint32) (uint32, error) {
+	b, err := r.bytes(relAddr, 2)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(binary.LittleEndian.Uint16(b)), nil
+}
+
+// uint32 decodes an unsigned 32-bit integer at the given relative address.
+func (r *nativeReader) uint32(relAddr uint32) (uint32, error) {
+	b, err := r.bytes(relAddr, 4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// decodeUnsigned decodes a variable-length unsigned integer at the given
+// relative address, returning the value and the relative address following
+// the encoded value.
+func (r *nativeReader) decodeUnsigned(relAddr uint32) (uint32, uint32, error) {
+	b, err := r.bytes(relAddr, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	n := encodedSize(b[0])
+	if n == 0 {
+		return 0, 0, errors.Errorf("invalid NativeFormat encoding 0x%02X at relative address 0x%08X", b[0], relAddr)
+	}
+	p, err := r.bytes(relAddr, n)
+	if err != nil {
+		return 0, 0, err
+	}
+	var v uint32
+	switch n {
+	case 1:
+		v = uint32(p[0]) >> 1
+	case 2:
+		v = uint32(p[0])>>2 | uint32(p[1])<<6
+	case 3:
+		v = uint32(p[0])>>3 | uint32(p[1])<<5 | uint32(p[2])<<13
+	case 4:
+		v = uint32(p[0])>>4 | uint32(p[1])<<4 | uint32(p[2])<<12 | uint32(p[3])<<20
+	case 5:
+		v = binary.LittleEndian.Uint32(p[1:])
+	}
+	return v, relAddr + n, nil
+}
+
+// decodeSigned decodes a variable-length signed integer at the given relative
+// address, returning the value and the relative address following the encoded
+// value.
+func (r *nativeReader) decodeSigned(relAddr uint32) (int32, uint32, error) {
+	b, err := r.bytes(relAddr, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	n := encodedSize(b[0])
+	if n == 0 {
+		return 0, 0, errors.Errorf("invalid NativeFormat encoding 0x%02X at relative address 0x%08X", b[0], relAddr)
+	}
+	p, err := r.bytes(relAddr, n)
+	if err != nil {
+		return 0, 0, err
+	}
+	var v int32
+	switch n {
+	case 1:
+		v = int32(int8(p[0])) >> 1
+	case 2:
+		v = int32(p[0]>>2) | int32(int8(p[1]))<<6
+	case 3:
+		v = int32(p[0]>>3) | int32(p[1])<<5 | int32(int8(p[2]))<<13
+	case 4:
+		v = int32(p[0]>>4) | int32(p[1])<<4 | int32(p[2])<<12 | int32(int8(p[3]))<<20
+	case 5:
+		v = int32(binary.LittleEndian.Uint32(p[1:]))
+	}
+	return v, relAddr + n, nil
+}
+
+// encodedSize returns the size in bytes of a variable-length integer based on
+// its first byte, or zero if invalid.
+func encodedSize(b byte) uint32 {
+	switch {
+	case b&0x01 == 0:
+		return 1
+	case b&0x02 == 0:
+		return 2
+	case b&0x04 == 0:
+		return 3
+	case b&0x08 == 0:
+		return 4
+	case b&0x10 == 0:
+		return 5
+	}
+	return 0
+}
+
+// ~~~ [ NativeArray ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// nativeArrayBlockSize is the number of elements per block of a NativeArray.
+const nativeArrayBlockSize = 16
+
+// nativeArray is a sparse array of NativeFormat encoded elements.
+type nativeArray struct {
+	r *nativeReader
+	// Relative address of block index.
+	base uint32
+	// Number of elements.
+	n uint32
+	// Size of block index entries (0: 1 byte, 1: 2 bytes, 2: 4 bytes).
+	entryIndexSize uint32
+}
+
+// newNativeArray returns the NativeArray at the given relative address.
+func (r *nativeReader) newNativeArray(relAddr uint32) (*nativeArray, error) {
+	v, base, err := r.decodeUnsigned(relAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	arr := &nativeArray{
+		r:              r,
+		base:           base,
+		n:              v >> 2,
+		entryIndexSize: v & 0x3,
+	}
+	return arr, nil
+}
+
+// get returns the relative address of the element at the given index, and a
+// boolean indicating whether the element is present.
+func (arr *nativeArray) get(index uint32) (uint32, bool, error) {
+	if index >= arr.n {
+		return 0, false, nil
+	}
+	var (
+		offset uint32
+		err    error
+	)
+	block := index / nativeArrayBlockSize
+	switch arr.entryIndexSize {
+	case 0:
+		offset, err = arr.r.uint8(arr.base + block)
+	case 1:
+		offset, err = arr.r.uint16(arr.base + 2*block)
+	default:
+		offset, err = arr.r.uint32(arr.base + 4*block)
+	}
+	if err != nil {
+		return 0, false, errors.WithStack(err)
+	}
+	relAddr := arr.base + offset
+	// Walk the binary tree of the block.
+	for bit := uint32(nativeArrayBlockSize >> 1); bit > 0; bit >>= 1 {
+		v, next, err := arr.r.decodeUnsigned(relAddr)
+		if err != nil {
+			return 0, false, errors.WithStack(err)
+		}
+		if index&bit != 0 {
+			if v&0x2 != 0 {
+				relAddr += v >> 2
+				continue
+			}
+		} else if v&0x1 != 0 {
+			relAddr = next
+			continue
+		}
+		// Matching special leaf node?
+		if v&0x3 == 0 && v>>2 == index&(nativeArrayBlockSize-1) {
+			relAddr = next
+			break
+		}
+		return 0, false, nil
+	}
+	return relAddr, true, nil
+}
+
+// ~~~ [ NativeHashtable ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// nativeHashtableEntries returns the relative addresses of all entries of the
+// NativeHashtable at the given relative address.
+func (r *nativeReader) nativeHashtableEntries(relAddr uint32) ([]uint32, error) {
+	header, err := r.uint8(relAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	base := relAddr + 1
+	nbucketsShift := header >> 2
+	if nbucketsShift > 31 {
+		return nil, errors.Errorf("invalid number of NativeHashtable buckets; expected shift <= 31, got %d", nbucketsShift)
+	}
+	entryIndexSize := header & 0x3
+	nbuckets := uint32(1) << nbucketsShift
+	// bucket returns the relative address of the entries of the given bucket.
+	bucket := func(i uint32) (uint32, error) {
+		switch entryIndexSize {
+		case 0:
+			v, err := r.uint8(base + i)
+			return base + v, err
+		case 1:
+			v, err := r.uint16(base + 2*i)
+			return base + v, err
+		default:
+			v, err := r.uint32(base + 4*i)
+			return base + v, err
+		}
+	}
+	var entries []uint32
+	for i := uint32(0); i < nbuckets; i++ {
+		start, err := bucket(i)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		end, err := bucket(i + 1)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for pos := start; pos < end; {
+			// Skip low 8 bits of hash code.
+			pos++
+			delta, next, err := r.decodeSigned(pos)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			entries = append(entries, uint32(int64(pos)+int64(delta)))
+			pos = next
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,193 @@
+package pe
+
+import "testing"
+
+func TestNativeReaderDecode(t *testing.T) {
+	golden := []struct {
+		buf []byte
+		// Decoded unsigned and signed value.
+		u uint32
+		s int32
+		// Size of encoded value in number of bytes; 0 if invalid.
+		n uint32
+	}{
+		// 1 byte encoding.
+		{buf: []byte{0x00}, u: 0, s: 0, n: 1},
+		{buf: []byte{0x54}, u: 0x2A, s: 0x2A, n: 1},
+		{buf: []byte{0xFE}, u: 0x7F, s: -1, n: 1},
+		// 2 byte encoding.
+		{buf: []byte{0x05, 0x04}, u: 0x101, s: 0x101, n: 2},
+		{buf: []byte{0xFD, 0xFF}, u: 0x3FFF, s: -1, n: 2},
+		// 3 byte encoding.
+		{buf: []byte{0x0B, 0x00, 0x01}, u: 0x2001, s: 0x2001, n: 3},
+		// 4 byte encoding.
+		{buf: []byte{0x17, 0x00, 0x00, 0x01}, u: 0x100001, s: 0x100001, n: 4},
+		// 5 byte encoding.
+		{buf: []byte{0x0F, 0x78, 0x56, 0x34, 0x12}, u: 0x12345678, s: 0x12345678, n: 5},
+		{buf: []byte{0x0F, 0xFE, 0xFF, 0xFF, 0xFF}, u: 0xFFFFFFFE, s: -2, n: 5},
+		// Invalid encoding.
+		{buf: []byte{0x1F, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		// Truncated encodings.
+		{buf: []byte{}},
+		{buf: []byte{0x05}},
+		{buf: []byte{0x0B, 0x00}},
+		{buf: []byte{0x0F, 0x78, 0x56, 0x34}},
+	}
+	const base = 0x1000
+	for _, g := range golden {
+		r := &nativeReader{buf: g.buf, base: base}
+		u, next, err := r.decodeUnsigned(base)
+		if g.n == 0 {
+			if err == nil {
+				t.Errorf("% X: expected error for unsigned value", g.buf)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("% X: unable to decode unsigned value; %v", g.buf, err)
+			} else if u != g.u || next != base+g.n {
+				t.Errorf("% X: unsigned value mismatch; expected 0x%X (next 0x%X), got 0x%X (next 0x%X)", g.buf, g.u, base+g.n, u, next)
+			}
+		}
+		s, next, err := r.decodeSigned(base)
+		if g.n == 0 {
+			if err == nil {
+				t.Errorf("% X: expected error for signed value", g.buf)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("% X: unable to decode signed value; %v", g.buf, err)
+		} else if s != g.s || next != base+g.n {
+			t.Errorf("% X: signed value mismatch; expected %d (next 0x%X), got %d (next 0x%X)", g.buf, g.s, base+g.n, s, next)
+		}
+	}
+}
+
+func TestNativeReaderBounds(t *testing.T) {
+	const base = 0x1000
+	r := &nativeReader{buf: []byte{0x01, 0x02, 0x03, 0x04}, base: base}
+	golden := []struct {
+		relAddr uint32
+		n       uint32
+		valid   bool
+	}{
+		{relAddr: base, n: 4, valid: true},
+		{relAddr: base + 3, n: 1, valid: true},
+		{relAddr: base + 4, n: 0, valid: true},
+		{relAddr: base - 1, n: 1},
+		{relAddr: base + 3, n: 2},
+		{relAddr: base + 4, n: 1},
+		{relAddr: base + 1, n: 0xFFFFFFFF},
+		{relAddr: 0xFFFFFFFF, n: 2},
+	}
+	for _, g := range golden {
+		_, err := r.bytes(g.relAddr, g.n)
+		if g.valid && err != nil {
+			t.Errorf("0x%08X (%d bytes): unexpected error; %v", g.relAddr, g.n, err)
+		}
+		if !g.valid && err == nil {
+			t.Errorf("0x%08X (%d bytes): expected out of bounds error", g.relAddr, g.n)
+		}
+	}
+	if _, err := r.uint32(base + 1); err == nil {
+		t.Error("expected out of bounds error for uint32")
+	}
+	if _, err := r.uint16(base + 3); err == nil {
+		t.Error("expected out of bounds error for uint16")
+	}
+}
+
+func TestNativeArray(t *testing.T) {
+	const base = 0x1000
+	// NativeArray of 2 elements with 1-byte block index, where only element 0
+	// is present.
+	//
+	//    0x1000  header (n = 2, entryIndexSize = 0)
+	//    0x1001  block index (offset 1)
+	//    0x1002  special leaf node of element 0
+	//    0x1003  element 0
+	buf := []byte{0x10, 0x01, 0x00, 0x2A}
+	r := &nativeReader{buf: buf, base: base}
+	arr, err := r.newNativeArray(base)
+	if err != nil {
+		t.Fatalf("unable to decode NativeArray; %v", err)
+	}
+	golden := []struct {
+		index   uint32
+		relAddr uint32
+		present bool
+	}{
+		{index: 0, relAddr: base + 3, present: true},
+		{index: 1},
+		{index: 2},
+		{index: 0xFFFFFFFF},
+	}
+	for _, g := range golden {
+		relAddr, present, err := arr.get(g.index)
+		if err != nil {
+			t.Errorf("element %d: unable to locate element; %v", g.index, err)
+			continue
+		}
+		if present != g.present || relAddr != g.relAddr {
+			t.Errorf("element %d: mismatch; expected present %v at 0x%08X, got present %v at 0x%08X", g.index, g.present, g.relAddr, present, relAddr)
+		}
+	}
+	// Truncated NativeArray.
+	truncated := []struct {
+		name string
+		buf  []byte
+	}{
+		{name: "missing header", buf: []byte{}},
+		{name: "missing block index", buf: []byte{0x10}},
+		{name: "block offset out of bounds", buf: []byte{0x10, 0x7F}},
+		{name: "missing node", buf: []byte{0x10, 0x01}},
+		{name: "missing 4-byte block index", buf: []byte{0x14, 0x01, 0x00}},
+	}
+	for _, g := range truncated {
+		r := &nativeReader{buf: g.buf, base: base}
+		arr, err := r.newNativeArray(base)
+		if err != nil {
+			continue
+		}
+		if _, _, err := arr.get(0); err == nil {
+			t.Errorf("%s: expected error", g.name)
+		}
+	}
+}
+
+func TestNativeHashtable(t *testing.T) {
+	const base = 0x1000
+	// NativeHashtable of 1 bucket with 1-byte bucket offsets.
+	//
+	//    0x1000  header (nbucketsShift = 0, entryIndexSize = 0)
+	//    0x1001  offset of bucket 0 (2)
+	//    0x1002  offset of end of bucket 0 (4)
+	//    0x1003  low 8 bits of hash code of entry
+	//    0x1004  relative offset of entry (1)
+	//    0x1005  entry
+	buf := []byte{0x00, 0x02, 0x04, 0xAB, 0x02, 0x77}
+	r := &nativeReader{buf: buf, base: base}
+	entries, err := r.nativeHashtableEntries(base)
+	if err != nil {
+		t.Fatalf("unable to decode NativeHashtable; %v", err)
+	}
+	if len(entries) != 1 || entries[0] != base+5 {
+		t.Errorf("NativeHashtable entries mismatch; expected [0x%08X], got %#x", base+5, entries)
+	}
+	invalid := []struct {
+		name string
+		buf  []byte
+	}{
+		{name: "missing header", buf: []byte{}},
+		{name: "invalid number of buckets", buf: []byte{0x80}},
+		{name: "missing bucket offsets", buf: []byte{0x00, 0x02}},
+		{name: "bucket out of bounds", buf: []byte{0x00, 0x02, 0x7F, 0xAB, 0x02, 0x77}},
+		{name: "truncated entry", buf: []byte{0x00, 0x02, 0x04, 0xAB, 0x05}},
+	}
+	for _, g := range invalid {
+		r := &nativeReader{buf: g.buf, base: base}
+		if _, err := r.nativeHashtableEntries(base); err == nil {
+			t.Errorf("%s: expected error", g.name)
+		}
+	}
+}
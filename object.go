@@ -0,0 +1,72 @@
+package pe
+
+import (
+	"time"
+
+	"github.com/mewmew/pe/enum"
+)
+
+// --- [ Anonymous object header ] ---------------------------------------------
+
+// Class IDs of anonymous object files.
+var (
+	// Class ID of big object files ({D1BAA1C7-BAEE-4BA9-AF20-FAF66AA4DCB8}).
+	BigObjClassID = GUID{0xC7, 0xA1, 0xBA, 0xD1, 0xEE, 0xBA, 0xA9, 0x4B, 0xAF, 0x20, 0xFA, 0xF6, 0x6A, 0xA4, 0xDC, 0xB8}
+	// Class ID of LTCG object files, containing compiler intermediate code
+	// ({0CB3FE38-D9A5-4DAB-AC9B-D6B6222653C2}).
+	LTCGClassID = GUID{0x38, 0xFE, 0xB3, 0x0C, 0xA5, 0xD9, 0xAB, 0x4D, 0xAC, 0x9B, 0xD6, 0xB6, 0x22, 0x26, 0x53, 0xC2}
+)
+
+// AnonObjectHeader is the header of an anonymous object file.
+type AnonObjectHeader struct {
+	// Header version.
+	Version uint16
+	// Target CPU type.
+	Machine enum.MachineType
+	// File creation time.
+	Date time.Time
+	// Class ID identifying the object format.
+	ClassID GUID
+	// Size in bytes of data following the header.
+	DataSize uint32
+	// Object flags (version 2 and above).
+	Flags uint32
+	// Size in bytes of CLR metadata (version 2 and above).
+	MetadataSize uint32
+	// File offset of CLR metadata (version 2 and above).
+	MetadataOffset uint32
+}
+
+// IsBigObj reports whether the anonymous object header is the header of a big
+// object file.
+func (hdr *AnonObjectHeader) IsBigObj() bool {
+	return hdr.Version >= 2 && hdr.ClassID == BigObjClassID
+}
+
+// --- [ Import object header ] ------------------------------------------------
+
+// ImportObjectHeader is the header of a short import library member, describing
+// a single import.
+type ImportObjectHeader struct {
+	// Structure version.
+	Version uint16
+	// Target CPU type.
+	Machine enum.MachineType
+	// File creation time.
+	Date time.Time
+	// Size in bytes of the strings following the header.
+	DataSize uint32
+	// Ordinal of the import if NameType is ImportNameTypeOrdinal; otherwise
+	// hint into the export name table of the DLL.
+	OrdinalOrHint uint16
+	// Import type.
+	Type enum.ImportType
+	// Import name type.
+	NameType enum.ImportNameType
+	// Public symbol name.
+	SymbolName string
+	// DLL name.
+	DLLName string
+	// Export name (only used if NameType is ImportNameTypeNameExportAs).
+	ExportName string
+}
@@ -0,0 +1,277 @@
+package pe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mewmew/pe/enum"
+	"github.com/mewmew/pe/internal/pe"
+)
+
+func TestBigObj(t *testing.T) {
+	const path = "testdata/symtab.o"
+	obj, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to read file; %+v", path, err)
+	}
+	want, err := ParseBytes(obj)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	bigObj := toBigObj(t, obj)
+	file, err := ParseBytes(bigObj)
+	if err != nil {
+		t.Fatalf("%q: unable to parse big object file; %+v", path, err)
+	}
+	if file.AnonHdr == nil || !file.AnonHdr.IsBigObj() {
+		t.Fatalf("%q: expected big object header, got %+v", path, file.AnonHdr)
+	}
+	if file.AnonHdr.Version != 2 || file.AnonHdr.Machine != enum.MachineTypeAMD64 {
+		t.Errorf("%q: anonymous object header mismatch; expected version 2 and machine %v, got version %d and machine %v", path, enum.MachineTypeAMD64, file.AnonHdr.Version, file.AnonHdr.Machine)
+	}
+	if file.FileHdr.NSections != want.FileHdr.NSections {
+		t.Errorf("%q: number of sections mismatch; expected %d, got %d", path, want.FileHdr.NSections, file.FileHdr.NSections)
+	}
+	if file.FileHdr.NSymbols != want.FileHdr.NSymbols {
+		t.Errorf("%q: number of symbols mismatch; expected %d, got %d", path, want.FileHdr.NSymbols, file.FileHdr.NSymbols)
+	}
+	if len(file.SectHdrs) != len(want.SectHdrs) {
+		t.Fatalf("%q: number of section headers mismatch; expected %d, got %d", path, len(want.SectHdrs), len(file.SectHdrs))
+	}
+	for i, wantSect := range want.SectHdrs {
+		got := file.SectHdrs[i]
+		if got.Name != wantSect.Name || got.DataSize != wantSect.DataSize || !reflect.DeepEqual(got.Relocs, wantSect.Relocs) {
+			t.Errorf("%q: section %d mismatch; expected %+v, got %+v", path, i, wantSect, got)
+			continue
+		}
+		gotData := bigObj[got.DataOffset : got.DataOffset+got.DataSize]
+		wantData := obj[wantSect.DataOffset : wantSect.DataOffset+wantSect.DataSize]
+		if !bytes.Equal(gotData, wantData) {
+			t.Errorf("%q: contents of section %q mismatch; expected % X, got % X", path, wantSect.Name, wantData, gotData)
+		}
+	}
+	// Symbols and auxiliary symbol records are identical, except for the wider
+	// symbol table entries.
+	if !reflect.DeepEqual(file.Symbols, want.Symbols) {
+		t.Errorf("%q: symbols mismatch; expected %+v, got %+v", path, want.Symbols, file.Symbols)
+	}
+	if !bytes.Equal(file.StrTable, want.StrTable) {
+		t.Errorf("%q: string table mismatch; expected %q, got %q", path, want.StrTable, file.StrTable)
+	}
+}
+
+// toBigObj converts the given COFF object file to the big object file format.
+func toBigObj(t *testing.T, obj []byte) []byte {
+	var fileHdr pe.RawFileHeader
+	if err := binary.Read(bytes.NewReader(obj), binary.LittleEndian, &fileHdr); err != nil {
+		t.Fatalf("unable to parse COFF file header; %+v", err)
+	}
+	hdr := pe.RawBigObjHeader{
+		NSections:         uint32(fileHdr.NSections),
+		SymbolTableOffset: fileHdr.SymbolTableOffset,
+		NSymbols:          fileHdr.NSymbols,
+	}
+	hdr.Sig2 = 0xFFFF
+	hdr.Version = 2
+	hdr.Machine = fileHdr.Machine
+	hdr.Date = fileHdr.Date
+	hdr.ClassID = BigObjClassID
+	// All file offsets are shifted by the larger header.
+	delta := uint32(binary.Size(hdr) - binary.Size(fileHdr))
+	hdr.SymbolTableOffset += delta
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, binary.LittleEndian, hdr); err != nil {
+		t.Fatalf("unable to write big object header; %+v", err)
+	}
+	const sectHdrSize = 40
+	sectHdrsStart := uint32(binary.Size(fileHdr)) + uint32(fileHdr.OptHdrSize)
+	sectHdrs := append([]byte(nil), obj[sectHdrsStart:sectHdrsStart+uint32(fileHdr.NSections)*sectHdrSize]...)
+	for i := 0; i < int(fileHdr.NSections); i++ {
+		// Update offsets of section contents, relocations and line numbers.
+		for _, off := range []int{20, 24, 28} {
+			b := sectHdrs[i*sectHdrSize+off:]
+			if v := binary.LittleEndian.Uint32(b); v != 0 {
+				binary.LittleEndian.PutUint32(b, v+delta)
+			}
+		}
+	}
+	buf.Write(sectHdrs)
+	symStart := fileHdr.SymbolTableOffset
+	buf.Write(obj[sectHdrsStart+uint32(len(sectHdrs)) : symStart])
+	// Widen symbol table entries; section numbers are extended to 32 bits and
+	// auxiliary symbol records are padded to 20 bytes.
+	syms := obj[symStart : symStart+fileHdr.NSymbols*symbolSize]
+	for len(syms) > 0 {
+		var sym pe.RawSymbol
+		if err := binary.Read(bytes.NewReader(syms), binary.LittleEndian, &sym); err != nil {
+			t.Fatalf("unable to parse symbol; %+v", err)
+		}
+		if err := binary.Write(buf, binary.LittleEndian, rawSymbolEx(sym)); err != nil {
+			t.Fatalf("unable to write symbol; %+v", err)
+		}
+		aux := syms[symbolSize : symbolSize*(1+int(sym.NAux))]
+		bigAux := make([]byte, bigObjSymbolSize*int(sym.NAux))
+		if enum.StorageClass(sym.StorageClass) == enum.StorageClassFile {
+			// File names span all auxiliary symbol records.
+			copy(bigAux, aux)
+		} else {
+			for i := 0; i < int(sym.NAux); i++ {
+				copy(bigAux[i*bigObjSymbolSize:], aux[i*symbolSize:(i+1)*symbolSize])
+			}
+		}
+		buf.Write(bigAux)
+		syms = syms[symbolSize*(1+int(sym.NAux)):]
+	}
+	// String table.
+	buf.Write(obj[symStart+fileHdr.NSymbols*symbolSize:])
+	return buf.Bytes()
+}
+
+func TestAnonObjectHeader(t *testing.T) {
+	const date = 0x5C3A8B2E
+	v1 := pe.RawAnonObjectHeader{
+		Sig2:     0xFFFF,
+		Version:  1,
+		Machine:  enum.MachineTypeI386,
+		Date:     date,
+		ClassID:  LTCGClassID,
+		DataSize: 0x10,
+	}
+	v2 := pe.RawAnonObjectHeaderV2{
+		RawAnonObjectHeader: v1,
+		Flags:               0x1,
+		MetadataSize:        0x20,
+		MetadataOffset:      0x30,
+	}
+	v2.Version = 2
+	v2.Machine = enum.MachineTypeAMD64
+	golden := []struct {
+		name string
+		raw  interface{}
+		want *AnonObjectHeader
+	}{
+		{
+			name: "version 1",
+			raw:  v1,
+			want: &AnonObjectHeader{Version: 1, Machine: enum.MachineTypeI386, Date: time.Unix(date, 0), ClassID: LTCGClassID, DataSize: 0x10},
+		},
+		{
+			name: "version 2",
+			raw:  v2,
+			want: &AnonObjectHeader{Version: 2, Machine: enum.MachineTypeAMD64, Date: time.Unix(date, 0), ClassID: LTCGClassID, DataSize: 0x10, Flags: 0x1, MetadataSize: 0x20, MetadataOffset: 0x30},
+		},
+	}
+	for _, g := range golden {
+		buf := &bytes.Buffer{}
+		if err := binary.Write(buf, binary.LittleEndian, g.raw); err != nil {
+			t.Fatalf("%q: unable to write anonymous object header; %+v", g.name, err)
+		}
+		// Data following the header.
+		buf.Write(make([]byte, 0x10))
+		file, err := ParseBytes(buf.Bytes())
+		if err != nil {
+			t.Errorf("%q: unable to parse file; %+v", g.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(file.AnonHdr, g.want) {
+			t.Errorf("%q: anonymous object header mismatch; expected %+v, got %+v", g.name, g.want, file.AnonHdr)
+		}
+		if file.AnonHdr.IsBigObj() {
+			t.Errorf("%q: unexpected big object header", g.name)
+		}
+		// Anonymous objects other than big object files contain no sections.
+		if file.FileHdr != nil || file.ImportHdr != nil {
+			t.Errorf("%q: unexpected COFF file header or import object header", g.name)
+		}
+	}
+}
+
+func TestImportObjectHeader(t *testing.T) {
+	const date = 0x5C3A8B2E
+	golden := []struct {
+		name     string
+		hint     uint16
+		typ      enum.ImportType
+		nameType enum.ImportNameType
+		data     string
+		want     *ImportObjectHeader
+		wantErr  bool
+	}{
+		{
+			name:     "by name",
+			hint:     7,
+			typ:      enum.ImportTypeCode,
+			nameType: enum.ImportNameTypeName,
+			data:     "CreateFileW\x00kernel32.dll\x00",
+			want:     &ImportObjectHeader{OrdinalOrHint: 7, Type: enum.ImportTypeCode, NameType: enum.ImportNameTypeName, SymbolName: "CreateFileW", DLLName: "kernel32.dll"},
+		},
+		{
+			name:     "by ordinal",
+			hint:     256,
+			typ:      enum.ImportTypeData,
+			nameType: enum.ImportNameTypeOrdinal,
+			data:     "__imp_data\x00mfc140u.dll\x00",
+			want:     &ImportObjectHeader{OrdinalOrHint: 256, Type: enum.ImportTypeData, NameType: enum.ImportNameTypeOrdinal, SymbolName: "__imp_data", DLLName: "mfc140u.dll"},
+		},
+		{
+			name:     "export as",
+			typ:      enum.ImportTypeCode,
+			nameType: enum.ImportNameTypeNameExportAs,
+			data:     "#foo\x00arm64ec.dll\x00foo\x00",
+			want:     &ImportObjectHeader{Type: enum.ImportTypeCode, NameType: enum.ImportNameTypeNameExportAs, SymbolName: "#foo", DLLName: "arm64ec.dll", ExportName: "foo"},
+		},
+		{
+			name:    "missing DLL name",
+			data:    "foo\x00",
+			wantErr: true,
+		},
+	}
+	for _, g := range golden {
+		raw := pe.RawImportObjectHeader{
+			Sig2:          0xFFFF,
+			Machine:       enum.MachineTypeAMD64,
+			Date:          date,
+			DataSize:      uint32(len(g.data)),
+			OrdinalOrHint: g.hint,
+			Types:         uint16(g.typ) | uint16(g.nameType)<<2,
+		}
+		buf := &bytes.Buffer{}
+		if err := binary.Write(buf, binary.LittleEndian, raw); err != nil {
+			t.Fatalf("%q: unable to write import object header; %+v", g.name, err)
+		}
+		buf.WriteString(g.data)
+		file, err := ParseBytes(buf.Bytes())
+		if g.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", g.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unable to parse file; %+v", g.name, err)
+			continue
+		}
+		g.want.Machine = enum.MachineTypeAMD64
+		g.want.Date = time.Unix(date, 0)
+		g.want.DataSize = uint32(len(g.data))
+		if !reflect.DeepEqual(file.ImportHdr, g.want) {
+			t.Errorf("%q: import object header mismatch; expected %+v, got %+v", g.name, g.want, file.ImportHdr)
+		}
+		if file.FileHdr != nil || file.AnonHdr != nil {
+			t.Errorf("%q: unexpected COFF file header or anonymous object header", g.name)
+		}
+	}
+	// Import object data out of bounds.
+	raw := pe.RawImportObjectHeader{Sig2: 0xFFFF, Machine: enum.MachineTypeAMD64, DataSize: 0x100}
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, binary.LittleEndian, raw); err != nil {
+		t.Fatalf("unable to write import object header; %+v", err)
+	}
+	if _, err := ParseBytes(buf.Bytes()); err == nil {
+		t.Error("expected error for import object data out of bounds, got nil")
+	}
+}
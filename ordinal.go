@@ -0,0 +1,862 @@
+package pe
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/mewmew/pe/enum"
+)
+
+// --- [ Ordinal names ] -------------------------------------------------------
+
+// ImportName is the best-effort name of an imported function.
+type ImportName struct {
+	// Function name; or a synthesized name ("Ordinal" followed by the ordinal
+	// number) if not resolved.
+	Name string
+	// Specifies whether the name is known; false for imports by ordinal not
+	// present in the ordinal name tables.
+	Resolved bool
+}
+
+// Names returns the best-effort names of the imported functions of the import
+// entry, in order of occurrence; resolving imports by ordinal through the
+// ordinal name tables of the DLL (see RegisterOrdinalNames).
+func (imp ImportEntry) Names() []ImportName {
+	var names []ImportName
+	for _, entry := range imp.entries() {
+		name, ok := entry.ResolveName(imp.ImpDir.Name)
+		names = append(names, ImportName{Name: name, Resolved: ok})
+	}
+	return names
+}
+
+// entries returns the import name table entries of the import entry; or the
+// import address table entries if no import name table is present.
+func (imp ImportEntry) entries() []INTEntry {
+	if len(imp.INTs) == 0 {
+		return imp.IATs
+	}
+	return imp.INTs
+}
+
+// ResolveName returns the name of the imported function of the INT entry;
+// resolving imports by ordinal through the ordinal name tables of the given DLL
+// (see RegisterOrdinalNames). The boolean return value indicates whether the
+// name is known; unresolved imports by ordinal are given a synthesized name
+// ("Ordinal" followed by the ordinal number).
+func (entry INTEntry) ResolveName(dllName string) (string, bool) {
+	if !entry.IsOrdinal {
+		return entry.NameEntry.Name, true
+	}
+	if name, ok := OrdinalName(dllName, entry.Ordinal); ok {
+		return name, true
+	}
+	return fmt.Sprintf(noNameFormat, entry.Ordinal), false
+}
+
+// ordinalNamesMutex guards ordinalNames.
+var ordinalNamesMutex sync.RWMutex
+
+// ordinalNames maps from DLL name (as normalized by ordinalDLLName) to ordinal
+// names of exports, of DLLs conventionally imported by ordinal.
+//
+// Tables of further DLLs imported by ordinal (e.g. mfc42.dll and mfc42u.dll)
+// are not built in; they may be generated from the import libraries of the DLLs
+// by the pe_ordinals tool, or registered at run time (see
+// RegisterOrdinalNames and OrdinalNamesFromImportLib).
+var ordinalNames = map[string]map[uint16]string{
+	"ws2_32":   ws2_32Ordinals,
+	"wsock32":  wsock32Ordinals,
+	"oleaut32": oleaut32Ordinals,
+	"comctl32": comctl32Ordinals,
+}
+
+// OrdinalName returns the name of the export with the given ordinal of the
+// given DLL (e.g. "WS2_32.dll"), as recorded by the built-in and registered
+// ordinal name tables. The boolean return value indicates success.
+func OrdinalName(dllName string, ordinal uint16) (string, bool) {
+	ordinalNamesMutex.RLock()
+	defer ordinalNamesMutex.RUnlock()
+	name, ok := ordinalNames[ordinalDLLName(dllName)][ordinal]
+	return name, ok
+}
+
+// RegisterOrdinalNames registers ordinal names of exports of the given DLL
+// (e.g. "mfc42.dll"), used to resolve imports by ordinal. Registered names take
+// precedence over built-in and previously registered names of the same
+// ordinal.
+func RegisterOrdinalNames(dllName string, names map[uint16]string) {
+	ordinalNamesMutex.Lock()
+	defer ordinalNamesMutex.Unlock()
+	key := ordinalDLLName(dllName)
+	// Copy to leave built-in tables and the given map unmodified.
+	m := make(map[uint16]string, len(ordinalNames[key])+len(names))
+	for ordinal, name := range ordinalNames[key] {
+		m[ordinal] = name
+	}
+	for ordinal, name := range names {
+		m[ordinal] = name
+	}
+	ordinalNames[key] = m
+}
+
+// OrdinalNamesFromExports returns the ordinal names of the named exports of the
+// given DLL; e.g. to be registered for the DLL using RegisterOrdinalNames.
+func OrdinalNamesFromExports(file *File) map[uint16]string {
+	names := make(map[uint16]string)
+	for _, exp := range file.Exps {
+		if len(exp.Name) == 0 || exp.Ordinal > 0xFFFF {
+			continue
+		}
+		names[uint16(exp.Ordinal)] = exp.Name
+	}
+	return names
+}
+
+// OrdinalNamesFromImportLib returns the ordinal names of the imports by ordinal
+// of the given import library, indexed by DLL name; e.g. to be registered using
+// RegisterOrdinalNames. The public symbol names of import library members are
+// used as names.
+func OrdinalNamesFromImportLib(arch *Archive) map[string]map[uint16]string {
+	dlls := make(map[string]map[uint16]string)
+	for _, member := range arch.Members {
+		if member.File == nil || member.File.ImportHdr == nil {
+			continue
+		}
+		hdr := member.File.ImportHdr
+		if hdr.NameType != enum.ImportNameTypeOrdinal {
+			continue
+		}
+		names, ok := dlls[hdr.DLLName]
+		if !ok {
+			names = make(map[uint16]string)
+			dlls[hdr.DLLName] = names
+		}
+		names[hdr.OrdinalOrHint] = hdr.SymbolName
+	}
+	return dlls
+}
+
+// ordinalDLLName returns the normalized DLL name used to index ordinal name
+// tables; in lowercase and without file extension (e.g. "ws2_32").
+func ordinalDLLName(dllName string) string {
+	name := strings.ToLower(dllName)
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// ws2_32Ordinals maps from ordinal to export name of ws2_32.dll (also used for
+// wsock32.dll by the import hash).
+var ws2_32Ordinals = map[uint16]string{
+	1:   "accept",
+	2:   "bind",
+	3:   "closesocket",
+	4:   "connect",
+	5:   "getpeername",
+	6:   "getsockname",
+	7:   "getsockopt",
+	8:   "htonl",
+	9:   "htons",
+	10:  "ioctlsocket",
+	11:  "inet_addr",
+	12:  "inet_ntoa",
+	13:  "listen",
+	14:  "ntohl",
+	15:  "ntohs",
+	16:  "recv",
+	17:  "recvfrom",
+	18:  "select",
+	19:  "send",
+	20:  "sendto",
+	21:  "setsockopt",
+	22:  "shutdown",
+	23:  "socket",
+	24:  "GetAddrInfoW",
+	25:  "GetNameInfoW",
+	26:  "WSApSetPostRoutine",
+	27:  "FreeAddrInfoW",
+	28:  "WPUCompleteOverlappedRequest",
+	29:  "WSAAccept",
+	30:  "WSAAddressToStringA",
+	31:  "WSAAddressToStringW",
+	32:  "WSACloseEvent",
+	33:  "WSAConnect",
+	34:  "WSACreateEvent",
+	35:  "WSADuplicateSocketA",
+	36:  "WSADuplicateSocketW",
+	37:  "WSAEnumNameSpaceProvidersA",
+	38:  "WSAEnumNameSpaceProvidersW",
+	39:  "WSAEnumNetworkEvents",
+	40:  "WSAEnumProtocolsA",
+	41:  "WSAEnumProtocolsW",
+	42:  "WSAEventSelect",
+	43:  "WSAGetOverlappedResult",
+	44:  "WSAGetQOSByName",
+	45:  "WSAGetServiceClassInfoA",
+	46:  "WSAGetServiceClassInfoW",
+	47:  "WSAGetServiceClassNameByClassIdA",
+	48:  "WSAGetServiceClassNameByClassIdW",
+	49:  "WSAHtonl",
+	50:  "WSAHtons",
+	51:  "gethostbyaddr",
+	52:  "gethostbyname",
+	53:  "getprotobyname",
+	54:  "getprotobynumber",
+	55:  "getservbyname",
+	56:  "getservbyport",
+	57:  "gethostname",
+	58:  "WSAInstallServiceClassA",
+	59:  "WSAInstallServiceClassW",
+	60:  "WSAIoctl",
+	61:  "WSAJoinLeaf",
+	62:  "WSALookupServiceBeginA",
+	63:  "WSALookupServiceBeginW",
+	64:  "WSALookupServiceEnd",
+	65:  "WSALookupServiceNextA",
+	66:  "WSALookupServiceNextW",
+	67:  "WSANSPIoctl",
+	68:  "WSANtohl",
+	69:  "WSANtohs",
+	70:  "WSAProviderConfigChange",
+	71:  "WSARecv",
+	72:  "WSARecvDisconnect",
+	73:  "WSARecvFrom",
+	74:  "WSARemoveServiceClass",
+	75:  "WSAResetEvent",
+	76:  "WSASend",
+	77:  "WSASendDisconnect",
+	78:  "WSASendTo",
+	79:  "WSASetEvent",
+	80:  "WSASetServiceA",
+	81:  "WSASetServiceW",
+	82:  "WSASocketA",
+	83:  "WSASocketW",
+	84:  "WSAStringToAddressA",
+	85:  "WSAStringToAddressW",
+	86:  "WSAWaitForMultipleEvents",
+	87:  "WSCDeinstallProvider",
+	88:  "WSCEnableNSProvider",
+	89:  "WSCEnumProtocols",
+	90:  "WSCGetProviderPath",
+	91:  "WSCInstallNameSpace",
+	92:  "WSCInstallProvider",
+	93:  "WSCUnInstallNameSpace",
+	94:  "WSCUpdateProvider",
+	95:  "WSCWriteNameSpaceOrder",
+	96:  "WSCWriteProviderOrder",
+	97:  "freeaddrinfo",
+	98:  "getaddrinfo",
+	99:  "getnameinfo",
+	101: "WSAAsyncSelect",
+	102: "WSAAsyncGetHostByAddr",
+	103: "WSAAsyncGetHostByName",
+	104: "WSAAsyncGetProtoByNumber",
+	105: "WSAAsyncGetProtoByName",
+	106: "WSAAsyncGetServByPort",
+	107: "WSAAsyncGetServByName",
+	108: "WSACancelAsyncRequest",
+	109: "WSASetBlockingHook",
+	110: "WSAUnhookBlockingHook",
+	111: "WSAGetLastError",
+	112: "WSASetLastError",
+	113: "WSACancelBlockingCall",
+	114: "WSAIsBlocking",
+	115: "WSAStartup",
+	116: "WSACleanup",
+	151: "__WSAFDIsSet",
+	500: "WEP",
+}
+
+// oleaut32Ordinals maps from ordinal to export name of oleaut32.dll.
+var oleaut32Ordinals = map[uint16]string{
+	2:   "SysAllocString",
+	3:   "SysReAllocString",
+	4:   "SysAllocStringLen",
+	5:   "SysReAllocStringLen",
+	6:   "SysFreeString",
+	7:   "SysStringLen",
+	8:   "VariantInit",
+	9:   "VariantClear",
+	10:  "VariantCopy",
+	11:  "VariantCopyInd",
+	12:  "VariantChangeType",
+	13:  "VariantTimeToDosDateTime",
+	14:  "DosDateTimeToVariantTime",
+	15:  "SafeArrayCreate",
+	16:  "SafeArrayDestroy",
+	17:  "SafeArrayGetDim",
+	18:  "SafeArrayGetElemsize",
+	19:  "SafeArrayGetUBound",
+	20:  "SafeArrayGetLBound",
+	21:  "SafeArrayLock",
+	22:  "SafeArrayUnlock",
+	23:  "SafeArrayAccessData",
+	24:  "SafeArrayUnaccessData",
+	25:  "SafeArrayGetElement",
+	26:  "SafeArrayPutElement",
+	27:  "SafeArrayCopy",
+	28:  "DispGetParam",
+	29:  "DispGetIDsOfNames",
+	30:  "DispInvoke",
+	31:  "CreateDispTypeInfo",
+	32:  "CreateStdDispatch",
+	33:  "RegisterActiveObject",
+	34:  "RevokeActiveObject",
+	35:  "GetActiveObject",
+	36:  "SafeArrayAllocDescriptor",
+	37:  "SafeArrayAllocData",
+	38:  "SafeArrayDestroyDescriptor",
+	39:  "SafeArrayDestroyData",
+	40:  "SafeArrayRedim",
+	41:  "SafeArrayAllocDescriptorEx",
+	42:  "SafeArrayCreateEx",
+	43:  "SafeArrayCreateVectorEx",
+	44:  "SafeArraySetRecordInfo",
+	45:  "SafeArrayGetRecordInfo",
+	46:  "VarParseNumFromStr",
+	47:  "VarNumFromParseNum",
+	48:  "VarI2FromUI1",
+	49:  "VarI2FromI4",
+	50:  "VarI2FromR4",
+	51:  "VarI2FromR8",
+	52:  "VarI2FromCy",
+	53:  "VarI2FromDate",
+	54:  "VarI2FromStr",
+	55:  "VarI2FromDisp",
+	56:  "VarI2FromBool",
+	57:  "SafeArraySetIID",
+	58:  "VarI4FromUI1",
+	59:  "VarI4FromI2",
+	60:  "VarI4FromR4",
+	61:  "VarI4FromR8",
+	62:  "VarI4FromCy",
+	63:  "VarI4FromDate",
+	64:  "VarI4FromStr",
+	65:  "VarI4FromDisp",
+	66:  "VarI4FromBool",
+	67:  "SafeArrayGetIID",
+	68:  "VarR4FromUI1",
+	69:  "VarR4FromI2",
+	70:  "VarR4FromI4",
+	71:  "VarR4FromR8",
+	72:  "VarR4FromCy",
+	73:  "VarR4FromDate",
+	74:  "VarR4FromStr",
+	75:  "VarR4FromDisp",
+	76:  "VarR4FromBool",
+	77:  "SafeArrayGetVartype",
+	78:  "VarR8FromUI1",
+	79:  "VarR8FromI2",
+	80:  "VarR8FromI4",
+	81:  "VarR8FromR4",
+	82:  "VarR8FromCy",
+	83:  "VarR8FromDate",
+	84:  "VarR8FromStr",
+	85:  "VarR8FromDisp",
+	86:  "VarR8FromBool",
+	87:  "VarFormat",
+	88:  "VarDateFromUI1",
+	89:  "VarDateFromI2",
+	90:  "VarDateFromI4",
+	91:  "VarDateFromR4",
+	92:  "VarDateFromR8",
+	93:  "VarDateFromCy",
+	94:  "VarDateFromStr",
+	95:  "VarDateFromDisp",
+	96:  "VarDateFromBool",
+	97:  "VarFormatDateTime",
+	98:  "VarCyFromUI1",
+	99:  "VarCyFromI2",
+	100: "VarCyFromI4",
+	101: "VarCyFromR4",
+	102: "VarCyFromR8",
+	103: "VarCyFromDate",
+	104: "VarCyFromStr",
+	105: "VarCyFromDisp",
+	106: "VarCyFromBool",
+	107: "VarFormatNumber",
+	108: "VarBstrFromUI1",
+	109: "VarBstrFromI2",
+	110: "VarBstrFromI4",
+	111: "VarBstrFromR4",
+	112: "VarBstrFromR8",
+	113: "VarBstrFromCy",
+	114: "VarBstrFromDate",
+	115: "VarBstrFromDisp",
+	116: "VarBstrFromBool",
+	117: "VarFormatPercent",
+	118: "VarBoolFromUI1",
+	119: "VarBoolFromI2",
+	120: "VarBoolFromI4",
+	121: "VarBoolFromR4",
+	122: "VarBoolFromR8",
+	123: "VarBoolFromDate",
+	124: "VarBoolFromCy",
+	125: "VarBoolFromStr",
+	126: "VarBoolFromDisp",
+	127: "VarFormatCurrency",
+	128: "VarWeekdayName",
+	129: "VarMonthName",
+	130: "VarUI1FromI2",
+	131: "VarUI1FromI4",
+	132: "VarUI1FromR4",
+	133: "VarUI1FromR8",
+	134: "VarUI1FromCy",
+	135: "VarUI1FromDate",
+	136: "VarUI1FromStr",
+	137: "VarUI1FromDisp",
+	138: "VarUI1FromBool",
+	139: "VarFormatFromTokens",
+	140: "VarTokenizeFormatString",
+	141: "VarAdd",
+	142: "VarAnd",
+	143: "VarDiv",
+	144: "DllCanUnloadNow",
+	145: "DllGetClassObject",
+	146: "DispCallFunc",
+	147: "VariantChangeTypeEx",
+	148: "SafeArrayPtrOfIndex",
+	149: "SysStringByteLen",
+	150: "SysAllocStringByteLen",
+	151: "DllRegisterServer",
+	152: "VarEqv",
+	153: "VarIdiv",
+	154: "VarImp",
+	155: "VarMod",
+	156: "VarMul",
+	157: "VarOr",
+	158: "VarPow",
+	159: "VarSub",
+	160: "CreateTypeLib",
+	161: "LoadTypeLib",
+	162: "LoadRegTypeLib",
+	163: "RegisterTypeLib",
+	164: "QueryPathOfRegTypeLib",
+	165: "LHashValOfNameSys",
+	166: "LHashValOfNameSysA",
+	167: "VarXor",
+	168: "VarAbs",
+	169: "VarFix",
+	170: "OaBuildVersion",
+	171: "ClearCustData",
+	172: "VarInt",
+	173: "VarNeg",
+	174: "VarNot",
+	175: "VarRound",
+	176: "VarCmp",
+	177: "VarDecAdd",
+	178: "VarDecDiv",
+	179: "VarDecMul",
+	180: "CreateTypeLib2",
+	181: "VarDecSub",
+	182: "VarDecAbs",
+	183: "LoadTypeLibEx",
+	184: "SystemTimeToVariantTime",
+	185: "VariantTimeToSystemTime",
+	186: "UnRegisterTypeLib",
+	187: "VarDecFix",
+	188: "VarDecInt",
+	189: "VarDecNeg",
+	190: "VarDecFromUI1",
+	191: "VarDecFromI2",
+	192: "VarDecFromI4",
+	193: "VarDecFromR4",
+	194: "VarDecFromR8",
+	195: "VarDecFromDate",
+	196: "VarDecFromCy",
+	197: "VarDecFromStr",
+	198: "VarDecFromDisp",
+	199: "VarDecFromBool",
+	200: "GetErrorInfo",
+	201: "SetErrorInfo",
+	202: "CreateErrorInfo",
+	203: "VarDecRound",
+	204: "VarDecCmp",
+	205: "VarI2FromI1",
+	206: "VarI2FromUI2",
+	207: "VarI2FromUI4",
+	208: "VarI2FromDec",
+	209: "VarI4FromI1",
+	210: "VarI4FromUI2",
+	211: "VarI4FromUI4",
+	212: "VarI4FromDec",
+	213: "VarR4FromI1",
+	214: "VarR4FromUI2",
+	215: "VarR4FromUI4",
+	216: "VarR4FromDec",
+	217: "VarR8FromI1",
+	218: "VarR8FromUI2",
+	219: "VarR8FromUI4",
+	220: "VarR8FromDec",
+	221: "VarDateFromI1",
+	222: "VarDateFromUI2",
+	223: "VarDateFromUI4",
+	224: "VarDateFromDec",
+	225: "VarCyFromI1",
+	226: "VarCyFromUI2",
+	227: "VarCyFromUI4",
+	228: "VarCyFromDec",
+	229: "VarBstrFromI1",
+	230: "VarBstrFromUI2",
+	231: "VarBstrFromUI4",
+	232: "VarBstrFromDec",
+	233: "VarBoolFromI1",
+	234: "VarBoolFromUI2",
+	235: "VarBoolFromUI4",
+	236: "VarBoolFromDec",
+	237: "VarUI1FromI1",
+	238: "VarUI1FromUI2",
+	239: "VarUI1FromUI4",
+	240: "VarUI1FromDec",
+	241: "VarDecFromI1",
+	242: "VarDecFromUI2",
+	243: "VarDecFromUI4",
+	244: "VarI1FromUI1",
+	245: "VarI1FromI2",
+	246: "VarI1FromI4",
+	247: "VarI1FromR4",
+	248: "VarI1FromR8",
+	249: "VarI1FromDate",
+	250: "VarI1FromCy",
+	251: "VarI1FromStr",
+	252: "VarI1FromDisp",
+	253: "VarI1FromBool",
+	254: "VarI1FromUI2",
+	255: "VarI1FromUI4",
+	256: "VarI1FromDec",
+	257: "VarUI2FromUI1",
+	258: "VarUI2FromI2",
+	259: "VarUI2FromI4",
+	260: "VarUI2FromR4",
+	261: "VarUI2FromR8",
+	262: "VarUI2FromDate",
+	263: "VarUI2FromCy",
+	264: "VarUI2FromStr",
+	265: "VarUI2FromDisp",
+	266: "VarUI2FromBool",
+	267: "VarUI2FromI1",
+	268: "VarUI2FromUI4",
+	269: "VarUI2FromDec",
+	270: "VarUI4FromUI1",
+	271: "VarUI4FromI2",
+	272: "VarUI4FromI4",
+	273: "VarUI4FromR4",
+	274: "VarUI4FromR8",
+	275: "VarUI4FromDate",
+	276: "VarUI4FromCy",
+	277: "VarUI4FromStr",
+	278: "VarUI4FromDisp",
+	279: "VarUI4FromBool",
+	280: "VarUI4FromI1",
+	281: "VarUI4FromUI2",
+	282: "VarUI4FromDec",
+	283: "BSTR_UserSize",
+	284: "BSTR_UserMarshal",
+	285: "BSTR_UserUnmarshal",
+	286: "BSTR_UserFree",
+	287: "VARIANT_UserSize",
+	288: "VARIANT_UserMarshal",
+	289: "VARIANT_UserUnmarshal",
+	290: "VARIANT_UserFree",
+	291: "LPSAFEARRAY_UserSize",
+	292: "LPSAFEARRAY_UserMarshal",
+	293: "LPSAFEARRAY_UserUnmarshal",
+	294: "LPSAFEARRAY_UserFree",
+	295: "LPSAFEARRAY_Size",
+	296: "LPSAFEARRAY_Marshal",
+	297: "LPSAFEARRAY_Unmarshal",
+	298: "VarDecCmpR8",
+	299: "VarCyAdd",
+	300: "DllUnregisterServer",
+	301: "OACreateTypeLib2",
+	303: "VarCyMul",
+	304: "VarCyMulI4",
+	305: "VarCySub",
+	306: "VarCyAbs",
+	307: "VarCyFix",
+	308: "VarCyInt",
+	309: "VarCyNeg",
+	310: "VarCyRound",
+	311: "VarCyCmp",
+	312: "VarCyCmpR8",
+	313: "VarBstrCat",
+	314: "VarBstrCmp",
+	315: "VarR8Pow",
+	316: "VarR4CmpR8",
+	317: "VarR8Round",
+	318: "VarCat",
+	319: "VarDateFromUdateEx",
+	322: "GetRecordInfoFromGuids",
+	323: "GetRecordInfoFromTypeInfo",
+	325: "SetVarConversionLocaleSetting",
+	326: "GetVarConversionLocaleSetting",
+	327: "SetOaNoCache",
+	329: "VarCyMulI8",
+	330: "VarDateFromUdate",
+	331: "VarUdateFromDate",
+	332: "GetAltMonthNames",
+	333: "VarI8FromUI1",
+	334: "VarI8FromI2",
+	335: "VarI8FromR4",
+	336: "VarI8FromR8",
+	337: "VarI8FromCy",
+	338: "VarI8FromDate",
+	339: "VarI8FromStr",
+	340: "VarI8FromDisp",
+	341: "VarI8FromBool",
+	342: "VarI8FromI1",
+	343: "VarI8FromUI2",
+	344: "VarI8FromUI4",
+	345: "VarI8FromDec",
+	346: "VarI2FromI8",
+	347: "VarI2FromUI8",
+	348: "VarI4FromI8",
+	349: "VarI4FromUI8",
+	360: "VarR4FromI8",
+	361: "VarR4FromUI8",
+	362: "VarR8FromI8",
+	363: "VarR8FromUI8",
+	364: "VarDateFromI8",
+	365: "VarDateFromUI8",
+	366: "VarCyFromI8",
+	367: "VarCyFromUI8",
+	368: "VarBstrFromI8",
+	369: "VarBstrFromUI8",
+	370: "VarBoolFromI8",
+	371: "VarBoolFromUI8",
+	372: "VarUI1FromI8",
+	373: "VarUI1FromUI8",
+	374: "VarDecFromI8",
+	375: "VarDecFromUI8",
+	376: "VarI1FromI8",
+	377: "VarI1FromUI8",
+	378: "VarUI2FromI8",
+	379: "VarUI2FromUI8",
+	401: "OleLoadPictureEx",
+	402: "OleLoadPictureFileEx",
+	411: "SafeArrayCreateVector",
+	412: "SafeArrayCopyData",
+	413: "VectorFromBstr",
+	414: "BstrFromVector",
+	415: "OleIconToCursor",
+	416: "OleCreatePropertyFrameIndirect",
+	417: "OleCreatePropertyFrame",
+	418: "OleLoadPicture",
+	419: "OleCreatePictureIndirect",
+	420: "OleCreateFontIndirect",
+	421: "OleTranslateColor",
+	422: "OleLoadPictureFile",
+	423: "OleSavePictureFile",
+	424: "OleLoadPicturePath",
+	425: "VarUI4FromI8",
+	426: "VarUI4FromUI8",
+	427: "VarI8FromUI8",
+	428: "VarUI8FromI8",
+	429: "VarUI8FromUI1",
+	430: "VarUI8FromI2",
+	431: "VarUI8FromR4",
+	432: "VarUI8FromR8",
+	433: "VarUI8FromCy",
+	434: "VarUI8FromDate",
+	435: "VarUI8FromStr",
+	436: "VarUI8FromDisp",
+	437: "VarUI8FromBool",
+	438: "VarUI8FromI1",
+	439: "VarUI8FromUI2",
+	440: "VarUI8FromUI4",
+	441: "VarUI8FromDec",
+	442: "RegisterTypeLibForUser",
+	443: "UnRegisterTypeLibForUser",
+}
+
+// wsock32Ordinals maps from ordinal to export name of wsock32.dll.
+var wsock32Ordinals = map[uint16]string{
+	1:    "accept",
+	2:    "bind",
+	3:    "closesocket",
+	4:    "connect",
+	5:    "getpeername",
+	6:    "getsockname",
+	7:    "getsockopt",
+	8:    "htonl",
+	9:    "htons",
+	10:   "ioctlsocket",
+	11:   "inet_addr",
+	12:   "inet_ntoa",
+	13:   "listen",
+	14:   "ntohl",
+	15:   "ntohs",
+	16:   "recv",
+	17:   "recvfrom",
+	18:   "select",
+	19:   "send",
+	20:   "sendto",
+	21:   "setsockopt",
+	22:   "shutdown",
+	23:   "socket",
+	51:   "gethostbyaddr",
+	52:   "gethostbyname",
+	53:   "getprotobyname",
+	54:   "getprotobynumber",
+	55:   "getservbyname",
+	56:   "getservbyport",
+	57:   "gethostname",
+	101:  "WSAAsyncSelect",
+	102:  "WSAAsyncGetHostByAddr",
+	103:  "WSAAsyncGetHostByName",
+	104:  "WSAAsyncGetProtoByNumber",
+	105:  "WSAAsyncGetProtoByName",
+	106:  "WSAAsyncGetServByPort",
+	107:  "WSAAsyncGetServByName",
+	108:  "WSACancelAsyncRequest",
+	109:  "WSASetBlockingHook",
+	110:  "WSAUnhookBlockingHook",
+	111:  "WSAGetLastError",
+	112:  "WSASetLastError",
+	113:  "WSACancelBlockingCall",
+	114:  "WSAIsBlocking",
+	115:  "WSAStartup",
+	116:  "WSACleanup",
+	151:  "__WSAFDIsSet",
+	500:  "WEP",
+	1100: "inet_network",
+	1101: "getnetbyname",
+	1102: "rcmd",
+	1103: "rexec",
+	1104: "rresvport",
+	1105: "sethostname",
+	1106: "dn_expand",
+	1107: "WSARecvEx",
+	1108: "s_perror",
+	1109: "GetAddressByNameA",
+	1110: "GetAddressByNameW",
+	1111: "EnumProtocolsA",
+	1112: "EnumProtocolsW",
+	1113: "GetTypeByNameA",
+	1114: "GetTypeByNameW",
+	1115: "GetNameByTypeA",
+	1116: "GetNameByTypeW",
+	1117: "SetServiceA",
+	1118: "SetServiceW",
+	1119: "GetServiceA",
+	1120: "GetServiceW",
+	1130: "NPLoadNameSpaces",
+	1140: "TransmitFile",
+	1141: "AcceptEx",
+	1142: "GetAcceptExSockaddrs",
+}
+
+// comctl32Ordinals maps from ordinal to export name of comctl32.dll, for
+// exports conventionally imported by ordinal.
+var comctl32Ordinals = map[uint16]string{
+	2:   "MenuHelp",
+	3:   "ShowHideMenuCtl",
+	4:   "GetEffectiveClientRect",
+	5:   "DrawStatusTextA",
+	6:   "CreateStatusWindowA",
+	7:   "CreateToolbar",
+	8:   "CreateMappedBitmap",
+	9:   "DPA_LoadStream",
+	10:  "DPA_SaveStream",
+	11:  "DPA_Merge",
+	13:  "MakeDragList",
+	14:  "LBItemFromPt",
+	15:  "DrawInsert",
+	16:  "CreateUpDownControl",
+	17:  "InitCommonControls",
+	71:  "Alloc",
+	72:  "ReAlloc",
+	73:  "Free",
+	74:  "GetSize",
+	151: "CreateMRUListA",
+	152: "FreeMRUList",
+	153: "AddMRUStringA",
+	154: "EnumMRUListA",
+	155: "FindMRUStringA",
+	156: "DelMRUString",
+	157: "CreateMRUListLazyA",
+	163: "CreatePage",
+	164: "CreateProxyPage",
+	167: "AddMRUData",
+	169: "FindMRUData",
+	233: "Str_GetPtrA",
+	234: "Str_SetPtrA",
+	235: "Str_GetPtrW",
+	236: "Str_SetPtrW",
+	320: "DSA_Create",
+	321: "DSA_Destroy",
+	322: "DSA_GetItem",
+	323: "DSA_GetItemPtr",
+	324: "DSA_InsertItem",
+	325: "DSA_SetItem",
+	326: "DSA_DeleteItem",
+	327: "DSA_DeleteAllItems",
+	328: "DPA_Create",
+	329: "DPA_Destroy",
+	330: "DPA_Grow",
+	331: "DPA_Clone",
+	332: "DPA_GetPtr",
+	333: "DPA_GetPtrIndex",
+	334: "DPA_InsertPtr",
+	335: "DPA_SetPtr",
+	336: "DPA_DeletePtr",
+	337: "DPA_DeleteAllPtrs",
+	338: "DPA_Sort",
+	339: "DPA_Search",
+	340: "DPA_CreateEx",
+	341: "SendNotify",
+	342: "SendNotifyEx",
+	350: "StrChrA",
+	351: "StrRChrA",
+	352: "StrCmpNA",
+	353: "StrCmpNIA",
+	354: "StrStrA",
+	355: "StrStrIA",
+	356: "StrCSpnA",
+	357: "StrToIntA",
+	358: "StrChrW",
+	359: "StrRChrW",
+	360: "StrCmpNW",
+	361: "StrCmpNIW",
+	362: "StrStrW",
+	363: "StrStrIW",
+	364: "StrCSpnW",
+	365: "StrToIntW",
+	366: "StrChrIA",
+	367: "StrChrIW",
+	368: "StrRChrIA",
+	369: "StrRChrIW",
+	372: "StrRStrIA",
+	373: "StrRStrIW",
+	374: "StrCSpnIA",
+	375: "StrCSpnIW",
+	376: "IntlStrEqWorkerA",
+	377: "IntlStrEqWorkerW",
+	382: "SmoothScrollWindow",
+	383: "DoReaderMode",
+	384: "SetPathWordBreakProc",
+	385: "DPA_EnumCallback",
+	386: "DPA_DestroyCallback",
+	387: "DSA_EnumCallback",
+	388: "DSA_DestroyCallback",
+	390: "ImageList_SetColorTable",
+	400: "CreateMRUListW",
+	401: "AddMRUStringW",
+	402: "FindMRUStringW",
+	403: "EnumMRUListW",
+	404: "CreateMRUListLazyW",
+	410: "SetWindowSubclass",
+	411: "GetWindowSubclass",
+	412: "RemoveWindowSubclass",
+	413: "DefSubclassProc",
+	414: "MirrorIcon",
+	415: "DrawTextWrap",
+	416: "DrawTextExPrivWrap",
+	417: "ExtTextOutWrap",
+	418: "GetCharWidthWrap",
+	419: "GetTextExtentPointWrap",
+	420: "GetTextExtentPoint32Wrap",
+	421: "TextOutWrap",
+}
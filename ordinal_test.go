@@ -0,0 +1,188 @@
+package pe
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/pe/enum"
+)
+
+func TestINTEntryResolveName(t *testing.T) {
+	golden := []struct {
+		entry   INTEntry
+		dllName string
+		want    string
+		wantOK  bool
+	}{
+		// Import by name.
+		{entry: INTEntry{NameEntry: NameEntry{Hint: 2, Name: "ExitProcess"}}, dllName: "KERNEL32.dll", want: "ExitProcess", wantOK: true},
+		// Import by ordinal of built-in ordinal name tables; case insensitive
+		// DLL name, with or without extension.
+		{entry: INTEntry{IsOrdinal: true, Ordinal: 115}, dllName: "WS2_32.dll", want: "WSAStartup", wantOK: true},
+		{entry: INTEntry{IsOrdinal: true, Ordinal: 115}, dllName: "ws2_32", want: "WSAStartup", wantOK: true},
+		{entry: INTEntry{IsOrdinal: true, Ordinal: 1141}, dllName: "WSOCK32.DLL", want: "AcceptEx", wantOK: true},
+		{entry: INTEntry{IsOrdinal: true, Ordinal: 6}, dllName: "OLEAUT32.dll", want: "SysFreeString", wantOK: true},
+		{entry: INTEntry{IsOrdinal: true, Ordinal: 17}, dllName: "COMCTL32.dll", want: "InitCommonControls", wantOK: true},
+		// Unknown ordinal.
+		{entry: INTEntry{IsOrdinal: true, Ordinal: 999}, dllName: "WS2_32.dll", want: "Ordinal999", wantOK: false},
+		// Unknown DLL.
+		{entry: INTEntry{IsOrdinal: true, Ordinal: 7}, dllName: "foo.ocx", want: "Ordinal7", wantOK: false},
+	}
+	for _, g := range golden {
+		got, ok := g.entry.ResolveName(g.dllName)
+		if got != g.want || ok != g.wantOK {
+			t.Errorf("%s %+v: name mismatch; expected %q (%v), got %q (%v)", g.dllName, g.entry, g.want, g.wantOK, got, ok)
+		}
+	}
+}
+
+func TestImportEntryNames(t *testing.T) {
+	const path = "testdata/imphash.exe"
+	file, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	want := map[string][]ImportName{
+		"WS2_32.dll": {
+			{Name: "WSAStartup", Resolved: true},
+			{Name: "closesocket", Resolved: true},
+			{Name: "Ordinal999"},
+			{Name: "select", Resolved: true},
+		},
+		"WSOCK32.dll":  {{Name: "closesocket", Resolved: true}},
+		"KERNEL32.dll": {{Name: "ExitProcess", Resolved: true}},
+		"OLEAUT32.dll": {
+			{Name: "SysAllocString", Resolved: true},
+			{Name: "SysFreeString", Resolved: true},
+		},
+		"foo.ocx": {{Name: "Ordinal7"}},
+		"Bar.SYS": {{Name: "BarFunc", Resolved: true}},
+		"baz.drv": {{Name: "Ordinal1"}},
+	}
+	if len(file.Imps) != len(want) {
+		t.Errorf("%q: number of import entries mismatch; expected %d, got %d", path, len(want), len(file.Imps))
+	}
+	for _, imp := range file.Imps {
+		got := imp.Names()
+		if !reflect.DeepEqual(got, want[imp.ImpDir.Name]) {
+			t.Errorf("%q: names of %q mismatch; expected %v, got %v", path, imp.ImpDir.Name, want[imp.ImpDir.Name], got)
+		}
+	}
+	// Fall back to import address table entries if no import name table is
+	// present.
+	imp := ImportEntry{
+		ImpDir: ImportDirectory{Name: "WS2_32.dll"},
+		IATs: []INTEntry{
+			{IsOrdinal: true, Ordinal: 3},
+			{NameEntry: NameEntry{Name: "WSACleanup"}},
+		},
+	}
+	wantIATs := []ImportName{
+		{Name: "closesocket", Resolved: true},
+		{Name: "WSACleanup", Resolved: true},
+	}
+	if got := imp.Names(); !reflect.DeepEqual(got, wantIATs) {
+		t.Errorf("names of import address table mismatch; expected %v, got %v", wantIATs, got)
+	}
+}
+
+func TestRegisterOrdinalNames(t *testing.T) {
+	// Restore ordinal name tables modified by the test.
+	defer restoreOrdinalNames("ws2_32")()
+	defer restoreOrdinalNames("test_ordinals")()
+
+	// Register ordinal names of a DLL without built-in ordinal name table.
+	names := map[uint16]string{1: "First", 2: "Second"}
+	RegisterOrdinalNames("Test_Ordinals.dll", names)
+	// Registered names take precedence over previously registered names.
+	RegisterOrdinalNames("test_ordinals", map[uint16]string{2: "Other", 3: "Third"})
+	// Registered names take precedence over built-in names.
+	RegisterOrdinalNames("WS2_32.DLL", map[uint16]string{3: "my_closesocket"})
+	golden := []struct {
+		dllName string
+		ordinal uint16
+		want    string
+		wantOK  bool
+	}{
+		{dllName: "test_ordinals.dll", ordinal: 1, want: "First", wantOK: true},
+		{dllName: "TEST_ORDINALS.DLL", ordinal: 2, want: "Other", wantOK: true},
+		{dllName: "test_ordinals.dll", ordinal: 3, want: "Third", wantOK: true},
+		{dllName: "test_ordinals.dll", ordinal: 4},
+		{dllName: "ws2_32.dll", ordinal: 3, want: "my_closesocket", wantOK: true},
+		// Other built-in names are retained.
+		{dllName: "ws2_32.dll", ordinal: 115, want: "WSAStartup", wantOK: true},
+		// Tables of other DLLs are not affected.
+		{dllName: "wsock32.dll", ordinal: 3, want: "closesocket", wantOK: true},
+	}
+	for _, g := range golden {
+		got, ok := OrdinalName(g.dllName, g.ordinal)
+		if got != g.want || ok != g.wantOK {
+			t.Errorf("%s ordinal %d: name mismatch; expected %q (%v), got %q (%v)", g.dllName, g.ordinal, g.want, g.wantOK, got, ok)
+		}
+	}
+	// The given map and built-in tables are left unmodified.
+	if want := map[uint16]string{1: "First", 2: "Second"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("registered map modified; expected %v, got %v", want, names)
+	}
+	if got := ws2_32Ordinals[3]; got != "closesocket" {
+		t.Errorf("built-in ordinal name table modified; expected %q, got %q", "closesocket", got)
+	}
+}
+
+// restoreOrdinalNames returns a function which restores the ordinal name table
+// of the given DLL (as normalized by ordinalDLLName) to its current state.
+func restoreOrdinalNames(key string) func() {
+	ordinalNamesMutex.RLock()
+	names, ok := ordinalNames[key]
+	ordinalNamesMutex.RUnlock()
+	return func() {
+		ordinalNamesMutex.Lock()
+		defer ordinalNamesMutex.Unlock()
+		if !ok {
+			delete(ordinalNames, key)
+			return
+		}
+		ordinalNames[key] = names
+	}
+}
+
+func TestOrdinalNamesFromImportLib(t *testing.T) {
+	// Import library of two DLLs, with imports by name and by ordinal (NONAME).
+	foo := &importLib{dllName: "foo.dll", machine: enum.MachineTypeAMD64}
+	bar := &importLib{dllName: "BAR.DLL", machine: enum.MachineTypeI386}
+	members := []archiveEntry{
+		foo.importDescriptor(),
+		foo.nullImportDescriptor(),
+		foo.nullThunk(),
+		foo.shortImport(libExport{Name: "ByName", Ordinal: 1}),
+		foo.shortImport(libExport{Name: "?Func@@YAXXZ", Ordinal: 2, NoName: true}),
+		foo.shortImport(libExport{Name: "Data", Ordinal: 3, NoName: true, Data: true}),
+		bar.shortImport(libExport{Name: "?Method@CBar@@QAEXXZ", Ordinal: 10, NoName: true}),
+		bar.shortImport(libExport{Name: "BarFunc", Ordinal: 11, NoName: true}),
+	}
+	buf := &bytes.Buffer{}
+	if err := writeArchive(buf, members); err != nil {
+		t.Fatalf("unable to write import library; %+v", err)
+	}
+	arch, err := ParseArchiveBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unable to parse import library; %+v", err)
+	}
+	got := OrdinalNamesFromImportLib(arch)
+	// Public symbol names are used as names; C symbols of 32-bit x86 are
+	// decorated with a leading underscore.
+	want := map[string]map[uint16]string{
+		"foo.dll": {
+			2: "?Func@@YAXXZ",
+			3: "Data",
+		},
+		"BAR.DLL": {
+			10: "?Method@CBar@@QAEXXZ",
+			11: "_BarFunc",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ordinal names mismatch; expected %v, got %v", want, got)
+	}
+}
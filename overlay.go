@@ -0,0 +1,68 @@
+package pe
+
+import (
+	"bytes"
+	"io"
+)
+
+// --- [ Overlay ] -------------------------------------------------------------
+
+// Overlay returns the file offset and size of the overlay of the PE image, and
+// a reader of its contents. The overlay is the data appended after the raw
+// data of the last section (e.g. by installers and self-extracting archives),
+// excluding the certificate table if located at the end of the file. The size
+// is zero if no overlay is present.
+func (file *File) Overlay() (offset, size int64, sr *io.SectionReader) {
+	end := file.overlayEnd()
+	offset = end
+	if file.OptHdr != nil {
+		offset = file.overlayStart()
+	}
+	if offset > end {
+		offset = end
+	}
+	size = end - offset
+	return offset, size, io.NewSectionReader(bytes.NewReader(file.Content), offset, size)
+}
+
+// overlayStart returns the file offset of the end of the headers and the raw
+// data of sections of the PE image.
+func (file *File) overlayStart() int64 {
+	start := int64(file.OptHdr.HeadersSize)
+	if file.FileHdr != nil {
+		if end := int64(file.sectHdrsEnd()); end > start {
+			start = end
+		}
+	}
+	for _, sectHdr := range file.SectHdrs {
+		if sectHdr.DataOffset == 0 || sectHdr.DataSize == 0 {
+			continue
+		}
+		if end := int64(sectHdr.DataOffset) + int64(sectHdr.DataSize); end > start {
+			start = end
+		}
+	}
+	return start
+}
+
+// overlayEnd returns the file offset of the end of the overlay; i.e. the start
+// of the certificate table if located at the end of the file, or the end of the
+// file otherwise.
+func (file *File) overlayEnd() int64 {
+	end := int64(len(file.Content))
+	if len(file.DataDirs) <= 4 {
+		return end
+	}
+	// The relative address of the certificate table is a file offset.
+	certDir := file.DataDirs[4]
+	if certDir.Size == 0 {
+		return end
+	}
+	certStart := int64(certDir.RelAddr)
+	certEnd := certStart + int64(certDir.Size)
+	// Allow for alignment padding (8 bytes) after the certificate table.
+	if certStart <= end && end-8 < certEnd && certEnd <= end {
+		return certStart
+	}
+	return end
+}
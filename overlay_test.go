@@ -0,0 +1,138 @@
+package pe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	const (
+		exePath = "testdata/cli-64.exe"
+		// Certificate table of size 10272 located at file offset 40960, at the
+		// end of the file.
+		dllPath = "testdata/Microsoft.TestPlatform.PlatformAbstractions.dll"
+	)
+	golden := []struct {
+		name string
+		path string
+		// modify modifies the given file contents; nil if unmodified.
+		modify     func(t *testing.T, buf []byte) []byte
+		wantOffset int64
+		wantSize   int64
+		// Trailing contents of the overlay.
+		wantData string
+	}{
+		// No overlay; offset at the end of the file.
+		{name: "no overlay", path: exePath, wantOffset: 14336},
+		// Overlay without certificate table.
+		{
+			name: "overlay",
+			path: exePath,
+			modify: func(t *testing.T, buf []byte) []byte {
+				return append(buf, "overlay data"...)
+			},
+			wantOffset: 14336,
+			wantSize:   12,
+			wantData:   "overlay data",
+		},
+		// Certificate table at the end of the file is not part of the overlay.
+		{name: "certificate table", path: dllPath, wantOffset: 40960},
+		// Alignment padding after the certificate table.
+		{
+			name: "padded certificate table",
+			path: dllPath,
+			modify: func(t *testing.T, buf []byte) []byte {
+				return append(buf, make([]byte, 7)...)
+			},
+			wantOffset: 40960,
+		},
+		// Data after the certificate table exceeding the alignment padding; the
+		// certificate table is part of the overlay.
+		{
+			name: "data after certificate table",
+			path: dllPath,
+			modify: func(t *testing.T, buf []byte) []byte {
+				return append(buf, "trailing"...)
+			},
+			wantOffset: 40960,
+			wantSize:   10272 + 8,
+			wantData:   "trailing",
+		},
+		// Overlay followed by certificate table.
+		{
+			name: "overlay before certificate table",
+			path: exePath,
+			modify: func(t *testing.T, buf []byte) []byte {
+				buf = append(buf, "overlay!"...)
+				return appendCert(t, buf, 16, 0)
+			},
+			wantOffset: 14336,
+			wantSize:   8,
+			wantData:   "overlay!",
+		},
+		// Overlay followed by padded certificate table.
+		{
+			name: "overlay before padded certificate table",
+			path: exePath,
+			modify: func(t *testing.T, buf []byte) []byte {
+				buf = append(buf, "overlay!"...)
+				return appendCert(t, buf, 12, 4)
+			},
+			wantOffset: 14336,
+			wantSize:   8,
+			wantData:   "overlay!",
+		},
+	}
+	for _, g := range golden {
+		buf, err := ioutil.ReadFile(g.path)
+		if err != nil {
+			t.Errorf("%q: unable to read file; %+v", g.name, err)
+			continue
+		}
+		if g.modify != nil {
+			buf = g.modify(t, buf)
+		}
+		file, err := ParseBytes(buf)
+		if err != nil {
+			t.Errorf("%q: unable to parse file; %+v", g.name, err)
+			continue
+		}
+		offset, size, sr := file.Overlay()
+		if offset != g.wantOffset {
+			t.Errorf("%q: overlay offset mismatch; expected %d, got %d", g.name, g.wantOffset, offset)
+		}
+		data, err := ioutil.ReadAll(sr)
+		if err != nil {
+			t.Errorf("%q: unable to read overlay; %+v", g.name, err)
+			continue
+		}
+		if size != g.wantSize {
+			t.Errorf("%q: overlay size mismatch; expected %d, got %d", g.name, g.wantSize, size)
+		}
+		if !bytes.HasSuffix(data, []byte(g.wantData)) {
+			t.Errorf("%q: overlay contents mismatch; expected suffix %q, got %q", g.name, g.wantData, data)
+		}
+	}
+}
+
+// appendCert appends a certificate table of the given size, containing a single
+// attribute certificate, followed by the given amount of zero padding to the
+// contents of the PE file, and updates the certificate table data directory
+// accordingly.
+func appendCert(t *testing.T, buf []byte, size, padding int) []byte {
+	file, err := ParseBytes(buf)
+	if err != nil {
+		t.Fatalf("unable to parse file; %+v", err)
+	}
+	offset := file.dataDirOffset(4)
+	binary.LittleEndian.PutUint32(buf[offset:], uint32(len(buf)))
+	binary.LittleEndian.PutUint32(buf[offset+4:], uint32(size))
+	cert := make([]byte, size+padding)
+	// WIN_CERTIFICATE header; length, revision and certificate type.
+	binary.LittleEndian.PutUint32(cert[0:], uint32(size))
+	binary.LittleEndian.PutUint16(cert[4:], 0x0200)
+	binary.LittleEndian.PutUint16(cert[6:], 0x0002)
+	return append(buf, cert...)
+}
@@ -0,0 +1,294 @@
+package pdb
+
+import (
+	"github.com/mewmew/pe/enum"
+	"github.com/pkg/errors"
+)
+
+// --- [ DBI stream ] ----------------------------------------------------------
+
+// DBIStream is the DBI (debug info) stream (stream 3).
+//
+// ref: https://llvm.org/docs/PDB/DbiStream.html
+type DBIStream struct {
+	// DBI stream version (e.g. 19990903 for V70).
+	Version uint32
+	// Incremental number matching the age of the RSDS CodeView debug
+	// information of the image.
+	Age uint32
+	// Stream index of the global symbol stream.
+	GlobalStream uint16
+	// Build number of the toolchain (major and minor version).
+	BuildNumber uint16
+	// Stream index of the public symbol stream.
+	PublicStream uint16
+	// Version number of mspdbXXXX.dll used to produce the PDB file.
+	PDBDLLVersion uint16
+	// Stream index of the symbol record stream.
+	SymRecordStream uint16
+	// Rebuild number of mspdbXXXX.dll used to produce the PDB file.
+	PDBDLLRebuild uint16
+	// DBI flags.
+	Flags uint16
+	// Target machine of the image.
+	Machine enum.MachineType
+	// Modules (object files) contributing to the image.
+	Modules []Module
+	// Section contributions, sorted by section and offset.
+	SectionContribs []SectionContrib
+	// Section map, describing the segments of the image.
+	SectionMap []SectionMapEntry
+	// Stream indices of optional debug streams (e.g. FPO data and section
+	// headers), indexed by debug stream kind; nilStream if not present.
+	DbgStreams []uint16
+}
+
+// Module describes a module (object file) contributing to the image.
+type Module struct {
+	// First section contribution of the module.
+	SectionContrib SectionContrib
+	// Module flags.
+	Flags uint16
+	// Stream index of the module symbol stream; nilStream if not present.
+	SymStream uint16
+	// Size of symbol records in the module symbol stream in number of bytes.
+	SymSize uint32
+	// Size of C11 line information in the module symbol stream in number of
+	// bytes.
+	C11Size uint32
+	// Size of C13 line information in the module symbol stream in number of
+	// bytes.
+	C13Size uint32
+	// Number of source files contributing to the module.
+	NSourceFiles uint16
+	// Module name; object file name or import library member name.
+	Name string
+	// Object file name; or archive name of import library members.
+	ObjFileName string
+}
+
+// SectionContrib is a contribution of a module to a section of the image.
+type SectionContrib struct {
+	// Section number (1-based).
+	Section uint16
+	// Offset of contribution (relative to section start).
+	Offset uint32
+	// Size of contribution in number of bytes.
+	Size uint32
+	// Section flags.
+	Flags enum.SectionFlag
+	// Index of contributing module.
+	ModuleIndex uint16
+	// CRC of section contribution data.
+	DataCRC uint32
+	// CRC of section contribution relocations.
+	RelocCRC uint32
+}
+
+// SectionMapEntry describes a segment of the image.
+type SectionMapEntry struct {
+	// Segment descriptor flags.
+	Flags uint16
+	// Logical overlay number.
+	Overlay uint16
+	// Group index into descriptor array.
+	Group uint16
+	// Frame; section number (1-based) of segment.
+	Frame uint16
+	// Byte index of segment name in the string table; 0xFFFF if none.
+	SectionName uint16
+	// Byte index of class name in the string table; 0xFFFF if none.
+	ClassName uint16
+	// Byte offset of logical segment within physical segment.
+	Offset uint32
+	// Size of segment in number of bytes.
+	Length uint32
+}
+
+// Optional debug stream kinds of the DBI stream.
+const (
+	DbgStreamFPO            = 0
+	DbgStreamException      = 1
+	DbgStreamFixup          = 2
+	DbgStreamOMapToSrc      = 3
+	DbgStreamOMapFromSrc    = 4
+	DbgStreamSectionHdr     = 5
+	DbgStreamTokenRIDMap    = 6
+	DbgStreamXData          = 7
+	DbgStreamPData          = 8
+	DbgStreamNewFPO         = 9
+	DbgStreamSectionHdrOrig = 10
+)
+
+// Section contribution substream versions.
+const (
+	sectionContribVer60 = 0xEFFE0000 + 19970605
+	sectionContribV2    = 0xEFFE0000 + 20140516
+)
+
+// parseDBIStream parses the given DBI stream.
+func parseDBIStream(buf []byte) (*DBIStream, error) {
+	d := &decoder{buf: buf}
+	// Version signature; always -1.
+	d.u32()
+	dbi := &DBIStream{
+		Version:         d.u32(),
+		Age:             d.u32(),
+		GlobalStream:    d.u16(),
+		BuildNumber:     d.u16(),
+		PublicStream:    d.u16(),
+		PDBDLLVersion:   d.u16(),
+		SymRecordStream: d.u16(),
+		PDBDLLRebuild:   d.u16(),
+	}
+	modInfoSize := d.u32()
+	sectionContribSize := d.u32()
+	sectionMapSize := d.u32()
+	sourceInfoSize := d.u32()
+	typeServerMapSize := d.u32()
+	// MFC type server index.
+	d.u32()
+	dbgHeaderSize := d.u32()
+	ecSize := d.u32()
+	dbi.Flags = d.u16()
+	dbi.Machine = enum.MachineType(d.u16())
+	// Padding.
+	d.u32()
+	// Substreams follow the header, in order.
+	modInfo := d.read(int(modInfoSize))
+	sectionContribs := d.read(int(sectionContribSize))
+	sectionMap := d.read(int(sectionMapSize))
+	d.read(int(sourceInfoSize))
+	d.read(int(typeServerMapSize))
+	d.read(int(ecSize))
+	dbgHeader := d.read(int(dbgHeaderSize))
+	if d.err != nil {
+		return nil, errors.Wrap(d.err, "unable to decode DBI stream header and substreams")
+	}
+	mods, err := parseModInfo(modInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse module info substream")
+	}
+	dbi.Modules = mods
+	contribs, err := parseSectionContribs(sectionContribs)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse section contribution substream")
+	}
+	dbi.SectionContribs = contribs
+	secMap, err := parseSectionMap(sectionMap)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse section map substream")
+	}
+	dbi.SectionMap = secMap
+	hd := &decoder{buf: dbgHeader}
+	for hd.pos+2 <= len(hd.buf) {
+		dbi.DbgStreams = append(dbi.DbgStreams, hd.u16())
+	}
+	return dbi, nil
+}
+
+// parseModInfo parses the given module info substream of the DBI stream.
+func parseModInfo(buf []byte) ([]Module, error) {
+	d := &decoder{buf: buf}
+	var mods []Module
+	for d.pos < len(d.buf) {
+		// Unused.
+		d.u32()
+		mod := Module{
+			SectionContrib: parseSectionContrib(d),
+			Flags:          d.u16(),
+			SymStream:      d.u16(),
+			SymSize:        d.u32(),
+			C11Size:        d.u32(),
+			C13Size:        d.u32(),
+			NSourceFiles:   d.u16(),
+		}
+		// Padding, unused, source file name index and PDB file path name index.
+		d.read(2 + 4 + 4 + 4)
+		mod.Name = d.cstring()
+		mod.ObjFileName = d.cstring()
+		// Module info entries are aligned to 4 bytes.
+		d.align(4)
+		if d.err != nil {
+			return nil, errors.Wrapf(d.err, "unable to decode module info entry %d", len(mods))
+		}
+		mods = append(mods, mod)
+	}
+	return mods, nil
+}
+
+// parseSectionContribs parses the given section contribution substream of the
+// DBI stream.
+func parseSectionContribs(buf []byte) ([]SectionContrib, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	d := &decoder{buf: buf}
+	ver := d.u32()
+	switch ver {
+	case sectionContribVer60, sectionContribV2:
+		// valid version.
+	default:
+		return nil, errors.Errorf("support for section contribution substream version 0x%08X not yet implemented", ver)
+	}
+	var contribs []SectionContrib
+	for d.pos < len(d.buf) {
+		contrib := parseSectionContrib(d)
+		if ver == sectionContribV2 {
+			// COFF section index.
+			d.u32()
+		}
+		if d.err != nil {
+			return nil, errors.Wrapf(d.err, "unable to decode section contribution %d", len(contribs))
+		}
+		contribs = append(contribs, contrib)
+	}
+	return contribs, nil
+}
+
+// parseSectionContrib parses a section contribution entry, reading from d.
+func parseSectionContrib(d *decoder) SectionContrib {
+	contrib := SectionContrib{
+		Section: d.u16(),
+	}
+	// Padding.
+	d.u16()
+	contrib.Offset = d.u32()
+	contrib.Size = d.u32()
+	contrib.Flags = enum.SectionFlag(d.u32())
+	contrib.ModuleIndex = d.u16()
+	// Padding.
+	d.u16()
+	contrib.DataCRC = d.u32()
+	contrib.RelocCRC = d.u32()
+	return contrib
+}
+
+// parseSectionMap parses the given section map substream of the DBI stream.
+func parseSectionMap(buf []byte) ([]SectionMapEntry, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	d := &decoder{buf: buf}
+	n := d.u16()
+	// Number of logical segments.
+	d.u16()
+	var entries []SectionMapEntry
+	for i := 0; i < int(n); i++ {
+		entry := SectionMapEntry{
+			Flags:       d.u16(),
+			Overlay:     d.u16(),
+			Group:       d.u16(),
+			Frame:       d.u16(),
+			SectionName: d.u16(),
+			ClassName:   d.u16(),
+			Offset:      d.u32(),
+			Length:      d.u32(),
+		}
+		if d.err != nil {
+			return nil, errors.Wrapf(d.err, "unable to decode section map entry %d", i)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,148 @@
+package pdb
+
+import (
+	"time"
+
+	"github.com/mewmew/pe"
+	"github.com/pkg/errors"
+)
+
+// --- [ PDB info stream ] -----------------------------------------------------
+
+// InfoStream is the PDB info stream (stream 1).
+//
+// ref: https://llvm.org/docs/PDB/PdbStream.html
+type InfoStream struct {
+	// PDB format version (e.g. 20000404 for VC70).
+	Version uint32
+	// Creation time of the PDB file.
+	Date time.Time
+	// Incremental number, initially set to 1 and incremented for each write to
+	// the PDB file.
+	Age uint32
+	// PDB signature GUID; matches the GUID of the RSDS CodeView debug
+	// information of the image.
+	GUID pe.GUID
+	// Stream indices of named streams (e.g. "/names"), indexed by stream name.
+	NamedStreams map[string]uint32
+	// Feature codes (e.g. 20140508 for VC140).
+	Features []uint32
+}
+
+// parseInfoStream parses the given PDB info stream.
+func parseInfoStream(buf []byte) (*InfoStream, error) {
+	d := &decoder{buf: buf}
+	info := &InfoStream{
+		Version: d.u32(),
+		Date:    time.Unix(int64(d.u32()), 0),
+		Age:     d.u32(),
+	}
+	copy(info.GUID[:], d.read(16))
+	if d.err != nil {
+		return nil, errors.WithStack(d.err)
+	}
+	names, err := parseNamedStreamMap(d)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse named stream map")
+	}
+	info.NamedStreams = names
+	// Feature codes follow until the end of the stream.
+	for d.pos+4 <= len(d.buf) {
+		info.Features = append(info.Features, d.u32())
+	}
+	return info, nil
+}
+
+// parseNamedStreamMap parses the named stream map of the PDB info stream.
+func parseNamedStreamMap(d *decoder) (map[string]uint32, error) {
+	// Named stream map.
+	//
+	//    StringBufferSize uint32
+	//    StringBuffer     [StringBufferSize]byte
+	//    HashTable        hashTable
+	strs := d.read(int(d.u32()))
+	size := d.u32()
+	capacity := d.u32()
+	present := parseBitVector(d)
+	// Deleted bit vector.
+	parseBitVector(d)
+	if d.err != nil {
+		return nil, errors.WithStack(d.err)
+	}
+	names := make(map[string]uint32, size)
+	for i := uint32(0); i < capacity; i++ {
+		if !present.isSet(i) {
+			continue
+		}
+		key := d.u32()
+		value := d.u32()
+		if d.err != nil {
+			return nil, errors.WithStack(d.err)
+		}
+		if int(key) >= len(strs) {
+			return nil, errors.Errorf("invalid offset %d of stream name; expected < %d", key, len(strs))
+		}
+		names[parseCString(strs[key:])] = value
+	}
+	return names, nil
+}
+
+// bitVector is a bit vector of a serialized hash table.
+type bitVector []uint32
+
+// parseBitVector parses a bit vector, reading from d.
+func parseBitVector(d *decoder) bitVector {
+	n := d.u32()
+	if d.err != nil || uint64(n)*4 > uint64(len(d.buf)-d.pos) {
+		d.read(int(n) * 4)
+		return nil
+	}
+	words := make(bitVector, n)
+	for i := range words {
+		words[i] = d.u32()
+	}
+	return words
+}
+
+// isSet reports whether the bit at the given index is set.
+func (v bitVector) isSet(i uint32) bool {
+	if int(i/32) >= len(v) {
+		return false
+	}
+	return v[i/32]&(1<<(i%32)) != 0
+}
+
+// --- [ /names stream ] -------------------------------------------------------
+
+// StringTable is the string buffer of the /names stream, containing
+// NULL-terminated strings referenced by offset (e.g. source file names).
+//
+// ref: https://llvm.org/docs/PDB/StringTable.html
+type StringTable []byte
+
+// Get returns the string at the given offset of the string table.
+func (tab StringTable) Get(offset uint32) string {
+	if int(offset) >= len(tab) {
+		return ""
+	}
+	return parseCString(tab[offset:])
+}
+
+// String table signature of the /names stream.
+const stringTableSignature = 0xEFFEEFFE
+
+// parseStringTable parses the given /names stream.
+func parseStringTable(buf []byte) (StringTable, error) {
+	d := &decoder{buf: buf}
+	sig := d.u32()
+	// Hash version.
+	d.u32()
+	strs := d.read(int(d.u32()))
+	if d.err != nil {
+		return nil, errors.WithStack(d.err)
+	}
+	if sig != stringTableSignature {
+		return nil, errors.Errorf("invalid string table signature; expected 0x%08X, got 0x%08X", stringTableSignature, sig)
+	}
+	return StringTable(strs), nil
+}
@@ -0,0 +1,181 @@
+package pdb
+
+import (
+	"github.com/pkg/errors"
+)
+
+// --- [ C13 line information ] ------------------------------------------------
+
+// C13 debug subsection kinds.
+//
+// ref: https://github.com/Microsoft/microsoft-pdb/blob/master/include/cvinfo.h
+const (
+	debugSubsectionLines         = 0xF2 // DEBUG_S_LINES
+	debugSubsectionFileChecksums = 0xF4 // DEBUG_S_FILECHKSMS
+	// Flag indicating that the subsection should be ignored.
+	debugSubsectionIgnore = 0x80000000
+)
+
+// Flag of the lines subsection header indicating the presence of column
+// information (CV_LINES_HAVE_COLUMNS).
+const linesHaveColumns = 0x0001
+
+// LineBlock is a block of line information of a single source file, covering a
+// contiguous range of code.
+type LineBlock struct {
+	// Section number (1-based).
+	Segment uint16
+	// Offset of code (relative to section start).
+	Offset uint32
+	// Size of code in number of bytes, as covered by the enclosing lines
+	// subsection.
+	Size uint32
+	// Source file name.
+	FileName string
+	// Line entries, in order of increasing offset.
+	Lines []Line
+}
+
+// Line maps a code offset to a source line.
+type Line struct {
+	// Offset of code (relative to LineBlock.Offset).
+	Offset uint32
+	// Start line number (1-based).
+	LineNum uint32
+	// End line number (1-based).
+	EndLineNum uint32
+	// Specifies whether the code corresponds to a statement (as opposed to an
+	// expression).
+	IsStatement bool
+	// Start column number (1-based); zero if not present.
+	StartColumn uint16
+	// End column number (1-based); zero if not present.
+	EndColumn uint16
+}
+
+// parseC13Lines parses the given C13 line information of a module symbol
+// stream, resolving source file names through the given string table.
+//
+// ref: https://llvm.org/docs/PDB/ModiStream.html
+func parseC13Lines(buf []byte, names StringTable) ([]LineBlock, error) {
+	// Locate file checksums subsection, as referred to by line blocks.
+	type subsection struct {
+		kind uint32
+		data []byte
+	}
+	var subsections []subsection
+	var checksums []byte
+	d := &decoder{buf: buf}
+	for d.pos < len(d.buf) {
+		kind := d.u32()
+		data := d.read(int(d.u32()))
+		// Subsections are aligned to 4 bytes.
+		d.align(4)
+		if d.err != nil {
+			return nil, errors.Wrap(d.err, "unable to decode C13 debug subsection")
+		}
+		if kind&debugSubsectionIgnore != 0 {
+			continue
+		}
+		if kind == debugSubsectionFileChecksums {
+			checksums = data
+		}
+		subsections = append(subsections, subsection{kind: kind, data: data})
+	}
+	var blocks []LineBlock
+	for _, sub := range subsections {
+		if sub.kind != debugSubsectionLines {
+			continue
+		}
+		bs, err := parseLinesSubsection(sub.data, checksums, names)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		blocks = append(blocks, bs...)
+	}
+	return blocks, nil
+}
+
+// parseLinesSubsection parses the given lines subsection.
+func parseLinesSubsection(buf, checksums []byte, names StringTable) ([]LineBlock, error) {
+	// Lines subsection header.
+	//
+	//    RelocOffset  uint32
+	//    RelocSegment uint16
+	//    Flags        uint16
+	//    CodeSize     uint32
+	d := &decoder{buf: buf}
+	offset := d.u32()
+	segment := d.u16()
+	flags := d.u16()
+	size := d.u32()
+	var blocks []LineBlock
+	for d.err == nil && d.pos < len(d.buf) {
+		// Line block header.
+		//
+		//    NameIndex uint32
+		//    NLines    uint32
+		//    BlockSize uint32
+		fileIndex := d.u32()
+		nlines := d.u32()
+		d.u32()
+		if d.err != nil {
+			break
+		}
+		if uint64(nlines)*8 > uint64(len(d.buf)-d.pos) {
+			return nil, errors.Errorf("invalid number of line entries %d of line block", nlines)
+		}
+		fileName, err := checksumFileName(checksums, fileIndex, names)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		block := LineBlock{
+			Segment:  segment,
+			Offset:   offset,
+			Size:     size,
+			FileName: fileName,
+			Lines:    make([]Line, nlines),
+		}
+		for i := range block.Lines {
+			// Line entry.
+			//
+			//    Offset       uint32
+			//    LineNumStart : 24
+			//    DeltaLineEnd : 7
+			//    IsStatement  : 1
+			l := &block.Lines[i]
+			l.Offset = d.u32()
+			v := d.u32()
+			l.LineNum = v & 0x00FFFFFF
+			l.EndLineNum = l.LineNum + (v>>24)&0x7F
+			l.IsStatement = v&0x80000000 != 0
+		}
+		if flags&linesHaveColumns != 0 {
+			for i := range block.Lines {
+				block.Lines[i].StartColumn = d.u16()
+				block.Lines[i].EndColumn = d.u16()
+			}
+		}
+		blocks = append(blocks, block)
+	}
+	if d.err != nil {
+		return nil, errors.Wrap(d.err, "unable to decode lines subsection")
+	}
+	return blocks, nil
+}
+
+// checksumFileName returns the source file name of the file checksum entry at
+// the given offset into the file checksums subsection.
+func checksumFileName(checksums []byte, offset uint32, names StringTable) (string, error) {
+	// File checksum entry.
+	//
+	//    FileNameOffset uint32
+	//    ChecksumSize   uint8
+	//    ChecksumKind   uint8
+	//    Checksum       [ChecksumSize]byte
+	if uint64(offset)+4 > uint64(len(checksums)) {
+		return "", errors.Errorf("invalid file checksum offset 0x%X; expected <= 0x%X", offset, len(checksums))
+	}
+	d := &decoder{buf: checksums, pos: int(offset)}
+	return names.Get(d.u32()), nil
+}
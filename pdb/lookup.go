@@ -0,0 +1,159 @@
+package pdb
+
+import (
+	"github.com/mewmew/pe"
+	"github.com/pkg/errors"
+)
+
+// --- [ Address lookup ] ------------------------------------------------------
+
+// Location is the function and source location of code.
+type Location struct {
+	// Function name; empty if unknown.
+	Func string
+	// Relative address of function start (relative to image base).
+	FuncRelAddr uint32
+	// Source file name; empty if unknown.
+	FileName string
+	// Source line number (1-based); zero if unknown.
+	Line int
+	// Source column number (1-based); zero if unknown.
+	Column int
+}
+
+// Matches reports whether the PDB file matches the RSDS CodeView debug
+// information of the given image, based on PDB signature GUID and age.
+func (f *File) Matches(file *pe.File) bool {
+	age := f.Info.Age
+	if f.DBI != nil {
+		age = f.DBI.Age
+	}
+	for _, dbgData := range file.DbgData {
+		dbgCodeView, ok := dbgData.(*pe.DebugCodeView)
+		if !ok {
+			continue
+		}
+		if rsds, ok := dbgCodeView.Info.(*pe.CodeViewRSDS); ok {
+			return rsds.GUID == f.Info.GUID && rsds.Age == age
+		}
+	}
+	return false
+}
+
+// Lookup returns the function and source location of the code at the given
+// relative address (relative to image base) of the given image. The boolean
+// return value indicates success.
+//
+// Functions are resolved through the procedure symbols of modules, falling back
+// to public symbols; source locations are resolved through C13 line
+// information.
+func (f *File) Lookup(file *pe.File, relAddr uint32) (Location, bool, error) {
+	var loc Location
+	seg, offset, ok := sectionOffset(file, relAddr)
+	if !ok || f.DBI == nil {
+		return loc, false, nil
+	}
+	sectRelAddr := file.SectHdrs[seg-1].RelAddr
+	// Locate the module contributing the code; or search all modules if no
+	// section contributions are present.
+	var modIndices []int
+	var contrib *SectionContrib
+	for i, c := range f.DBI.SectionContribs {
+		if c.Section == seg && c.Offset <= offset && uint64(offset) < uint64(c.Offset)+uint64(c.Size) {
+			contrib = &f.DBI.SectionContribs[i]
+			modIndices = append(modIndices, int(c.ModuleIndex))
+			break
+		}
+	}
+	if len(f.DBI.SectionContribs) == 0 {
+		for i := range f.DBI.Modules {
+			modIndices = append(modIndices, i)
+		}
+	}
+	for _, modIndex := range modIndices {
+		info, err := f.ModuleInfo(modIndex)
+		if err != nil {
+			return loc, false, errors.WithStack(err)
+		}
+		for _, proc := range info.Procs {
+			if proc.Segment == seg && proc.Offset <= offset && uint64(offset) < uint64(proc.Offset)+uint64(proc.Size) {
+				loc.Func = proc.Name
+				loc.FuncRelAddr = sectRelAddr + proc.Offset
+				break
+			}
+		}
+		if line, fileName, ok := lineAt(info.Lines, seg, offset); ok {
+			loc.FileName = fileName
+			loc.Line = int(line.LineNum)
+			loc.Column = int(line.StartColumn)
+		}
+		if loc.Func != "" || loc.Line != 0 {
+			break
+		}
+	}
+	if loc.Func == "" {
+		// Fall back to the closest preceding public function symbol, within the
+		// same section contribution if present; e.g. for code of modules without
+		// debug information, or code not covered by any section contribution.
+		var pub *PublicSymbol
+		for i, sym := range f.Publics {
+			if sym.Segment != seg || sym.Offset > offset || !sym.IsFunction() {
+				continue
+			}
+			if contrib != nil && sym.Offset < contrib.Offset {
+				continue
+			}
+			if pub == nil || sym.Offset > pub.Offset {
+				pub = &f.Publics[i]
+			}
+		}
+		if pub != nil {
+			loc.Func = pub.Name
+			loc.FuncRelAddr = sectRelAddr + pub.Offset
+		}
+	}
+	return loc, loc.Func != "" || loc.Line != 0, nil
+}
+
+// lineAt returns the line entry and source file name of the code at the given
+// section offset, as recorded by the given line blocks; i.e. the line entry
+// with the closest preceding code offset. The boolean return value indicates
+// success.
+func lineAt(blocks []LineBlock, seg uint16, offset uint32) (Line, string, bool) {
+	var line Line
+	var fileName string
+	var start uint32
+	found := false
+	for _, block := range blocks {
+		if block.Segment != seg || offset < block.Offset || uint64(offset) >= uint64(block.Offset)+uint64(block.Size) {
+			continue
+		}
+		for _, l := range block.Lines {
+			addr := block.Offset + l.Offset
+			if addr > offset || (found && addr < start) {
+				continue
+			}
+			line = l
+			fileName = block.FileName
+			start = addr
+			found = true
+		}
+	}
+	return line, fileName, found
+}
+
+// sectionOffset returns the section number (1-based) and section offset of the
+// given relative address (relative to image base) of the given image. The
+// boolean return value indicates success.
+func sectionOffset(file *pe.File, relAddr uint32) (uint16, uint32, bool) {
+	for i, sectHdr := range file.SectHdrs {
+		size := sectHdr.VirtualSize
+		if size == 0 {
+			size = sectHdr.DataSize
+		}
+		if sectHdr.RelAddr <= relAddr && uint64(relAddr) < uint64(sectHdr.RelAddr)+uint64(size) {
+			return uint16(i + 1), relAddr - sectHdr.RelAddr, true
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,286 @@
+package pdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/pe"
+)
+
+// testdata/lookup.pdb was generated from testdata/lookup.yaml using LLVM:
+//
+//    llvm-pdbutil yaml2pdb --pdb=lookup.pdb lookup.yaml
+//
+// The PDB signature GUID and age match the RSDS CodeView debug information of
+// ../testdata/Microsoft.TestPlatform.PlatformAbstractions.dll.
+
+func TestParseLLVMPDB(t *testing.T) {
+	const path = "testdata/lookup.pdb"
+	f, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	if got, want := f.Info.GUID.String(), "A154F334-66B6-48AF-BC67-7B6B88C145FA"; got != want {
+		t.Errorf("%q: GUID mismatch; expected %s, got %s", path, want, got)
+	}
+	if f.DBI == nil {
+		t.Fatalf("%q: unable to locate DBI stream", path)
+	}
+	if f.DBI.Version != 19990903 || f.DBI.Age != 1 || f.DBI.BuildNumber != 36363 {
+		t.Errorf("%q: DBI stream header mismatch; expected version 19990903, age 1 and build number 36363, got version %d, age %d and build number %d", path, f.DBI.Version, f.DBI.Age, f.DBI.BuildNumber)
+	}
+	if f.DBI.PublicStream != nilStream || len(f.Publics) != 0 {
+		t.Errorf("%q: unexpected publics stream %d with %d public symbols", path, f.DBI.PublicStream, len(f.Publics))
+	}
+	golden := []struct {
+		name     string
+		nfiles   uint16
+		wantInfo *ModuleInfo
+	}{
+		{
+			name:   `C:\src\foo.obj`,
+			nfiles: 1,
+			wantInfo: &ModuleInfo{
+				Procs: []Proc{{Name: "foo", Global: true, Segment: 1, Offset: 0x10, Size: 0x20}},
+				Lines: []LineBlock{
+					{
+						Segment:  1,
+						Offset:   0x10,
+						Size:     0x20,
+						FileName: `C:\src\foo.c`,
+						Lines: []Line{
+							{Offset: 0, LineNum: 3, EndLineNum: 3, IsStatement: true, StartColumn: 5, EndColumn: 17},
+							{Offset: 8, LineNum: 4, EndLineNum: 4, IsStatement: true, StartColumn: 9, EndColumn: 21},
+							{Offset: 20, LineNum: 5, EndLineNum: 5, IsStatement: true, StartColumn: 1, EndColumn: 2},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   `C:\src\bar.obj`,
+			nfiles: 2,
+			wantInfo: &ModuleInfo{
+				Procs: []Proc{{Name: "bar", Segment: 1, Offset: 0x40, Size: 0x10}},
+				Lines: []LineBlock{
+					{Segment: 1, Offset: 0x40, Size: 0x10, FileName: `C:\src\bar.c`, Lines: []Line{{Offset: 0, LineNum: 10, EndLineNum: 10, IsStatement: true}}},
+					{Segment: 1, Offset: 0x40, Size: 0x10, FileName: `C:\src\bar.h`, Lines: []Line{{Offset: 6, LineNum: 42, EndLineNum: 44, IsStatement: true}}},
+				},
+			},
+		},
+	}
+	if len(f.DBI.Modules) != len(golden) {
+		t.Fatalf("%q: number of modules mismatch; expected %d, got %d", path, len(golden), len(f.DBI.Modules))
+	}
+	for i, g := range golden {
+		mod := f.DBI.Modules[i]
+		if mod.Name != g.name || mod.ObjFileName != g.name || mod.NSourceFiles != g.nfiles {
+			t.Errorf("%q: module %d mismatch; expected %q with %d source files, got %q (%q) with %d source files", path, i, g.name, g.nfiles, mod.Name, mod.ObjFileName, mod.NSourceFiles)
+		}
+		info, err := f.ModuleInfo(i)
+		if err != nil {
+			t.Errorf("%q: unable to parse debug information of module %d; %+v", path, i, err)
+			continue
+		}
+		if !reflect.DeepEqual(info, g.wantInfo) {
+			t.Errorf("%q: debug information of module %d mismatch; expected %+v, got %+v", path, i, g.wantInfo, info)
+		}
+	}
+	if _, err := f.ModuleInfo(len(golden)); err == nil {
+		t.Errorf("%q: expected error for invalid module index %d", path, len(golden))
+	}
+}
+
+func TestMatches(t *testing.T) {
+	const path = "testdata/lookup.pdb"
+	f, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	const imagePath = "../testdata/Microsoft.TestPlatform.PlatformAbstractions.dll"
+	file, err := pe.ParseFile(imagePath)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", imagePath, err)
+	}
+	if !f.Matches(file) {
+		t.Errorf("%q: expected match of %q", path, imagePath)
+	}
+	// Images without CodeView debug information.
+	if f.Matches(&pe.File{}) {
+		t.Errorf("%q: unexpected match of image without debug information", path)
+	}
+	// The age of the DBI stream takes precedence over the age of the PDB info
+	// stream.
+	f.DBI.Age = 2
+	if f.Matches(file) {
+		t.Errorf("%q: unexpected match of %q with mismatching age", path, imagePath)
+	}
+}
+
+// lookupImage is an image with a single code section, located at relative
+// address 0x1000.
+var lookupImage = &pe.File{
+	SectHdrs: []pe.SectionHeader{
+		{Name: ".text", VirtualSize: 0x100, RelAddr: 0x1000},
+	},
+}
+
+func TestLookup(t *testing.T) {
+	const path = "testdata/lookup.pdb"
+	f, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	// Without section contributions, all modules are searched.
+	golden := []struct {
+		relAddr uint32
+		want    Location
+		wantOK  bool
+	}{
+		{relAddr: 0x1010, want: Location{Func: "foo", FuncRelAddr: 0x1010, FileName: `C:\src\foo.c`, Line: 3, Column: 5}, wantOK: true},
+		{relAddr: 0x101C, want: Location{Func: "foo", FuncRelAddr: 0x1010, FileName: `C:\src\foo.c`, Line: 4, Column: 9}, wantOK: true},
+		{relAddr: 0x102F, want: Location{Func: "foo", FuncRelAddr: 0x1010, FileName: `C:\src\foo.c`, Line: 5, Column: 1}, wantOK: true},
+		// Line blocks of different source files within the same lines
+		// subsection.
+		{relAddr: 0x1042, want: Location{Func: "bar", FuncRelAddr: 0x1040, FileName: `C:\src\bar.c`, Line: 10}, wantOK: true},
+		{relAddr: 0x1048, want: Location{Func: "bar", FuncRelAddr: 0x1040, FileName: `C:\src\bar.h`, Line: 42}, wantOK: true},
+		// Code without debug information or public symbols.
+		{relAddr: 0x1000, wantOK: false},
+		{relAddr: 0x1050, wantOK: false},
+		// Outside of sections.
+		{relAddr: 0x2000, wantOK: false},
+	}
+	for _, g := range golden {
+		loc, ok, err := f.Lookup(lookupImage, g.relAddr)
+		if err != nil {
+			t.Errorf("%q: unable to look up relative address 0x%08X; %+v", path, g.relAddr, err)
+			continue
+		}
+		if ok != g.wantOK || loc != g.want {
+			t.Errorf("%q: location of relative address 0x%08X mismatch; expected %+v (%v), got %+v (%v)", path, g.relAddr, g.want, g.wantOK, loc, ok)
+		}
+	}
+}
+
+func TestLookupPublics(t *testing.T) {
+	const path = "testdata/lookup.pdb"
+	content := withPublics(t, path)
+	f, err := ParseBytes(content)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file with publics; %+v", path, err)
+	}
+	if !reflect.DeepEqual(f.Publics, lookupPublics) {
+		t.Errorf("%q: public symbols mismatch; expected %+v, got %+v", path, lookupPublics, f.Publics)
+	}
+	if len(f.DBI.SectionContribs) != 3 {
+		t.Fatalf("%q: number of section contributions mismatch; expected 3, got %d", path, len(f.DBI.SectionContribs))
+	}
+	golden := []struct {
+		relAddr uint32
+		want    Location
+		wantOK  bool
+	}{
+		// Module located through section contribution.
+		{relAddr: 0x1018, want: Location{Func: "foo", FuncRelAddr: 0x1010, FileName: `C:\src\foo.c`, Line: 4, Column: 9}, wantOK: true},
+		{relAddr: 0x1048, want: Location{Func: "bar", FuncRelAddr: 0x1040, FileName: `C:\src\bar.h`, Line: 42}, wantOK: true},
+		// Section contribution of module without procedure symbol; public
+		// symbol within the section contribution.
+		{relAddr: 0x1068, want: Location{Func: "_baz", FuncRelAddr: 0x1060}, wantOK: true},
+		// Code not covered by any section contribution; closest preceding
+		// public function symbol.
+		{relAddr: 0x1004, want: Location{Func: "_start", FuncRelAddr: 0x1000}, wantOK: true},
+		{relAddr: 0x100C, want: Location{Func: "_start", FuncRelAddr: 0x1000}, wantOK: true},
+		{relAddr: 0x1090, want: Location{Func: "_baz", FuncRelAddr: 0x1060}, wantOK: true},
+	}
+	for _, g := range golden {
+		loc, ok, err := f.Lookup(lookupImage, g.relAddr)
+		if err != nil {
+			t.Errorf("%q: unable to look up relative address 0x%08X; %+v", path, g.relAddr, err)
+			continue
+		}
+		if ok != g.wantOK || loc != g.want {
+			t.Errorf("%q: location of relative address 0x%08X mismatch; expected %+v (%v), got %+v (%v)", path, g.relAddr, g.want, g.wantOK, loc, ok)
+		}
+	}
+}
+
+// lookupPublics are the public symbols added by withPublics, sorted by address.
+var lookupPublics = []PublicSymbol{
+	{Flags: pubSymFlagFunction, Segment: 1, Offset: 0x00, Name: "_start"},
+	{Segment: 1, Offset: 0x08, Name: "_data"},
+	{Flags: pubSymFlagFunction, Segment: 1, Offset: 0x10, Name: "foo"},
+	{Flags: pubSymFlagFunction, Segment: 1, Offset: 0x60, Name: "_baz"},
+}
+
+// withPublics returns the contents of the given PDB file, extended with section
+// contributions, a publics stream and a symbol record stream.
+func withPublics(t *testing.T, path string) []byte {
+	f, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	streams := append([][]byte(nil), f.Streams...)
+	// Symbol record stream of S_PUB32 records, each aligned to 4 bytes.
+	symRecords := &bytes.Buffer{}
+	addrMap := &bytes.Buffer{}
+	for _, pub := range lookupPublics {
+		binary.Write(addrMap, binary.LittleEndian, uint32(symRecords.Len()))
+		rec := &bytes.Buffer{}
+		binary.Write(rec, binary.LittleEndian, uint16(symPub32))
+		binary.Write(rec, binary.LittleEndian, pub.Flags)
+		binary.Write(rec, binary.LittleEndian, pub.Offset)
+		binary.Write(rec, binary.LittleEndian, pub.Segment)
+		rec.WriteString(pub.Name)
+		rec.WriteByte(0)
+		for (2+rec.Len())%4 != 0 {
+			rec.WriteByte(0)
+		}
+		binary.Write(symRecords, binary.LittleEndian, uint16(rec.Len()))
+		symRecords.Write(rec.Bytes())
+	}
+	// Publics stream header, with an empty GSI hash table.
+	publicsStream := &bytes.Buffer{}
+	binary.Write(publicsStream, binary.LittleEndian, uint32(0))
+	binary.Write(publicsStream, binary.LittleEndian, uint32(addrMap.Len()))
+	publicsStream.Write(make([]byte, 4+4+2+2+4+4))
+	publicsStream.Write(addrMap.Bytes())
+	publicStream := len(streams)
+	symRecordStream := len(streams) + 1
+	streams = append(streams, publicsStream.Bytes(), symRecords.Bytes())
+	// Section contribution substream; the code at offset 0x00-0x10 and 0x80 and
+	// above is not covered by any section contribution.
+	contribs := &bytes.Buffer{}
+	binary.Write(contribs, binary.LittleEndian, uint32(sectionContribVer60))
+	for _, c := range []SectionContrib{
+		{Section: 1, Offset: 0x10, Size: 0x20, ModuleIndex: 0},
+		{Section: 1, Offset: 0x40, Size: 0x10, ModuleIndex: 1},
+		{Section: 1, Offset: 0x60, Size: 0x20, ModuleIndex: 1},
+	} {
+		binary.Write(contribs, binary.LittleEndian, c.Section)
+		binary.Write(contribs, binary.LittleEndian, uint16(0))
+		binary.Write(contribs, binary.LittleEndian, c.Offset)
+		binary.Write(contribs, binary.LittleEndian, c.Size)
+		binary.Write(contribs, binary.LittleEndian, uint32(c.Flags))
+		binary.Write(contribs, binary.LittleEndian, c.ModuleIndex)
+		binary.Write(contribs, binary.LittleEndian, uint16(0))
+		binary.Write(contribs, binary.LittleEndian, c.DataCRC)
+		binary.Write(contribs, binary.LittleEndian, c.RelocCRC)
+	}
+	// Update the DBI stream header and replace the section contribution
+	// substream, which follows the 64-byte header and module info substream.
+	dbi := append([]byte(nil), streams[StreamDBI]...)
+	binary.LittleEndian.PutUint16(dbi[16:], uint16(publicStream))
+	binary.LittleEndian.PutUint16(dbi[20:], uint16(symRecordStream))
+	modInfoSize := binary.LittleEndian.Uint32(dbi[24:])
+	oldContribsSize := binary.LittleEndian.Uint32(dbi[28:])
+	binary.LittleEndian.PutUint32(dbi[28:], uint32(contribs.Len()))
+	contribsStart := 64 + modInfoSize
+	newDBI := append([]byte(nil), dbi[:contribsStart]...)
+	newDBI = append(newDBI, contribs.Bytes()...)
+	newDBI = append(newDBI, dbi[contribsStart+oldContribsSize:]...)
+	streams[StreamDBI] = newDBI
+	b := &msfBuilder{blockSize: 512}
+	return b.build(streams, 0)
+}
@@ -0,0 +1,64 @@
+package pdb
+
+import "github.com/pkg/errors"
+
+// --- [ Module debug information ] --------------------------------------------
+
+// ModuleInfo is the debug information of a module, as stored in its module
+// symbol stream.
+type ModuleInfo struct {
+	// Procedure symbols.
+	Procs []Proc
+	// C13 line information.
+	Lines []LineBlock
+}
+
+// ModuleInfo returns the debug information of the module with the given index
+// into the module list of the DBI stream.
+func (f *File) ModuleInfo(modIndex int) (*ModuleInfo, error) {
+	if info, ok := f.modInfos[modIndex]; ok {
+		return info, nil
+	}
+	if f.DBI == nil || modIndex < 0 || modIndex >= len(f.DBI.Modules) {
+		return nil, errors.Errorf("invalid module index %d", modIndex)
+	}
+	mod := f.DBI.Modules[modIndex]
+	info := &ModuleInfo{}
+	if mod.SymStream != nilStream {
+		// Module symbol stream.
+		//
+		//    Signature uint32
+		//    Symbols   [SymSize-4]byte
+		//    C11Lines  [C11Size]byte
+		//    C13Lines  [C13Size]byte
+		d := &decoder{buf: f.Stream(int(mod.SymStream))}
+		sig := d.u32()
+		syms := d.read(int(mod.SymSize) - 4)
+		d.read(int(mod.C11Size))
+		lines := d.read(int(mod.C13Size))
+		if d.err != nil {
+			return nil, errors.Wrapf(d.err, "unable to decode symbol stream of module %q", mod.Name)
+		}
+		if sig != cvSignatureC13 {
+			return nil, errors.Errorf("support for module symbol stream signature %d not yet implemented", sig)
+		}
+		procs, err := parseModuleSymbols(syms)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse symbols of module %q", mod.Name)
+		}
+		info.Procs = procs
+		blocks, err := parseC13Lines(lines, f.Names)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse C13 line information of module %q", mod.Name)
+		}
+		info.Lines = blocks
+	}
+	if f.modInfos == nil {
+		f.modInfos = make(map[int]*ModuleInfo)
+	}
+	f.modInfos[modIndex] = info
+	return info, nil
+}
+
+// Module symbol stream signature of C13 line information (CV_SIGNATURE_C13).
+const cvSignatureC13 = 4
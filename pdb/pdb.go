@@ -0,0 +1,348 @@
+// Package pdb provides access to Microsoft program database (PDB) files, as
+// stored in the multi-stream file (MSF) format.
+//
+// ref: https://llvm.org/docs/PDB/index.html
+// ref: https://github.com/Microsoft/microsoft-pdb
+package pdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// File is a PDB file.
+type File struct {
+	// File contents.
+	Content []byte
+	// MSF superblock.
+	SuperBlock *SuperBlock
+	// Stream contents, indexed by stream index; nil for nil streams.
+	Streams [][]byte
+	// PDB info stream.
+	Info *InfoStream
+	// String table of the /names stream; nil if not present.
+	Names StringTable
+	// DBI stream; nil if not present.
+	DBI *DBIStream
+	// Public symbols, in order of the address map of the publics stream.
+	Publics []PublicSymbol
+
+	// Module debug information, indexed by module index; lazily initialized.
+	modInfos map[int]*ModuleInfo
+}
+
+// SuperBlock is the MSF superblock, located at the start of the file.
+type SuperBlock struct {
+	// Block size in number of bytes.
+	BlockSize uint32
+	// Block index of the active free block map.
+	FreeBlockMapBlock uint32
+	// Number of blocks in the file.
+	NBlocks uint32
+	// Size of the stream directory in number of bytes.
+	NDirBytes uint32
+	// Block index of the block map, listing the blocks of the stream
+	// directory.
+	BlockMapAddr uint32
+}
+
+// Fixed stream indices.
+const (
+	// Old MSF directory.
+	StreamOldDir = 0
+	// PDB info stream.
+	StreamPDB = 1
+	// TPI (type info) stream.
+	StreamTPI = 2
+	// DBI (debug info) stream.
+	StreamDBI = 3
+	// IPI (ID info) stream.
+	StreamIPI = 4
+)
+
+// nilStream is the stream index denoting a nil stream.
+const nilStream = 0xFFFF
+
+// nilStreamSize is the stream size of nil streams in the stream directory.
+const nilStreamSize = 0xFFFFFFFF
+
+// msfMagic is the file signature of MSF 7.00 files.
+var msfMagic = []byte("Microsoft C/C++ MSF 7.00\r\n\x1ADS\x00\x00\x00")
+
+// ParseFile parses the given PDB file.
+func ParseFile(path string) (*File, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ParseBytes(buf)
+}
+
+// Parse parses the given PDB file, reading from r.
+func Parse(r io.Reader) (*File, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ParseBytes(buf)
+}
+
+// ParseBytes parses the given PDB file, reading from content.
+func ParseBytes(content []byte) (*File, error) {
+	f := &File{
+		Content: content,
+	}
+	if err := f.parseMSF(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	info, err := parseInfoStream(f.Stream(StreamPDB))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse PDB info stream")
+	}
+	f.Info = info
+	if index, ok := info.NamedStreams["/names"]; ok {
+		names, err := parseStringTable(f.Stream(int(index)))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to parse /names stream")
+		}
+		f.Names = names
+	}
+	if buf := f.Stream(StreamDBI); len(buf) > 0 {
+		dbi, err := parseDBIStream(buf)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to parse DBI stream")
+		}
+		f.DBI = dbi
+		if dbi.PublicStream != nilStream {
+			publics, err := parsePublics(f.Stream(int(dbi.PublicStream)), f.Stream(int(dbi.SymRecordStream)))
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to parse publics stream")
+			}
+			f.Publics = publics
+		}
+	}
+	return f, nil
+}
+
+// Stream returns the contents of the stream with the given stream index; or nil
+// if not present.
+func (f *File) Stream(index int) []byte {
+	if index < 0 || index >= len(f.Streams) {
+		return nil
+	}
+	return f.Streams[index]
+}
+
+// parseMSF parses the MSF superblock and stream directory of the file, and
+// reads the contents of each stream.
+func (f *File) parseMSF() error {
+	if !bytes.HasPrefix(f.Content, msfMagic) {
+		return errors.Errorf("invalid MSF signature; expected %q", msfMagic)
+	}
+	var raw struct {
+		Magic             [32]byte
+		BlockSize         uint32
+		FreeBlockMapBlock uint32
+		NBlocks           uint32
+		NDirBytes         uint32
+		Unknown           uint32
+		BlockMapAddr      uint32
+	}
+	if err := binary.Read(bytes.NewReader(f.Content), binary.LittleEndian, &raw); err != nil {
+		return errors.WithStack(err)
+	}
+	switch raw.BlockSize {
+	case 512, 1024, 2048, 4096:
+		// valid block size.
+	default:
+		return errors.Errorf("invalid MSF block size %d", raw.BlockSize)
+	}
+	f.SuperBlock = &SuperBlock{
+		BlockSize:         raw.BlockSize,
+		FreeBlockMapBlock: raw.FreeBlockMapBlock,
+		NBlocks:           raw.NBlocks,
+		NDirBytes:         raw.NDirBytes,
+		BlockMapAddr:      raw.BlockMapAddr,
+	}
+	// The block map lists the blocks of the stream directory, and is stored
+	// in consecutive blocks starting at the block map address.
+	ndirBlocks := blockCount(raw.NDirBytes, raw.BlockSize)
+	blockMapSize := 4 * ndirBlocks
+	mapBlocks := make([]uint32, blockCount(blockMapSize, raw.BlockSize))
+	for i := range mapBlocks {
+		mapBlocks[i] = raw.BlockMapAddr + uint32(i)
+	}
+	blockMap, err := f.readBlocks(mapBlocks, blockMapSize)
+	if err != nil {
+		return errors.Wrap(err, "unable to read block map")
+	}
+	dirBlocks := make([]uint32, ndirBlocks)
+	for i := range dirBlocks {
+		dirBlocks[i] = binary.LittleEndian.Uint32(blockMap[4*i:])
+	}
+	dir, err := f.readBlocks(dirBlocks, raw.NDirBytes)
+	if err != nil {
+		return errors.Wrap(err, "unable to read stream directory")
+	}
+	// Stream directory.
+	//
+	//    NStreams    uint32
+	//    StreamSizes [NStreams]uint32
+	//    StreamBlocks[NStreams][]uint32
+	d := &decoder{buf: dir}
+	nstreams := d.u32()
+	if uint64(nstreams)*4 > uint64(len(dir)) {
+		return errors.Errorf("invalid number of streams %d in stream directory of size %d", nstreams, len(dir))
+	}
+	sizes := make([]uint32, nstreams)
+	for i := range sizes {
+		sizes[i] = d.u32()
+	}
+	f.Streams = make([][]byte, nstreams)
+	for i, size := range sizes {
+		if size == nilStreamSize {
+			continue
+		}
+		nblocks := blockCount(size, raw.BlockSize)
+		if uint64(nblocks)*4 > uint64(len(dir)-d.pos) {
+			return errors.Errorf("invalid size %d of stream %d; block list exceeds stream directory", size, i)
+		}
+		blocks := make([]uint32, nblocks)
+		for j := range blocks {
+			blocks[j] = d.u32()
+		}
+		if d.err != nil {
+			return errors.Wrap(d.err, "unable to decode stream directory")
+		}
+		buf, err := f.readBlocks(blocks, size)
+		if err != nil {
+			return errors.Wrapf(err, "unable to read stream %d", i)
+		}
+		f.Streams[i] = buf
+	}
+	return nil
+}
+
+// readBlocks returns the first size bytes of the contents of the given blocks.
+func (f *File) readBlocks(blocks []uint32, size uint32) ([]byte, error) {
+	blockSize := uint64(f.SuperBlock.BlockSize)
+	// Validate size before allocation.
+	if max := uint64(f.SuperBlock.NBlocks) * blockSize; uint64(size) > max {
+		return nil, errors.Errorf("size %d exceeds MSF file size of %d bytes", size, max)
+	}
+	if uint64(size) > uint64(len(f.Content)) {
+		return nil, errors.Errorf("size %d exceeds file size of %d bytes", size, len(f.Content))
+	}
+	if max := uint64(len(blocks)) * blockSize; uint64(size) > max {
+		return nil, errors.Errorf("size %d exceeds total size of %d blocks (%d bytes)", size, len(blocks), max)
+	}
+	buf := make([]byte, 0, size)
+	for _, block := range blocks {
+		n := uint64(size) - uint64(len(buf))
+		if n > blockSize {
+			n = blockSize
+		}
+		start := uint64(block) * blockSize
+		end := start + n
+		if end > uint64(len(f.Content)) {
+			return nil, errors.Errorf("block %d out of bounds; expected end <= %d, got %d", block, len(f.Content), end)
+		}
+		buf = append(buf, f.Content[start:end]...)
+	}
+	return buf, nil
+}
+
+// blockCount returns the number of blocks required to store size bytes.
+func blockCount(size, blockSize uint32) uint32 {
+	return uint32((uint64(size) + uint64(blockSize) - 1) / uint64(blockSize))
+}
+
+// ### [ Helper functions ] ####################################################
+
+// decoder decodes little-endian values of a stream. Errors are sticky; once an
+// error is encountered, all subsequent reads return zero.
+type decoder struct {
+	// Stream contents.
+	buf []byte
+	// Current read position.
+	pos int
+	// First error encountered.
+	err error
+}
+
+// read returns the next n bytes of the stream.
+func (d *decoder) read(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.pos+n > len(d.buf) {
+		d.err = errors.WithStack(io.ErrUnexpectedEOF)
+		return nil
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+// u8 decodes a 1-byte value.
+func (d *decoder) u8() uint8 {
+	b := d.read(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+// u16 decodes a 2-byte value.
+func (d *decoder) u16() uint16 {
+	b := d.read(2)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(b)
+}
+
+// u32 decodes a 4-byte value.
+func (d *decoder) u32() uint32 {
+	b := d.read(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+// cstring decodes a NULL-terminated string.
+func (d *decoder) cstring() string {
+	if d.err != nil {
+		return ""
+	}
+	pos := bytes.IndexByte(d.buf[d.pos:], 0)
+	if pos == -1 {
+		d.err = errors.WithStack(io.ErrUnexpectedEOF)
+		return ""
+	}
+	s := string(d.buf[d.pos : d.pos+pos])
+	d.pos += pos + 1
+	return s
+}
+
+// align advances the read position to the next multiple of n.
+func (d *decoder) align(n int) {
+	if rem := d.pos % n; rem != 0 {
+		d.pos += n - rem
+	}
+}
+
+// parseCString parses the given a NULL-terminated string into a corresponding
+// Go string.
+func parseCString(b []byte) string {
+	pos := bytes.IndexByte(b, '\x00')
+	if pos != -1 {
+		b = b[:pos]
+	}
+	return string(b)
+}
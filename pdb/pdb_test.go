@@ -0,0 +1,175 @@
+package pdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// msfBuilder builds MSF files for testing.
+type msfBuilder struct {
+	// Block size in number of bytes.
+	blockSize int
+	// Blocks of the file, excluding the superblock and free block maps.
+	blocks [][]byte
+}
+
+// alloc stores the given data in consecutive blocks, returning the block
+// indices.
+func (b *msfBuilder) alloc(data []byte) []uint32 {
+	var indices []uint32
+	for len(data) > 0 {
+		n := len(data)
+		if n > b.blockSize {
+			n = b.blockSize
+		}
+		block := make([]byte, b.blockSize)
+		copy(block, data[:n])
+		data = data[n:]
+		// Blocks 0-2 are the superblock and the two free block maps.
+		indices = append(indices, uint32(3+len(b.blocks)))
+		b.blocks = append(b.blocks, block)
+	}
+	return indices
+}
+
+// build returns the contents of an MSF file with the given streams (nil for nil
+// streams), and the stream directory padded with nstreamsPad nil streams.
+func (b *msfBuilder) build(streams [][]byte, nstreamsPad int) []byte {
+	dir := &bytes.Buffer{}
+	binary.Write(dir, binary.LittleEndian, uint32(len(streams)+nstreamsPad))
+	var blocks [][]uint32
+	for _, stream := range streams {
+		if stream == nil {
+			binary.Write(dir, binary.LittleEndian, uint32(nilStreamSize))
+			blocks = append(blocks, nil)
+			continue
+		}
+		binary.Write(dir, binary.LittleEndian, uint32(len(stream)))
+		blocks = append(blocks, b.alloc(stream))
+	}
+	for i := 0; i < nstreamsPad; i++ {
+		binary.Write(dir, binary.LittleEndian, uint32(nilStreamSize))
+	}
+	for _, bs := range blocks {
+		binary.Write(dir, binary.LittleEndian, bs)
+	}
+	dirBlocks := b.alloc(dir.Bytes())
+	blockMap := &bytes.Buffer{}
+	binary.Write(blockMap, binary.LittleEndian, dirBlocks)
+	mapBlocks := b.alloc(blockMap.Bytes())
+	buf := &bytes.Buffer{}
+	buf.Write(msfMagic)
+	binary.Write(buf, binary.LittleEndian, uint32(b.blockSize))
+	binary.Write(buf, binary.LittleEndian, uint32(1))
+	binary.Write(buf, binary.LittleEndian, uint32(3+len(b.blocks)))
+	binary.Write(buf, binary.LittleEndian, uint32(dir.Len()))
+	binary.Write(buf, binary.LittleEndian, uint32(0))
+	binary.Write(buf, binary.LittleEndian, mapBlocks[0])
+	buf.Write(make([]byte, 3*b.blockSize-buf.Len()))
+	for _, block := range b.blocks {
+		buf.Write(block)
+	}
+	return buf.Bytes()
+}
+
+// infoStream returns the contents of a minimal PDB info stream with the given
+// age.
+func infoStream(age uint32) []byte {
+	buf := &bytes.Buffer{}
+	binary.Write(buf, binary.LittleEndian, uint32(20000404)) // version
+	binary.Write(buf, binary.LittleEndian, uint32(0))        // date
+	binary.Write(buf, binary.LittleEndian, age)
+	buf.Write(make([]byte, 16)) // GUID
+	// Empty named stream map: string buffer size, size, capacity, present and
+	// deleted bit vectors.
+	buf.Write(make([]byte, 5*4))
+	return buf.Bytes()
+}
+
+func TestParseMinimalMSF(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	b := &msfBuilder{blockSize: 512}
+	content := b.build([][]byte{{}, infoStream(3), nil, nil, data}, 0)
+	f, err := ParseBytes(content)
+	if err != nil {
+		t.Fatalf("unable to parse MSF file; %+v", err)
+	}
+	if len(f.Streams) != 5 {
+		t.Fatalf("number of streams mismatch; expected 5, got %d", len(f.Streams))
+	}
+	if f.Info.Age != 3 {
+		t.Errorf("age mismatch; expected 3, got %d", f.Info.Age)
+	}
+	if f.Stream(StreamTPI) != nil {
+		t.Errorf("expected nil stream %d", StreamTPI)
+	}
+	if got := f.Stream(4); !bytes.Equal(got, data) {
+		t.Errorf("contents mismatch of stream 4 (spanning %d blocks)", blockCount(uint32(len(data)), 512))
+	}
+}
+
+func TestParseMSFMultiBlockBlockMap(t *testing.T) {
+	// The stream directory of 17000 nil streams spans 133 blocks of 512 bytes;
+	// thus the block map (4 bytes per directory block) spans 2 blocks.
+	const nstreamsPad = 17000
+	b := &msfBuilder{blockSize: 512}
+	content := b.build([][]byte{{}, infoStream(1)}, nstreamsPad)
+	f, err := ParseBytes(content)
+	if err != nil {
+		t.Fatalf("unable to parse MSF file; %+v", err)
+	}
+	if ndirBlocks := blockCount(f.SuperBlock.NDirBytes, 512); 4*ndirBlocks <= 512 {
+		t.Fatalf("block map of %d bytes fits in a single block", 4*ndirBlocks)
+	}
+	if len(f.Streams) != 2+nstreamsPad {
+		t.Errorf("number of streams mismatch; expected %d, got %d", 2+nstreamsPad, len(f.Streams))
+	}
+}
+
+func TestParseMSFTruncated(t *testing.T) {
+	b := &msfBuilder{blockSize: 512}
+	content := b.build([][]byte{{}, infoStream(1)}, 17000)
+	golden := []struct {
+		name    string
+		content func() []byte
+	}{
+		{
+			name: "truncated block map",
+			content: func() []byte {
+				// Drop the second block of the block map.
+				return content[:len(content)-512]
+			},
+		},
+		{
+			name: "truncated superblock",
+			content: func() []byte {
+				return content[:40]
+			},
+		},
+		{
+			name: "oversized stream directory",
+			content: func() []byte {
+				buf := append([]byte(nil), content...)
+				binary.LittleEndian.PutUint32(buf[44:], 0xFFFFFFF0)
+				return buf
+			},
+		},
+		{
+			name: "oversized stream",
+			content: func() []byte {
+				b := &msfBuilder{blockSize: 512}
+				buf := b.build([][]byte{{}, infoStream(1)}, 0)
+				// Size of stream 1, located after the number of streams and
+				// the size of stream 0 in the stream directory (block 4).
+				binary.LittleEndian.PutUint32(buf[4*512+8:], 0x7FFFFFFF)
+				return buf
+			},
+		},
+	}
+	for _, g := range golden {
+		if _, err := ParseBytes(g.content()); err == nil {
+			t.Errorf("%s: expected error", g.name)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package pdb
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// --- [ Symbol records ] ------------------------------------------------------
+
+// Symbol record kinds.
+//
+// ref: https://github.com/Microsoft/microsoft-pdb/blob/master/include/cvinfo.h
+const (
+	symPub32        = 0x110E // S_PUB32
+	symLProc32      = 0x110F // S_LPROC32
+	symGProc32      = 0x1110 // S_GPROC32
+	symLProc32ID    = 0x1146 // S_LPROC32_ID
+	symGProc32ID    = 0x1147 // S_GPROC32_ID
+	symLProc32DPC   = 0x1155 // S_LPROC32_DPC
+	symLProc32DPCID = 0x1156 // S_LPROC32_DPC_ID
+)
+
+// Public symbol flag indicating a function (cvpsfFunction).
+const pubSymFlagFunction = 0x2
+
+// symbolRecord is a CodeView symbol record.
+type symbolRecord struct {
+	// Symbol record kind.
+	kind uint16
+	// Symbol record data, excluding the record length and kind.
+	data []byte
+}
+
+// parseSymbolRecord parses the symbol record at the start of buf, returning the
+// record and its size in number of bytes.
+func parseSymbolRecord(buf []byte) (symbolRecord, int, error) {
+	if len(buf) < 4 {
+		return symbolRecord{}, 0, errors.Errorf("symbol record too short; expected >= 4 bytes, got %d", len(buf))
+	}
+	// The record length excludes the length field itself.
+	n := int(binary.LittleEndian.Uint16(buf)) + 2
+	if n < 4 || n > len(buf) {
+		return symbolRecord{}, 0, errors.Errorf("invalid symbol record length %d; expected >= 4 and <= %d", n, len(buf))
+	}
+	sym := symbolRecord{
+		kind: binary.LittleEndian.Uint16(buf[2:]),
+		data: buf[4:n],
+	}
+	return sym, n, nil
+}
+
+// ~~~ [ Public symbols ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// PublicSymbol is a public symbol (S_PUB32) of the publics stream.
+type PublicSymbol struct {
+	// Public symbol flags.
+	Flags uint32
+	// Section number (1-based).
+	Segment uint16
+	// Offset of symbol (relative to section start).
+	Offset uint32
+	// Symbol name; decorated.
+	Name string
+}
+
+// IsFunction reports whether the public symbol refers to a function.
+func (sym PublicSymbol) IsFunction() bool {
+	return sym.Flags&pubSymFlagFunction != 0
+}
+
+// parsePublics parses the given publics stream, resolving public symbols
+// through the given symbol record stream.
+//
+// ref: https://llvm.org/docs/PDB/PublicStream.html
+func parsePublics(buf, symRecords []byte) ([]PublicSymbol, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	// Publics stream header.
+	//
+	//    SymHash         uint32
+	//    AddrMap         uint32
+	//    NThunks         uint32
+	//    SizeOfThunk     uint32
+	//    ISectThunkTable uint16
+	//    Padding         uint16
+	//    OffThunkTable   uint32
+	//    NSections       uint32
+	d := &decoder{buf: buf}
+	symHashSize := d.u32()
+	addrMapSize := d.u32()
+	d.read(4 + 4 + 2 + 2 + 4 + 4)
+	// The GSI hash table precedes the address map.
+	d.read(int(symHashSize))
+	addrMap := d.read(int(addrMapSize))
+	if d.err != nil {
+		return nil, errors.Wrap(d.err, "unable to decode publics stream header")
+	}
+	// The address map contains offsets into the symbol record stream of each
+	// public symbol, sorted by address.
+	var publics []PublicSymbol
+	for i := 0; i+4 <= len(addrMap); i += 4 {
+		offset := binary.LittleEndian.Uint32(addrMap[i:])
+		if int(offset) >= len(symRecords) {
+			return nil, errors.Errorf("invalid symbol record offset 0x%X; expected < 0x%X", offset, len(symRecords))
+		}
+		sym, _, err := parseSymbolRecord(symRecords[offset:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse symbol record at offset 0x%X", offset)
+		}
+		if sym.kind != symPub32 {
+			return nil, errors.Errorf("invalid symbol record kind of public symbol at offset 0x%X; expected 0x%04X, got 0x%04X", offset, symPub32, sym.kind)
+		}
+		sd := &decoder{buf: sym.data}
+		pub := PublicSymbol{
+			Flags:   sd.u32(),
+			Offset:  sd.u32(),
+			Segment: sd.u16(),
+		}
+		pub.Name = parseCString(sd.read(len(sd.buf) - sd.pos))
+		if sd.err != nil {
+			return nil, errors.Wrapf(sd.err, "unable to decode public symbol at offset 0x%X", offset)
+		}
+		publics = append(publics, pub)
+	}
+	return publics, nil
+}
+
+// ~~~ [ Procedures ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// Proc is a procedure symbol (S_GPROC32, S_LPROC32, ...) of a module symbol
+// stream.
+type Proc struct {
+	// Procedure name.
+	Name string
+	// Specifies whether the procedure is global.
+	Global bool
+	// Section number (1-based).
+	Segment uint16
+	// Offset of procedure (relative to section start).
+	Offset uint32
+	// Size of procedure code in number of bytes.
+	Size uint32
+}
+
+// parseModuleSymbols parses the procedure symbols of the given symbol records of
+// a module symbol stream.
+func parseModuleSymbols(buf []byte) ([]Proc, error) {
+	var procs []Proc
+	for pos := 0; pos < len(buf); {
+		sym, n, err := parseSymbolRecord(buf[pos:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse symbol record at offset 0x%X", pos)
+		}
+		pos += n
+		switch sym.kind {
+		case symGProc32, symLProc32, symGProc32ID, symLProc32ID, symLProc32DPC, symLProc32DPCID:
+			// Procedure symbol.
+			//
+			//    Parent       uint32
+			//    End          uint32
+			//    Next         uint32
+			//    CodeSize     uint32
+			//    DbgStart     uint32
+			//    DbgEnd       uint32
+			//    FunctionType uint32
+			//    CodeOffset   uint32
+			//    Segment      uint16
+			//    Flags        uint8
+			//    Name         string
+			d := &decoder{buf: sym.data}
+			d.read(4 + 4 + 4)
+			proc := Proc{
+				Global: sym.kind == symGProc32 || sym.kind == symGProc32ID,
+				Size:   d.u32(),
+			}
+			d.read(4 + 4 + 4)
+			proc.Offset = d.u32()
+			proc.Segment = d.u16()
+			d.u8()
+			proc.Name = d.cstring()
+			if d.err != nil {
+				return nil, errors.Wrapf(d.err, "unable to decode procedure symbol at offset 0x%X", pos-n)
+			}
+			procs = append(procs, proc)
+		}
+	}
+	return procs, nil
+}
@@ -1,4 +1,5 @@
-// Package pe provides access to Portable Executable (PE) files.
+// Package pe provides access to Portable Executable (PE) files, COFF object
+// files and COFF archives.
 //
 // ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format
 package pe
@@ -6,12 +7,16 @@ package pe
 import (
 	"bytes"
 	"encoding/binary"
-	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
 
 	"github.com/mewmew/pe/enum"
 	"github.com/mewmew/pe/internal/pe"
+	"github.com/mewmew/pe/metadata"
 	"github.com/pkg/errors"
 )
 
@@ -34,6 +39,10 @@ func Parse(r io.Reader) (*File, error) {
 }
 
 // ParseBytes parses the given PE file, reading from content.
+//
+// Files not starting with an MS-DOS header are parsed as COFF object files,
+// big object files, anonymous objects or import objects, as identified by their
+// header.
 func ParseBytes(content []byte) (*File, error) {
 	return parse(content)
 }
@@ -48,6 +57,9 @@ type reader interface {
 // PE signature.
 var signature = []byte("PE\x00\x00")
 
+// MS-DOS signature of PE images.
+var dosSignature = []byte("MZ")
+
 // parse parses the given PE file, reading from content.
 func parse(content []byte) (*File, error) {
 	file := &File{
@@ -55,23 +67,84 @@ func parse(content []byte) (*File, error) {
 	}
 	r := bytes.NewReader(content)
 	// Parse COFF file header.
-	fileHdr, err := parseFileHeader(r)
-	if err != nil {
-		return nil, errors.WithStack(err)
+	//
+	// PE images start with an MS-DOS header, while COFF object files start with
+	// the COFF file header.
+	var fileHdr *FileHeader
+	switch {
+	case bytes.HasPrefix(content, dosSignature):
+		dosHdr, err := parseDOSHeader(r)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		file.DOSHdr = dosHdr
+		hdr, err := parseFileHeader(r, dosHdr.PEOffset)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		fileHdr = hdr
+		// Parse Rich header; located between the MS-DOS stub and the PE
+		// signature.
+		file.RichHdr = parseRichHeader(content)
+		// The MS-DOS stub is located between the MS-DOS header and the Rich
+		// header (or PE signature if not present).
+		stubEnd := dosHdr.PEOffset
+		if file.RichHdr != nil {
+			stubEnd = file.RichHdr.Offset
+		}
+		if stubEnd > dosHeaderSize {
+			file.DOSStub = content[dosHeaderSize:stubEnd]
+		}
+	case isAnonObject(content):
+		// Import object headers and anonymous object headers (including big
+		// object headers) start with an unknown machine type followed by 0xFFFF.
+		hdr, err := file.parseAnonObjectHeader(r)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		// Only big object files contain sections and symbols.
+		if hdr == nil {
+			return file, nil
+		}
+		fileHdr = hdr
+	default:
+		hdr, err := parseObjectFileHeader(r)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		fileHdr = hdr
 	}
 	file.FileHdr = fileHdr
-	// Parse optional header.
-	optHdr, err := parseOptHeader(r)
-	if err != nil {
-		return nil, errors.WithStack(err)
+	// Parse optional header; not present in COFF object files.
+	if fileHdr.OptHdrSize != 0 {
+		optHdr, err := parseOptHeader(r)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		file.OptHdr = optHdr
+		// Parse data directories.
+		dataDirs, err := file.parseDataDirs(r)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		file.DataDirs = dataDirs
 	}
-	file.OptHdr = optHdr
-	// Parse data directories.
-	dataDirs, err := file.parseDataDirs(r)
-	if err != nil {
-		return nil, errors.WithStack(err)
+	// Parse COFF symbol table and string table.
+	//
+	// The string table is parsed before the section headers, as it is used to
+	// resolve long section names.
+	if fileHdr.SymbolTableOffset != 0 {
+		if err := file.parseSymbolTable(); err != nil {
+			if file.DOSHdr == nil {
+				return nil, errors.WithStack(err)
+			}
+			// COFF symbol tables are deprecated for images, and often stale;
+			// record the error and continue.
+			file.Symbols = nil
+			file.StrTable = nil
+			file.SymbolTableErr = err
+		}
 	}
-	file.DataDirs = dataDirs
 	// Parse section headers.
 	//
 	// After parsing the section headers, we may read data using relative
@@ -81,6 +154,14 @@ func parse(content []byte) (*File, error) {
 		return nil, errors.WithStack(err)
 	}
 	file.SectHdrs = sectHdrs
+	// Parse COFF relocations of sections.
+	if err := file.parseRelocs(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// Parse COFF line numbers of sections.
+	if err := file.parseLineNums(); err != nil {
+		return nil, errors.WithStack(err)
+	}
 	// Parse contents of data directories.
 	if err := file.parseDataDirsContent(r); err != nil {
 		return nil, errors.WithStack(err)
@@ -88,14 +169,22 @@ func parse(content []byte) (*File, error) {
 	return file, nil
 }
 
-// parseFileHeader parses the COFF file header of the given PE file.
-func parseFileHeader(r reader) (*FileHeader, error) {
-	// Get offset of COFF file header.
-	var offset uint32
-	sr := io.NewSectionReader(r, 0x3C, 4)
-	if err := binary.Read(sr, binary.LittleEndian, &offset); err != nil {
+// Size of the MS-DOS header in bytes.
+const dosHeaderSize = 0x40
+
+// parseDOSHeader parses the MS-DOS header of the given PE file.
+func parseDOSHeader(r reader) (*DOSHeader, error) {
+	var raw pe.RawDOSHeader
+	sr := io.NewSectionReader(r, 0, dosHeaderSize)
+	if err := binary.Read(sr, binary.LittleEndian, &raw); err != nil {
 		return nil, errors.WithStack(err)
 	}
+	return goDOSHeader(&raw), nil
+}
+
+// parseFileHeader parses the COFF file header of the given PE file, located at
+// the given file offset of the PE signature.
+func parseFileHeader(r reader, offset uint32) (*FileHeader, error) {
 	// Parse PE signature.
 	if _, err := r.Seek(int64(offset), io.SeekStart); err != nil {
 		return nil, errors.WithStack(err)
@@ -107,7 +196,183 @@ func parseFileHeader(r reader) (*FileHeader, error) {
 	if !bytes.Equal(signature, sig) {
 		return nil, errors.Errorf("invalid PE signature; expected %q, got %q", signature, sig)
 	}
-	// Parse COFF file header.
+	return parseCOFFFileHeader(r)
+}
+
+// Signatures of the Rich header.
+var (
+	// Signature of the start of the Rich header (XOR-ed with the key).
+	richStartSignature = []byte("DanS")
+	// Signature of the end of the Rich header (not XOR-ed).
+	richEndSignature = []byte("Rich")
+)
+
+// parseRichHeader parses the Rich header of the given PE image, located between
+// the MS-DOS stub and the PE signature. A nil Rich header is returned if not
+// present.
+//
+// ref: http://bytepointer.com/articles/the_microsoft_rich_header.htm
+func parseRichHeader(content []byte) *RichHeader {
+	if len(content) < 0x40 {
+		return nil
+	}
+	peOffset := binary.LittleEndian.Uint32(content[0x3C:])
+	if uint64(peOffset) > uint64(len(content)) {
+		return nil
+	}
+	// Locate "Rich" signature, followed by the key; aligned to 4 bytes.
+	end := -1
+	for i := 0x40; i+8 <= int(peOffset); i += 4 {
+		if bytes.Equal(content[i:i+4], richEndSignature) {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		return nil
+	}
+	key := binary.LittleEndian.Uint32(content[end+4:])
+	// Locate "DanS" signature by decoding backwards from the "Rich" signature.
+	start := -1
+	for i := end - 4; i >= 0x40; i -= 4 {
+		v := binary.LittleEndian.Uint32(content[i:]) ^ key
+		if v == binary.LittleEndian.Uint32(richStartSignature) {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return nil
+	}
+	hdr := &RichHeader{
+		Offset: uint32(start),
+		Size:   uint32(end + 8 - start),
+		Key:    key,
+	}
+	// Skip "DanS" signature and three padding values.
+	for i := start + 16; i+8 <= end; i += 8 {
+		compID := binary.LittleEndian.Uint32(content[i:]) ^ key
+		entry := RichEntry{
+			ProductID: uint16(compID >> 16),
+			Build:     uint16(compID),
+			Count:     binary.LittleEndian.Uint32(content[i+4:]) ^ key,
+		}
+		hdr.Entries = append(hdr.Entries, entry)
+	}
+	// Compute checksum from the Rich header offset, the MS-DOS header (and
+	// stub) with the e_lfanew field excluded, and the Rich header entries.
+	checksum := uint32(start)
+	for i := 0; i < start; i++ {
+		if 0x3C <= i && i < 0x40 {
+			continue
+		}
+		checksum += bits.RotateLeft32(uint32(content[i]), i)
+	}
+	for _, entry := range hdr.Entries {
+		checksum += bits.RotateLeft32(entry.CompID(), int(entry.Count&0x1F))
+	}
+	hdr.Checksum = checksum
+	return hdr
+}
+
+// parseObjectFileHeader parses the COFF file header of the given COFF object
+// file, located at the start of the file.
+func parseObjectFileHeader(r reader) (*FileHeader, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return parseCOFFFileHeader(r)
+}
+
+// isAnonObject reports whether the given file contents start with the signature
+// of an import object header or anonymous object header.
+func isAnonObject(content []byte) bool {
+	if len(content) < 4 {
+		return false
+	}
+	sig1 := binary.LittleEndian.Uint16(content[0:])
+	sig2 := binary.LittleEndian.Uint16(content[2:])
+	return enum.MachineType(sig1) == enum.MachineTypeUnknown && sig2 == 0xFFFF
+}
+
+// parseAnonObjectHeader parses the import object header or anonymous object
+// header at the start of the given file, reading from r. The COFF file header
+// of big object files is returned; or nil if the file has no sections.
+func (file *File) parseAnonObjectHeader(r reader) (*FileHeader, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(file.Content) < 6 {
+		return nil, errors.Errorf("object header out of bounds; expected length >= 6, got %d", len(file.Content))
+	}
+	version := binary.LittleEndian.Uint16(file.Content[4:])
+	// Import object headers have version 0.
+	if version == 0 {
+		importHdr, err := file.parseImportObjectHeader(r)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		file.ImportHdr = importHdr
+		return nil, nil
+	}
+	if version == 1 {
+		raw := pe.RawAnonObjectHeader{}
+		if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		file.AnonHdr = goAnonObjectHeader(pe.RawAnonObjectHeaderV2{RawAnonObjectHeader: raw})
+		return nil, nil
+	}
+	raw := pe.RawAnonObjectHeaderV2{}
+	if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	file.AnonHdr = goAnonObjectHeader(raw)
+	if !file.AnonHdr.IsBigObj() {
+		return nil, nil
+	}
+	// Big object header; re-read the complete header.
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	bigObjHdr := pe.RawBigObjHeader{}
+	if err := binary.Read(r, binary.LittleEndian, &bigObjHdr); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return goBigObjFileHeader(bigObjHdr), nil
+}
+
+// parseImportObjectHeader parses the import object header of the given short
+// import library member, reading from r.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#import-library-format
+func (file *File) parseImportObjectHeader(r reader) (*ImportObjectHeader, error) {
+	raw := pe.RawImportObjectHeader{}
+	if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	start := uint64(binary.Size(raw))
+	end := start + uint64(raw.DataSize)
+	if end > uint64(len(file.Content)) {
+		return nil, errors.Errorf("import object data out of bounds; expected end <= %d, got %d", len(file.Content), end)
+	}
+	// The import name and DLL name are stored as consecutive NULL-terminated
+	// strings, optionally followed by the export name.
+	strs := strings.Split(string(file.Content[start:end]), "\x00")
+	if len(strs) < 3 {
+		return nil, errors.Errorf("invalid import object data; expected at least 2 NULL-terminated strings, got %d", len(strs)-1)
+	}
+	hdr := goImportObjectHeader(raw)
+	hdr.SymbolName = strs[0]
+	hdr.DLLName = strs[1]
+	if hdr.NameType == enum.ImportNameTypeNameExportAs && len(strs) > 2 {
+		hdr.ExportName = strs[2]
+	}
+	return hdr, nil
+}
+
+// parseCOFFFileHeader parses the COFF file header, reading from r.
+func parseCOFFFileHeader(r reader) (*FileHeader, error) {
 	raw := &pe.RawFileHeader{}
 	if err := binary.Read(r, binary.LittleEndian, raw); err != nil {
 		return nil, errors.WithStack(err)
@@ -168,11 +433,349 @@ func (file *File) parseSectionHdrs(r reader) ([]SectionHeader, error) {
 		if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
 			return nil, errors.WithStack(err)
 		}
-		sectHdrs = append(sectHdrs, goSectionHeader(raw))
+		name := parseSectionName(raw.Name, file.StrTable)
+		sectHdrs = append(sectHdrs, goSectionHeader(raw, name))
 	}
 	return sectHdrs, nil
 }
 
+// parseSectionName parses the given raw section name, resolving long section
+// names ("/n" and "//base64") against the string table. The raw section name is
+// returned if the long section name cannot be resolved.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#section-table-section-headers
+func parseSectionName(raw [8]byte, strTable StringTable) string {
+	name := parseCString(raw[:])
+	// Long section names are only present in files with a string table;
+	// stripped images may retain the literal "/n" form.
+	if len(name) < 2 || name[0] != '/' || strTable == nil {
+		return name
+	}
+	var offset uint64
+	if name[1] == '/' {
+		// Base64 encoded string table offset ("//" followed by 6 digits, most
+		// significant first), used by large object files.
+		const base64 = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+		for i := 2; i < len(name); i++ {
+			digit := strings.IndexByte(base64, name[i])
+			if digit == -1 {
+				// Not a long section name.
+				return name
+			}
+			offset = offset*64 + uint64(digit)
+		}
+	} else {
+		// Decimal string table offset.
+		v, err := strconv.ParseUint(name[1:], 10, 32)
+		if err != nil {
+			// Not a long section name.
+			return name
+		}
+		offset = v
+	}
+	if offset > math.MaxUint32 {
+		return name
+	}
+	s, err := strTable.Get(uint32(offset))
+	if err != nil {
+		// String table offset out of bounds.
+		return name
+	}
+	return s
+}
+
+// --- [ COFF relocations ] ----------------------------------------------------
+
+// relocSize is the size in bytes of COFF relocation entries.
+const relocSize = 10
+
+// parseRelocs parses the COFF relocations of each section.
+func (file *File) parseRelocs() error {
+	for i := range file.SectHdrs {
+		sectHdr := &file.SectHdrs[i]
+		if sectHdr.RelocsOffset == 0 || sectHdr.NRelocs == 0 {
+			continue
+		}
+		relocs, err := file.parseSectRelocs(*sectHdr)
+		if err != nil {
+			return errors.Wrapf(err, "unable to parse relocations of section %q", sectHdr.Name)
+		}
+		sectHdr.Relocs = relocs
+	}
+	return nil
+}
+
+// parseSectRelocs parses the COFF relocations of the given section.
+func (file *File) parseSectRelocs(sectHdr SectionHeader) ([]Reloc, error) {
+	n := uint64(sectHdr.NRelocs)
+	start := uint64(sectHdr.RelocsOffset)
+	// read returns the raw relocation entry at the given index.
+	read := func(i uint64) (pe.RawReloc, error) {
+		var raw pe.RawReloc
+		offset := start + i*relocSize
+		if offset+relocSize > uint64(len(file.Content)) {
+			return raw, errors.Errorf("relocation entry out of bounds; expected end <= %d, got %d", len(file.Content), offset+relocSize)
+		}
+		if err := binary.Read(bytes.NewReader(file.Content[offset:offset+relocSize]), binary.LittleEndian, &raw); err != nil {
+			return raw, errors.WithStack(err)
+		}
+		return raw, nil
+	}
+	first := uint64(0)
+	// Sections with more than 0xFFFF relocations store the actual number of
+	// relocations in the offset of the first relocation entry.
+	if sectHdr.Flags&enum.SectionFlagLinkNRelocOverflow != 0 && n == 0xFFFF {
+		raw, err := read(0)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		n = uint64(raw.Offset)
+		first = 1
+	}
+	if start+n*relocSize > uint64(len(file.Content)) {
+		return nil, errors.Errorf("relocation entries out of bounds; expected end <= %d, got %d", len(file.Content), start+n*relocSize)
+	}
+	var relocs []Reloc
+	for i := first; i < n; i++ {
+		raw, err := read(i)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		relocs = append(relocs, goReloc(raw, file.FileHdr.Machine))
+	}
+	return relocs, nil
+}
+
+// --- [ COFF line numbers ] ---------------------------------------------------
+
+// lineNumSize is the size in bytes of COFF line number entries.
+const lineNumSize = 6
+
+// parseLineNums parses the COFF line numbers of each section.
+func (file *File) parseLineNums() error {
+	for i := range file.SectHdrs {
+		sectHdr := &file.SectHdrs[i]
+		if sectHdr.LineNumsOffset == 0 || sectHdr.NLineNums == 0 {
+			continue
+		}
+		start := uint64(sectHdr.LineNumsOffset)
+		end := start + uint64(sectHdr.NLineNums)*lineNumSize
+		if end > uint64(len(file.Content)) {
+			return errors.Errorf("line number entries of section %q out of bounds; expected end <= %d, got %d", sectHdr.Name, len(file.Content), end)
+		}
+		raws := make([]pe.RawLineNum, sectHdr.NLineNums)
+		if err := binary.Read(bytes.NewReader(file.Content[start:end]), binary.LittleEndian, raws); err != nil {
+			return errors.WithStack(err)
+		}
+		sectHdr.LineNums = make([]LineNum, len(raws))
+		for j, raw := range raws {
+			sectHdr.LineNums[j] = goLineNum(raw)
+		}
+	}
+	return nil
+}
+
+// --- [ COFF symbol table ] ---------------------------------------------------
+
+// symbolSize is the size in bytes of COFF symbol table entries.
+const symbolSize = 18
+
+// bigObjSymbolSize is the size in bytes of COFF symbol table entries of big
+// object files.
+const bigObjSymbolSize = 20
+
+// symbolEntrySize returns the size in bytes of COFF symbol table entries, based
+// on whether the file is a big object file.
+func symbolEntrySize(bigObj bool) uint32 {
+	if bigObj {
+		return bigObjSymbolSize
+	}
+	return symbolSize
+}
+
+// parseSymbolTable parses the COFF symbol table and string table of the given
+// PE file.
+func (file *File) parseSymbolTable() error {
+	start := uint64(file.FileHdr.SymbolTableOffset)
+	bigObj := file.AnonHdr != nil && file.AnonHdr.IsBigObj()
+	end := start + uint64(file.FileHdr.NSymbols)*uint64(symbolEntrySize(bigObj))
+	if end > uint64(len(file.Content)) {
+		return errors.Errorf("COFF symbol table out of bounds; expected end <= %d, got %d", len(file.Content), end)
+	}
+	// The string table immediately follows the symbol table.
+	strTable, err := parseStringTable(file.Content[end:])
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	file.StrTable = strTable
+	syms, err := parseSymbols(file.Content[start:end], strTable, bigObj)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	file.Symbols = syms
+	return nil
+}
+
+// parseStringTable parses the COFF string table at the start of buf.
+func parseStringTable(buf []byte) (StringTable, error) {
+	// The string table may be omitted if empty.
+	if len(buf) < 4 {
+		return nil, nil
+	}
+	size := binary.LittleEndian.Uint32(buf)
+	if size < 4 {
+		return nil, nil
+	}
+	if uint64(size) > uint64(len(buf)) {
+		return nil, errors.Errorf("COFF string table out of bounds; expected size <= %d, got %d", len(buf), size)
+	}
+	return StringTable(buf[:size]), nil
+}
+
+// parseSymbols parses the COFF symbol table entries of buf, using the given
+// string table to resolve long symbol names. Big object files use the wider
+// symbol table entry format.
+func parseSymbols(buf []byte, strTable StringTable, bigObj bool) ([]Symbol, error) {
+	var syms []Symbol
+	size := symbolEntrySize(bigObj)
+	n := uint32(len(buf)) / size
+	for index := uint32(0); index < n; {
+		var raw pe.RawSymbolEx
+		b := buf[index*size : (index+1)*size]
+		if bigObj {
+			if err := binary.Read(bytes.NewReader(b), binary.LittleEndian, &raw); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		} else {
+			var small pe.RawSymbol
+			if err := binary.Read(bytes.NewReader(b), binary.LittleEndian, &small); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			raw = rawSymbolEx(small)
+		}
+		name, err := parseSymbolName(raw.Name, strTable)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse name of symbol %d", index)
+		}
+		sym := goSymbol(raw, index, name)
+		if uint64(index)+1+uint64(raw.NAux) > uint64(n) {
+			return nil, errors.Errorf("auxiliary symbol records of symbol %d out of bounds; expected end <= %d, got %d", index, n, uint64(index)+1+uint64(raw.NAux))
+		}
+		auxStart := (index + 1) * size
+		auxEnd := auxStart + uint32(raw.NAux)*size
+		aux, err := parseSymbolAux(sym, buf[auxStart:auxEnd], bigObj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse auxiliary symbol records of symbol %d", index)
+		}
+		sym.Aux = aux
+		syms = append(syms, sym)
+		index += 1 + uint32(raw.NAux)
+	}
+	return syms, nil
+}
+
+// parseSymbolName parses the given short symbol name, or string table offset.
+func parseSymbolName(name [8]byte, strTable StringTable) (string, error) {
+	// Long names are stored as four zero bytes followed by a string table
+	// offset.
+	if binary.LittleEndian.Uint32(name[:4]) == 0 {
+		offset := binary.LittleEndian.Uint32(name[4:])
+		s, err := strTable.Get(offset)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		return s, nil
+	}
+	return parseCString(name[:]), nil
+}
+
+// parseSymbolAux parses the auxiliary symbol records of the given symbol,
+// reading from buf. The format of the auxiliary symbol records is determined by
+// the storage class of the symbol.
+func parseSymbolAux(sym Symbol, buf []byte, bigObj bool) ([]SymbolAux, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	// File names span all auxiliary symbol records.
+	if sym.StorageClass == enum.StorageClassFile {
+		aux := &AuxFile{
+			Name: parseCString(buf),
+		}
+		return []SymbolAux{aux}, nil
+	}
+	var auxs []SymbolAux
+	size := int(symbolEntrySize(bigObj))
+	for ; len(buf) >= size; buf = buf[size:] {
+		r := bytes.NewReader(buf[:size])
+		switch {
+		case sym.StorageClass == enum.StorageClassExternal && sym.ComplexType == enum.SymbolComplexTypeFunction && sym.SectNum > 0:
+			var raw pe.RawAuxFuncDef
+			if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			aux := &AuxFuncDef{
+				TagIndex:       raw.TagIndex,
+				TotalSize:      raw.TotalSize,
+				LineNumsOffset: raw.LineNumsOffset,
+				NextFunc:       raw.NextFunc,
+			}
+			auxs = append(auxs, aux)
+		case sym.StorageClass == enum.StorageClassFunction:
+			var raw pe.RawAuxBfEf
+			if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			aux := &AuxBfEf{
+				LineNum:  raw.LineNum,
+				NextFunc: raw.NextFunc,
+			}
+			auxs = append(auxs, aux)
+		case sym.StorageClass == enum.StorageClassWeakExternal, sym.StorageClass == enum.StorageClassExternal && sym.SectNum == SectNumUndefined && sym.Value == 0:
+			var raw pe.RawAuxWeakExternal
+			if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			aux := &AuxWeakExternal{
+				TagIndex:        raw.TagIndex,
+				Characteristics: raw.Characteristics,
+			}
+			auxs = append(auxs, aux)
+		case sym.StorageClass == enum.StorageClassStatic && sym.Value == 0 && sym.SectNum > 0:
+			var raw pe.RawAuxSectionDef
+			if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			aux := &AuxSectionDef{
+				Length:    raw.Length,
+				NRelocs:   raw.NRelocs,
+				NLineNums: raw.NLineNums,
+				Checksum:  raw.Checksum,
+				Number:    uint32(raw.Number),
+				Selection: raw.Selection,
+			}
+			if bigObj {
+				aux.Number |= uint32(raw.NumberHigh) << 16
+			}
+			auxs = append(auxs, aux)
+		case sym.StorageClass == enum.StorageClassCLRToken:
+			var raw pe.RawAuxCLRToken
+			if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			aux := &AuxCLRToken{
+				SymbolIndex: raw.SymbolIndex,
+			}
+			auxs = append(auxs, aux)
+		default:
+			aux := &AuxRaw{
+				Data: buf[:size],
+			}
+			auxs = append(auxs, aux)
+		}
+	}
+	return auxs, nil
+}
+
 // parseDataDirsContent parses the contents of the data directories.
 func (file *File) parseDataDirsContent(r reader) error {
 	for idx, dataDir := range file.DataDirs {
@@ -183,7 +786,12 @@ func (file *File) parseDataDirsContent(r reader) error {
 		switch idx {
 		case 0:
 			// Export Table
-			panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
+			expDir, exps, err := file.parseExports(dataDir)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			file.ExpDir = expDir
+			file.Exps = exps
 		case 1:
 			// Import Table
 			imps, err := file.parseImports(dataDir)
@@ -197,10 +805,14 @@ func (file *File) parseDataDirsContent(r reader) error {
 			//panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
 		case 3:
 			// Exception Table
-			panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
+			// TODO: parse exception table.
 		case 4:
 			// Certificate Table
-			panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
+			certs, err := file.parseCerts(dataDir)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			file.Certs = certs
 		case 5:
 			// Base Relocation Table
 			baseRelocBlocks, err := file.parseBaseRelocBlocks(dataDir)
@@ -217,59 +829,187 @@ func (file *File) parseDataDirsContent(r reader) error {
 			file.DbgData = dbgData
 		case 7:
 			// Architecture
-			panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
+			// reserved, must be zero.
 		case 8:
 			// Global Pointer Register
-			panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
+			// TODO: parse global pointer register value.
 		case 9:
 			// TLS Table
-			panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
+			// TODO: parse TLS table.
 		case 10:
 			// Load Config Table
-			panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
+			// TODO: parse load configuration table.
 		case 11:
 			// Bound Import Table
-			panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
+			// TODO: parse bound import table.
 		case 12:
 			// Import Address Table
 			// already handled when parsing import table.
 		case 13:
 			// Delay Import Descriptor
-			panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
+			// TODO: parse delay import descriptors.
 		case 14:
 			// CLR Header
-			panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
+			clrHdr, err := file.parseCLRHeader(dataDir)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			file.CLRHdr = clrHdr
+			if clrHdr.Metadata.RelAddr != 0 {
+				md, err := file.parseMetadata()
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				file.Metadata = md
+			}
+			if clrHdr.ManagedNativeHeader.RelAddr != 0 {
+				r2r, err := file.parseReadyToRun(clrHdr.ManagedNativeHeader)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				file.R2R = r2r
+			}
 		case 15:
 			// Reserved
-			panic(fmt.Errorf("support for data directory index %d not yet implemented", idx))
+			// reserved, must be zero.
 		default:
-			panic(fmt.Errorf("invalid data directory index; expected < 16, got %d", idx))
+			// ignore data directories past the 16 defined by the PE format.
 		}
 	}
 	return nil
 }
 
-// --- [ 1 - Import Table ] ----------------------------------------------------
+// --- [ 0 - Export Table ] ----------------------------------------------------
 
-// parseImports parses the import table of the given data directory.
-func (file *File) parseImports(dataDir DataDirectory) ([]ImportEntry, error) {
-	impDirs, err := file.parseImportDirs(dataDir)
+// parseExports parses the export directory and exports of the given data
+// directory.
+func (file *File) parseExports(dataDir DataDirectory) (*ExportDirectory, []Export, error) {
+	buf, err := file.readFrom(dataDir.RelAddr)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, nil, errors.WithStack(err)
 	}
-	var imps []ImportEntry
-	for _, impDir := range impDirs {
-		imp, err := file.parseImportEntry(impDir)
-		if err != nil {
-			return nil, errors.WithStack(err)
+	var raw pe.RawExportDirectory
+	if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, &raw); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	expDir := goExportDirectory(raw)
+	name, err := file.readCString(raw.NameRelAddr)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to parse DLL name of export directory")
+	}
+	expDir.Name = name
+	// Parse export address table.
+	eat, err := file.readUint32s(raw.EATRelAddr, raw.NFuncs)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to parse export address table")
+	}
+	exps := make([]Export, 0, len(eat))
+	// index maps from EAT index to index of export; or -1 if unused.
+	index := make([]int, len(eat))
+	for i, relAddr := range eat {
+		index[i] = -1
+		// Unused EAT entries are zero.
+		if relAddr == 0 {
+			continue
 		}
-		imps = append(imps, imp)
+		exp := Export{
+			Ordinal: raw.OrdinalBase + uint32(i),
+			RelAddr: relAddr,
+		}
+		// Export addresses within the export data directory refer to forwarder
+		// strings.
+		if dataDir.RelAddr <= relAddr && uint64(relAddr) < uint64(dataDir.RelAddr)+uint64(dataDir.Size) {
+			forwarder, err := file.readCString(relAddr)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "unable to parse forwarder of export ordinal %d", exp.Ordinal)
+			}
+			exp.Forwarder = forwarder
+		}
+		index[i] = len(exps)
+		exps = append(exps, exp)
 	}
-	return imps, nil
-}
-
-// parseImportDirs parses the import data directories.
-func (file *File) parseImportDirs(dataDir DataDirectory) ([]ImportDirectory, error) {
+	// Parse export name pointer table and export ordinal table.
+	namePtrs, err := file.readUint32s(raw.NamePtrsRelAddr, raw.NNames)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unable to parse export name pointer table")
+	}
+	if raw.NNames > 0 {
+		ordsBuf, err := file.readFrom(raw.OrdinalsRelAddr)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "unable to parse export ordinal table")
+		}
+		if uint64(raw.NNames)*2 > uint64(len(ordsBuf)) {
+			return nil, nil, errors.Errorf("export ordinal table out of bounds; expected end <= %d, got %d", len(ordsBuf), uint64(raw.NNames)*2)
+		}
+		for i, namePtr := range namePtrs {
+			// Export ordinal table entries are unbiased indices into the EAT.
+			eatIndex := binary.LittleEndian.Uint16(ordsBuf[i*2:])
+			if int(eatIndex) >= len(eat) || index[eatIndex] == -1 {
+				return nil, nil, errors.Errorf("invalid export address table index of export name %d; expected < %d, got %d", i, len(eat), eatIndex)
+			}
+			name, err := file.readCString(namePtr)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "unable to parse export name %d", i)
+			}
+			exp := &exps[index[eatIndex]]
+			exp.Name = name
+			exp.Hint = uint16(i)
+		}
+	}
+	return expDir, exps, nil
+}
+
+// readCString reads the NULL-terminated string at the given relative address
+// (relative to image base).
+func (file *File) readCString(relAddr uint32) (string, error) {
+	buf, err := file.readFrom(relAddr)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return parseCString(buf), nil
+}
+
+// readUint32s reads n little-endian 32-bit integers at the given relative
+// address (relative to image base).
+func (file *File) readUint32s(relAddr, n uint32) ([]uint32, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	buf, err := file.readFrom(relAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if uint64(n)*4 > uint64(len(buf)) {
+		return nil, errors.Errorf("table out of bounds; expected end <= %d, got %d", len(buf), uint64(n)*4)
+	}
+	vs := make([]uint32, n)
+	for i := range vs {
+		vs[i] = binary.LittleEndian.Uint32(buf[i*4:])
+	}
+	return vs, nil
+}
+
+// --- [ 1 - Import Table ] ----------------------------------------------------
+
+// parseImports parses the import table of the given data directory.
+func (file *File) parseImports(dataDir DataDirectory) ([]ImportEntry, error) {
+	impDirs, err := file.parseImportDirs(dataDir)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var imps []ImportEntry
+	for _, impDir := range impDirs {
+		imp, err := file.parseImportEntry(impDir)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		imps = append(imps, imp)
+	}
+	return imps, nil
+}
+
+// parseImportDirs parses the import data directories.
+func (file *File) parseImportDirs(dataDir DataDirectory) ([]ImportDirectory, error) {
 	addr := file.OptHdr.ImageBase + uint64(dataDir.RelAddr)
 	buf := file.ReadData(addr, int64(dataDir.Size))
 	r := bytes.NewReader(buf)
@@ -363,6 +1103,39 @@ loop:
 	return ints, nil
 }
 
+// --- [ 4 - Certificate Table ] -----------------------------------------------
+
+// parseCerts parses the attribute certificate table of the given data
+// directory. Note, the relative address of the certificate table data directory
+// is a file offset.
+func (file *File) parseCerts(dataDir DataDirectory) ([]Certificate, error) {
+	start := uint64(dataDir.RelAddr)
+	end := start + uint64(dataDir.Size)
+	if end > uint64(len(file.Content)) {
+		return nil, errors.Errorf("certificate table out of bounds; expected end <= %d, got %d", len(file.Content), end)
+	}
+	buf := file.Content[start:end]
+	var certs []Certificate
+	for len(buf) > 0 {
+		var raw pe.RawCertificateHeader
+		if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, &raw); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		const hdrSize = 8
+		if raw.Length < hdrSize || uint64(raw.Length) > uint64(len(buf)) {
+			return nil, errors.Errorf("invalid attribute certificate length; expected >= %d and <= %d, got %d", hdrSize, len(buf), raw.Length)
+		}
+		certs = append(certs, goCertificate(raw, buf[hdrSize:raw.Length]))
+		// Attribute certificates are aligned on 8-byte boundaries.
+		n := (uint64(raw.Length) + 7) &^ 7
+		if n > uint64(len(buf)) {
+			break
+		}
+		buf = buf[n:]
+	}
+	return certs, nil
+}
+
 // --- [ 5 - Base Relocation Table ] -------------------------------------------
 
 // parseBaseRelocBlocks parses the base relocation table of the given data
@@ -444,8 +1217,58 @@ func (file *File) parseDebugData(dataDir DataDirectory) ([]DebugData, error) {
 				Content: buf,
 			}
 			dbgData = append(dbgData, dbgMisc)
+		case enum.DebugTypeOMapToSrc, enum.DebugTypeOMapFromSrc:
+			dbgOMap, err := parseDebugOMap(dbgDir, buf)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			dbgData = append(dbgData, dbgOMap)
+		case enum.DebugTypeVCFeature:
+			dbgVCFeature, err := parseDebugVCFeature(dbgDir, buf)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			dbgData = append(dbgData, dbgVCFeature)
+		case enum.DebugTypePOGO:
+			dbgPOGO, err := parseDebugPOGO(dbgDir, buf)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			dbgData = append(dbgData, dbgPOGO)
+		case enum.DebugTypeRepro:
+			dbgRepro, err := parseDebugRepro(dbgDir, buf)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			dbgData = append(dbgData, dbgRepro)
+		case enum.DebugTypeEmbeddedPortablePDB:
+			dbgEmbeddedPDB, err := parseDebugEmbeddedPortablePDB(dbgDir, buf)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			dbgData = append(dbgData, dbgEmbeddedPDB)
+		case enum.DebugTypePDBChecksum:
+			dbgPDBChecksum, err := parseDebugPDBChecksum(dbgDir, buf)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			dbgData = append(dbgData, dbgPDBChecksum)
+		case enum.DebugTypeExDLLCharacteristics:
+			if len(buf) < 4 {
+				return nil, errors.Errorf("extended DLL characteristics debug data too short; expected length >= 4, got %d", len(buf))
+			}
+			dbgExDLLCharacteristics := &DebugExDLLCharacteristics{
+				DbgDir:          dbgDir,
+				Characteristics: enum.ExDLLCharacteristic(binary.LittleEndian.Uint32(buf)),
+			}
+			dbgData = append(dbgData, dbgExDLLCharacteristics)
 		default:
-			panic(fmt.Errorf("support for debug data type %q not yet implemented", dbgDir.Type))
+			// Store raw content of debug data of unknown format.
+			dbgRaw := &DebugRaw{
+				DbgDir:  dbgDir,
+				Content: buf,
+			}
+			dbgData = append(dbgData, dbgRaw)
 		}
 	}
 	return dbgData, nil
@@ -486,23 +1309,67 @@ func (file *File) readDebugData(dbgDir DebugDirectory) []byte {
 // parseDebugCodeViewInfo parses the CodeView debug data of the given debug data
 // directory contents.
 func parseDebugCodeViewInfo(dbgDir DebugDirectory, buf []byte) (*DebugCodeView, error) {
-	var raw pe.RawCodeViewInfo
-	r := bytes.NewReader(buf)
-	if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
-		return nil, errors.WithStack(err)
-	}
-	b, err := ioutil.ReadAll(r)
+	info, err := parseCodeViewInfo(buf)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	pdbPath := parseCString(b)
 	dbgCodeView := &DebugCodeView{
-		DbgDir:       dbgDir,
-		CodeViewInfo: goCodeViewInfo(raw, pdbPath),
+		DbgDir: dbgDir,
+		Info:   info,
 	}
 	return dbgCodeView, nil
 }
 
+// parseCodeViewInfo parses the given CodeView debug information, based on its
+// CodeView signature.
+func parseCodeViewInfo(buf []byte) (CodeViewInfo, error) {
+	if len(buf) < 4 {
+		return nil, errors.Errorf("invalid CodeView debug information size; expected >= 4, got %d", len(buf))
+	}
+	r := bytes.NewReader(buf)
+	switch sig := string(buf[:4]); sig {
+	case "NB09", "NB11":
+		var raw pe.RawCodeViewNB09
+		if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if sig == "NB09" {
+			return &CodeViewNB09{DirOffset: raw.DirOffset}, nil
+		}
+		return &CodeViewNB11{DirOffset: raw.DirOffset}, nil
+	case "NB10":
+		var raw pe.RawCodeViewNB10
+		if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		pdbPath := parseCString(buf[binary.Size(raw):])
+		return goCodeViewNB10(raw, pdbPath), nil
+	case "RSDS":
+		var raw pe.RawCodeViewRSDS
+		if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		pdbPath := parseCString(buf[binary.Size(raw):])
+		return goCodeViewRSDS(raw, pdbPath), nil
+	case "MTOC":
+		var raw pe.RawCodeViewMTOC
+		if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		info := &CodeViewMTOC{
+			UUID:    raw.UUID,
+			PDBPath: parseCString(buf[binary.Size(raw):]),
+		}
+		return info, nil
+	default:
+		info := &CodeViewRaw{
+			Data: buf,
+		}
+		copy(info.Signature[:], buf)
+		return info, nil
+	}
+}
+
 // ~~~ [ FPO ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // parseDebugFPO parses the FPO debug data of the given debug data directory
@@ -526,3 +1393,511 @@ func parseDebugFPO(dbgDir DebugDirectory, buf []byte) (*DebugFPO, error) {
 	}
 	return dbgFPO, nil
 }
+
+// ~~~ [ OMAP ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// parseDebugOMap parses the OMAP debug data of the given debug data directory
+// contents.
+func parseDebugOMap(dbgDir DebugDirectory, buf []byte) (*DebugOMap, error) {
+	raws := make([]pe.RawOMapEntry, len(buf)/binary.Size(pe.RawOMapEntry{}))
+	if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, raws); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	dbgOMap := &DebugOMap{
+		DbgDir:  dbgDir,
+		Entries: make([]OMapEntry, len(raws)),
+	}
+	for i, raw := range raws {
+		dbgOMap.Entries[i] = OMapEntry{
+			RelAddr:   raw.RelAddr,
+			RelAddrTo: raw.RelAddrTo,
+		}
+	}
+	return dbgOMap, nil
+}
+
+// ~~~ [ VC_FEATURE ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// parseDebugVCFeature parses the Visual C++ feature debug data of the given
+// debug data directory contents.
+func parseDebugVCFeature(dbgDir DebugDirectory, buf []byte) (*DebugVCFeature, error) {
+	var raw pe.RawVCFeature
+	if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, &raw); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	dbgVCFeature := &DebugVCFeature{
+		DbgDir:  dbgDir,
+		PreVC11: raw.PreVC11,
+		CCpp:    raw.CCpp,
+		GS:      raw.GS,
+		SDL:     raw.SDL,
+		GuardN:  raw.GuardN,
+	}
+	return dbgVCFeature, nil
+}
+
+// ~~~ [ POGO ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// parseDebugPOGO parses the POGO debug data of the given debug data directory
+// contents.
+func parseDebugPOGO(dbgDir DebugDirectory, buf []byte) (*DebugPOGO, error) {
+	if len(buf) < 4 {
+		return nil, errors.Errorf("POGO debug data too short; expected length >= 4, got %d", len(buf))
+	}
+	dbgPOGO := &DebugPOGO{
+		DbgDir:    dbgDir,
+		Signature: enum.POGOType(binary.LittleEndian.Uint32(buf)),
+	}
+	const entryHdrSize = 8
+	for buf = buf[4:]; len(buf) >= entryHdrSize; {
+		var raw pe.RawPOGOEntry
+		if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, &raw); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		name := parseCString(buf[entryHdrSize:])
+		dbgPOGO.Entries = append(dbgPOGO.Entries, POGOEntry{
+			RelAddr: raw.RelAddr,
+			Size:    raw.Size,
+			Name:    name,
+		})
+		// Names are NULL-terminated and padded to 4-byte alignment.
+		n := entryHdrSize + (len(name)+1+3)&^3
+		if n > len(buf) {
+			break
+		}
+		buf = buf[n:]
+	}
+	return dbgPOGO, nil
+}
+
+// ~~~ [ REPRO ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// parseDebugRepro parses the reproducibility debug data of the given debug data
+// directory contents.
+func parseDebugRepro(dbgDir DebugDirectory, buf []byte) (*DebugRepro, error) {
+	dbgRepro := &DebugRepro{
+		DbgDir: dbgDir,
+	}
+	// The debug data is empty if no hash is present; otherwise, it contains
+	// the hash size followed by the hash.
+	if len(buf) == 0 {
+		return dbgRepro, nil
+	}
+	if len(buf) < 4 {
+		return nil, errors.Errorf("reproducibility debug data too short; expected length >= 4, got %d", len(buf))
+	}
+	size := uint64(binary.LittleEndian.Uint32(buf))
+	if 4+size > uint64(len(buf)) {
+		return nil, errors.Errorf("reproducibility hash out of bounds; expected end <= %d, got %d", len(buf), 4+size)
+	}
+	dbgRepro.Hash = buf[4 : 4+size]
+	return dbgRepro, nil
+}
+
+// ~~~ [ EMBEDDED_PORTABLE_PDB ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// parseDebugEmbeddedPortablePDB parses the embedded portable PDB debug data of
+// the given debug data directory contents.
+func parseDebugEmbeddedPortablePDB(dbgDir DebugDirectory, buf []byte) (*DebugEmbeddedPortablePDB, error) {
+	var raw pe.RawEmbeddedPortablePDB
+	r := bytes.NewReader(buf)
+	if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if string(raw.Signature[:]) != "MPDB" {
+		return nil, errors.Errorf("invalid embedded portable PDB signature; expected %q, got %q", "MPDB", raw.Signature[:])
+	}
+	dbgEmbeddedPDB := &DebugEmbeddedPortablePDB{
+		DbgDir: dbgDir,
+		Size:   raw.Size,
+		Data:   buf[binary.Size(raw):],
+	}
+	return dbgEmbeddedPDB, nil
+}
+
+// ~~~ [ PDBCHECKSUM ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// parseDebugPDBChecksum parses the PDB checksum debug data of the given debug
+// data directory contents.
+func parseDebugPDBChecksum(dbgDir DebugDirectory, buf []byte) (*DebugPDBChecksum, error) {
+	// The debug data contains the NULL-terminated UTF-8 encoded name of the hash
+	// algorithm, followed by the checksum.
+	pos := bytes.IndexByte(buf, 0)
+	if pos == -1 {
+		return nil, errors.New("unable to locate NULL-terminator of PDB checksum algorithm name")
+	}
+	dbgPDBChecksum := &DebugPDBChecksum{
+		DbgDir:    dbgDir,
+		Algorithm: string(buf[:pos]),
+		Checksum:  buf[pos+1:],
+	}
+	return dbgPDBChecksum, nil
+}
+
+// --- [ 14 - CLR Header ] -----------------------------------------------------
+
+// parseCLRHeader parses the CLR header of the given data directory.
+func (file *File) parseCLRHeader(dataDir DataDirectory) (*CLRHeader, error) {
+	addr := file.OptHdr.ImageBase + uint64(dataDir.RelAddr)
+	buf := file.ReadData(addr, int64(dataDir.Size))
+	var raw pe.RawCLRHeader
+	if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, &raw); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return goCLRHeader(raw), nil
+}
+
+// ~~~ [ ReadyToRun ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// r2rSignature is the signature of ReadyToRun headers ("RTR\x00").
+const r2rSignature = 0x00525452
+
+// parseReadyToRun parses the ReadyToRun header of the given managed native
+// header data directory. A nil header is returned if the managed native header
+// is not a ReadyToRun header (e.g. an NGen header).
+func (file *File) parseReadyToRun(dataDir DataDirectory) (*ReadyToRun, error) {
+	buf, err := file.readFrom(dataDir.RelAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	r := bytes.NewReader(buf)
+	var raw pe.RawR2RHeader
+	if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if raw.Signature != r2rSignature {
+		return nil, nil
+	}
+	coreHdr, err := parseR2RCoreHeader(r, raw.RawR2RCoreHeader)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	machine, os := r2rMachine(file.FileHdr.Machine)
+	r2r := &ReadyToRun{
+		MajorVer:      raw.MajorVer,
+		MinorVer:      raw.MinorVer,
+		Machine:       machine,
+		OS:            os,
+		R2RCoreHeader: coreHdr,
+	}
+	for _, sect := range coreHdr.Sects {
+		switch sect.Type {
+		case enum.R2RSectionTypeCompilerIdentifier:
+			buf, err := file.readR2RSection(sect)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			r2r.CompilerIdentifier = parseCString(buf)
+		case enum.R2RSectionTypeOwnerCompositeExecutable:
+			buf, err := file.readR2RSection(sect)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			r2r.OwnerCompositeExecutable = parseCString(buf)
+		case enum.R2RSectionTypeRuntimeFunctions:
+			funcs, err := file.parseR2RRuntimeFuncs(sect, machine)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			r2r.RuntimeFuncs = funcs
+		case enum.R2RSectionTypeImportSections:
+			impSects, err := file.parseR2RImportSections(sect)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			r2r.ImportSects = impSects
+		case enum.R2RSectionTypeComponentAssemblies:
+			comps, err := file.parseR2RComponentAssemblies(sect)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			r2r.ComponentAssemblies = comps
+		}
+	}
+	// Parse contents of core headers, now that the runtime functions are known.
+	if err := file.parseR2RCoreHeaderContent(coreHdr, r2r.RuntimeFuncs); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, comp := range r2r.ComponentAssemblies {
+		if err := file.parseR2RCoreHeaderContent(comp.CoreHdr, r2r.RuntimeFuncs); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return r2r, nil
+}
+
+// r2rMachine returns the target CPU type and operating system of a ReadyToRun
+// image, based on the machine type of the COFF file header.
+func r2rMachine(machine enum.MachineType) (enum.MachineType, enum.R2ROS) {
+	oses := []enum.R2ROS{enum.R2ROSWindows, enum.R2ROSLinux, enum.R2ROSApple, enum.R2ROSFreeBSD, enum.R2ROSNetBSD, enum.R2ROSSunOS}
+	for _, os := range oses {
+		m := enum.MachineType(uint16(machine) ^ uint16(os))
+		switch m {
+		case enum.MachineTypeI386, enum.MachineTypeAMD64, enum.MachineTypeARMNT, enum.MachineTypeARM64, enum.MachineTypeRISCV64:
+			return m, os
+		}
+	}
+	return machine, enum.R2ROSWindows
+}
+
+// parseR2RCoreHeader parses the sections of the given ReadyToRun core header,
+// reading from r.
+func parseR2RCoreHeader(r *bytes.Reader, raw pe.RawR2RCoreHeader) (*R2RCoreHeader, error) {
+	const sectSize = 12
+	if uint64(raw.NSections)*sectSize > uint64(r.Len()) {
+		return nil, errors.Errorf("ReadyToRun sections out of bounds; expected <= %d sections, got %d", r.Len()/sectSize, raw.NSections)
+	}
+	hdr := &R2RCoreHeader{
+		Flags: raw.Flags,
+	}
+	for i := uint32(0); i < raw.NSections; i++ {
+		var rawSect pe.RawR2RSection
+		if err := binary.Read(r, binary.LittleEndian, &rawSect); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		hdr.Sects = append(hdr.Sects, goR2RSection(rawSect))
+	}
+	return hdr, nil
+}
+
+// readR2RSection reads the contents of the given ReadyToRun section.
+func (file *File) readR2RSection(sect R2RSection) ([]byte, error) {
+	buf, err := file.readFrom(sect.RelAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if uint64(sect.Size) > uint64(len(buf)) {
+		return nil, errors.Errorf("ReadyToRun section %v out of bounds; expected size <= %d, got %d", sect.Type, len(buf), sect.Size)
+	}
+	return buf[:sect.Size], nil
+}
+
+// parseR2RRuntimeFuncs parses the runtime functions of the given ReadyToRun
+// section.
+func (file *File) parseR2RRuntimeFuncs(sect R2RSection, machine enum.MachineType) ([]R2RRuntimeFunc, error) {
+	buf, err := file.readR2RSection(sect)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// Runtime functions have an end address on x64 only.
+	size := 8
+	if machine == enum.MachineTypeAMD64 {
+		size = 12
+	}
+	var funcs []R2RRuntimeFunc
+	for ; len(buf) >= size; buf = buf[size:] {
+		f := R2RRuntimeFunc{
+			StartRelAddr: binary.LittleEndian.Uint32(buf[0:]),
+		}
+		if size == 12 {
+			f.EndRelAddr = binary.LittleEndian.Uint32(buf[4:])
+			f.UnwindRelAddr = binary.LittleEndian.Uint32(buf[8:])
+		} else {
+			f.UnwindRelAddr = binary.LittleEndian.Uint32(buf[4:])
+		}
+		funcs = append(funcs, f)
+	}
+	return funcs, nil
+}
+
+// parseR2RImportSections parses the import sections of the given ReadyToRun
+// section.
+func (file *File) parseR2RImportSections(sect R2RSection) ([]R2RImportSection, error) {
+	buf, err := file.readR2RSection(sect)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	r := bytes.NewReader(buf)
+	var impSects []R2RImportSection
+	for {
+		var raw pe.RawR2RImportSection
+		if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+			if errors.Cause(err) == io.EOF {
+				break
+			}
+			return nil, errors.WithStack(err)
+		}
+		impSect := goR2RImportSection(raw)
+		imps, err := file.parseR2RImports(impSect)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		impSect.Imps = imps
+		impSects = append(impSects, impSect)
+	}
+	return impSects, nil
+}
+
+// parseR2RImports parses the import cells of the given ReadyToRun import
+// section.
+func (file *File) parseR2RImports(impSect R2RImportSection) ([]R2RImport, error) {
+	if impSect.EntrySize == 0 {
+		return nil, nil
+	}
+	n := impSect.Size / uint32(impSect.EntrySize)
+	var sigs []byte
+	if impSect.SigsRelAddr != 0 {
+		buf, err := file.readFrom(impSect.SigsRelAddr)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if uint64(n)*4 > uint64(len(buf)) {
+			return nil, errors.Errorf("ReadyToRun import signatures out of bounds; expected <= %d signatures, got %d", len(buf)/4, n)
+		}
+		sigs = buf
+	}
+	imps := make([]R2RImport, n)
+	for i := range imps {
+		imp := R2RImport{
+			RelAddr: impSect.RelAddr + uint32(i)*uint32(impSect.EntrySize),
+		}
+		if sigs != nil {
+			imp.SigRelAddr = binary.LittleEndian.Uint32(sigs[4*i:])
+			sig, err := file.readFrom(imp.SigRelAddr)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			imp.Kind = enum.R2RFixupKind(sig[0]) &^ enum.R2RFixupKindModuleOverride
+			imp.ModuleOverride = enum.R2RFixupKind(sig[0])&enum.R2RFixupKindModuleOverride != 0
+		}
+		imps[i] = imp
+	}
+	return imps, nil
+}
+
+// parseR2RComponentAssemblies parses the component assemblies of the given
+// ReadyToRun section.
+func (file *File) parseR2RComponentAssemblies(sect R2RSection) ([]R2RComponentAssembly, error) {
+	buf, err := file.readR2RSection(sect)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	r := bytes.NewReader(buf)
+	var comps []R2RComponentAssembly
+	for {
+		var raw pe.RawR2RComponentAssembly
+		if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+			if errors.Cause(err) == io.EOF {
+				break
+			}
+			return nil, errors.WithStack(err)
+		}
+		buf, err := file.readFrom(raw.CoreHeader.RelAddr)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		cr := bytes.NewReader(buf)
+		var rawCoreHdr pe.RawR2RCoreHeader
+		if err := binary.Read(cr, binary.LittleEndian, &rawCoreHdr); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		coreHdr, err := parseR2RCoreHeader(cr, rawCoreHdr)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		comp := R2RComponentAssembly{
+			CLRHdr:  goDataDirectory(raw.CLRHeader),
+			CoreHdr: coreHdr,
+		}
+		comps = append(comps, comp)
+	}
+	return comps, nil
+}
+
+// parseR2RCoreHeaderContent parses the method entry points and available types
+// of the given ReadyToRun core header.
+func (file *File) parseR2RCoreHeaderContent(hdr *R2RCoreHeader, funcs []R2RRuntimeFunc) error {
+	if sect, ok := hdr.Sect(enum.R2RSectionTypeMethodDefEntryPoints); ok {
+		entries, err := file.parseR2RMethodEntryPoints(sect, funcs)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		hdr.MethodEntryPoints = entries
+	}
+	if sect, ok := hdr.Sect(enum.R2RSectionTypeAvailableTypes); ok {
+		types, err := file.parseR2RAvailableTypes(sect)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		hdr.AvailableTypes = types
+	}
+	return nil
+}
+
+// parseR2RMethodEntryPoints parses the method entry points of the given
+// ReadyToRun section.
+func (file *File) parseR2RMethodEntryPoints(sect R2RSection, funcs []R2RRuntimeFunc) ([]R2RMethodEntryPoint, error) {
+	r, err := file.newNativeReader(sect.RelAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// The NativeArray is indexed by MethodDef RID - 1.
+	arr, err := r.newNativeArray(sect.RelAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var entries []R2RMethodEntryPoint
+	for i := uint32(0); i < arr.n; i++ {
+		relAddr, ok, err := arr.get(i)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if !ok {
+			continue
+		}
+		id, next, err := r.decodeUnsigned(relAddr)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		entry := R2RMethodEntryPoint{
+			Method: metadata.NewToken(metadata.TableMethodDef, i+1),
+		}
+		if id&0x1 != 0 {
+			// Method has fixups; either directly following the entry or at a
+			// preceding offset.
+			entry.FixupsRelAddr = next
+			if id&0x2 != 0 {
+				delta, _, err := r.decodeUnsigned(next)
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+				entry.FixupsRelAddr = next - delta
+			}
+			id >>= 2
+		} else {
+			id >>= 1
+		}
+		entry.RuntimeFuncIndex = id
+		if int(id) < len(funcs) {
+			entry.CodeRelAddr = funcs[id].StartRelAddr
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseR2RAvailableTypes parses the available types of the given ReadyToRun
+// section, returning TypeDef and ExportedType tokens.
+func (file *File) parseR2RAvailableTypes(sect R2RSection) ([]metadata.Token, error) {
+	r, err := file.newNativeReader(sect.RelAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	entries, err := r.nativeHashtableEntries(sect.RelAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var types []metadata.Token
+	for _, relAddr := range entries {
+		v, _, err := r.decodeUnsigned(relAddr)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		table := metadata.TableTypeDef
+		if v&0x1 != 0 {
+			table = metadata.TableExportedType
+		}
+		types = append(types, metadata.NewToken(table, v>>1))
+	}
+	return types, nil
+}
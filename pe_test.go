@@ -0,0 +1,129 @@
+package pe
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestParseSectionName(t *testing.T) {
+	// String table with size field followed by ".debug_info" at offset 4.
+	strTable := StringTable("\x10\x00\x00\x00.debug_info\x00")
+	golden := []struct {
+		raw  string
+		want string
+	}{
+		{raw: ".text", want: ".text"},
+		{raw: "/4", want: ".debug_info"},
+		{raw: "//AAAAAE", want: ".debug_info"},
+		// Fall back to the raw section name on invalid long section names.
+		{raw: "/abc", want: "/abc"},
+		{raw: "/1000", want: "/1000"},
+		{raw: "//AA!AAE", want: "//AA!AAE"},
+		{raw: "//AAAAAA", want: "//AAAAAA"},
+	}
+	for _, g := range golden {
+		var raw [8]byte
+		copy(raw[:], g.raw)
+		got := parseSectionName(raw, strTable)
+		if got != g.want {
+			t.Errorf("%q: section name mismatch; expected %q, got %q", g.raw, g.want, got)
+		}
+	}
+}
+
+func TestParseDOSHeader(t *testing.T) {
+	const path = "testdata/cli-64.exe"
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to read file; %+v", path, err)
+	}
+	got, err := parseDOSHeader(bytes.NewReader(buf))
+	if err != nil {
+		t.Fatalf("%q: unable to parse MS-DOS header; %+v", path, err)
+	}
+	want := &DOSHeader{
+		Magic:            [2]byte{'M', 'Z'},
+		LastPageSize:     0x90,
+		NPages:           3,
+		HeaderParagraphs: 4,
+		MaxAlloc:         0xFFFF,
+		SP:               0xB8,
+		RelocTableOffset: 0x40,
+		PEOffset:         0x100,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%q: MS-DOS header mismatch; expected %+v, got %+v", path, want, got)
+	}
+	// Truncated MS-DOS header.
+	if _, err := parseDOSHeader(bytes.NewReader(buf[:0x3C])); err == nil {
+		t.Errorf("%q: expected error for truncated MS-DOS header, got nil", path)
+	}
+}
+
+func TestDOSStub(t *testing.T) {
+	golden := []struct {
+		path string
+		// tamper modifies the given file contents; nil if unmodified.
+		tamper func(buf []byte)
+		// Length of the MS-DOS stub.
+		wantLen int
+		// Rich header present.
+		wantRich     bool
+		wantStandard bool
+	}{
+		// Standard stub followed by a Rich header; the stub ends at the Rich
+		// header.
+		{path: "testdata/cli-64.exe", wantLen: 0x40, wantRich: true, wantStandard: true},
+		// Standard stub without a Rich header; zero padding up to the PE
+		// signature is ignored.
+		{path: "testdata/Microsoft.TestPlatform.PlatformAbstractions.dll", wantLen: 0x40, wantStandard: true},
+		// Non-standard stub; zero-filled.
+		{path: "testdata/exports.dll", wantLen: 0x40},
+		// Non-standard stub; modified message.
+		{
+			path: "testdata/cli-64.exe",
+			tamper: func(buf []byte) {
+				copy(buf[0x4E:], "Win32")
+			},
+			wantLen:  0x40,
+			wantRich: true,
+		},
+		// Non-standard stub; trailing data after the standard stub.
+		{
+			path: "testdata/cli-64.exe",
+			tamper: func(buf []byte) {
+				buf[0x7F] = 0x01
+			},
+			wantLen:  0x40,
+			wantRich: true,
+		},
+		// No stub; PE signature directly follows the MS-DOS header.
+		{path: "testdata/imphash.exe"},
+	}
+	for _, g := range golden {
+		buf, err := ioutil.ReadFile(g.path)
+		if err != nil {
+			t.Errorf("%q: unable to read file; %+v", g.path, err)
+			continue
+		}
+		if g.tamper != nil {
+			g.tamper(buf)
+		}
+		file, err := ParseBytes(buf)
+		if err != nil {
+			t.Errorf("%q: unable to parse file; %+v", g.path, err)
+			continue
+		}
+		if len(file.DOSStub) != g.wantLen {
+			t.Errorf("%q: MS-DOS stub length mismatch; expected %d, got %d", g.path, g.wantLen, len(file.DOSStub))
+		}
+		if rich := file.RichHdr != nil; rich != g.wantRich {
+			t.Errorf("%q: Rich header presence mismatch; expected %v, got %v", g.path, g.wantRich, rich)
+		}
+		if got := file.HasStandardDOSStub(); got != g.wantStandard {
+			t.Errorf("%q: standard MS-DOS stub mismatch; expected %v, got %v", g.path, g.wantStandard, got)
+		}
+	}
+}
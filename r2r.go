@@ -0,0 +1,150 @@
+package pe
+
+import (
+	"github.com/mewmew/pe/enum"
+	"github.com/mewmew/pe/metadata"
+)
+
+// --- [ ReadyToRun ] ----------------------------------------------------------
+
+// ReadyToRun is a ReadyToRun header of a managed PE file, describing native
+// code precompiled from CIL.
+type ReadyToRun struct {
+	// Major version of ReadyToRun format.
+	MajorVer uint16
+	// Minor version of ReadyToRun format.
+	MinorVer uint16
+	// Target CPU type, decoded from the machine type of the COFF file header.
+	Machine enum.MachineType
+	// Target operating system, decoded from the machine type of the COFF file
+	// header.
+	OS enum.R2ROS
+	// Core header; for composite images, the sections common to all component
+	// assemblies.
+	*R2RCoreHeader
+	// Identifier of compiler used to produce the image (e.g. "Crossgen2
+	// 8.0.20").
+	CompilerIdentifier string
+	// Runtime functions (native code blocks).
+	RuntimeFuncs []R2RRuntimeFunc
+	// Import sections.
+	ImportSects []R2RImportSection
+	// File name of the composite image containing the native code of this
+	// component assembly; used if Flags has R2RFlagComponent set.
+	OwnerCompositeExecutable string
+	// Component assemblies of a composite image.
+	ComponentAssemblies []R2RComponentAssembly
+}
+
+// R2RCoreHeader is a ReadyToRun core header.
+type R2RCoreHeader struct {
+	// ReadyToRun flags.
+	Flags enum.R2RFlag
+	// ReadyToRun sections.
+	Sects []R2RSection
+	// Precompiled methods, as specified by the MethodDefEntryPoints section.
+	MethodEntryPoints []R2RMethodEntryPoint
+	// Types of the version bubble, as specified by the AvailableTypes section.
+	AvailableTypes []metadata.Token
+}
+
+// Sect returns the section of the given type, and a boolean indicating
+// success.
+func (hdr *R2RCoreHeader) Sect(typ enum.R2RSectionType) (R2RSection, bool) {
+	for _, sect := range hdr.Sects {
+		if sect.Type == typ {
+			return sect, true
+		}
+	}
+	return R2RSection{}, false
+}
+
+// IsPrecompiled reports whether native code is present for the given
+// MethodDef.
+func (hdr *R2RCoreHeader) IsPrecompiled(method metadata.Token) bool {
+	for _, entry := range hdr.MethodEntryPoints {
+		if entry.Method == method {
+			return true
+		}
+	}
+	return false
+}
+
+// R2RSection is a ReadyToRun section.
+type R2RSection struct {
+	// Section type.
+	Type enum.R2RSectionType
+	// Relative address of section contents (relative to image base).
+	RelAddr uint32
+	// Size of section contents in bytes.
+	Size uint32
+}
+
+// R2RMethodEntryPoint is the entry point of a precompiled method.
+type R2RMethodEntryPoint struct {
+	// MethodDef of method.
+	Method metadata.Token
+	// Index of runtime function containing the main body of the method.
+	RuntimeFuncIndex uint32
+	// Relative address of native code (relative to image base).
+	CodeRelAddr uint32
+	// Relative address of NativeFormat encoded list of fixups (relative to
+	// image base) which must be resolved before the method is executed; zero
+	// if none.
+	FixupsRelAddr uint32
+}
+
+// R2RRuntimeFunc is a runtime function of a ReadyToRun image.
+type R2RRuntimeFunc struct {
+	// Relative address of start of function (relative to image base).
+	StartRelAddr uint32
+	// Relative address of end of function (relative to image base); only
+	// present on x64.
+	EndRelAddr uint32
+	// Relative address of unwind information (relative to image base).
+	UnwindRelAddr uint32
+}
+
+// R2RImportSection is a ReadyToRun import section.
+type R2RImportSection struct {
+	// Relative address of import cells (relative to image base).
+	RelAddr uint32
+	// Size of import cells in bytes.
+	Size uint32
+	// Import section flags.
+	Flags enum.R2RImportSectionFlag
+	// Import section type.
+	Type enum.R2RImportSectionType
+	// Size of import cell in number of bytes.
+	EntrySize uint8
+	// Relative address of fixup signature relative addresses (relative to image
+	// base); zero if not present.
+	SigsRelAddr uint32
+	// Relative address of auxiliary data (relative to image base); zero if not
+	// present.
+	AuxDataRelAddr uint32
+	// Import cells.
+	Imps []R2RImport
+}
+
+// R2RImport is an import cell of a ReadyToRun import section.
+type R2RImport struct {
+	// Relative address of import cell (relative to image base).
+	RelAddr uint32
+	// Relative address of fixup signature (relative to image base); zero if not
+	// present.
+	SigRelAddr uint32
+	// Fixup kind of signature.
+	Kind enum.R2RFixupKind
+	// Specifies whether the fixup signature is prefixed by a module override.
+	ModuleOverride bool
+}
+
+// R2RComponentAssembly is a component assembly of a composite ReadyToRun
+// image.
+type R2RComponentAssembly struct {
+	// Relative address and size of the CLR header of the component assembly.
+	CLRHdr DataDirectory
+	// ReadyToRun core header of the component assembly.
+	CoreHdr *R2RCoreHeader
+}
@@ -0,0 +1,145 @@
+package pe
+
+import (
+	"testing"
+
+	"github.com/mewmew/pe/enum"
+	"github.com/mewmew/pe/metadata"
+)
+
+// testdata/System.Runtime.CompilerServices.VisualC.dll is part of the .NET 8.0
+// shared framework (linux-x64), compiled to native code by Crossgen2.
+
+func TestReadyToRun(t *testing.T) {
+	const path = "testdata/System.Runtime.CompilerServices.VisualC.dll"
+	file, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	r2r := file.R2R
+	if r2r == nil {
+		t.Fatalf("%q: missing ReadyToRun header", path)
+	}
+	if r2r.MajorVer != 9 || r2r.MinorVer != 1 {
+		t.Errorf("%q: ReadyToRun version mismatch; expected 9.1, got %d.%d", path, r2r.MajorVer, r2r.MinorVer)
+	}
+	if r2r.Machine != enum.MachineTypeAMD64 || r2r.OS != enum.R2ROSLinux {
+		t.Errorf("%q: target mismatch; expected %v %v, got %v %v", path, enum.MachineTypeAMD64, enum.R2ROSLinux, r2r.Machine, r2r.OS)
+	}
+	wantFlags := enum.R2RFlagPlatformNeutralSource | enum.R2RFlagSkipTypeValidation | enum.R2RFlagNonSharedPInvokeStubs | enum.R2RFlagMultiModuleVersionBubble
+	if r2r.Flags != wantFlags {
+		t.Errorf("%q: flags mismatch; expected %v, got %v", path, enum.R2RFlagString(wantFlags), enum.R2RFlagString(r2r.Flags))
+	}
+	if want := "Crossgen2 8.0.2025.41914"; r2r.CompilerIdentifier != want {
+		t.Errorf("%q: compiler identifier mismatch; expected %q, got %q", path, want, r2r.CompilerIdentifier)
+	}
+	if len(r2r.Sects) != 13 {
+		t.Errorf("%q: number of sections mismatch; expected 13, got %d", path, len(r2r.Sects))
+	}
+	sect, ok := r2r.Sect(enum.R2RSectionTypeMethodDefEntryPoints)
+	if want := (R2RSection{Type: enum.R2RSectionTypeMethodDefEntryPoints, RelAddr: 0x31D88, Size: 29}); !ok || sect != want {
+		t.Errorf("%q: MethodDefEntryPoints section mismatch; expected %+v, got %+v", path, want, sect)
+	}
+	if _, ok := r2r.Sect(enum.R2RSectionTypeComponentAssemblies); ok {
+		t.Errorf("%q: unexpected ComponentAssemblies section", path)
+	}
+	// Runtime functions.
+	if len(r2r.RuntimeFuncs) != 12 {
+		t.Fatalf("%q: number of runtime functions mismatch; expected 12, got %d", path, len(r2r.RuntimeFuncs))
+	}
+	if want := (R2RRuntimeFunc{StartRelAddr: 0x10A50, EndRelAddr: 0x10A5A, UnwindRelAddr: 0x1089C}); r2r.RuntimeFuncs[4] != want {
+		t.Errorf("%q: runtime function 4 mismatch; expected %+v, got %+v", path, want, r2r.RuntimeFuncs[4])
+	}
+	// Method entry points; all methods of the assembly are precompiled.
+	if len(r2r.MethodEntryPoints) != len(file.Metadata.Tables.MethodDef) {
+		t.Errorf("%q: number of method entry points mismatch; expected %d, got %d", path, len(file.Metadata.Tables.MethodDef), len(r2r.MethodEntryPoints))
+	}
+	for i := range file.Metadata.Tables.MethodDef {
+		method := metadata.NewToken(metadata.TableMethodDef, uint32(i+1))
+		if !r2r.IsPrecompiled(method) {
+			t.Errorf("%q: method %v not precompiled", path, method)
+		}
+	}
+	for _, entry := range r2r.MethodEntryPoints {
+		if int(entry.RuntimeFuncIndex) >= len(r2r.RuntimeFuncs) {
+			t.Errorf("%q: runtime function index of method %v out of bounds; expected < %d, got %d", path, entry.Method, len(r2r.RuntimeFuncs), entry.RuntimeFuncIndex)
+			continue
+		}
+		if start := r2r.RuntimeFuncs[entry.RuntimeFuncIndex].StartRelAddr; entry.CodeRelAddr != start {
+			t.Errorf("%q: code address of method %v mismatch; expected 0x%08X, got 0x%08X", path, entry.Method, start, entry.CodeRelAddr)
+		}
+	}
+	entry := r2r.MethodEntryPoints[9]
+	want := R2RMethodEntryPoint{
+		Method:           metadata.NewToken(metadata.TableMethodDef, 10),
+		RuntimeFuncIndex: 9,
+		CodeRelAddr:      0x10AA0,
+		FixupsRelAddr:    0x31D9F,
+	}
+	if entry != want {
+		t.Errorf("%q: method entry point 9 mismatch; expected %+v, got %+v", path, want, entry)
+	}
+	if got, want := file.Metadata.TokenName(entry.Method), "instance void System.Runtime.CompilerServices.RequiredAttributeAttribute::.ctor(class [System.Runtime]System.Type)"; got != want {
+		t.Errorf("%q: method name mismatch; expected %q, got %q", path, want, got)
+	}
+	// Available types, including exported types (forwarders).
+	if len(r2r.AvailableTypes) != 27 {
+		t.Errorf("%q: number of available types mismatch; expected 27, got %d", path, len(r2r.AvailableTypes))
+	}
+	var ntypeDefs, nexpTypes int
+	for _, typ := range r2r.AvailableTypes {
+		switch typ.Table() {
+		case metadata.TableTypeDef:
+			ntypeDefs++
+		case metadata.TableExportedType:
+			nexpTypes++
+		default:
+			t.Errorf("%q: invalid table of available type %v", path, typ)
+		}
+	}
+	if ntypeDefs != len(file.Metadata.Tables.TypeDef) || nexpTypes != 4 {
+		t.Errorf("%q: available types mismatch; expected %d TypeDefs and 4 ExportedTypes, got %d and %d", path, len(file.Metadata.Tables.TypeDef), ntypeDefs, nexpTypes)
+	}
+	// Import sections.
+	if len(r2r.ImportSects) != 7 {
+		t.Fatalf("%q: number of import sections mismatch; expected 7, got %d", path, len(r2r.ImportSects))
+	}
+	golden := []struct {
+		flags enum.R2RImportSectionFlag
+		typ   enum.R2RImportSectionType
+		kinds []enum.R2RFixupKind
+	}{
+		{},
+		{flags: enum.R2RImportSectionFlagPCode, typ: enum.R2RImportSectionTypeStubDispatch},
+		{
+			flags: enum.R2RImportSectionFlagEager,
+			kinds: []enum.R2RFixupKind{enum.R2RFixupKindCheckInstructionSetSupport, enum.R2RFixupKindHelper, enum.R2RFixupKindHelper, enum.R2RFixupKindHelper, enum.R2RFixupKindHelper, enum.R2RFixupKindHelper},
+		},
+		{flags: enum.R2RImportSectionFlagPCode},
+		{
+			flags: enum.R2RImportSectionFlagPCode,
+			typ:   enum.R2RImportSectionTypeStubDispatch,
+			kinds: []enum.R2RFixupKind{enum.R2RFixupKindMethodEntryRefToken, enum.R2RFixupKindMethodEntryRefToken},
+		},
+		{flags: enum.R2RImportSectionFlagPCode, kinds: []enum.R2RFixupKind{enum.R2RFixupKindFieldBaseOffset}},
+		{typ: enum.R2RImportSectionTypeStringHandle},
+	}
+	for i, g := range golden {
+		impSect := r2r.ImportSects[i]
+		if impSect.Flags != g.flags || impSect.Type != g.typ || impSect.EntrySize != 8 {
+			t.Errorf("%q: import section %d mismatch; expected flags %v, type %v, entry size 8, got flags %v, type %v, entry size %d", path, i, g.flags, g.typ, impSect.Flags, impSect.Type, impSect.EntrySize)
+		}
+		if len(impSect.Imps) != len(g.kinds) {
+			t.Errorf("%q: number of imports of import section %d mismatch; expected %d, got %d", path, i, len(g.kinds), len(impSect.Imps))
+			continue
+		}
+		for j, imp := range impSect.Imps {
+			if imp.Kind != g.kinds[j] {
+				t.Errorf("%q: fixup kind of import %d of import section %d mismatch; expected %v, got %v", path, j, i, g.kinds[j], imp.Kind)
+			}
+			if want := impSect.RelAddr + uint32(j)*uint32(impSect.EntrySize); imp.RelAddr != want {
+				t.Errorf("%q: address of import %d of import section %d mismatch; expected 0x%08X, got 0x%08X", path, j, i, want, imp.RelAddr)
+			}
+		}
+	}
+}
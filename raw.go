@@ -7,11 +7,36 @@ import (
 	"github.com/mewmew/pe/internal/pe"
 )
 
+// goDOSHeader converts the raw MS-DOS header into a corresponding Go version.
+func goDOSHeader(raw *pe.RawDOSHeader) *DOSHeader {
+	return &DOSHeader{
+		Magic:            raw.Magic,
+		LastPageSize:     raw.LastPageSize,
+		NPages:           raw.NPages,
+		NRelocs:          raw.NRelocs,
+		HeaderParagraphs: raw.HeaderParagraphs,
+		MinAlloc:         raw.MinAlloc,
+		MaxAlloc:         raw.MaxAlloc,
+		SS:               raw.SS,
+		SP:               raw.SP,
+		Checksum:         raw.Checksum,
+		IP:               raw.IP,
+		CS:               raw.CS,
+		RelocTableOffset: raw.RelocTableOffset,
+		OverlayNum:       raw.OverlayNum,
+		Reserved1:        raw.Reserved1,
+		OEMID:            raw.OEMID,
+		OEMInfo:          raw.OEMInfo,
+		Reserved2:        raw.Reserved2,
+		PEOffset:         raw.PEOffset,
+	}
+}
+
 // goFileHeader converts the raw file header into a corresponding Go version.
 func goFileHeader(raw *pe.RawFileHeader) *FileHeader {
 	return &FileHeader{
 		Machine:           raw.Machine,
-		NSections:         raw.NSections,
+		NSections:         uint32(raw.NSections),
 		Date:              parseDateFromEpoch(raw.Date),
 		SymbolTableOffset: raw.SymbolTableOffset,
 		NSymbols:          raw.NSymbols,
@@ -20,6 +45,54 @@ func goFileHeader(raw *pe.RawFileHeader) *FileHeader {
 	}
 }
 
+// --- [ Anonymous object header ] ---------------------------------------------
+
+// goAnonObjectHeader converts the raw anonymous object header into a
+// corresponding Go version.
+func goAnonObjectHeader(raw pe.RawAnonObjectHeaderV2) *AnonObjectHeader {
+	return &AnonObjectHeader{
+		Version:        raw.Version,
+		Machine:        raw.Machine,
+		Date:           parseDateFromEpoch(raw.Date),
+		ClassID:        GUID(raw.ClassID),
+		DataSize:       raw.DataSize,
+		Flags:          raw.Flags,
+		MetadataSize:   raw.MetadataSize,
+		MetadataOffset: raw.MetadataOffset,
+	}
+}
+
+// goBigObjFileHeader converts the raw big object header into a corresponding
+// COFF file header.
+func goBigObjFileHeader(raw pe.RawBigObjHeader) *FileHeader {
+	return &FileHeader{
+		Machine:           raw.Machine,
+		NSections:         raw.NSections,
+		Date:              parseDateFromEpoch(raw.Date),
+		SymbolTableOffset: raw.SymbolTableOffset,
+		NSymbols:          raw.NSymbols,
+	}
+}
+
+// goImportObjectHeader converts the raw import object header into a
+// corresponding Go version. The names following the header are not converted.
+func goImportObjectHeader(raw pe.RawImportObjectHeader) *ImportObjectHeader {
+	// TODO: use binary literals.
+	// Type     : 2 bits
+	typ := enum.ImportType(raw.Types & 0x0003) // 0b0000000000000011
+	// NameType : 3 bits
+	nameType := enum.ImportNameType(raw.Types & 0x001C >> 2) // 0b0000000000011100
+	return &ImportObjectHeader{
+		Version:       raw.Version,
+		Machine:       raw.Machine,
+		Date:          parseDateFromEpoch(raw.Date),
+		DataSize:      raw.DataSize,
+		OrdinalOrHint: raw.OrdinalOrHint,
+		Type:          typ,
+		NameType:      nameType,
+	}
+}
+
 // goOptHeader32 converts the raw optional header into a corresponding Go
 // version.
 func goOptHeader32(raw *pe.RawOptHeader32, magic uint16) *OptHeader {
@@ -93,11 +166,12 @@ func goOptHeader64(raw *pe.RawOptHeader64, magic uint16) *OptHeader {
 	}
 }
 
-// goSectionHeader converts the raw section header into a corresponding Go
-// version.
-func goSectionHeader(raw pe.RawSectionHeader) SectionHeader {
+// goSectionHeader converts the raw section header with the given (resolved)
+// name into a corresponding Go version.
+func goSectionHeader(raw pe.RawSectionHeader, name string) SectionHeader {
 	return SectionHeader{
-		Name:           parseCString(raw.Name[:]),
+		Name:           name,
+		RawName:        raw.Name,
 		VirtualSize:    raw.VirtualSize,
 		RelAddr:        raw.RelAddr,
 		DataSize:       raw.DataSize,
@@ -110,8 +184,104 @@ func goSectionHeader(raw pe.RawSectionHeader) SectionHeader {
 	}
 }
 
+// --- [ COFF relocations ] ----------------------------------------------------
+
+// goReloc converts the raw COFF relocation entry of the given machine type into
+// a corresponding Go version.
+func goReloc(raw pe.RawReloc, machine enum.MachineType) Reloc {
+	return Reloc{
+		Offset:      raw.Offset,
+		SymbolIndex: raw.SymbolIndex,
+		Type:        goRelocType(raw.Type, machine),
+	}
+}
+
+// goRelocType converts the raw COFF relocation type of the given machine type
+// into a corresponding Go version.
+func goRelocType(typ uint16, machine enum.MachineType) enum.RelocType {
+	switch machine {
+	case enum.MachineTypeAMD64:
+		return enum.RelocTypeAMD64(typ)
+	case enum.MachineTypeI386:
+		return enum.RelocTypeI386(typ)
+	case enum.MachineTypeARM, enum.MachineTypeARMNT, enum.MachineTypeThumb:
+		return enum.RelocTypeARM(typ)
+	case enum.MachineTypeARM64:
+		return enum.RelocTypeARM64(typ)
+	default:
+		return enum.RelocTypeUnknown(typ)
+	}
+}
+
+// --- [ COFF line numbers ] ---------------------------------------------------
+
+// goLineNum converts the raw COFF line number entry into a corresponding Go
+// version.
+func goLineNum(raw pe.RawLineNum) LineNum {
+	if raw.LineNum == 0 {
+		return LineNum{SymbolIndex: raw.Addr}
+	}
+	return LineNum{
+		RelAddr: raw.Addr,
+		LineNum: raw.LineNum,
+	}
+}
+
+// --- [ COFF symbol table ] ---------------------------------------------------
+
+// goSymbol converts the raw symbol with the given symbol table index and name
+// into a corresponding Go version.
+func goSymbol(raw pe.RawSymbolEx, index uint32, name string) Symbol {
+	// TODO: use binary literals.
+	// BaseType    : 4 bits
+	baseType := enum.SymbolBaseType(raw.Type & 0x000F) // 0b0000000000001111
+	// ComplexType : 4 bits
+	complexType := enum.SymbolComplexType(raw.Type & 0x00F0 >> 4) // 0b0000000011110000
+	return Symbol{
+		Index:        index,
+		Name:         name,
+		Value:        raw.Value,
+		SectNum:      raw.SectNum,
+		BaseType:     baseType,
+		ComplexType:  complexType,
+		StorageClass: raw.StorageClass,
+	}
+}
+
+// rawSymbolEx converts the raw COFF symbol into the wider symbol format of big
+// object files.
+func rawSymbolEx(raw pe.RawSymbol) pe.RawSymbolEx {
+	return pe.RawSymbolEx{
+		Name:         raw.Name,
+		Value:        raw.Value,
+		SectNum:      int32(raw.SectNum),
+		Type:         raw.Type,
+		StorageClass: raw.StorageClass,
+		NAux:         raw.NAux,
+	}
+}
+
 // --- [ Data directories ] ----------------------------------------------------
 
+// ~~~ [ 0 - Export Table ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// goExportDirectory converts the raw export data directory into a
+// corresponding Go version. The DLL name is not converted.
+func goExportDirectory(raw pe.RawExportDirectory) *ExportDirectory {
+	return &ExportDirectory{
+		Characteristics: raw.Characteristics,
+		Date:            parseDateFromEpoch(raw.Date),
+		MajorVer:        raw.MajorVer,
+		MinorVer:        raw.MinorVer,
+		OrdinalBase:     raw.OrdinalBase,
+		NFuncs:          raw.NFuncs,
+		NNames:          raw.NNames,
+		EATRelAddr:      raw.EATRelAddr,
+		NamePtrsRelAddr: raw.NamePtrsRelAddr,
+		OrdinalsRelAddr: raw.OrdinalsRelAddr,
+	}
+}
+
 // ~~~ [ 1 - Import Table ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // goImportDirectory converts the raw import data directory into a corresponding
@@ -189,6 +359,18 @@ func (file *File) parseNameEntry(addr uint64) NameEntry {
 	}
 }
 
+// ~~~ [ 4 - Certificate Table ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// goCertificate converts the raw attribute certificate into a corresponding Go
+// version.
+func goCertificate(raw pe.RawCertificateHeader, data []byte) Certificate {
+	return Certificate{
+		Revision: raw.Revision,
+		Type:     raw.Type,
+		Data:     data,
+	}
+}
+
 // ~~~ [ 5 - Base Relocation Table ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // goBaseRelocEntry converts the raw base relocation entry into a corresponding
@@ -222,15 +404,24 @@ func goDebugDirectory(raw pe.RawDebugDirectory) DebugDirectory {
 	}
 }
 
-// goCodeViewInfo converts the raw CodeView debug info into a corresponding Go
-// version.
-func goCodeViewInfo(raw pe.RawCodeViewInfo, pdbPath string) CodeViewInfo {
-	return CodeViewInfo{
-		Signature: raw.Signature,
-		Offset:    raw.Offset,
-		Date:      parseDateFromEpoch(raw.Date),
-		Age:       raw.Age,
-		PDBPath:   pdbPath,
+// goCodeViewNB10 converts the raw NB10 CodeView debug info into a
+// corresponding Go version.
+func goCodeViewNB10(raw pe.RawCodeViewNB10, pdbPath string) *CodeViewNB10 {
+	return &CodeViewNB10{
+		Offset:  raw.Offset,
+		Date:    parseDateFromEpoch(raw.Date),
+		Age:     raw.Age,
+		PDBPath: pdbPath,
+	}
+}
+
+// goCodeViewRSDS converts the raw RSDS CodeView debug info into a
+// corresponding Go version.
+func goCodeViewRSDS(raw pe.RawCodeViewRSDS, pdbPath string) *CodeViewRSDS {
+	return &CodeViewRSDS{
+		GUID:    GUID(raw.GUID),
+		Age:     raw.Age,
+		PDBPath: pdbPath,
 	}
 }
 
@@ -261,3 +452,58 @@ func goFPOData(raw pe.RawFPOData) FPOData {
 	}
 	return fpo
 }
+
+// ~~~ [ 14 - CLR Header ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// goCLRHeader converts the raw CLR header into a corresponding Go version.
+func goCLRHeader(raw pe.RawCLRHeader) *CLRHeader {
+	return &CLRHeader{
+		Size:                    raw.Size,
+		MajorRuntimeVer:         raw.MajorRuntimeVer,
+		MinorRuntimeVer:         raw.MinorRuntimeVer,
+		Metadata:                goDataDirectory(raw.Metadata),
+		Flags:                   raw.Flags,
+		EntryPointToken:         raw.EntryPointToken,
+		Resources:               goDataDirectory(raw.Resources),
+		StrongNameSignature:     goDataDirectory(raw.StrongNameSignature),
+		CodeManagerTable:        goDataDirectory(raw.CodeManagerTable),
+		VTableFixups:            goDataDirectory(raw.VTableFixups),
+		ExportAddressTableJumps: goDataDirectory(raw.ExportAddressTableJumps),
+		ManagedNativeHeader:     goDataDirectory(raw.ManagedNativeHeader),
+	}
+}
+
+// goDataDirectory converts the raw data directory into a corresponding Go
+// version.
+func goDataDirectory(raw pe.RawDataDirectory) DataDirectory {
+	return DataDirectory{
+		RelAddr: raw.RelAddr,
+		Size:    raw.Size,
+	}
+}
+
+// ~~~ [ ReadyToRun ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// goR2RSection converts the raw ReadyToRun section into a corresponding Go
+// version.
+func goR2RSection(raw pe.RawR2RSection) R2RSection {
+	return R2RSection{
+		Type:    raw.Type,
+		RelAddr: raw.Section.RelAddr,
+		Size:    raw.Section.Size,
+	}
+}
+
+// goR2RImportSection converts the raw ReadyToRun import section into a
+// corresponding Go version.
+func goR2RImportSection(raw pe.RawR2RImportSection) R2RImportSection {
+	return R2RImportSection{
+		RelAddr:        raw.Section.RelAddr,
+		Size:           raw.Section.Size,
+		Flags:          raw.Flags,
+		Type:           raw.Type,
+		EntrySize:      raw.EntrySize,
+		SigsRelAddr:    raw.Signatures,
+		AuxDataRelAddr: raw.AuxiliaryData,
+	}
+}
@@ -2,6 +2,8 @@ package pe
 
 import "github.com/mewmew/pe/enum"
 
+// --- [ Base Relocation Table ] -----------------------------------------------
+
 // BaseRelocBlock is a base relocation block descriptor.
 type BaseRelocBlock struct {
 	// Relative address of page. The address of a relocation is computed by
@@ -21,3 +23,16 @@ type BaseRelocEntry struct {
 	// Offset of base relocation from the start of the base relocation block.
 	Offset uint16
 }
+
+// --- [ COFF relocations ] ----------------------------------------------------
+
+// Reloc is a COFF relocation entry of a section.
+type Reloc struct {
+	// Offset of the item to which relocation is applied (relative to start of
+	// section in object files).
+	Offset uint32
+	// Symbol table index of the relocation target.
+	SymbolIndex uint32
+	// Machine-specific relocation type.
+	Type enum.RelocType
+}
@@ -0,0 +1,387 @@
+package pe
+
+import "github.com/mewmew/pe/enum"
+
+// --- [ Rich header ] ---------------------------------------------------------
+
+// RichHeader is the undocumented Rich header, located between the MS-DOS stub
+// and the PE signature of images linked by Microsoft linkers. It records the
+// tools (product ID and build number) used to produce the objects of the image.
+//
+// The Rich header is XOR-ed with a key, which is the checksum of the MS-DOS
+// header and the Rich header entries. The decoded header consists of a "DanS"
+// signature, three zero padding values and the entries, followed by a "Rich"
+// signature and the key (not XOR-ed).
+type RichHeader struct {
+	// File offset of the Rich header ("DanS" signature).
+	Offset uint32
+	// Size of the Rich header in bytes, including the "Rich" signature and key.
+	Size uint32
+	// XOR key, as stored after the "Rich" signature.
+	Key uint32
+	// Checksum computed from the MS-DOS header and the Rich header entries;
+	// equal to the key unless the header has been tampered with.
+	Checksum uint32
+	// Rich header entries.
+	Entries []RichEntry
+}
+
+// Valid reports whether the stored key of the Rich header matches its computed
+// checksum.
+func (hdr *RichHeader) Valid() bool {
+	return hdr.Key == hdr.Checksum
+}
+
+// RichEntry is a Rich header entry, recording the number of objects produced
+// by a given tool.
+type RichEntry struct {
+	// Product ID of tool.
+	ProductID uint16
+	// Build number of tool.
+	Build uint16
+	// Number of objects (or imported functions) produced by the tool.
+	Count uint32
+}
+
+// CompID returns the combined product ID and build number of the entry.
+func (entry RichEntry) CompID() uint32 {
+	return uint32(entry.ProductID)<<16 | uint32(entry.Build)
+}
+
+// RichProduct describes the tool identified by a product ID of the Rich header.
+type RichProduct struct {
+	// Product name (e.g. "Utc1900_CPP").
+	Name string
+	// Tool kind.
+	Kind enum.RichToolKind
+	// Visual Studio version (e.g. "Visual Studio 2019"); empty if unknown.
+	VSVersion string
+}
+
+// Product returns the tool of the entry, as identified by its product ID and
+// build number. The boolean return value indicates whether the product ID is
+// known.
+func (entry RichEntry) Product() (RichProduct, bool) {
+	if int(entry.ProductID) >= len(richProducts) {
+		return RichProduct{Kind: enum.RichToolKindUnknown}, false
+	}
+	product := richProducts[entry.ProductID]
+	if product.VSVersion == vs2015 {
+		// Visual Studio 2015 and later share product IDs (toolset 14.x), and
+		// are distinguished by build number.
+		product.VSVersion = vsVersion14(entry.Build)
+	}
+	return product, true
+}
+
+// vsVersion14 returns the Visual Studio version of the given build number of a
+// 14.x toolset (best-effort).
+func vsVersion14(build uint16) string {
+	switch {
+	case build < 25000:
+		return vs2015
+	case build < 27500:
+		return vs2017
+	case build < 30700:
+		return vs2019
+	case build < 35700:
+		return vs2022
+	default:
+		return vs2026
+	}
+}
+
+// Visual Studio versions.
+const (
+	vs97   = "Visual Studio 97"
+	vs6    = "Visual Studio 6.0"
+	vs2002 = "Visual Studio .NET 2002"
+	vs2003 = "Visual Studio .NET 2003"
+	vs2005 = "Visual Studio 2005"
+	vs2008 = "Visual Studio 2008"
+	vs2010 = "Visual Studio 2010"
+	vs2012 = "Visual Studio 2012"
+	vs2013 = "Visual Studio 2013"
+	vs2015 = "Visual Studio 2015"
+	vs2017 = "Visual Studio 2017"
+	vs2019 = "Visual Studio 2019"
+	vs2022 = "Visual Studio 2022"
+	vs2026 = "Visual Studio 2026"
+)
+
+// richProducts maps from product ID to tool of Rich header entries.
+//
+// ref: https://github.com/dishather/richprint
+var richProducts = [...]RichProduct{
+	{"Unknown", enum.RichToolKindUnknown, ""},             // 0x0000
+	{"Import0", enum.RichToolKindImport, ""},              // 0x0001
+	{"Linker510", enum.RichToolKindLinker, vs97},          // 0x0002
+	{"Cvtomf510", enum.RichToolKindCVTOMF, vs97},          // 0x0003
+	{"Linker600", enum.RichToolKindLinker, vs6},           // 0x0004
+	{"Cvtomf600", enum.RichToolKindCVTOMF, vs6},           // 0x0005
+	{"Cvtres500", enum.RichToolKindResource, vs97},        // 0x0006
+	{"Utc11_Basic", enum.RichToolKindBasic, vs97},         // 0x0007
+	{"Utc11_C", enum.RichToolKindC, vs97},                 // 0x0008
+	{"Utc12_Basic", enum.RichToolKindBasic, vs6},          // 0x0009
+	{"Utc12_C", enum.RichToolKindC, vs6},                  // 0x000A
+	{"Utc12_CPP", enum.RichToolKindCPP, vs6},              // 0x000B
+	{"AliasObj60", enum.RichToolKindAliasObj, vs6},        // 0x000C
+	{"VisualBasic60", enum.RichToolKindBasic, vs6},        // 0x000D
+	{"Masm613", enum.RichToolKindMASM, vs6},               // 0x000E
+	{"Masm710", enum.RichToolKindMASM, vs2003},            // 0x000F
+	{"Linker511", enum.RichToolKindLinker, vs97},          // 0x0010
+	{"Cvtomf511", enum.RichToolKindCVTOMF, vs97},          // 0x0011
+	{"Masm614", enum.RichToolKindMASM, vs6},               // 0x0012
+	{"Linker512", enum.RichToolKindLinker, vs97},          // 0x0013
+	{"Cvtomf512", enum.RichToolKindCVTOMF, vs97},          // 0x0014
+	{"Utc12_C_Std", enum.RichToolKindC, vs6},              // 0x0015
+	{"Utc12_CPP_Std", enum.RichToolKindCPP, vs6},          // 0x0016
+	{"Utc12_C_Book", enum.RichToolKindC, vs6},             // 0x0017
+	{"Utc12_CPP_Book", enum.RichToolKindCPP, vs6},         // 0x0018
+	{"Implib700", enum.RichToolKindImplib, vs2002},        // 0x0019
+	{"Cvtomf700", enum.RichToolKindCVTOMF, vs2002},        // 0x001A
+	{"Utc13_Basic", enum.RichToolKindBasic, vs2002},       // 0x001B
+	{"Utc13_C", enum.RichToolKindC, vs2002},               // 0x001C
+	{"Utc13_CPP", enum.RichToolKindCPP, vs2002},           // 0x001D
+	{"Linker610", enum.RichToolKindLinker, vs6},           // 0x001E
+	{"Cvtomf610", enum.RichToolKindCVTOMF, vs6},           // 0x001F
+	{"Linker601", enum.RichToolKindLinker, vs6},           // 0x0020
+	{"Cvtomf601", enum.RichToolKindCVTOMF, vs6},           // 0x0021
+	{"Utc12_1_Basic", enum.RichToolKindBasic, vs6},        // 0x0022
+	{"Utc12_1_C", enum.RichToolKindC, vs6},                // 0x0023
+	{"Utc12_1_CPP", enum.RichToolKindCPP, vs6},            // 0x0024
+	{"Linker620", enum.RichToolKindLinker, vs6},           // 0x0025
+	{"Cvtomf620", enum.RichToolKindCVTOMF, vs6},           // 0x0026
+	{"AliasObj70", enum.RichToolKindAliasObj, vs2002},     // 0x0027
+	{"Linker621", enum.RichToolKindLinker, vs6},           // 0x0028
+	{"Cvtomf621", enum.RichToolKindCVTOMF, vs6},           // 0x0029
+	{"Masm615", enum.RichToolKindMASM, vs6},               // 0x002A
+	{"Utc13_LTCG_C", enum.RichToolKindC, vs2002},          // 0x002B
+	{"Utc13_LTCG_CPP", enum.RichToolKindCPP, vs2002},      // 0x002C
+	{"Masm620", enum.RichToolKindMASM, vs6},               // 0x002D
+	{"ILAsm100", enum.RichToolKindILAsm, vs2002},          // 0x002E
+	{"Utc12_2_Basic", enum.RichToolKindBasic, vs6},        // 0x002F
+	{"Utc12_2_C", enum.RichToolKindC, vs6},                // 0x0030
+	{"Utc12_2_CPP", enum.RichToolKindCPP, vs6},            // 0x0031
+	{"Utc12_2_C_Std", enum.RichToolKindC, vs6},            // 0x0032
+	{"Utc12_2_CPP_Std", enum.RichToolKindCPP, vs6},        // 0x0033
+	{"Utc12_2_C_Book", enum.RichToolKindC, vs6},           // 0x0034
+	{"Utc12_2_CPP_Book", enum.RichToolKindCPP, vs6},       // 0x0035
+	{"Implib622", enum.RichToolKindImplib, vs6},           // 0x0036
+	{"Cvtomf622", enum.RichToolKindCVTOMF, vs6},           // 0x0037
+	{"Cvtres501", enum.RichToolKindResource, vs6},         // 0x0038
+	{"Utc13_C_Std", enum.RichToolKindC, vs2002},           // 0x0039
+	{"Utc13_CPP_Std", enum.RichToolKindCPP, vs2002},       // 0x003A
+	{"Cvtpgd1300", enum.RichToolKindCVTPGD, vs2002},       // 0x003B
+	{"Linker622", enum.RichToolKindLinker, vs6},           // 0x003C
+	{"Linker700", enum.RichToolKindLinker, vs2002},        // 0x003D
+	{"Export622", enum.RichToolKindExport, vs6},           // 0x003E
+	{"Export700", enum.RichToolKindExport, vs2002},        // 0x003F
+	{"Masm700", enum.RichToolKindMASM, vs2002},            // 0x0040
+	{"Utc13_POGO_I_C", enum.RichToolKindC, vs2002},        // 0x0041
+	{"Utc13_POGO_I_CPP", enum.RichToolKindCPP, vs2002},    // 0x0042
+	{"Utc13_POGO_O_C", enum.RichToolKindC, vs2002},        // 0x0043
+	{"Utc13_POGO_O_CPP", enum.RichToolKindCPP, vs2002},    // 0x0044
+	{"Cvtres700", enum.RichToolKindResource, vs2002},      // 0x0045
+	{"Cvtres710p", enum.RichToolKindResource, vs2003},     // 0x0046
+	{"Linker710p", enum.RichToolKindLinker, vs2003},       // 0x0047
+	{"Cvtomf710p", enum.RichToolKindCVTOMF, vs2003},       // 0x0048
+	{"Export710p", enum.RichToolKindExport, vs2003},       // 0x0049
+	{"Implib710p", enum.RichToolKindImplib, vs2003},       // 0x004A
+	{"Masm710p", enum.RichToolKindMASM, vs2003},           // 0x004B
+	{"Utc1310p_C", enum.RichToolKindC, vs2003},            // 0x004C
+	{"Utc1310p_CPP", enum.RichToolKindCPP, vs2003},        // 0x004D
+	{"Utc1310p_C_Std", enum.RichToolKindC, vs2003},        // 0x004E
+	{"Utc1310p_CPP_Std", enum.RichToolKindCPP, vs2003},    // 0x004F
+	{"Utc1310p_LTCG_C", enum.RichToolKindC, vs2003},       // 0x0050
+	{"Utc1310p_LTCG_CPP", enum.RichToolKindCPP, vs2003},   // 0x0051
+	{"Utc1310p_POGO_I_C", enum.RichToolKindC, vs2003},     // 0x0052
+	{"Utc1310p_POGO_I_CPP", enum.RichToolKindCPP, vs2003}, // 0x0053
+	{"Utc1310p_POGO_O_C", enum.RichToolKindC, vs2003},     // 0x0054
+	{"Utc1310p_POGO_O_CPP", enum.RichToolKindCPP, vs2003}, // 0x0055
+	{"Linker624", enum.RichToolKindLinker, vs6},           // 0x0056
+	{"Cvtomf624", enum.RichToolKindCVTOMF, vs6},           // 0x0057
+	{"Export624", enum.RichToolKindExport, vs6},           // 0x0058
+	{"Implib624", enum.RichToolKindImplib, vs6},           // 0x0059
+	{"Linker710", enum.RichToolKindLinker, vs2003},        // 0x005A
+	{"Cvtomf710", enum.RichToolKindCVTOMF, vs2003},        // 0x005B
+	{"Export710", enum.RichToolKindExport, vs2003},        // 0x005C
+	{"Implib710", enum.RichToolKindImplib, vs2003},        // 0x005D
+	{"Cvtres710", enum.RichToolKindResource, vs2003},      // 0x005E
+	{"Utc1310_C", enum.RichToolKindC, vs2003},             // 0x005F
+	{"Utc1310_CPP", enum.RichToolKindCPP, vs2003},         // 0x0060
+	{"Utc1310_C_Std", enum.RichToolKindC, vs2003},         // 0x0061
+	{"Utc1310_CPP_Std", enum.RichToolKindCPP, vs2003},     // 0x0062
+	{"Utc1310_LTCG_C", enum.RichToolKindC, vs2003},        // 0x0063
+	{"Utc1310_LTCG_CPP", enum.RichToolKindCPP, vs2003},    // 0x0064
+	{"Utc1310_POGO_I_C", enum.RichToolKindC, vs2003},      // 0x0065
+	{"Utc1310_POGO_I_CPP", enum.RichToolKindCPP, vs2003},  // 0x0066
+	{"Utc1310_POGO_O_C", enum.RichToolKindC, vs2003},      // 0x0067
+	{"Utc1310_POGO_O_CPP", enum.RichToolKindCPP, vs2003},  // 0x0068
+	{"AliasObj710", enum.RichToolKindAliasObj, vs2003},    // 0x0069
+	{"AliasObj710p", enum.RichToolKindAliasObj, vs2003},   // 0x006A
+	{"Cvtpgd1310", enum.RichToolKindCVTPGD, vs2003},       // 0x006B
+	{"Cvtpgd1310p", enum.RichToolKindCVTPGD, vs2003},      // 0x006C
+	{"Utc1400_C", enum.RichToolKindC, vs2005},             // 0x006D
+	{"Utc1400_CPP", enum.RichToolKindCPP, vs2005},         // 0x006E
+	{"Utc1400_C_Std", enum.RichToolKindC, vs2005},         // 0x006F
+	{"Utc1400_CPP_Std", enum.RichToolKindCPP, vs2005},     // 0x0070
+	{"Utc1400_LTCG_C", enum.RichToolKindC, vs2005},        // 0x0071
+	{"Utc1400_LTCG_CPP", enum.RichToolKindCPP, vs2005},    // 0x0072
+	{"Utc1400_POGO_I_C", enum.RichToolKindC, vs2005},      // 0x0073
+	{"Utc1400_POGO_I_CPP", enum.RichToolKindCPP, vs2005},  // 0x0074
+	{"Utc1400_POGO_O_C", enum.RichToolKindC, vs2005},      // 0x0075
+	{"Utc1400_POGO_O_CPP", enum.RichToolKindCPP, vs2005},  // 0x0076
+	{"Cvtpgd1400", enum.RichToolKindCVTPGD, vs2005},       // 0x0077
+	{"Linker800", enum.RichToolKindLinker, vs2005},        // 0x0078
+	{"Cvtomf800", enum.RichToolKindCVTOMF, vs2005},        // 0x0079
+	{"Export800", enum.RichToolKindExport, vs2005},        // 0x007A
+	{"Implib800", enum.RichToolKindImplib, vs2005},        // 0x007B
+	{"Cvtres800", enum.RichToolKindResource, vs2005},      // 0x007C
+	{"Masm800", enum.RichToolKindMASM, vs2005},            // 0x007D
+	{"AliasObj800", enum.RichToolKindAliasObj, vs2005},    // 0x007E
+	{"PhoenixPrerelease", enum.RichToolKindUnknown, ""},   // 0x007F
+	{"Utc1400_CVTCIL_C", enum.RichToolKindC, vs2005},      // 0x0080
+	{"Utc1400_CVTCIL_CPP", enum.RichToolKindCPP, vs2005},  // 0x0081
+	{"Utc1400_LTCG_MSIL", enum.RichToolKindMSIL, vs2005},  // 0x0082
+	{"Utc1500_C", enum.RichToolKindC, vs2008},             // 0x0083
+	{"Utc1500_CPP", enum.RichToolKindCPP, vs2008},         // 0x0084
+	{"Utc1500_C_Std", enum.RichToolKindC, vs2008},         // 0x0085
+	{"Utc1500_CPP_Std", enum.RichToolKindCPP, vs2008},     // 0x0086
+	{"Utc1500_CVTCIL_C", enum.RichToolKindC, vs2008},      // 0x0087
+	{"Utc1500_CVTCIL_CPP", enum.RichToolKindCPP, vs2008},  // 0x0088
+	{"Utc1500_LTCG_C", enum.RichToolKindC, vs2008},        // 0x0089
+	{"Utc1500_LTCG_CPP", enum.RichToolKindCPP, vs2008},    // 0x008A
+	{"Utc1500_LTCG_MSIL", enum.RichToolKindMSIL, vs2008},  // 0x008B
+	{"Utc1500_POGO_I_C", enum.RichToolKindC, vs2008},      // 0x008C
+	{"Utc1500_POGO_I_CPP", enum.RichToolKindCPP, vs2008},  // 0x008D
+	{"Utc1500_POGO_O_C", enum.RichToolKindC, vs2008},      // 0x008E
+	{"Utc1500_POGO_O_CPP", enum.RichToolKindCPP, vs2008},  // 0x008F
+	{"Cvtpgd1500", enum.RichToolKindCVTPGD, vs2008},       // 0x0090
+	{"Linker900", enum.RichToolKindLinker, vs2008},        // 0x0091
+	{"Export900", enum.RichToolKindExport, vs2008},        // 0x0092
+	{"Implib900", enum.RichToolKindImplib, vs2008},        // 0x0093
+	{"Cvtres900", enum.RichToolKindResource, vs2008},      // 0x0094
+	{"Masm900", enum.RichToolKindMASM, vs2008},            // 0x0095
+	{"AliasObj900", enum.RichToolKindAliasObj, vs2008},    // 0x0096
+	{"Resource", enum.RichToolKindResource, ""},           // 0x0097
+	{"AliasObj1000", enum.RichToolKindAliasObj, vs2010},   // 0x0098
+	{"Cvtpgd1600", enum.RichToolKindCVTPGD, vs2010},       // 0x0099
+	{"Cvtres1000", enum.RichToolKindResource, vs2010},     // 0x009A
+	{"Export1000", enum.RichToolKindExport, vs2010},       // 0x009B
+	{"Implib1000", enum.RichToolKindImplib, vs2010},       // 0x009C
+	{"Linker1000", enum.RichToolKindLinker, vs2010},       // 0x009D
+	{"Masm1000", enum.RichToolKindMASM, vs2010},           // 0x009E
+	{"Phx1600_C", enum.RichToolKindC, vs2010},             // 0x009F
+	{"Phx1600_CPP", enum.RichToolKindCPP, vs2010},         // 0x00A0
+	{"Phx1600_CVTCIL_C", enum.RichToolKindC, vs2010},      // 0x00A1
+	{"Phx1600_CVTCIL_CPP", enum.RichToolKindCPP, vs2010},  // 0x00A2
+	{"Phx1600_LTCG_C", enum.RichToolKindC, vs2010},        // 0x00A3
+	{"Phx1600_LTCG_CPP", enum.RichToolKindCPP, vs2010},    // 0x00A4
+	{"Phx1600_LTCG_MSIL", enum.RichToolKindMSIL, vs2010},  // 0x00A5
+	{"Phx1600_POGO_I_C", enum.RichToolKindC, vs2010},      // 0x00A6
+	{"Phx1600_POGO_I_CPP", enum.RichToolKindCPP, vs2010},  // 0x00A7
+	{"Phx1600_POGO_O_C", enum.RichToolKindC, vs2010},      // 0x00A8
+	{"Phx1600_POGO_O_CPP", enum.RichToolKindCPP, vs2010},  // 0x00A9
+	{"Utc1600_C", enum.RichToolKindC, vs2010},             // 0x00AA
+	{"Utc1600_CPP", enum.RichToolKindCPP, vs2010},         // 0x00AB
+	{"Utc1600_CVTCIL_C", enum.RichToolKindC, vs2010},      // 0x00AC
+	{"Utc1600_CVTCIL_CPP", enum.RichToolKindCPP, vs2010},  // 0x00AD
+	{"Utc1600_LTCG_C", enum.RichToolKindC, vs2010},        // 0x00AE
+	{"Utc1600_LTCG_CPP", enum.RichToolKindCPP, vs2010},    // 0x00AF
+	{"Utc1600_LTCG_MSIL", enum.RichToolKindMSIL, vs2010},  // 0x00B0
+	{"Utc1600_POGO_I_C", enum.RichToolKindC, vs2010},      // 0x00B1
+	{"Utc1600_POGO_I_CPP", enum.RichToolKindCPP, vs2010},  // 0x00B2
+	{"Utc1600_POGO_O_C", enum.RichToolKindC, vs2010},      // 0x00B3
+	{"Utc1600_POGO_O_CPP", enum.RichToolKindCPP, vs2010},  // 0x00B4
+	{"AliasObj1010", enum.RichToolKindAliasObj, vs2010},   // 0x00B5
+	{"Cvtpgd1610", enum.RichToolKindCVTPGD, vs2010},       // 0x00B6
+	{"Cvtres1010", enum.RichToolKindResource, vs2010},     // 0x00B7
+	{"Export1010", enum.RichToolKindExport, vs2010},       // 0x00B8
+	{"Implib1010", enum.RichToolKindImplib, vs2010},       // 0x00B9
+	{"Linker1010", enum.RichToolKindLinker, vs2010},       // 0x00BA
+	{"Masm1010", enum.RichToolKindMASM, vs2010},           // 0x00BB
+	{"Utc1610_C", enum.RichToolKindC, vs2010},             // 0x00BC
+	{"Utc1610_CPP", enum.RichToolKindCPP, vs2010},         // 0x00BD
+	{"Utc1610_CVTCIL_C", enum.RichToolKindC, vs2010},      // 0x00BE
+	{"Utc1610_CVTCIL_CPP", enum.RichToolKindCPP, vs2010},  // 0x00BF
+	{"Utc1610_LTCG_C", enum.RichToolKindC, vs2010},        // 0x00C0
+	{"Utc1610_LTCG_CPP", enum.RichToolKindCPP, vs2010},    // 0x00C1
+	{"Utc1610_LTCG_MSIL", enum.RichToolKindMSIL, vs2010},  // 0x00C2
+	{"Utc1610_POGO_I_C", enum.RichToolKindC, vs2010},      // 0x00C3
+	{"Utc1610_POGO_I_CPP", enum.RichToolKindCPP, vs2010},  // 0x00C4
+	{"Utc1610_POGO_O_C", enum.RichToolKindC, vs2010},      // 0x00C5
+	{"Utc1610_POGO_O_CPP", enum.RichToolKindCPP, vs2010},  // 0x00C6
+	{"AliasObj1100", enum.RichToolKindAliasObj, vs2012},   // 0x00C7
+	{"Cvtpgd1700", enum.RichToolKindCVTPGD, vs2012},       // 0x00C8
+	{"Cvtres1100", enum.RichToolKindResource, vs2012},     // 0x00C9
+	{"Export1100", enum.RichToolKindExport, vs2012},       // 0x00CA
+	{"Implib1100", enum.RichToolKindImplib, vs2012},       // 0x00CB
+	{"Linker1100", enum.RichToolKindLinker, vs2012},       // 0x00CC
+	{"Masm1100", enum.RichToolKindMASM, vs2012},           // 0x00CD
+	{"Utc1700_C", enum.RichToolKindC, vs2012},             // 0x00CE
+	{"Utc1700_CPP", enum.RichToolKindCPP, vs2012},         // 0x00CF
+	{"Utc1700_CVTCIL_C", enum.RichToolKindC, vs2012},      // 0x00D0
+	{"Utc1700_CVTCIL_CPP", enum.RichToolKindCPP, vs2012},  // 0x00D1
+	{"Utc1700_LTCG_C", enum.RichToolKindC, vs2012},        // 0x00D2
+	{"Utc1700_LTCG_CPP", enum.RichToolKindCPP, vs2012},    // 0x00D3
+	{"Utc1700_LTCG_MSIL", enum.RichToolKindMSIL, vs2012},  // 0x00D4
+	{"Utc1700_POGO_I_C", enum.RichToolKindC, vs2012},      // 0x00D5
+	{"Utc1700_POGO_I_CPP", enum.RichToolKindCPP, vs2012},  // 0x00D6
+	{"Utc1700_POGO_O_C", enum.RichToolKindC, vs2012},      // 0x00D7
+	{"Utc1700_POGO_O_CPP", enum.RichToolKindCPP, vs2012},  // 0x00D8
+	{"AliasObj1200", enum.RichToolKindAliasObj, vs2013},   // 0x00D9
+	{"Cvtpgd1800", enum.RichToolKindCVTPGD, vs2013},       // 0x00DA
+	{"Cvtres1200", enum.RichToolKindResource, vs2013},     // 0x00DB
+	{"Export1200", enum.RichToolKindExport, vs2013},       // 0x00DC
+	{"Implib1200", enum.RichToolKindImplib, vs2013},       // 0x00DD
+	{"Linker1200", enum.RichToolKindLinker, vs2013},       // 0x00DE
+	{"Masm1200", enum.RichToolKindMASM, vs2013},           // 0x00DF
+	{"Utc1800_C", enum.RichToolKindC, vs2013},             // 0x00E0
+	{"Utc1800_CPP", enum.RichToolKindCPP, vs2013},         // 0x00E1
+	{"Utc1800_CVTCIL_C", enum.RichToolKindC, vs2013},      // 0x00E2
+	{"Utc1800_CVTCIL_CPP", enum.RichToolKindCPP, vs2013},  // 0x00E3
+	{"Utc1800_LTCG_C", enum.RichToolKindC, vs2013},        // 0x00E4
+	{"Utc1800_LTCG_CPP", enum.RichToolKindCPP, vs2013},    // 0x00E5
+	{"Utc1800_LTCG_MSIL", enum.RichToolKindMSIL, vs2013},  // 0x00E6
+	{"Utc1800_POGO_I_C", enum.RichToolKindC, vs2013},      // 0x00E7
+	{"Utc1800_POGO_I_CPP", enum.RichToolKindCPP, vs2013},  // 0x00E8
+	{"Utc1800_POGO_O_C", enum.RichToolKindC, vs2013},      // 0x00E9
+	{"Utc1800_POGO_O_CPP", enum.RichToolKindCPP, vs2013},  // 0x00EA
+	{"AliasObj1210", enum.RichToolKindAliasObj, vs2013},   // 0x00EB
+	{"Cvtpgd1810", enum.RichToolKindCVTPGD, vs2013},       // 0x00EC
+	{"Cvtres1210", enum.RichToolKindResource, vs2013},     // 0x00ED
+	{"Export1210", enum.RichToolKindExport, vs2013},       // 0x00EE
+	{"Implib1210", enum.RichToolKindImplib, vs2013},       // 0x00EF
+	{"Linker1210", enum.RichToolKindLinker, vs2013},       // 0x00F0
+	{"Masm1210", enum.RichToolKindMASM, vs2013},           // 0x00F1
+	{"Utc1810_C", enum.RichToolKindC, vs2013},             // 0x00F2
+	{"Utc1810_CPP", enum.RichToolKindCPP, vs2013},         // 0x00F3
+	{"Utc1810_CVTCIL_C", enum.RichToolKindC, vs2013},      // 0x00F4
+	{"Utc1810_CVTCIL_CPP", enum.RichToolKindCPP, vs2013},  // 0x00F5
+	{"Utc1810_LTCG_C", enum.RichToolKindC, vs2013},        // 0x00F6
+	{"Utc1810_LTCG_CPP", enum.RichToolKindCPP, vs2013},    // 0x00F7
+	{"Utc1810_LTCG_MSIL", enum.RichToolKindMSIL, vs2013},  // 0x00F8
+	{"Utc1810_POGO_I_C", enum.RichToolKindC, vs2013},      // 0x00F9
+	{"Utc1810_POGO_I_CPP", enum.RichToolKindCPP, vs2013},  // 0x00FA
+	{"Utc1810_POGO_O_C", enum.RichToolKindC, vs2013},      // 0x00FB
+	{"Utc1810_POGO_O_CPP", enum.RichToolKindCPP, vs2013},  // 0x00FC
+	{"AliasObj1400", enum.RichToolKindAliasObj, vs2015},   // 0x00FD
+	{"Cvtpgd1900", enum.RichToolKindCVTPGD, vs2015},       // 0x00FE
+	{"Cvtres1400", enum.RichToolKindResource, vs2015},     // 0x00FF
+	{"Export1400", enum.RichToolKindExport, vs2015},       // 0x0100
+	{"Implib1400", enum.RichToolKindImplib, vs2015},       // 0x0101
+	{"Linker1400", enum.RichToolKindLinker, vs2015},       // 0x0102
+	{"Masm1400", enum.RichToolKindMASM, vs2015},           // 0x0103
+	{"Utc1900_C", enum.RichToolKindC, vs2015},             // 0x0104
+	{"Utc1900_CPP", enum.RichToolKindCPP, vs2015},         // 0x0105
+	{"Utc1900_CVTCIL_C", enum.RichToolKindC, vs2015},      // 0x0106
+	{"Utc1900_CVTCIL_CPP", enum.RichToolKindCPP, vs2015},  // 0x0107
+	{"Utc1900_LTCG_C", enum.RichToolKindC, vs2015},        // 0x0108
+	{"Utc1900_LTCG_CPP", enum.RichToolKindCPP, vs2015},    // 0x0109
+	{"Utc1900_LTCG_MSIL", enum.RichToolKindMSIL, vs2015},  // 0x010A
+	{"Utc1900_POGO_I_C", enum.RichToolKindC, vs2015},      // 0x010B
+	{"Utc1900_POGO_I_CPP", enum.RichToolKindCPP, vs2015},  // 0x010C
+	{"Utc1900_POGO_O_C", enum.RichToolKindC, vs2015},      // 0x010D
+	{"Utc1900_POGO_O_CPP", enum.RichToolKindCPP, vs2015},  // 0x010E
+}
@@ -0,0 +1,187 @@
+package pe
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/pe/enum"
+)
+
+// testdata/cli-64.exe is the 64-bit console launcher of setuptools 78.1.1
+// (MIT license), linked by the Microsoft linker of Visual Studio 2022.
+
+func TestRichHeader(t *testing.T) {
+	const path = "testdata/cli-64.exe"
+	file, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	hdr := file.RichHdr
+	if hdr == nil {
+		t.Fatalf("%q: unable to locate Rich header", path)
+	}
+	if hdr.Offset != 0x80 || hdr.Size != 112 {
+		t.Errorf("%q: Rich header location mismatch; expected offset 0x80 and size 112, got offset 0x%X and size %d", path, hdr.Offset, hdr.Size)
+	}
+	if hdr.Key != 0x31A563A3 || hdr.Checksum != 0x31A563A3 || !hdr.Valid() {
+		t.Errorf("%q: Rich header key mismatch; expected key and checksum 0x31A563A3, got key 0x%08X and checksum 0x%08X", path, hdr.Key, hdr.Checksum)
+	}
+	want := []RichEntry{
+		{ProductID: 0x0093, Build: 30729, Count: 16},
+		{ProductID: 0x0101, Build: 32420, Count: 2},
+		{ProductID: 0x00FD, Build: 32420, Count: 4},
+		{ProductID: 0x0105, Build: 32420, Count: 19},
+		{ProductID: 0x0104, Build: 32420, Count: 10},
+		{ProductID: 0x0103, Build: 32420, Count: 3},
+		{ProductID: 0x0101, Build: 30795, Count: 3},
+		{ProductID: 0x0001, Build: 0, Count: 69},
+		{ProductID: 0x0104, Build: 32532, Count: 1},
+		{ProductID: 0x00FF, Build: 32532, Count: 1},
+		{ProductID: 0x0102, Build: 32532, Count: 1},
+	}
+	if !reflect.DeepEqual(hdr.Entries, want) {
+		t.Errorf("%q: Rich header entries mismatch; expected %+v, got %+v", path, want, hdr.Entries)
+	}
+	if got := hdr.Entries[3].CompID(); got != 0x01057EA4 {
+		t.Errorf("%q: CompID mismatch; expected 0x01057EA4, got 0x%08X", path, got)
+	}
+}
+
+func TestRichHeaderTampered(t *testing.T) {
+	const path = "testdata/cli-64.exe"
+	orig, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to read file; %+v", path, err)
+	}
+	const (
+		start = 0x80       // "DanS" signature.
+		end   = 0x80 + 104 // "Rich" signature.
+		key   = 0x31A563A3
+	)
+	golden := []struct {
+		name string
+		// tamper modifies the given file contents.
+		tamper func(buf []byte)
+		// Entry count of the first entry.
+		wantCount uint32
+		wantKey   uint32
+	}{
+		{
+			name: "re-encoded with different key",
+			tamper: func(buf []byte) {
+				const newKey = 0x12345678
+				for i := start; i < end; i += 4 {
+					v := binary.LittleEndian.Uint32(buf[i:])
+					binary.LittleEndian.PutUint32(buf[i:], v^key^newKey)
+				}
+				binary.LittleEndian.PutUint32(buf[end+4:], newKey)
+			},
+			wantCount: 16,
+			wantKey:   0x12345678,
+		},
+		{
+			name: "modified entry count",
+			tamper: func(buf []byte) {
+				// Count of the first entry, following the "DanS" signature,
+				// three padding values and the CompID of the entry.
+				buf[start+20] ^= 0x01
+			},
+			wantCount: 17,
+			wantKey:   key,
+		},
+		{
+			name: "modified MS-DOS stub",
+			tamper: func(buf []byte) {
+				buf[0x40] ^= 0xFF
+			},
+			wantCount: 16,
+			wantKey:   key,
+		},
+	}
+	for _, g := range golden {
+		buf := append([]byte(nil), orig...)
+		g.tamper(buf)
+		file, err := ParseBytes(buf)
+		if err != nil {
+			t.Errorf("%q: unable to parse file; %+v", g.name, err)
+			continue
+		}
+		hdr := file.RichHdr
+		if hdr == nil {
+			t.Errorf("%q: unable to locate Rich header", g.name)
+			continue
+		}
+		if hdr.Valid() {
+			t.Errorf("%q: expected invalid Rich header; key and checksum 0x%08X", g.name, hdr.Key)
+		}
+		if hdr.Key != g.wantKey {
+			t.Errorf("%q: key mismatch; expected 0x%08X, got 0x%08X", g.name, g.wantKey, hdr.Key)
+		}
+		if len(hdr.Entries) != 11 || hdr.Entries[0].Count != g.wantCount {
+			t.Errorf("%q: Rich header entries mismatch; expected 11 entries with first count %d, got %+v", g.name, g.wantCount, hdr.Entries)
+		}
+	}
+	// Missing "DanS" signature.
+	buf := append([]byte(nil), orig...)
+	buf[start] ^= 0xFF
+	file, err := ParseBytes(buf)
+	if err != nil {
+		t.Fatalf("unable to parse file with missing DanS signature; %+v", err)
+	}
+	if file.RichHdr != nil {
+		t.Errorf("unexpected Rich header without DanS signature; %+v", file.RichHdr)
+	}
+}
+
+func TestRichEntryProduct(t *testing.T) {
+	golden := []struct {
+		entry  RichEntry
+		want   RichProduct
+		wantOK bool
+	}{
+		{entry: RichEntry{ProductID: 0x0001}, want: RichProduct{Name: "Import0", Kind: enum.RichToolKindImport}, wantOK: true},
+		// Product IDs prior to toolset 14.x identify the Visual Studio version,
+		// regardless of build number.
+		{entry: RichEntry{ProductID: 0x0093, Build: 30729}, want: RichProduct{Name: "Implib900", Kind: enum.RichToolKindImplib, VSVersion: "Visual Studio 2008"}, wantOK: true},
+		{entry: RichEntry{ProductID: 0x0091, Build: 32420}, want: RichProduct{Name: "Linker900", Kind: enum.RichToolKindLinker, VSVersion: "Visual Studio 2008"}, wantOK: true},
+		// Toolset 14.x; distinguished by build number.
+		{entry: RichEntry{ProductID: 0x0105, Build: 24215}, want: RichProduct{Name: "Utc1900_CPP", Kind: enum.RichToolKindCPP, VSVersion: "Visual Studio 2015"}, wantOK: true},
+		{entry: RichEntry{ProductID: 0x0105, Build: 26706}, want: RichProduct{Name: "Utc1900_CPP", Kind: enum.RichToolKindCPP, VSVersion: "Visual Studio 2017"}, wantOK: true},
+		{entry: RichEntry{ProductID: 0x0104, Build: 29335}, want: RichProduct{Name: "Utc1900_C", Kind: enum.RichToolKindC, VSVersion: "Visual Studio 2019"}, wantOK: true},
+		{entry: RichEntry{ProductID: 0x0102, Build: 32532}, want: RichProduct{Name: "Linker1400", Kind: enum.RichToolKindLinker, VSVersion: "Visual Studio 2022"}, wantOK: true},
+		{entry: RichEntry{ProductID: 0x0102, Build: 35717}, want: RichProduct{Name: "Linker1400", Kind: enum.RichToolKindLinker, VSVersion: "Visual Studio 2026"}, wantOK: true},
+		// Unknown product ID.
+		{entry: RichEntry{ProductID: 0xFFFF, Build: 1}, want: RichProduct{Kind: enum.RichToolKindUnknown}},
+	}
+	for _, g := range golden {
+		got, ok := g.entry.Product()
+		if ok != g.wantOK || got != g.want {
+			t.Errorf("%+v: product mismatch; expected %+v (%v), got %+v (%v)", g.entry, g.want, g.wantOK, got, ok)
+		}
+	}
+}
+
+func TestVSVersion14(t *testing.T) {
+	golden := []struct {
+		build uint16
+		want  string
+	}{
+		{build: 0, want: "Visual Studio 2015"},
+		{build: 24999, want: "Visual Studio 2015"},
+		{build: 25000, want: "Visual Studio 2017"},
+		{build: 27499, want: "Visual Studio 2017"},
+		{build: 27500, want: "Visual Studio 2019"},
+		{build: 30699, want: "Visual Studio 2019"},
+		{build: 30700, want: "Visual Studio 2022"},
+		{build: 35699, want: "Visual Studio 2022"},
+		{build: 35700, want: "Visual Studio 2026"},
+		{build: 0xFFFF, want: "Visual Studio 2026"},
+	}
+	for _, g := range golden {
+		if got := vsVersion14(g.build); got != g.want {
+			t.Errorf("build %d: Visual Studio version mismatch; expected %q, got %q", g.build, g.want, got)
+		}
+	}
+}
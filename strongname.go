@@ -0,0 +1,184 @@
+package pe
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha1"   // register SHA-1 hash function
+	_ "crypto/sha256" // register SHA-256 hash function
+	_ "crypto/sha512" // register SHA-384 and SHA-512 hash functions
+	"encoding/binary"
+	"math/big"
+
+	"github.com/mewmew/pe/enum"
+	"github.com/mewmew/pe/internal/pe"
+	"github.com/pkg/errors"
+)
+
+// --- [ Strong Name ] ---------------------------------------------------------
+
+// VerifyStrongName verifies the strong name signature of the managed PE file,
+// using the public key of the Assembly metadata table. The optional test keys
+// (in the same public key blob format as the Assembly metadata table) are used
+// to detect test-signed assemblies.
+//
+// The strong name hash covers the PE headers (with the checksum and the
+// certificate table data directory zeroed) and the contents of each section,
+// excluding the strong name signature itself.
+func (file *File) VerifyStrongName(testKeys ...[]byte) (enum.StrongNameStatus, error) {
+	if file.CLRHdr == nil || file.Metadata == nil || file.Metadata.Tables == nil {
+		return 0, errors.New("unable to locate CLR metadata; not a managed PE file")
+	}
+	sigDir := file.CLRHdr.StrongNameSignature
+	assemblies := file.Metadata.Tables.Assembly
+	if sigDir.RelAddr == 0 || sigDir.Size == 0 || len(assemblies) == 0 {
+		return enum.StrongNameStatusNotSigned, nil
+	}
+	keyBlob := file.Metadata.Blob.Get(assemblies[0].PublicKey)
+	if len(keyBlob) == 0 {
+		return enum.StrongNameStatusNotSigned, nil
+	}
+	sigOffset, err := file.fileOffset(sigDir.RelAddr)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if uint64(sigOffset)+uint64(sigDir.Size) > uint64(len(file.Content)) {
+		return 0, errors.Errorf("strong name signature out of bounds; expected end <= %d, got %d", len(file.Content), uint64(sigOffset)+uint64(sigDir.Size))
+	}
+	sig := file.Content[sigOffset : sigOffset+sigDir.Size]
+	// Delay-signed assemblies reserve space for the signature, which is left
+	// zeroed until the assembly is signed.
+	if file.CLRHdr.Flags&enum.CLRFlagStrongNameSigned == 0 || isZero(sig) {
+		return enum.StrongNameStatusDelaySigned, nil
+	}
+	// The signature is stored in little-endian byte order.
+	sig = reverse(sig)
+	verify := func(keyBlob []byte) (bool, error) {
+		pub, hashFunc, err := parseStrongNameKey(keyBlob)
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		digest, err := file.strongNameHash(hashFunc, sigOffset, sigDir.Size)
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		return rsa.VerifyPKCS1v15(pub, hashFunc, digest, sig) == nil, nil
+	}
+	ok, err := verify(keyBlob)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if ok {
+		return enum.StrongNameStatusValid, nil
+	}
+	for _, testKey := range testKeys {
+		ok, err := verify(testKey)
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		if ok {
+			return enum.StrongNameStatusTestSigned, nil
+		}
+	}
+	return enum.StrongNameStatusInvalid, nil
+}
+
+// strongNameHash returns the strong name hash of the PE file, excluding the
+// strong name signature at the given file offset.
+func (file *File) strongNameHash(hashFunc crypto.Hash, sigOffset, sigSize uint32) ([]byte, error) {
+	h := hashFunc.New()
+	// Hash PE headers, with the checksum and certificate table data directory
+	// zeroed.
+	hdrsEnd := file.sectHdrsEnd()
+	if uint64(hdrsEnd) > uint64(len(file.Content)) {
+		return nil, errors.Errorf("section headers out of bounds; expected end <= %d, got %d", len(file.Content), hdrsEnd)
+	}
+	hdrs := make([]byte, hdrsEnd)
+	copy(hdrs, file.Content)
+	zero(hdrs[file.checksumOffset():][:4])
+	if len(file.DataDirs) > 4 {
+		zero(hdrs[file.dataDirOffset(4):][:8])
+	}
+	h.Write(hdrs)
+	// Hash section contents, excluding the strong name signature.
+	sigStart, sigEnd := uint64(sigOffset), uint64(sigOffset)+uint64(sigSize)
+	for _, sectHdr := range file.SectHdrs {
+		start := uint64(sectHdr.DataOffset)
+		end := start + uint64(sectHdr.DataSize)
+		if end > uint64(len(file.Content)) {
+			return nil, errors.Errorf("contents of section %q out of bounds; expected end <= %d, got %d", sectHdr.Name, len(file.Content), end)
+		}
+		if start <= sigStart && sigEnd <= end {
+			h.Write(file.Content[start:sigStart])
+			h.Write(file.Content[sigEnd:end])
+			continue
+		}
+		h.Write(file.Content[start:end])
+	}
+	return h.Sum(nil), nil
+}
+
+// CryptoAPI algorithm identifiers.
+const (
+	calgSHA1   = 0x8004
+	calgSHA256 = 0x800C
+	calgSHA384 = 0x800D
+	calgSHA512 = 0x800E
+)
+
+// ecmaKey is the ECMA standard public key.
+//
+// ref: ECMA-335, II.6.2.1.3 PublicKeyBlob
+var ecmaKey = []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+// rsaPublicKeyMagic is the magic number of RSA public key blobs ("RSA1").
+const rsaPublicKeyMagic = 0x31415352
+
+// parseStrongNameKey parses the given strong name public key blob, returning
+// the RSA public key and hash function used by the strong name signature.
+func parseStrongNameKey(blob []byte) (*rsa.PublicKey, crypto.Hash, error) {
+	r := bytes.NewReader(blob)
+	var hdr pe.RawStrongNameKey
+	if err := binary.Read(r, binary.LittleEndian, &hdr); err != nil {
+		return nil, 0, errors.WithStack(err)
+	}
+	var hashFunc crypto.Hash
+	switch hdr.HashAlgID {
+	case 0, calgSHA1:
+		hashFunc = crypto.SHA1
+	case calgSHA256:
+		hashFunc = crypto.SHA256
+	case calgSHA384:
+		hashFunc = crypto.SHA384
+	case calgSHA512:
+		hashFunc = crypto.SHA512
+	default:
+		return nil, 0, errors.Errorf("support for strong name hash algorithm 0x%04X not yet implemented", hdr.HashAlgID)
+	}
+	// The ECMA standard public key is a placeholder, replaced by the runtime
+	// with the actual public key of the platform.
+	if bytes.Equal(blob, ecmaKey) {
+		return nil, 0, errors.New("unable to verify strong name signed with the ECMA standard public key")
+	}
+	var raw pe.RawRSAPublicKey
+	if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+		return nil, 0, errors.WithStack(err)
+	}
+	if raw.Magic != rsaPublicKeyMagic {
+		return nil, 0, errors.Errorf("invalid RSA public key magic; expected 0x%08X, got 0x%08X", rsaPublicKeyMagic, raw.Magic)
+	}
+	n := int(raw.BitLen / 8)
+	if n == 0 || n > r.Len() {
+		return nil, 0, errors.Errorf("invalid RSA modulus size; expected > 0 and <= %d bytes, got %d", r.Len(), n)
+	}
+	modulus := make([]byte, n)
+	if _, err := r.Read(modulus); err != nil {
+		return nil, 0, errors.WithStack(err)
+	}
+	pub := &rsa.PublicKey{
+		// The modulus is stored in little-endian byte order.
+		N: new(big.Int).SetBytes(reverse(modulus)),
+		E: int(raw.PubExp),
+	}
+	return pub, hashFunc, nil
+}
@@ -0,0 +1,171 @@
+package pe
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+
+	"github.com/mewmew/pe/enum"
+)
+
+func TestVerifyStrongName(t *testing.T) {
+	const path = "testdata/Microsoft.TestPlatform.PlatformAbstractions.dll"
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := ParseBytes(content)
+	if err != nil {
+		t.Fatalf("unable to parse file; %+v", err)
+	}
+	status, err := file.VerifyStrongName()
+	if err != nil {
+		t.Fatalf("unable to verify strong name signature; %+v", err)
+	}
+	if status != enum.StrongNameStatusValid {
+		t.Errorf("strong name status mismatch; expected %v, got %v", enum.StrongNameStatusValid, status)
+	}
+	sigDir := file.CLRHdr.StrongNameSignature
+	sigOffset, err := file.fileOffset(sigDir.RelAddr)
+	if err != nil {
+		t.Fatalf("unable to locate strong name signature; %+v", err)
+	}
+	// Flip one byte of the CIL code of a method body, which is part of the
+	// strong name hash.
+	method := file.Metadata.Tables.MethodDef[0]
+	codeOffset, err := file.fileOffset(method.RelAddr + 1)
+	if err != nil {
+		t.Fatalf("unable to locate method body; %+v", err)
+	}
+	golden := []struct {
+		name   string
+		modify func(buf []byte, file *File)
+		// Sign the file using a generated test key.
+		testKey bool
+		want    enum.StrongNameStatus
+	}{
+		{
+			name: "flipped section byte",
+			modify: func(buf []byte, file *File) {
+				buf[codeOffset] ^= 0xFF
+			},
+			want: enum.StrongNameStatusInvalid,
+		},
+		{
+			name: "flipped signature byte",
+			modify: func(buf []byte, file *File) {
+				buf[sigOffset+10] ^= 0xFF
+			},
+			want: enum.StrongNameStatusInvalid,
+		},
+		{
+			// The checksum is excluded from the strong name hash.
+			name: "modified checksum",
+			modify: func(buf []byte, file *File) {
+				buf[file.checksumOffset()] ^= 0xFF
+			},
+			want: enum.StrongNameStatusValid,
+		},
+		{
+			name: "delay-signed",
+			modify: func(buf []byte, file *File) {
+				zero(buf[sigOffset : sigOffset+sigDir.Size])
+			},
+			want: enum.StrongNameStatusDelaySigned,
+		},
+		{
+			name: "strong name signed flag not set",
+			modify: func(buf []byte, file *File) {
+				file.CLRHdr.Flags &^= enum.CLRFlagStrongNameSigned
+			},
+			want: enum.StrongNameStatusDelaySigned,
+		},
+		{
+			name:    "test-signed",
+			testKey: true,
+			want:    enum.StrongNameStatusTestSigned,
+		},
+	}
+	for _, g := range golden {
+		buf := append([]byte(nil), content...)
+		file, err := ParseBytes(buf)
+		if err != nil {
+			t.Fatalf("%s: unable to parse file; %+v", g.name, err)
+		}
+		if g.modify != nil {
+			g.modify(buf, file)
+		}
+		var testKeys [][]byte
+		if g.testKey {
+			testKey, err := testSign(file, sigOffset, sigDir.Size)
+			if err != nil {
+				t.Fatalf("%s: unable to test sign file; %+v", g.name, err)
+			}
+			testKeys = append(testKeys, testKey)
+			// Without the test key, the test signature does not verify.
+			status, err := file.VerifyStrongName()
+			if err != nil {
+				t.Errorf("%s: unable to verify strong name signature; %+v", g.name, err)
+			} else if status != enum.StrongNameStatusInvalid {
+				t.Errorf("%s: strong name status mismatch without test key; expected %v, got %v", g.name, enum.StrongNameStatusInvalid, status)
+			}
+		}
+		status, err := file.VerifyStrongName(testKeys...)
+		if err != nil {
+			t.Errorf("%s: unable to verify strong name signature; %+v", g.name, err)
+			continue
+		}
+		if status != g.want {
+			t.Errorf("%s: strong name status mismatch; expected %v, got %v", g.name, g.want, status)
+		}
+	}
+}
+
+// testSign signs the given file with a generated test key, storing the strong
+// name signature at the given file offset. The public key blob of the test key
+// is returned.
+func testSign(file *File, sigOffset, sigSize uint32) ([]byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, int(sigSize)*8)
+	if err != nil {
+		return nil, err
+	}
+	// Public key blob.
+	//
+	//    SigAlgID  uint32 (CALG_RSA_SIGN)
+	//    HashAlgID uint32 (CALG_SHA1)
+	//    KeySize   uint32
+	//    Type      uint8  (PUBLICKEYBLOB)
+	//    Version   uint8
+	//    Reserved  uint16
+	//    KeyAlg    uint32 (CALG_RSA_SIGN)
+	//    Magic     uint32 ("RSA1")
+	//    BitLen    uint32
+	//    PubExp    uint32
+	//    Modulus   [BitLen/8]byte (little-endian)
+	modulus := reverse(key.N.Bytes())
+	blob := make([]byte, 32+len(modulus))
+	binary.LittleEndian.PutUint32(blob[0:], 0x2400)
+	binary.LittleEndian.PutUint32(blob[4:], calgSHA1)
+	binary.LittleEndian.PutUint32(blob[8:], uint32(20+len(modulus)))
+	blob[12] = 0x06
+	blob[13] = 0x02
+	binary.LittleEndian.PutUint32(blob[16:], 0x2400)
+	binary.LittleEndian.PutUint32(blob[20:], rsaPublicKeyMagic)
+	binary.LittleEndian.PutUint32(blob[24:], uint32(len(modulus)*8))
+	binary.LittleEndian.PutUint32(blob[28:], uint32(key.E))
+	copy(blob[32:], modulus)
+	digest, err := file.strongNameHash(crypto.SHA1, sigOffset, sigSize)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, digest)
+	if err != nil {
+		return nil, err
+	}
+	// The signature is stored in little-endian byte order.
+	copy(file.Content[sigOffset:], reverse(sig))
+	return blob, nil
+}
@@ -0,0 +1,183 @@
+package pe
+
+import (
+	"bytes"
+
+	"github.com/mewmew/pe/enum"
+	"github.com/pkg/errors"
+)
+
+// --- [ COFF symbol table ] ---------------------------------------------------
+
+// Special section numbers of COFF symbols.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/pe-format#section-number-values
+const (
+	// The symbol record is not yet assigned a section. A value of zero
+	// indicates that a reference to an external symbol is defined elsewhere. A
+	// value of non-zero is a common symbol with a size that is specified by the
+	// value.
+	SectNumUndefined = 0
+	// The symbol has an absolute (non-relocatable) value and is not an address.
+	SectNumAbsolute = -1
+	// The symbol provides general type or debugging information but does not
+	// correspond to a section.
+	SectNumDebug = -2
+)
+
+// Symbol is a COFF symbol table entry.
+type Symbol struct {
+	// Index of symbol in the symbol table (auxiliary symbol records are
+	// included when counting indices).
+	Index uint32
+	// Symbol name.
+	Name string
+	// Symbol value; interpretation depends on section number and storage
+	// class.
+	Value uint32
+	// 1-based section index, or special section number (see SectNumUndefined,
+	// SectNumAbsolute and SectNumDebug).
+	SectNum int32
+	// Base type of symbol.
+	BaseType enum.SymbolBaseType
+	// Complex type of symbol.
+	ComplexType enum.SymbolComplexType
+	// Storage class.
+	StorageClass enum.StorageClass
+	// Auxiliary symbol records.
+	Aux []SymbolAux
+}
+
+// SymbolAux is an auxiliary symbol record.
+//
+// SymbolAux is one of the following types.
+//
+//    *AuxFuncDef
+//    *AuxBfEf
+//    *AuxWeakExternal
+//    *AuxFile
+//    *AuxSectionDef
+//    *AuxCLRToken
+//    *AuxRaw
+type SymbolAux interface {
+	// isSymbolAux ensures that only auxiliary symbol records can be assigned to
+	// the SymbolAux interface.
+	isSymbolAux()
+}
+
+// AuxFuncDef is a function definition auxiliary symbol record.
+type AuxFuncDef struct {
+	// Symbol table index of the corresponding .bf symbol.
+	TagIndex uint32
+	// Size of function code in number of bytes.
+	TotalSize uint32
+	// File offset of first COFF line number entry of the function; zero if
+	// none.
+	LineNumsOffset uint32
+	// Symbol table index of the next function symbol; zero if last.
+	NextFunc uint32
+}
+
+// AuxBfEf is a .bf (beginning of function) or .ef (end of function) auxiliary
+// symbol record.
+type AuxBfEf struct {
+	// Actual ordinal line number (1-based) within the source file.
+	LineNum uint16
+	// Symbol table index of the next .bf symbol; zero if last (only used by .bf
+	// symbols).
+	NextFunc uint32
+}
+
+// AuxWeakExternal is a weak external auxiliary symbol record.
+type AuxWeakExternal struct {
+	// Symbol table index of the symbol to be linked if the weak external is not
+	// found.
+	TagIndex uint32
+	// Library search characteristics.
+	Characteristics enum.WeakExternalFlag
+}
+
+// AuxFile is a file name auxiliary symbol record; spanning all auxiliary
+// symbol records of the .file symbol.
+type AuxFile struct {
+	// Source file name.
+	Name string
+}
+
+// AuxSectionDef is a section definition auxiliary symbol record.
+type AuxSectionDef struct {
+	// Size of section data in number of bytes.
+	Length uint32
+	// Number of relocation entries of the section.
+	NRelocs uint16
+	// Number of line number entries of the section.
+	NLineNums uint16
+	// Checksum of section data (used by COMDAT sections).
+	Checksum uint32
+	// 1-based section index of associated section (used by associative COMDAT
+	// sections).
+	Number uint32
+	// COMDAT selection number.
+	Selection enum.ComdatSelection
+}
+
+// AuxCLRToken is a CLR token definition auxiliary symbol record.
+type AuxCLRToken struct {
+	// Symbol table index of the symbol defined by the CLR token.
+	SymbolIndex uint32
+}
+
+// AuxRaw is an auxiliary symbol record of unknown format.
+type AuxRaw struct {
+	// Raw contents of auxiliary symbol record.
+	Data []byte
+}
+
+// isSymbolAux ensures that only auxiliary symbol records can be assigned to
+// the SymbolAux interface.
+func (*AuxFuncDef) isSymbolAux()      {}
+func (*AuxBfEf) isSymbolAux()         {}
+func (*AuxWeakExternal) isSymbolAux() {}
+func (*AuxFile) isSymbolAux()         {}
+func (*AuxSectionDef) isSymbolAux()   {}
+func (*AuxCLRToken) isSymbolAux()     {}
+func (*AuxRaw) isSymbolAux()          {}
+
+// Symbol returns the symbol with the given symbol table index, and a boolean
+// indicating success.
+func (file *File) Symbol(index uint32) (*Symbol, bool) {
+	// Symbols are stored in order of increasing index.
+	lo, hi := 0, len(file.Symbols)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch sym := &file.Symbols[mid]; {
+		case sym.Index == index:
+			return sym, true
+		case sym.Index < index:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return nil, false
+}
+
+// ~~~ [ String table ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// StringTable is a COFF string table, including the leading 4-byte size field;
+// offsets into the string table are thus relative to its start.
+type StringTable []byte
+
+// Get returns the NULL-terminated string at the given offset of the string
+// table.
+func (t StringTable) Get(offset uint32) (string, error) {
+	// The first 4 bytes of the string table contain its size.
+	if offset < 4 || uint64(offset) >= uint64(len(t)) {
+		return "", errors.Errorf("string table offset out of bounds; expected >= 4 and < %d, got %d", len(t), offset)
+	}
+	b := t[offset:]
+	if pos := bytes.IndexByte(b, '\x00'); pos != -1 {
+		b = b[:pos]
+	}
+	return string(b), nil
+}
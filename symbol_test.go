@@ -0,0 +1,210 @@
+package pe
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/mewmew/pe/enum"
+)
+
+// testdata/symtab.o was assembled from testdata/symtab.s using LLVM:
+//
+//    llvm-mc -triple x86_64-w64-windows-gnu -filetype=obj symtab.s -o symtab.o
+
+func TestSymbolTable(t *testing.T) {
+	const path = "testdata/symtab.o"
+	file, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	golden := []Symbol{
+		{Index: 0, Name: ".text", SectNum: 1, StorageClass: enum.StorageClassStatic, Aux: []SymbolAux{&AuxSectionDef{Length: 27, NRelocs: 2, Checksum: 0x1AB2B63D, Number: 1}}},
+		{Index: 2, Name: ".data", SectNum: 2, StorageClass: enum.StorageClassStatic, Aux: []SymbolAux{&AuxSectionDef{Length: 4, Checksum: 0x9DD738B9, Number: 2}}},
+		{Index: 4, Name: ".bss", SectNum: 3, StorageClass: enum.StorageClassStatic, Aux: []SymbolAux{&AuxSectionDef{Number: 3}}},
+		// COMDAT section with long name.
+		{Index: 6, Name: ".text$inline_function", SectNum: 4, StorageClass: enum.StorageClassStatic, Aux: []SymbolAux{&AuxSectionDef{Length: 6, Checksum: 0x7F8535B7, Number: 4, Selection: enum.ComdatSelectionAny}}},
+		{Index: 8, Name: "inline_function", SectNum: 4, StorageClass: enum.StorageClassExternal},
+		// Function with long name.
+		{Index: 9, Name: "add_numbers_together", SectNum: 1, ComplexType: enum.SymbolComplexTypeFunction, StorageClass: enum.StorageClassExternal},
+		{Index: 10, Name: "helper", Value: 16, SectNum: 1, ComplexType: enum.SymbolComplexTypeFunction, StorageClass: enum.StorageClassStatic},
+		{Index: 11, Name: "external_function", SectNum: SectNumUndefined, StorageClass: enum.StorageClassExternal},
+		{Index: 12, Name: "counter", SectNum: 2, StorageClass: enum.StorageClassExternal},
+		{Index: 13, Name: "weak_function", SectNum: SectNumUndefined, StorageClass: enum.StorageClassWeakExternal, Aux: []SymbolAux{&AuxWeakExternal{TagIndex: 9, Characteristics: enum.WeakExternalFlagSearchAlias}}},
+		// File name spanning two auxiliary symbol records.
+		{Index: 15, Name: ".file", SectNum: SectNumDebug, StorageClass: enum.StorageClassFile, Aux: []SymbolAux{&AuxFile{Name: "a_rather_long_source_file_name.c"}}},
+	}
+	if len(file.Symbols) != len(golden) {
+		t.Fatalf("%q: number of symbols mismatch; expected %d, got %d", path, len(golden), len(file.Symbols))
+	}
+	for i, want := range golden {
+		got := file.Symbols[i]
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: symbol %d mismatch; expected %+v, got %+v", path, i, want, got)
+		}
+		sym, ok := file.Symbol(want.Index)
+		if !ok || sym.Name != want.Name {
+			t.Errorf("%q: unable to locate symbol with index %d", path, want.Index)
+		}
+	}
+	// Auxiliary symbol record indices do not refer to symbols.
+	if _, ok := file.Symbol(1); ok {
+		t.Errorf("%q: unexpected symbol with index 1", path)
+	}
+	// String table.
+	if len(file.StrTable) != 79 {
+		t.Errorf("%q: size of string table mismatch; expected 79, got %d", path, len(file.StrTable))
+	}
+	strs := []struct {
+		offset uint32
+		want   string
+	}{
+		{offset: 4, want: "add_numbers_together"},
+		{offset: 25, want: "external_function"},
+		{offset: 43, want: "weak_function"},
+		{offset: 57, want: ".text$inline_function"},
+	}
+	for _, g := range strs {
+		s, err := file.StrTable.Get(g.offset)
+		if err != nil {
+			t.Errorf("%q: unable to read string at offset %d; %v", path, g.offset, err)
+			continue
+		}
+		if s != g.want {
+			t.Errorf("%q: string at offset %d mismatch; expected %q, got %q", path, g.offset, g.want, s)
+		}
+	}
+	for _, offset := range []uint32{0, 3, 79} {
+		if _, err := file.StrTable.Get(offset); err == nil {
+			t.Errorf("%q: expected error for string table offset %d", path, offset)
+		}
+	}
+}
+
+func TestParseSymbolsFuncDef(t *testing.T) {
+	// Function definition as emitted by MSVC with /Zd: the function symbol is
+	// followed by .bf, .lf and .ef symbols.
+	var buf []byte
+	sym := func(name string, value uint32, sectNum int16, typ uint16, storageClass enum.StorageClass, naux uint8) {
+		b := make([]byte, symbolSize)
+		copy(b, name)
+		binary.LittleEndian.PutUint32(b[8:], value)
+		binary.LittleEndian.PutUint16(b[12:], uint16(sectNum))
+		binary.LittleEndian.PutUint16(b[14:], typ)
+		b[16] = uint8(storageClass)
+		b[17] = naux
+		buf = append(buf, b...)
+	}
+	funcDef := func(tagIndex, totalSize, lineNumsOffset, nextFunc uint32) {
+		b := make([]byte, symbolSize)
+		binary.LittleEndian.PutUint32(b[0:], tagIndex)
+		binary.LittleEndian.PutUint32(b[4:], totalSize)
+		binary.LittleEndian.PutUint32(b[8:], lineNumsOffset)
+		binary.LittleEndian.PutUint32(b[12:], nextFunc)
+		buf = append(buf, b...)
+	}
+	bfEf := func(lineNum uint16, nextFunc uint32) {
+		b := make([]byte, symbolSize)
+		binary.LittleEndian.PutUint16(b[4:], lineNum)
+		binary.LittleEndian.PutUint32(b[12:], nextFunc)
+		buf = append(buf, b...)
+	}
+	const typeFunc = 0x20
+	sym("main", 0x10, 1, typeFunc, enum.StorageClassExternal, 1)
+	funcDef(2, 0x24, 0x1F0, 0)
+	sym(".bf", 0x10, 1, 0, enum.StorageClassFunction, 1)
+	bfEf(12, 0)
+	sym(".lf", 3, 1, 0, enum.StorageClassFunction, 0)
+	sym(".ef", 0x34, 1, 0, enum.StorageClassFunction, 1)
+	bfEf(15, 0)
+	syms, err := parseSymbols(buf, nil, false)
+	if err != nil {
+		t.Fatalf("unable to parse symbols; %+v", err)
+	}
+	golden := []Symbol{
+		{Index: 0, Name: "main", Value: 0x10, SectNum: 1, ComplexType: enum.SymbolComplexTypeFunction, StorageClass: enum.StorageClassExternal, Aux: []SymbolAux{&AuxFuncDef{TagIndex: 2, TotalSize: 0x24, LineNumsOffset: 0x1F0}}},
+		{Index: 2, Name: ".bf", Value: 0x10, SectNum: 1, StorageClass: enum.StorageClassFunction, Aux: []SymbolAux{&AuxBfEf{LineNum: 12}}},
+		{Index: 4, Name: ".lf", Value: 3, SectNum: 1, StorageClass: enum.StorageClassFunction},
+		{Index: 5, Name: ".ef", Value: 0x34, SectNum: 1, StorageClass: enum.StorageClassFunction, Aux: []SymbolAux{&AuxBfEf{LineNum: 15}}},
+	}
+	if !reflect.DeepEqual(syms, golden) {
+		t.Errorf("symbols mismatch; expected %+v, got %+v", golden, syms)
+	}
+	// Auxiliary symbol records past the end of the symbol table.
+	if _, err := parseSymbols(buf[:len(buf)-symbolSize], nil, false); err == nil {
+		t.Error("expected error for truncated auxiliary symbol records")
+	}
+	// Long name with missing string table.
+	long := make([]byte, symbolSize)
+	binary.LittleEndian.PutUint32(long[4:], 4)
+	if _, err := parseSymbols(long, nil, false); err == nil {
+		t.Error("expected error for long symbol name without string table")
+	}
+}
+
+func TestSymbolTableInvalid(t *testing.T) {
+	// setSymbolTable sets the file offset of the symbol table and the number
+	// of symbols in the COFF file header at the given file offset.
+	setSymbolTable := func(buf []byte, fileHdrOffset, offset, nsyms uint32) {
+		binary.LittleEndian.PutUint32(buf[fileHdrOffset+8:], offset)
+		binary.LittleEndian.PutUint32(buf[fileHdrOffset+12:], nsyms)
+	}
+	golden := []struct {
+		name string
+		// Modifies the file contents; returns the new contents.
+		modify func(buf []byte, fileHdrOffset uint32) []byte
+	}{
+		{
+			name: "symbol table out of bounds",
+			modify: func(buf []byte, fileHdrOffset uint32) []byte {
+				setSymbolTable(buf, fileHdrOffset, uint32(len(buf))-10, 1)
+				return buf
+			},
+		},
+		{
+			name: "string table out of bounds",
+			modify: func(buf []byte, fileHdrOffset uint32) []byte {
+				setSymbolTable(buf, fileHdrOffset, uint32(len(buf)), 0)
+				return append(buf, 0x00, 0x10, 0x00, 0x00)
+			},
+		},
+	}
+	for _, g := range golden {
+		// Stale or invalid symbol tables of images are not fatal.
+		const imgPath = "testdata/imphash.exe"
+		content, err := ioutil.ReadFile(imgPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		orig, err := ParseBytes(content)
+		if err != nil {
+			t.Fatalf("%q: unable to parse file; %+v", imgPath, err)
+		}
+		buf := g.modify(append([]byte(nil), content...), orig.DOSHdr.PEOffset+4)
+		file, err := ParseBytes(buf)
+		if err != nil {
+			t.Errorf("%q: %s: unable to parse file; %+v", imgPath, g.name, err)
+		} else {
+			if file.SymbolTableErr == nil {
+				t.Errorf("%q: %s: expected symbol table error", imgPath, g.name)
+			}
+			if file.Symbols != nil || file.StrTable != nil {
+				t.Errorf("%q: %s: expected nil symbol table and string table", imgPath, g.name)
+			}
+			if len(file.Imps) != len(orig.Imps) {
+				t.Errorf("%q: %s: number of imports mismatch; expected %d, got %d", imgPath, g.name, len(orig.Imps), len(file.Imps))
+			}
+		}
+		// Invalid symbol tables of object files are fatal.
+		const objPath = "testdata/symtab.o"
+		content, err = ioutil.ReadFile(objPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf = g.modify(content, 0)
+		if _, err := ParseBytes(buf); err == nil {
+			t.Errorf("%q: %s: expected error", objPath, g.name)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package symstore
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// --- [ Cabinet files ] -------------------------------------------------------
+
+// Cabinet file signature.
+var cabSignature = []byte("MSCF")
+
+// Cabinet header flags.
+const (
+	cabFlagPrevCabinet    = 0x0001
+	cabFlagNextCabinet    = 0x0002
+	cabFlagReservePresent = 0x0004
+)
+
+// Compression types of cabinet folders.
+const (
+	cabCompressNone  = 0x0000
+	cabCompressMSZIP = 0x0001
+	// Mask of compression type.
+	cabCompressMask = 0x000F
+)
+
+// mszipWindowSize is the size of the history window of MSZIP compressed data,
+// which is preserved across data blocks.
+const mszipWindowSize = 32 * 1024
+
+// cabFolder is a folder of a cabinet file.
+type cabFolder struct {
+	// File offset of first data block.
+	dataOffset uint32
+	// Number of data blocks.
+	ndata uint16
+	// Compression type.
+	compress uint16
+}
+
+// decompressCAB returns the contents of the first file of the given cabinet
+// file, as stored by symbol stores for compressed files ("_" file names).
+//
+// ref: [MS-CAB]: Cabinet File Format
+func decompressCAB(buf []byte) ([]byte, error) {
+	if !bytes.HasPrefix(buf, cabSignature) {
+		return nil, errors.Errorf("invalid cabinet file signature; expected %q", cabSignature)
+	}
+	// Cabinet header.
+	//
+	//    Signature    [4]byte
+	//    Reserved1    uint32
+	//    CabinetSize  uint32
+	//    Reserved2    uint32
+	//    FilesOffset  uint32
+	//    Reserved3    uint32
+	//    MinorVer     uint8
+	//    MajorVer     uint8
+	//    NFolders     uint16
+	//    NFiles       uint16
+	//    Flags        uint16
+	//    SetID        uint16
+	//    CabinetIndex uint16
+	d := &decoder{buf: buf, pos: 4}
+	d.read(4 + 4 + 4)
+	filesOffset := d.u32()
+	d.read(4 + 1 + 1)
+	nfolders := d.u16()
+	nfiles := d.u16()
+	flags := d.u16()
+	d.read(2 + 2)
+	var folderReserve, dataReserve int
+	if flags&cabFlagReservePresent != 0 {
+		headerReserve := d.u16()
+		folderReserve = int(d.u8())
+		dataReserve = int(d.u8())
+		d.read(int(headerReserve))
+	}
+	if flags&(cabFlagPrevCabinet|cabFlagNextCabinet) != 0 {
+		return nil, errors.New("support for multi-cabinet sets not yet implemented")
+	}
+	var folders []cabFolder
+	for i := 0; i < int(nfolders); i++ {
+		folder := cabFolder{
+			dataOffset: d.u32(),
+			ndata:      d.u16(),
+			compress:   d.u16(),
+		}
+		d.read(folderReserve)
+		folders = append(folders, folder)
+	}
+	if d.err != nil {
+		return nil, errors.Wrap(d.err, "unable to decode cabinet header")
+	}
+	if nfiles < 1 {
+		return nil, errors.New("empty cabinet file")
+	}
+	// First file entry.
+	//
+	//    Size        uint32
+	//    FolderStart uint32
+	//    FolderIndex uint16
+	//    Date        uint16
+	//    Time        uint16
+	//    Attrs       uint16
+	//    Name        string
+	d.pos = int(filesOffset)
+	size := d.u32()
+	start := d.u32()
+	folderIndex := d.u16()
+	if d.err != nil {
+		return nil, errors.Wrap(d.err, "unable to decode cabinet file entry")
+	}
+	if int(folderIndex) >= len(folders) {
+		return nil, errors.Errorf("invalid folder index 0x%04X of cabinet file entry; expected < %d", folderIndex, len(folders))
+	}
+	data, err := decompressFolder(buf, folders[folderIndex], dataReserve)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	end := uint64(start) + uint64(size)
+	if end > uint64(len(data)) {
+		return nil, errors.Errorf("cabinet file entry out of bounds; expected end <= %d, got %d", len(data), end)
+	}
+	return data[start:end], nil
+}
+
+// decompressFolder returns the decompressed contents of the given folder of the
+// cabinet file.
+func decompressFolder(buf []byte, folder cabFolder, dataReserve int) ([]byte, error) {
+	compress := folder.compress & cabCompressMask
+	switch compress {
+	case cabCompressNone, cabCompressMSZIP:
+		// supported compression type.
+	default:
+		return nil, errors.Errorf("support for cabinet compression type %d not yet implemented", compress)
+	}
+	var out []byte
+	d := &decoder{buf: buf, pos: int(folder.dataOffset)}
+	for i := 0; i < int(folder.ndata); i++ {
+		// Data block.
+		//
+		//    Checksum         uint32
+		//    Size             uint16
+		//    UncompressedSize uint16
+		//    Reserved         [dataReserve]byte
+		//    Data             [Size]byte
+		d.u32()
+		size := d.u16()
+		usize := d.u16()
+		d.read(dataReserve)
+		data := d.read(int(size))
+		if d.err != nil {
+			return nil, errors.Wrapf(d.err, "unable to decode cabinet data block %d", i)
+		}
+		if compress == cabCompressNone {
+			out = append(out, data...)
+			continue
+		}
+		// Each MSZIP data block contains a "CK" signature followed by a deflate
+		// compressed stream, which may refer to the uncompressed data of
+		// previous blocks.
+		if !bytes.HasPrefix(data, []byte("CK")) {
+			return nil, errors.Errorf("invalid MSZIP signature of cabinet data block %d", i)
+		}
+		var dict []byte
+		if len(out) > mszipWindowSize {
+			dict = out[len(out)-mszipWindowSize:]
+		} else {
+			dict = out
+		}
+		r := flate.NewReaderDict(bytes.NewReader(data[2:]), dict)
+		block := make([]byte, usize)
+		if _, err := io.ReadFull(r, block); err != nil {
+			return nil, errors.Wrapf(err, "unable to decompress cabinet data block %d", i)
+		}
+		r.Close()
+		out = append(out, block...)
+	}
+	return out, nil
+}
@@ -0,0 +1,136 @@
+package symstore
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// cabBlockSize is the maximum uncompressed size of cabinet data blocks.
+const cabBlockSize = 32 * 1024
+
+// writeCAB returns a cabinet file containing a single file with the given name
+// and contents, stored in data blocks using the given compression type.
+func writeCAB(t *testing.T, name string, data []byte, compress uint16) []byte {
+	// Data blocks.
+	blocks := &bytes.Buffer{}
+	ndata := 0
+	for off := 0; off < len(data); off += cabBlockSize {
+		end := off + cabBlockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := data[off:end]
+		payload := chunk
+		if compress == cabCompressMSZIP {
+			// Each block is compressed separately, with the previous
+			// uncompressed data as dictionary.
+			dict := data[:off]
+			if len(dict) > mszipWindowSize {
+				dict = dict[len(dict)-mszipWindowSize:]
+			}
+			buf := &bytes.Buffer{}
+			buf.WriteString("CK")
+			w, err := flate.NewWriterDict(buf, flate.BestCompression, dict)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write(chunk)
+			w.Close()
+			payload = buf.Bytes()
+		}
+		binary.Write(blocks, binary.LittleEndian, uint32(0)) // checksum
+		binary.Write(blocks, binary.LittleEndian, uint16(len(payload)))
+		binary.Write(blocks, binary.LittleEndian, uint16(len(chunk)))
+		blocks.Write(payload)
+		ndata++
+	}
+	const (
+		headerSize = 36
+		folderSize = 8
+	)
+	fileEntrySize := 16 + len(name) + 1
+	filesOffset := headerSize + folderSize
+	dataOffset := filesOffset + fileEntrySize
+	cabSize := dataOffset + blocks.Len()
+	buf := &bytes.Buffer{}
+	// Cabinet header.
+	buf.Write(cabSignature)
+	binary.Write(buf, binary.LittleEndian, uint32(0))
+	binary.Write(buf, binary.LittleEndian, uint32(cabSize))
+	binary.Write(buf, binary.LittleEndian, uint32(0))
+	binary.Write(buf, binary.LittleEndian, uint32(filesOffset))
+	binary.Write(buf, binary.LittleEndian, uint32(0))
+	buf.Write([]byte{3, 1})                           // version 1.3
+	binary.Write(buf, binary.LittleEndian, uint16(1)) // number of folders
+	binary.Write(buf, binary.LittleEndian, uint16(1)) // number of files
+	binary.Write(buf, binary.LittleEndian, uint16(0)) // flags
+	binary.Write(buf, binary.LittleEndian, uint16(0)) // set ID
+	binary.Write(buf, binary.LittleEndian, uint16(0)) // cabinet index
+	// Folder.
+	binary.Write(buf, binary.LittleEndian, uint32(dataOffset))
+	binary.Write(buf, binary.LittleEndian, uint16(ndata))
+	binary.Write(buf, binary.LittleEndian, compress)
+	// File entry.
+	binary.Write(buf, binary.LittleEndian, uint32(len(data)))
+	binary.Write(buf, binary.LittleEndian, uint32(0)) // folder start
+	binary.Write(buf, binary.LittleEndian, uint16(0)) // folder index
+	binary.Write(buf, binary.LittleEndian, uint16(0)) // date
+	binary.Write(buf, binary.LittleEndian, uint16(0)) // time
+	binary.Write(buf, binary.LittleEndian, uint16(0)) // attributes
+	buf.WriteString(name)
+	buf.WriteByte(0)
+	buf.Write(blocks.Bytes())
+	return buf.Bytes()
+}
+
+func TestDecompressCAB(t *testing.T) {
+	// Data spanning multiple data blocks, with matches across block
+	// boundaries.
+	var lines []string
+	for i := 0; i < 10000; i++ {
+		lines = append(lines, strings.Repeat("foo", i%7)+"bar")
+	}
+	large := []byte(strings.Join(lines, "\n"))
+	golden := []struct {
+		name     string
+		data     []byte
+		compress uint16
+	}{
+		{name: "stored", data: []byte("foo bar baz"), compress: cabCompressNone},
+		{name: "mszip", data: []byte("foo bar baz foo bar baz"), compress: cabCompressMSZIP},
+		{name: "mszip multi-block", data: large, compress: cabCompressMSZIP},
+		{name: "stored multi-block", data: large, compress: cabCompressNone},
+	}
+	for _, g := range golden {
+		cab := writeCAB(t, "foo.pdb", g.data, g.compress)
+		got, err := decompressCAB(cab)
+		if err != nil {
+			t.Errorf("%s: unable to decompress cabinet file; %+v", g.name, err)
+			continue
+		}
+		if !bytes.Equal(got, g.data) {
+			t.Errorf("%s: contents mismatch; expected %d bytes, got %d bytes", g.name, len(g.data), len(got))
+		}
+	}
+}
+
+func TestDecompressCABInvalid(t *testing.T) {
+	cab := writeCAB(t, "foo.pdb", []byte("foo bar baz"), cabCompressMSZIP)
+	golden := []struct {
+		name string
+		buf  []byte
+	}{
+		{name: "invalid signature", buf: append([]byte("MSCX"), cab[4:]...)},
+		{name: "truncated header", buf: cab[:20]},
+		{name: "truncated data block", buf: cab[:len(cab)-4]},
+		{name: "unsupported compression", buf: writeCAB(t, "foo.pdb", []byte("foo"), 0x0003)},
+	}
+	for _, g := range golden {
+		if _, err := decompressCAB(g.buf); err == nil {
+			t.Errorf("%s: expected error", g.name)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package symstore provides access to symbol stores, as used by symbol servers
+// to index PDB files and binaries.
+//
+// ref: https://docs.microsoft.com/en-us/windows/desktop/debug/using-symstore
+// ref: https://docs.microsoft.com/en-us/windows-hardware/drivers/debugger/symbol-path
+package symstore
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mewmew/pe"
+	"github.com/pkg/errors"
+)
+
+// Key is a symbol store key, identifying a file of a symbol store.
+//
+// Files are stored in the symbol store at "Name/ID/Name".
+type Key struct {
+	// File name (e.g. "kernel32.pdb").
+	Name string
+	// File identifier (e.g. "0B2A3C4D5E6F7A8B9C0D1E2F3A4B5C6D1").
+	ID string
+}
+
+// String returns the relative path of the file identified by the key within a
+// symbol store (e.g. "kernel32.pdb/0B2A3C4D5E6F7A8B9C0D1E2F3A4B5C6D1/kernel32.pdb").
+func (key Key) String() string {
+	return path.Join(key.Name, key.ID, key.Name)
+}
+
+// PDBKey returns the symbol store key of the PDB file of the given image, as
+// recorded by its CodeView debug information.
+//
+// The identifier of PDB 7.0 files (RSDS) is the PDB signature GUID followed by
+// the age, and of PDB 2.0 files (NB10) the PDB signature date followed by the
+// age; both in uppercase hexadecimal.
+func PDBKey(file *pe.File) (Key, error) {
+	for _, dbgData := range file.DbgData {
+		dbgCodeView, ok := dbgData.(*pe.DebugCodeView)
+		if !ok {
+			continue
+		}
+		switch info := dbgCodeView.Info.(type) {
+		case *pe.CodeViewRSDS:
+			name, err := baseName(info.PDBPath)
+			if err != nil {
+				return Key{}, errors.WithStack(err)
+			}
+			guid := strings.Replace(info.GUID.String(), "-", "", -1)
+			key := Key{
+				Name: name,
+				ID:   fmt.Sprintf("%s%X", guid, info.Age),
+			}
+			return key, nil
+		case *pe.CodeViewNB10:
+			name, err := baseName(info.PDBPath)
+			if err != nil {
+				return Key{}, errors.WithStack(err)
+			}
+			key := Key{
+				Name: name,
+				ID:   fmt.Sprintf("%08X%X", uint32(info.Date.Unix()), info.Age),
+			}
+			return key, nil
+		}
+	}
+	return Key{}, errors.New("unable to locate RSDS or NB10 CodeView debug information")
+}
+
+// BinaryKey returns the symbol store key of the given image with the given file
+// name.
+//
+// The identifier of binaries is the date stamp of the file header (8 digits)
+// followed by the size of image of the optional header; both in uppercase
+// hexadecimal.
+func BinaryKey(file *pe.File, name string) (Key, error) {
+	if file.FileHdr == nil || file.OptHdr == nil {
+		return Key{}, errors.New("unable to locate file header and optional header of image")
+	}
+	base, err := baseName(name)
+	if err != nil {
+		return Key{}, errors.WithStack(err)
+	}
+	key := Key{
+		Name: base,
+		ID:   fmt.Sprintf("%08X%X", uint32(file.FileHdr.Date.Unix()), file.OptHdr.ImageSize),
+	}
+	return key, nil
+}
+
+// baseName returns the last element of the given Windows or Unix path. An
+// error is returned if the last element is empty, "." or "..", as the path is
+// untrusted and the file name is used as a path element of the symbol store.
+func baseName(p string) (string, error) {
+	name := p
+	if pos := strings.LastIndexAny(p, `\/`); pos != -1 {
+		name = p[pos+1:]
+	}
+	switch name {
+	case "", ".", "..":
+		return "", errors.Errorf("invalid file name %q of path %q", name, p)
+	}
+	return name, nil
+}
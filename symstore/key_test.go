@@ -0,0 +1,102 @@
+package symstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mewmew/pe"
+)
+
+func TestPDBKey(t *testing.T) {
+	const path = "../testdata/Microsoft.TestPlatform.PlatformAbstractions.dll"
+	file, err := pe.ParseFile(path)
+	if err != nil {
+		t.Fatalf("%q: unable to parse file; %+v", path, err)
+	}
+	key, err := PDBKey(file)
+	if err != nil {
+		t.Fatalf("%q: unable to get PDB key; %+v", path, err)
+	}
+	const want = "Microsoft.TestPlatform.PlatformAbstractions.pdb/A154F33466B648AFBC677B6B88C145FA1/Microsoft.TestPlatform.PlatformAbstractions.pdb"
+	if key.String() != want {
+		t.Errorf("%q: PDB key mismatch; expected %q, got %q", path, want, key)
+	}
+	guid := pe.GUID{0x34, 0xF3, 0x54, 0xA1, 0xB6, 0x66, 0xAF, 0x48, 0xBC, 0x67, 0x7B, 0x6B, 0x88, 0xC1, 0x45, 0xFA}
+	golden := []struct {
+		info pe.CodeViewInfo
+		want string
+		err  bool
+	}{
+		{info: &pe.CodeViewRSDS{GUID: guid, Age: 0x1A, PDBPath: `C:\src\foo.pdb`}, want: "foo.pdb/A154F33466B648AFBC677B6B88C145FA1A/foo.pdb"},
+		{info: &pe.CodeViewRSDS{GUID: guid, Age: 1, PDBPath: "/src/foo.pdb"}, want: "foo.pdb/A154F33466B648AFBC677B6B88C145FA1/foo.pdb"},
+		{info: &pe.CodeViewNB10{Date: time.Unix(0x3A2B3C4D, 0), Age: 2, PDBPath: "foo.pdb"}, want: "foo.pdb/3A2B3C4D2/foo.pdb"},
+		// Invalid file names.
+		{info: &pe.CodeViewRSDS{GUID: guid, Age: 1, PDBPath: ""}, err: true},
+		{info: &pe.CodeViewRSDS{GUID: guid, Age: 1, PDBPath: `C:\src\`}, err: true},
+		{info: &pe.CodeViewRSDS{GUID: guid, Age: 1, PDBPath: "."}, err: true},
+		{info: &pe.CodeViewRSDS{GUID: guid, Age: 1, PDBPath: `C:\src\..`}, err: true},
+		{info: &pe.CodeViewNB10{Age: 1, PDBPath: "../.."}, err: true},
+		// No RSDS or NB10 CodeView debug information.
+		{info: &pe.CodeViewNB09{}, err: true},
+	}
+	for _, g := range golden {
+		file := &pe.File{
+			DbgData: []pe.DebugData{&pe.DebugCodeView{Info: g.info}},
+		}
+		key, err := PDBKey(file)
+		if g.err {
+			if err == nil {
+				t.Errorf("%#v: expected error, got key %q", g.info, key)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%#v: unable to get PDB key; %+v", g.info, err)
+			continue
+		}
+		if key.String() != g.want {
+			t.Errorf("%#v: PDB key mismatch; expected %q, got %q", g.info, g.want, key)
+		}
+	}
+}
+
+func TestBinaryKey(t *testing.T) {
+	file := &pe.File{
+		FileHdr: &pe.FileHeader{Date: time.Unix(0x5C3A8B2E, 0)},
+		OptHdr:  &pe.OptHeader{ImageSize: 0x1A000},
+	}
+	golden := []struct {
+		name string
+		want string
+		err  bool
+	}{
+		{name: "foo.dll", want: "foo.dll/5C3A8B2E1A000/foo.dll"},
+		{name: `C:\Windows\System32\foo.dll`, want: "foo.dll/5C3A8B2E1A000/foo.dll"},
+		// Invalid file names.
+		{name: "", err: true},
+		{name: "dir/", err: true},
+		{name: ".", err: true},
+		{name: "..", err: true},
+		{name: `foo\..`, err: true},
+	}
+	for _, g := range golden {
+		key, err := BinaryKey(file, g.name)
+		if g.err {
+			if err == nil {
+				t.Errorf("%q: expected error, got key %q", g.name, key)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unable to get binary key; %+v", g.name, err)
+			continue
+		}
+		if key.String() != g.want {
+			t.Errorf("%q: binary key mismatch; expected %q, got %q", g.name, g.want, key)
+		}
+	}
+	// Missing file header and optional header.
+	if _, err := BinaryKey(&pe.File{}, "foo.dll"); err == nil {
+		t.Error("expected error for image without file header and optional header")
+	}
+}
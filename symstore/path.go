@@ -0,0 +1,351 @@
+package symstore
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotFound is returned if a file is not present in any location of a symbol
+// path.
+var ErrNotFound = errors.New("file not found in symbol path")
+
+// SymbolPath is a symbol search path, consisting of a semicolon-separated list
+// of elements (e.g. "cache*/tmp/cache;srv*/mnt/symbols*https://example.com/symbols").
+type SymbolPath struct {
+	// Elements of the symbol path, searched in order.
+	Elems []*PathElem
+	// HTTP client used to access HTTP symbol stores; http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// PathElem is an element of a symbol path.
+//
+// Symbol server elements are specified as "srv*Store1*Store2*...", where each
+// store is either a local directory or an HTTP URL. Files located in a store
+// are copied to all preceding (downstream) local stores. Cache elements,
+// specified as "cache*Dir", add a downstream store to the symbol server
+// elements that follow. Other elements are plain directories, searched for
+// files by name.
+type PathElem struct {
+	// Symbol stores or directory.
+	Stores []string
+	// Specifies whether the element refers to symbol stores; otherwise, the
+	// element is a plain directory.
+	Server bool
+}
+
+// ParseSymbolPath parses the given symbol path.
+func ParseSymbolPath(s string) (*SymbolPath, error) {
+	symPath := &SymbolPath{}
+	var caches []string
+	for _, elem := range strings.Split(s, ";") {
+		if len(elem) == 0 {
+			continue
+		}
+		parts := strings.Split(elem, "*")
+		switch strings.ToLower(parts[0]) {
+		case "srv", "symsrv":
+			stores := parts[1:]
+			if strings.ToLower(parts[0]) == "symsrv" {
+				// Skip symbol server DLL name (e.g. "symsrv*symsrv.dll*Store").
+				if len(stores) < 1 {
+					return nil, errors.Errorf("invalid symbol path element %q; missing symbol server DLL", elem)
+				}
+				stores = stores[1:]
+			}
+			e := &PathElem{
+				Server: true,
+			}
+			e.Stores = append(e.Stores, caches...)
+			for _, store := range stores {
+				// An empty store denotes the default downstream store, which is
+				// not supported.
+				if len(store) > 0 {
+					e.Stores = append(e.Stores, store)
+				}
+			}
+			if len(e.Stores) == 0 {
+				return nil, errors.Errorf("invalid symbol path element %q; missing symbol store", elem)
+			}
+			symPath.Elems = append(symPath.Elems, e)
+		case "cache":
+			for _, dir := range parts[1:] {
+				if len(dir) > 0 {
+					caches = append(caches, dir)
+				}
+			}
+		default:
+			e := &PathElem{
+				Stores: []string{elem},
+			}
+			symPath.Elems = append(symPath.Elems, e)
+		}
+	}
+	return symPath, nil
+}
+
+// Lookup returns the contents of the file with the given symbol store key,
+// searching each element of the symbol path in order. Compressed files are
+// decompressed. ErrNotFound is returned if the file is not present.
+func (symPath *SymbolPath) Lookup(key Key) ([]byte, error) {
+	for _, elem := range symPath.Elems {
+		if !elem.Server {
+			buf, err := ioutil.ReadFile(filepath.Join(elem.Stores[0], key.Name))
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, errors.WithStack(err)
+			}
+			return buf, nil
+		}
+		for i, store := range elem.Stores {
+			buf, err := symPath.fetch(store, key)
+			if err != nil {
+				if errors.Cause(err) == ErrNotFound {
+					continue
+				}
+				return nil, errors.Wrapf(err, "unable to fetch %q from symbol store %q", key, store)
+			}
+			// Copy file to downstream stores.
+			for _, downstream := range elem.Stores[:i] {
+				if isURL(downstream) {
+					continue
+				}
+				if err := writeStoreFile(downstream, key, buf); err != nil {
+					return nil, errors.WithStack(err)
+				}
+			}
+			return buf, nil
+		}
+	}
+	return nil, errors.WithStack(ErrNotFound)
+}
+
+// fetch returns the contents of the file with the given key from the given
+// symbol store. The file is located either uncompressed, compressed ("_" file
+// name), or through a file pointer ("file.ptr") of local symbol stores.
+func (symPath *SymbolPath) fetch(store string, key Key) ([]byte, error) {
+	root := store
+	if !isURL(store) {
+		// Two-tier symbol stores, as indicated by the presence of an
+		// "index2.txt" file, use the first two characters of the file name as an
+		// additional directory level.
+		if _, err := os.Stat(filepath.Join(store, "index2.txt")); err == nil && len(key.Name) >= 2 {
+			store = filepath.Join(store, key.Name[:2])
+		}
+	}
+	dir := path.Join(key.Name, key.ID)
+	buf, err := symPath.readStoreFile(store, path.Join(dir, key.Name))
+	if err == nil {
+		return buf, nil
+	}
+	if errors.Cause(err) != ErrNotFound {
+		return nil, errors.WithStack(err)
+	}
+	buf, err = symPath.readStoreFile(store, path.Join(dir, compressedName(key.Name)))
+	if err == nil {
+		buf, err := decompressCAB(buf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to decompress %q", compressedName(key.Name))
+		}
+		return buf, nil
+	}
+	if errors.Cause(err) != ErrNotFound {
+		return nil, errors.WithStack(err)
+	}
+	// File pointers refer to paths of the local file system, and are therefore
+	// only followed for local symbol stores.
+	if isURL(store) {
+		return nil, errors.WithStack(ErrNotFound)
+	}
+	buf, err = symPath.readStoreFile(store, path.Join(dir, "file.ptr"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return readFilePtr(root, buf)
+}
+
+// readStoreFile returns the contents of the file at the given relative path
+// (slash-separated) of the given symbol store. ErrNotFound is returned if the
+// file is not present.
+func (symPath *SymbolPath) readStoreFile(store, relPath string) ([]byte, error) {
+	if !isURL(store) {
+		buf, err := ioutil.ReadFile(filepath.Join(store, filepath.FromSlash(relPath)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, errors.WithStack(ErrNotFound)
+			}
+			return nil, errors.WithStack(err)
+		}
+		return buf, nil
+	}
+	client := symPath.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	url := strings.TrimSuffix(store, "/") + "/" + relPath
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// file present.
+	case http.StatusNotFound:
+		return nil, errors.WithStack(ErrNotFound)
+	default:
+		return nil, errors.Errorf("unable to download %q; unexpected HTTP status %q", url, resp.Status)
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buf, nil
+}
+
+// readFilePtr returns the contents of the file referred to by the given file
+// pointer ("file.ptr") of the given local symbol store; containing either
+// "PATH:" followed by the path of the file, or "MSG:" followed by a message
+// explaining why the file is missing. Relative paths are relative to the
+// symbol store, and paths outside of the symbol store are rejected.
+func readFilePtr(store string, buf []byte) ([]byte, error) {
+	ptr := strings.TrimSpace(string(buf))
+	switch {
+	case strings.HasPrefix(ptr, "PATH:"):
+		p := strings.TrimPrefix(ptr, "PATH:")
+		candidates := []string{p}
+		if strings.Contains(p, `\`) {
+			candidates = append(candidates, strings.Replace(p, `\`, "/", -1))
+		}
+		for _, candidate := range candidates {
+			target, err := storePath(store, candidate)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			buf, err := ioutil.ReadFile(target)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, errors.WithStack(err)
+			}
+			return buf, nil
+		}
+		return nil, errors.Errorf("unable to locate file %q of file pointer", p)
+	case strings.HasPrefix(ptr, "MSG:"):
+		return nil, errors.Errorf("file not present in symbol store; %s", strings.TrimPrefix(ptr, "MSG:"))
+	default:
+		return nil, errors.Errorf("invalid file pointer %q", ptr)
+	}
+}
+
+// storePath returns the absolute path of the given file path, relative to the
+// given local symbol store unless absolute. An error is returned if the path is
+// located outside of the symbol store.
+func storePath(store, p string) (string, error) {
+	root, err := filepath.Abs(store)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(root, p)
+	}
+	target := filepath.Clean(p)
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("path %q of file pointer outside of symbol store %q", p, store)
+	}
+	return target, nil
+}
+
+// writeStoreFile writes the contents of the file with the given key to the
+// given local symbol store.
+func writeStoreFile(store string, key Key, buf []byte) error {
+	dst := filepath.Join(store, filepath.FromSlash(key.String()))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ioutil.WriteFile(dst, buf, 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// compressedName returns the file name of the compressed version of the given
+// file, with the last character replaced by an underscore (e.g. "foo.pd_").
+func compressedName(name string) string {
+	if len(name) == 0 {
+		return "_"
+	}
+	return name[:len(name)-1] + "_"
+}
+
+// isURL reports whether the given symbol store is an HTTP URL.
+func isURL(store string) bool {
+	s := strings.ToLower(store)
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// ### [ Helper functions ] ####################################################
+
+// decoder decodes little-endian values. Errors are sticky; once an error is
+// encountered, all subsequent reads return zero.
+type decoder struct {
+	// Contents.
+	buf []byte
+	// Current read position.
+	pos int
+	// First error encountered.
+	err error
+}
+
+// read returns the next n bytes.
+func (d *decoder) read(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.pos < 0 || d.pos+n > len(d.buf) {
+		d.err = errors.WithStack(io.ErrUnexpectedEOF)
+		return nil
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+// u8 decodes a 1-byte value.
+func (d *decoder) u8() uint8 {
+	b := d.read(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+// u16 decodes a 2-byte value.
+func (d *decoder) u16() uint16 {
+	b := d.read(2)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(b)
+}
+
+// u32 decodes a 4-byte value.
+func (d *decoder) u32() uint32 {
+	b := d.read(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
@@ -0,0 +1,250 @@
+package symstore
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseSymbolPath(t *testing.T) {
+	golden := []struct {
+		in   string
+		want []PathElem
+		err  bool
+	}{
+		{
+			in:   "/tmp/symbols",
+			want: []PathElem{{Stores: []string{"/tmp/symbols"}}},
+		},
+		{
+			in:   "srv*/mnt/symbols*https://example.com/symbols",
+			want: []PathElem{{Stores: []string{"/mnt/symbols", "https://example.com/symbols"}, Server: true}},
+		},
+		{
+			in:   "SymSrv*symsrv.dll*/mnt/symbols",
+			want: []PathElem{{Stores: []string{"/mnt/symbols"}, Server: true}},
+		},
+		{
+			// Cache elements apply to the symbol server elements that follow;
+			// empty elements and default downstream stores are ignored.
+			in: "/tmp/a;;srv**https://example.com/a;cache*/tmp/cache;srv*https://example.com/b",
+			want: []PathElem{
+				{Stores: []string{"/tmp/a"}},
+				{Stores: []string{"https://example.com/a"}, Server: true},
+				{Stores: []string{"/tmp/cache", "https://example.com/b"}, Server: true},
+			},
+		},
+		{in: "srv*", err: true},
+		{in: "symsrv", err: true},
+	}
+	for _, g := range golden {
+		symPath, err := ParseSymbolPath(g.in)
+		if g.err {
+			if err == nil {
+				t.Errorf("%q: expected error", g.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unable to parse symbol path; %v", g.in, err)
+			continue
+		}
+		var got []PathElem
+		for _, elem := range symPath.Elems {
+			got = append(got, *elem)
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("%q: symbol path mismatch; expected %+v, got %+v", g.in, g.want, got)
+		}
+	}
+}
+
+// tempDir creates a temporary directory, which is removed by the returned
+// function.
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "symstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+// writeFile writes the given contents to the given slash-separated path,
+// relative to dir.
+func writeFile(t *testing.T, dir, relPath, contents string) {
+	p := filepath.Join(dir, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLookupHTTP(t *testing.T) {
+	foo := Key{Name: "foo.pdb", ID: "0123456789ABCDEF0123456789ABCDEF1"}
+	bar := Key{Name: "bar.pdb", ID: "0123456789ABCDEF0123456789ABCDEF1"}
+	baz := Key{Name: "baz.pdb", ID: "0123456789ABCDEF0123456789ABCDEF1"}
+	// Local file referred to by the file pointer served over HTTP.
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	secret := filepath.Join(dir, "secret.txt")
+	if err := ioutil.WriteFile(secret, []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	compressed := writeCAB(t, "baz.pdb", []byte("baz contents"), cabCompressMSZIP)
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/symbols/" + foo.String():
+			w.Write([]byte("foo contents"))
+		case "/symbols/bar.pdb/" + bar.ID + "/file.ptr":
+			w.Write([]byte("PATH:" + secret))
+		case "/symbols/baz.pdb/" + baz.ID + "/baz.pd_":
+			w.Write(compressed)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	cache, cleanup := tempDir(t)
+	defer cleanup()
+	symPath, err := ParseSymbolPath("srv*" + cache + "*" + srv.URL + "/symbols")
+	if err != nil {
+		t.Fatal(err)
+	}
+	symPath.Client = srv.Client()
+	// Uncompressed file, copied to the downstream store.
+	buf, err := symPath.Lookup(foo)
+	if err != nil {
+		t.Fatalf("unable to locate %q; %+v", foo, err)
+	}
+	if string(buf) != "foo contents" {
+		t.Errorf("contents mismatch of %q; expected %q, got %q", foo, "foo contents", buf)
+	}
+	cached, err := ioutil.ReadFile(filepath.Join(cache, filepath.FromSlash(foo.String())))
+	if err != nil {
+		t.Fatalf("unable to read %q from downstream store; %v", foo, err)
+	}
+	if string(cached) != "foo contents" {
+		t.Errorf("contents mismatch of cached %q; expected %q, got %q", foo, "foo contents", cached)
+	}
+	// Cached file is located without accessing the HTTP store.
+	n := len(requests)
+	if _, err := symPath.Lookup(foo); err != nil {
+		t.Fatalf("unable to locate cached %q; %+v", foo, err)
+	}
+	if len(requests) != n {
+		t.Errorf("unexpected HTTP requests for cached file; %v", requests[n:])
+	}
+	// Compressed file.
+	buf, err = symPath.Lookup(baz)
+	if err != nil {
+		t.Fatalf("unable to locate %q; %+v", baz, err)
+	}
+	if string(buf) != "baz contents" {
+		t.Errorf("contents mismatch of %q; expected %q, got %q", baz, "baz contents", buf)
+	}
+	// File pointers of HTTP stores are not followed.
+	n = len(requests)
+	if _, err := symPath.Lookup(bar); errors.Cause(err) != ErrNotFound {
+		t.Errorf("expected ErrNotFound for file pointer of HTTP store, got %v", err)
+	}
+	for _, req := range requests[n:] {
+		if strings.HasSuffix(req, "/file.ptr") {
+			t.Errorf("unexpected request of file pointer %q from HTTP store", req)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(cache, filepath.FromSlash(bar.String()))); !os.IsNotExist(err) {
+		t.Errorf("unexpected copy of %q in downstream store", bar)
+	}
+}
+
+func TestLookupTwoTier(t *testing.T) {
+	key := Key{Name: "foo.pdb", ID: "0123456789ABCDEF0123456789ABCDEF1"}
+	store, cleanup := tempDir(t)
+	defer cleanup()
+	writeFile(t, store, "index2.txt", "")
+	writeFile(t, store, "fo/"+key.String(), "foo contents")
+	symPath, err := ParseSymbolPath("srv*" + store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := symPath.Lookup(key)
+	if err != nil {
+		t.Fatalf("unable to locate %q in two-tier store; %+v", key, err)
+	}
+	if string(buf) != "foo contents" {
+		t.Errorf("contents mismatch of %q; expected %q, got %q", key, "foo contents", buf)
+	}
+	// Files of two-tier stores are not located at the top level.
+	flat, cleanup := tempDir(t)
+	defer cleanup()
+	writeFile(t, flat, key.String(), "foo contents")
+	writeFile(t, flat, "index2.txt", "")
+	symPath, err = ParseSymbolPath("srv*" + flat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := symPath.Lookup(key); errors.Cause(err) != ErrNotFound {
+		t.Errorf("expected ErrNotFound for top level file of two-tier store, got %v", err)
+	}
+}
+
+func TestLookupFilePtr(t *testing.T) {
+	store, cleanup := tempDir(t)
+	defer cleanup()
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	outside := filepath.Join(dir, "outside.pdb")
+	if err := ioutil.WriteFile(outside, []byte("outside"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	const id = "0123456789ABCDEF0123456789ABCDEF1"
+	writeFile(t, store, "files/foo.pdb", "foo contents")
+	writeFile(t, store, "foo.pdb/"+id+"/file.ptr", "PATH:"+filepath.Join(store, "files", "foo.pdb"))
+	writeFile(t, store, "bar.pdb/"+id+"/file.ptr", `PATH:files\foo.pdb`)
+	writeFile(t, store, "baz.pdb/"+id+"/file.ptr", "PATH:"+outside)
+	writeFile(t, store, "qux.pdb/"+id+"/file.ptr", "PATH:../../"+filepath.Base(filepath.Dir(outside))+"/outside.pdb")
+	writeFile(t, store, "quux.pdb/"+id+"/file.ptr", "MSG:file removed")
+	symPath, err := ParseSymbolPath("srv*" + store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := []struct {
+		name string
+		want string
+		err  bool
+	}{
+		{name: "foo.pdb", want: "foo contents"},
+		{name: "bar.pdb", want: "foo contents"},
+		{name: "baz.pdb", err: true},
+		{name: "qux.pdb", err: true},
+		{name: "quux.pdb", err: true},
+	}
+	for _, g := range golden {
+		key := Key{Name: g.name, ID: id}
+		buf, err := symPath.Lookup(key)
+		if g.err {
+			if err == nil {
+				t.Errorf("%q: expected error, got %q", key, buf)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unable to locate file; %+v", key, err)
+			continue
+		}
+		if string(buf) != g.want {
+			t.Errorf("%q: contents mismatch; expected %q, got %q", key, g.want, buf)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+	.file	"a_rather_long_source_file_name.c"
+	.text
+	.def	add_numbers_together;
+	.scl	2;
+	.type	32;
+	.endef
+	.globl	add_numbers_together
+	.p2align	4, 0x90
+add_numbers_together:
+	leal	(%rcx,%rdx), %eax
+	retq
+
+	.def	helper;
+	.scl	3;
+	.type	32;
+	.endef
+	.p2align	4, 0x90
+helper:
+	callq	add_numbers_together
+	callq	external_function
+	retq
+
+	.section	.text$inline_function,"xr",discard,inline_function
+	.globl	inline_function
+inline_function:
+	movl	$42, %eax
+	retq
+
+	.data
+	.globl	counter
+	.p2align	2
+counter:
+	.long	7
+
+	.weak	weak_function
+weak_function = add_numbers_together
@@ -0,0 +1,76 @@
+package upx
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// --- [ Filters ] -------------------------------------------------------------
+
+// unfilter reverts the given UPX filter of x86 code, with the given call trick
+// offset (cto) and base value added to the position of calls and jumps.
+//
+// UPX filters x86 code before compression by converting the relative
+// displacements of CALL (E8) and JMP (E9) instructions into absolute addresses,
+// optionally stored in big-endian byte order and marked by the high byte (cto),
+// to improve compression.
+//
+// Supported filters:
+//
+//	0x11  CALL
+//	0x12  JMP
+//	0x13  CALL and JMP
+//	0x14  CALL (big-endian)
+//	0x15  JMP (big-endian)
+//	0x16  CALL and JMP (big-endian)
+//	0x24  CALL (big-endian, cto)
+//	0x25  JMP (big-endian, cto)
+//	0x26  CALL and JMP (big-endian, cto)
+//	0x49  CALL, JMP and Jcc (big-endian, cto)
+//
+// ref: https://github.com/upx/upx/tree/devel/src/filter
+func unfilter(id, cto uint8, buf []byte, addvalue uint32) error {
+	var call, jmp, jcc, bswap, marked bool
+	switch id {
+	case 0x11, 0x12, 0x13:
+		call, jmp = id != 0x12, id != 0x11
+	case 0x14, 0x15, 0x16:
+		call, jmp, bswap = id != 0x15, id != 0x14, true
+	case 0x24, 0x25, 0x26:
+		call, jmp, bswap, marked = id != 0x25, id != 0x24, true, true
+	case 0x49:
+		call, jmp, jcc, bswap, marked = true, true, true, true, true
+	default:
+		return errors.Errorf("support for UPX filter 0x%02X not yet implemented", id)
+	}
+	if len(buf) < 5 {
+		return nil
+	}
+	lastcall := -1
+	for i := 0; i < len(buf)-5; i++ {
+		b := buf[i]
+		switch {
+		case b == 0xE8 && call, b == 0xE9 && jmp:
+		case jcc && 0x80 <= b && b <= 0x8F && i > 0 && buf[i-1] == 0x0F && lastcall != i:
+		default:
+			continue
+		}
+		if marked && buf[i+1] != cto {
+			continue
+		}
+		var v uint32
+		if bswap {
+			v = binary.BigEndian.Uint32(buf[i+1:])
+		} else {
+			v = binary.LittleEndian.Uint32(buf[i+1:])
+		}
+		if marked {
+			v -= uint32(cto) << 24
+		}
+		binary.LittleEndian.PutUint32(buf[i+1:], v-uint32(i+1)-addvalue)
+		i += 4
+		lastcall = i + 1
+	}
+	return nil
+}
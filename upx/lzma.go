@@ -0,0 +1,377 @@
+package upx
+
+import (
+	"github.com/pkg/errors"
+)
+
+// --- [ LZMA decompression ] --------------------------------------------------
+
+// decompressLZMA decompresses the given LZMA compressed data of UPX into dst.
+// The length of dst specifies the size of the decompressed data.
+//
+// UPX stores a 2-byte header of LZMA properties, followed by a raw LZMA stream
+// (without dictionary size, uncompressed size or end marker). The first byte of
+// the header holds ((lc+lp)<<3 | pb) and the second byte (lp<<4 | lc).
+//
+// ref: https://github.com/upx/upx/blob/devel/src/compress/compress_lzma.cpp
+func decompressLZMA(src, dst []byte) error {
+	if len(src) < 2 {
+		return errors.Errorf("LZMA compressed data too short; expected >= 2 bytes, got %d", len(src))
+	}
+	pb := uint(src[0] & 7)
+	lp := uint(src[1] >> 4)
+	lc := uint(src[1] & 0x0F)
+	if pb > 4 || lp > 4 || lc > 8 {
+		return errors.Errorf("invalid LZMA properties (lc=%d, lp=%d, pb=%d)", lc, lp, pb)
+	}
+	d := &lzmaDecoder{lc: lc, lp: lp, pb: pb, out: dst}
+	if err := d.rc.init(src[2:]); err != nil {
+		return errors.WithStack(err)
+	}
+	d.init()
+	return d.decode()
+}
+
+// Number of bits of probability model.
+const (
+	lzmaProbBits = 11
+	lzmaProbInit = 1 << (lzmaProbBits - 1)
+)
+
+// rangeDecoder is the range decoder of LZMA.
+type rangeDecoder struct {
+	// Compressed data.
+	src []byte
+	// Current read position.
+	pos int
+	// Range and code of decoder.
+	rng, code uint32
+	// Specifies whether the end of the compressed data has been reached
+	// prematurely.
+	eof bool
+}
+
+// init initializes the range decoder with the given compressed data.
+func (rc *rangeDecoder) init(src []byte) error {
+	rc.src = src
+	rc.rng = 0xFFFFFFFF
+	if rc.getbyte() != 0 {
+		return errors.New("invalid first byte of LZMA range coder")
+	}
+	for i := 0; i < 4; i++ {
+		rc.code = rc.code<<8 | uint32(rc.getbyte())
+	}
+	if rc.eof {
+		return errors.New("unexpected end of LZMA compressed data")
+	}
+	return nil
+}
+
+// getbyte returns the next byte of the compressed data.
+func (rc *rangeDecoder) getbyte() byte {
+	if rc.pos >= len(rc.src) {
+		rc.eof = true
+		return 0
+	}
+	b := rc.src[rc.pos]
+	rc.pos++
+	return b
+}
+
+// normalize normalizes the range of the decoder.
+func (rc *rangeDecoder) normalize() {
+	if rc.rng < 1<<24 {
+		rc.rng <<= 8
+		rc.code = rc.code<<8 | uint32(rc.getbyte())
+	}
+}
+
+// decodeBit decodes a bit using the given probability model.
+func (rc *rangeDecoder) decodeBit(prob *uint16) uint32 {
+	bound := (rc.rng >> lzmaProbBits) * uint32(*prob)
+	var bit uint32
+	if rc.code < bound {
+		*prob += (1<<lzmaProbBits - *prob) >> 5
+		rc.rng = bound
+	} else {
+		*prob -= *prob >> 5
+		rc.code -= bound
+		rc.rng -= bound
+		bit = 1
+	}
+	rc.normalize()
+	return bit
+}
+
+// decodeDirect decodes the given number of bits with fixed probabilities.
+func (rc *rangeDecoder) decodeDirect(n uint) uint32 {
+	var v uint32
+	for ; n > 0; n-- {
+		rc.rng >>= 1
+		rc.code -= rc.rng
+		t := 0 - (rc.code >> 31)
+		rc.code += rc.rng & t
+		v = v<<1 + t + 1
+		rc.normalize()
+	}
+	return v
+}
+
+// decodeTree decodes a value of the given number of bits, most significant bit
+// first, using the given bit tree of probability models.
+func (rc *rangeDecoder) decodeTree(probs []uint16, n uint) uint32 {
+	m := uint32(1)
+	for i := uint(0); i < n; i++ {
+		m = m<<1 + rc.decodeBit(&probs[m])
+	}
+	return m - 1<<n
+}
+
+// decodeReverseTree decodes a value of the given number of bits, least
+// significant bit first, using the given bit tree of probability models.
+func (rc *rangeDecoder) decodeReverseTree(probs []uint16, n uint) uint32 {
+	m := uint32(1)
+	var v uint32
+	for i := uint(0); i < n; i++ {
+		bit := rc.decodeBit(&probs[m])
+		m = m<<1 + bit
+		v |= bit << i
+	}
+	return v
+}
+
+// lenDecoder decodes match lengths of LZMA.
+type lenDecoder struct {
+	choice, choice2 uint16
+	low, mid        [1 << 4][1 << 3]uint16
+	high            [1 << 8]uint16
+}
+
+// init initializes the probability models of the length decoder.
+func (ld *lenDecoder) init() {
+	ld.choice = lzmaProbInit
+	ld.choice2 = lzmaProbInit
+	initProbs(ld.high[:])
+	for i := range ld.low {
+		initProbs(ld.low[i][:])
+		initProbs(ld.mid[i][:])
+	}
+}
+
+// decode decodes a match length (minus 2) for the given position state.
+func (ld *lenDecoder) decode(rc *rangeDecoder, posState uint32) uint32 {
+	if rc.decodeBit(&ld.choice) == 0 {
+		return rc.decodeTree(ld.low[posState][:], 3)
+	}
+	if rc.decodeBit(&ld.choice2) == 0 {
+		return 8 + rc.decodeTree(ld.mid[posState][:], 3)
+	}
+	return 16 + rc.decodeTree(ld.high[:], 8)
+}
+
+// LZMA constants.
+const (
+	lzmaNumStates        = 12
+	lzmaNumLenToPosState = 4
+	lzmaEndPosModelIndex = 14
+	lzmaNumFullDistances = 1 << (lzmaEndPosModelIndex >> 1)
+	lzmaNumAlignBits     = 4
+	lzmaMatchMinLen      = 2
+)
+
+// lzmaDecoder is a decoder of raw LZMA streams.
+type lzmaDecoder struct {
+	// Literal context bits, literal position bits and position bits.
+	lc, lp, pb uint
+	// Range decoder.
+	rc rangeDecoder
+	// Decompressed data.
+	out []byte
+	// Current write position.
+	pos int
+
+	// Probability models.
+	literals    []uint16
+	posSlot     [lzmaNumLenToPosState][1 << 6]uint16
+	posDecoders [1 + lzmaNumFullDistances - lzmaEndPosModelIndex]uint16
+	align       [1 << lzmaNumAlignBits]uint16
+	isMatch     [lzmaNumStates << 4]uint16
+	isRep       [lzmaNumStates]uint16
+	isRepG0     [lzmaNumStates]uint16
+	isRepG1     [lzmaNumStates]uint16
+	isRepG2     [lzmaNumStates]uint16
+	isRep0Long  [lzmaNumStates << 4]uint16
+	lenDec      lenDecoder
+	repLenDec   lenDecoder
+}
+
+// init initializes the probability models of the decoder.
+func (d *lzmaDecoder) init() {
+	d.literals = make([]uint16, 0x300<<(d.lc+d.lp))
+	initProbs(d.literals)
+	for i := range d.posSlot {
+		initProbs(d.posSlot[i][:])
+	}
+	initProbs(d.posDecoders[:])
+	initProbs(d.align[:])
+	initProbs(d.isMatch[:])
+	initProbs(d.isRep[:])
+	initProbs(d.isRepG0[:])
+	initProbs(d.isRepG1[:])
+	initProbs(d.isRepG2[:])
+	initProbs(d.isRep0Long[:])
+	d.lenDec.init()
+	d.repLenDec.init()
+}
+
+// decode decodes the LZMA stream until the decompressed data is complete.
+func (d *lzmaDecoder) decode() error {
+	var rep0, rep1, rep2, rep3 uint32
+	state := uint32(0)
+	pbMask := uint32(1)<<d.pb - 1
+	for d.pos < len(d.out) {
+		if d.rc.eof {
+			return errors.Errorf("unexpected end of LZMA compressed data at output position %d", d.pos)
+		}
+		posState := uint32(d.pos) & pbMask
+		if d.rc.decodeBit(&d.isMatch[state<<4+posState]) == 0 {
+			// Literal.
+			d.decodeLiteral(state, rep0)
+			switch {
+			case state < 4:
+				state = 0
+			case state < 10:
+				state -= 3
+			default:
+				state -= 6
+			}
+			continue
+		}
+		var length uint32
+		if d.rc.decodeBit(&d.isRep[state]) != 0 {
+			// Repeated match.
+			if d.pos == 0 {
+				return errors.New("invalid LZMA repeated match at start of stream")
+			}
+			if d.rc.decodeBit(&d.isRepG0[state]) == 0 {
+				if d.rc.decodeBit(&d.isRep0Long[state<<4+posState]) == 0 {
+					// Short repeated match of a single byte.
+					if state < 7 {
+						state = 9
+					} else {
+						state = 11
+					}
+					d.out[d.pos] = d.out[d.pos-int(rep0)-1]
+					d.pos++
+					continue
+				}
+			} else {
+				var dist uint32
+				if d.rc.decodeBit(&d.isRepG1[state]) == 0 {
+					dist = rep1
+				} else {
+					if d.rc.decodeBit(&d.isRepG2[state]) == 0 {
+						dist = rep2
+					} else {
+						dist = rep3
+						rep3 = rep2
+					}
+					rep2 = rep1
+				}
+				rep1 = rep0
+				rep0 = dist
+			}
+			length = d.repLenDec.decode(&d.rc, posState)
+			if state < 7 {
+				state = 8
+			} else {
+				state = 11
+			}
+		} else {
+			// Simple match.
+			rep3, rep2, rep1 = rep2, rep1, rep0
+			length = d.lenDec.decode(&d.rc, posState)
+			if state < 7 {
+				state = 7
+			} else {
+				state = 10
+			}
+			rep0 = d.decodeDistance(length)
+			if rep0 == 0xFFFFFFFF {
+				// End marker.
+				break
+			}
+		}
+		length += lzmaMatchMinLen
+		if uint64(rep0) >= uint64(d.pos) {
+			return errors.Errorf("invalid LZMA match distance %d at output position %d", rep0+1, d.pos)
+		}
+		if d.pos+int(length) > len(d.out) {
+			return errors.Errorf("LZMA decompressed data exceeds %d bytes", len(d.out))
+		}
+		for i := uint32(0); i < length; i++ {
+			d.out[d.pos] = d.out[d.pos-int(rep0)-1]
+			d.pos++
+		}
+	}
+	if d.pos != len(d.out) {
+		return errors.Errorf("LZMA decompressed size mismatch; expected %d, got %d", len(d.out), d.pos)
+	}
+	return nil
+}
+
+// decodeLiteral decodes a literal byte.
+func (d *lzmaDecoder) decodeLiteral(state, rep0 uint32) {
+	var prevByte uint32
+	if d.pos > 0 {
+		prevByte = uint32(d.out[d.pos-1])
+	}
+	litState := (uint32(d.pos)&(1<<d.lp-1))<<d.lc + prevByte>>(8-d.lc)
+	probs := d.literals[0x300*litState:]
+	symbol := uint32(1)
+	if state >= 7 {
+		// Literal following a match; use the byte at the match distance.
+		matchByte := uint32(d.out[d.pos-int(rep0)-1])
+		for symbol < 0x100 {
+			matchBit := (matchByte >> 7) & 1
+			matchByte <<= 1
+			bit := d.rc.decodeBit(&probs[(1+matchBit)<<8+symbol])
+			symbol = symbol<<1 | bit
+			if matchBit != bit {
+				break
+			}
+		}
+	}
+	for symbol < 0x100 {
+		symbol = symbol<<1 | d.rc.decodeBit(&probs[symbol])
+	}
+	d.out[d.pos] = byte(symbol)
+	d.pos++
+}
+
+// decodeDistance decodes a match distance (minus 1) for the given match length
+// (minus 2).
+func (d *lzmaDecoder) decodeDistance(length uint32) uint32 {
+	lenState := length
+	if lenState > lzmaNumLenToPosState-1 {
+		lenState = lzmaNumLenToPosState - 1
+	}
+	posSlot := d.rc.decodeTree(d.posSlot[lenState][:], 6)
+	if posSlot < 4 {
+		return posSlot
+	}
+	numDirectBits := uint(posSlot>>1) - 1
+	dist := (2 | posSlot&1) << numDirectBits
+	if posSlot < lzmaEndPosModelIndex {
+		return dist + d.rc.decodeReverseTree(d.posDecoders[dist-posSlot:], numDirectBits)
+	}
+	dist += d.rc.decodeDirect(numDirectBits-lzmaNumAlignBits) << lzmaNumAlignBits
+	return dist + d.rc.decodeReverseTree(d.align[:], lzmaNumAlignBits)
+}
+
+// initProbs initializes the given probability models.
+func initProbs(probs []uint16) {
+	for i := range probs {
+		probs[i] = lzmaProbInit
+	}
+}
@@ -0,0 +1,45 @@
+// Code generated by "stringer -trimprefix Method -type Method"; DO NOT EDIT.
+
+package upx
+
+import "strconv"
+
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the stringer command to generate them again.
+	var x [1]struct{}
+	_ = x[MethodNRV2B32-2]
+	_ = x[MethodNRV2B8-3]
+	_ = x[MethodNRV2B16-4]
+	_ = x[MethodNRV2D32-5]
+	_ = x[MethodNRV2D8-6]
+	_ = x[MethodNRV2D16-7]
+	_ = x[MethodNRV2E32-8]
+	_ = x[MethodNRV2E8-9]
+	_ = x[MethodNRV2E16-10]
+	_ = x[MethodLZMA-14]
+	_ = x[MethodDeflate-15]
+}
+
+const (
+	_Method_name_0 = "NRV2B32NRV2B8NRV2B16NRV2D32NRV2D8NRV2D16NRV2E32NRV2E8NRV2E16"
+	_Method_name_1 = "LZMADeflate"
+)
+
+var (
+	_Method_index_0 = [...]uint8{0, 7, 13, 20, 27, 33, 40, 47, 53, 60}
+	_Method_index_1 = [...]uint8{0, 4, 11}
+)
+
+func (i Method) String() string {
+	switch {
+	case 2 <= i && i <= 10:
+		i -= 2
+		return _Method_name_0[_Method_index_0[i]:_Method_index_0[i+1]]
+	case 14 <= i && i <= 15:
+		i -= 14
+		return _Method_name_1[_Method_index_1[i]:_Method_index_1[i+1]]
+	default:
+		return "Method(" + strconv.FormatInt(int64(i), 10) + ")"
+	}
+}
@@ -0,0 +1,191 @@
+package upx
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// --- [ NRV decompression ] ---------------------------------------------------
+
+// nrvReader reads the bit stream and bytes of NRV compressed data. Bits are
+// read most significant first from bit buffers of 8, 16 or 32 bits
+// (little-endian), interleaved with the literal bytes of the stream.
+type nrvReader struct {
+	// Compressed data.
+	src []byte
+	// Current read position.
+	pos int
+	// Size of bit buffers in bits (8, 16 or 32).
+	width uint
+	// Current bit buffer.
+	bb uint32
+	// Number of unread bits in the bit buffer.
+	bc uint
+	// Specifies whether the end of the compressed data has been reached
+	// prematurely.
+	eof bool
+}
+
+// getbit returns the next bit of the bit stream.
+func (r *nrvReader) getbit() uint32 {
+	if r.bc == 0 {
+		n := int(r.width / 8)
+		if r.pos+n > len(r.src) {
+			r.eof = true
+			return 0
+		}
+		switch r.width {
+		case 8:
+			r.bb = uint32(r.src[r.pos])
+		case 16:
+			r.bb = uint32(binary.LittleEndian.Uint16(r.src[r.pos:]))
+		default:
+			r.bb = binary.LittleEndian.Uint32(r.src[r.pos:])
+		}
+		r.pos += n
+		r.bc = r.width
+	}
+	r.bc--
+	return (r.bb >> r.bc) & 1
+}
+
+// getbyte returns the next byte of the compressed data.
+func (r *nrvReader) getbyte() uint32 {
+	if r.pos >= len(r.src) {
+		r.eof = true
+		return 0
+	}
+	b := r.src[r.pos]
+	r.pos++
+	return uint32(b)
+}
+
+// gamma returns the next Elias gamma-like encoded value of the bit stream,
+// starting from the given value.
+func (r *nrvReader) gamma(v uint32) uint32 {
+	for {
+		v = v<<1 | r.getbit()
+		if r.getbit() == 1 || r.eof || v > 0x1000002 {
+			return v
+		}
+	}
+}
+
+// nrvVariant specifies the NRV compression variant.
+type nrvVariant uint8
+
+// NRV compression variants.
+const (
+	nrv2b nrvVariant = iota
+	nrv2d
+	nrv2e
+)
+
+// decompressNRV decompresses the given NRV2B, NRV2D or NRV2E compressed data,
+// with bit buffers of the given size in bits (8, 16 or 32), into dst. The
+// length of dst specifies the size of the decompressed data.
+//
+// ref: http://www.oberhumer.com/opensource/ucl/
+func decompressNRV(variant nrvVariant, width uint, src, dst []byte) error {
+	r := &nrvReader{src: src, width: width}
+	olen := 0
+	lastOff := uint32(1)
+	for {
+		// Literals.
+		for r.getbit() == 1 {
+			if olen >= len(dst) {
+				return errors.Errorf("NRV decompressed data exceeds %d bytes", len(dst))
+			}
+			dst[olen] = byte(r.getbyte())
+			olen++
+		}
+		if r.eof {
+			return errors.Errorf("unexpected end of NRV compressed data at offset %d", r.pos)
+		}
+		// Match offset.
+		var off uint32
+		switch variant {
+		case nrv2b:
+			off = r.gamma(1)
+		default:
+			off = 1
+			for {
+				off = off<<1 | r.getbit()
+				if r.getbit() == 1 || r.eof || off > 0x1000002 {
+					break
+				}
+				off = (off-1)<<1 | r.getbit()
+			}
+		}
+		var mlen uint32
+		if off == 2 {
+			// Reuse last match offset.
+			off = lastOff
+			if variant != nrv2b {
+				mlen = r.getbit()
+			}
+		} else {
+			off = (off-3)*256 + r.getbyte()
+			if off == 0xFFFFFFFF {
+				// End of stream.
+				break
+			}
+			if variant != nrv2b {
+				mlen = (off ^ 0xFFFFFFFF) & 1
+				off >>= 1
+			}
+			off++
+			lastOff = off
+		}
+		// Match length.
+		var farOff uint32
+		switch variant {
+		case nrv2b:
+			mlen = r.getbit()<<1 | r.getbit()
+			if mlen == 0 {
+				mlen = r.gamma(1) + 2
+			}
+			farOff = 0xD00
+		case nrv2d:
+			mlen = mlen<<1 | r.getbit()
+			if mlen == 0 {
+				mlen = r.gamma(1) + 2
+			}
+			farOff = 0x500
+		case nrv2e:
+			switch {
+			case mlen != 0:
+				mlen = 1 + r.getbit()
+			case r.getbit() == 1:
+				mlen = 3 + r.getbit()
+			default:
+				mlen = r.gamma(1) + 3
+			}
+			farOff = 0x500
+		}
+		if off > farOff {
+			mlen++
+		}
+		if r.eof {
+			return errors.Errorf("unexpected end of NRV compressed data at offset %d", r.pos)
+		}
+		// Copy match (mlen+1 bytes).
+		if uint64(off) > uint64(olen) {
+			return errors.Errorf("invalid NRV match offset %d at output position %d", off, olen)
+		}
+		if uint64(olen)+uint64(mlen)+1 > uint64(len(dst)) {
+			return errors.Errorf("NRV decompressed data exceeds %d bytes", len(dst))
+		}
+		mpos := olen - int(off)
+		for i := 0; i <= int(mlen); i++ {
+			dst[olen] = dst[mpos]
+			olen++
+			mpos++
+		}
+	}
+	if olen != len(dst) {
+		return errors.Errorf("NRV decompressed size mismatch; expected %d, got %d", len(dst), olen)
+	}
+	return nil
+}
@@ -0,0 +1,1010 @@
+package upx
+
+import (
+	"encoding/binary"
+	"hash/adler32"
+	"sort"
+
+	"github.com/mewmew/pe"
+	"github.com/mewmew/pe/enum"
+	"github.com/pkg/errors"
+)
+
+// --- [ Unpacker ] ------------------------------------------------------------
+
+// Sizes in bytes of the original PE header (PE signature, COFF file header and
+// optional header) stored by UPX.
+const (
+	peHeaderSize32 = 4 + 20 + 224
+	peHeaderSize64 = 4 + 20 + 240
+)
+
+// Size in bytes of a section header.
+const sectHdrSize = 40
+
+// Magic number of PE32+ optional header.
+const magic64 = 0x020B
+
+// Data directory indices.
+const (
+	dirExport   = 0
+	dirImport   = 1
+	dirResource = 2
+	dirReloc    = 5
+)
+
+// unpacker tracks the state of unpacking a UPX packed file.
+type unpacker struct {
+	// Packed PE file.
+	file *pe.File
+	// Pack header.
+	ph *PackHeader
+	// Decompressed data; the original image (starting at rvamin), followed by
+	// extra info used to rebuild the original PE file.
+	obuf []byte
+	// Relative address of the first section of the original image.
+	rvamin uint32
+	// Original PE header (PE signature, COFF file header and optional header).
+	oh []byte
+	// Original section headers.
+	osects []byte
+	// Offset into obuf of the unread extra info.
+	extra uint32
+}
+
+// unpack decompresses the original image and rebuilds its tables.
+func (u *unpacker) unpack() error {
+	if u.file.DOSHdr == nil || u.file.FileHdr == nil || u.file.OptHdr == nil {
+		return errors.New("invalid UPX packed file; missing MS-DOS header, COFF file header or optional header of PE image")
+	}
+	content := u.file.Content
+	start := uint64(u.ph.Offset) + packHeaderSize
+	end := start + uint64(u.ph.CLen)
+	if end > uint64(len(content)) {
+		return errors.Errorf("UPX compressed data out of bounds; expected end <= %d, got %d", len(content), end)
+	}
+	u.obuf = make([]byte, u.ph.ULen)
+	if err := decompress(u.ph.Method, content[start:end], u.obuf); err != nil {
+		return errors.WithStack(err)
+	}
+	if sum := adler32.Checksum(u.obuf); sum != u.ph.UAdler {
+		return errors.Errorf("Adler-32 checksum mismatch of decompressed data; expected 0x%08X, got 0x%08X", u.ph.UAdler, sum)
+	}
+	if err := u.readHeaders(); err != nil {
+		return errors.WithStack(err)
+	}
+	if u.ph.Filter != 0 {
+		codeBase := u.ohUint32(24 + 20)
+		codeSize := u.ohUint32(24 + 4)
+		code, err := u.image(codeBase, codeSize)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := unfilter(u.ph.Filter, u.ph.FilterCTO, code, codeBase-u.rvamin); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if u.file.FileHdr.Characteristics&enum.CharacteristicRelocsStripped != 0 {
+		flags := binary.LittleEndian.Uint16(u.oh[4+18:])
+		binary.LittleEndian.PutUint16(u.oh[4+18:], flags|uint16(enum.CharacteristicRelocsStripped))
+		u.setDataDir(dirReloc, pe.DataDirectory{})
+	}
+	if err := u.rebuildImports(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := u.rebuildRelocs(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := u.rebuildExports(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := u.rebuildResources(); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// readHeaders reads the original PE header and section headers from the extra
+// info of the decompressed data.
+func (u *unpacker) readHeaders() error {
+	if len(u.obuf) < 4 {
+		return errors.Errorf("UPX decompressed data too short; expected >= 4 bytes, got %d", len(u.obuf))
+	}
+	u.extra = binary.LittleEndian.Uint32(u.obuf[len(u.obuf)-4:])
+	if uint64(u.extra)+24+2 > uint64(len(u.obuf)) {
+		return errors.Errorf("UPX extra info offset 0x%X out of bounds", u.extra)
+	}
+	size := uint32(peHeaderSize32)
+	if binary.LittleEndian.Uint16(u.obuf[u.extra+24:]) == magic64 {
+		size = peHeaderSize64
+	}
+	oh, err := u.next(size)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	u.oh = append([]byte(nil), oh...)
+	nsects := uint32(binary.LittleEndian.Uint16(u.oh[4+2:]))
+	if nsects == 0 {
+		return errors.New("invalid number of sections of original PE header; expected > 0, got 0")
+	}
+	osects, err := u.next(nsects * sectHdrSize)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	u.osects = append([]byte(nil), osects...)
+	u.rvamin = u.osect(0).relAddr
+	return nil
+}
+
+// --- [ Import table ] --------------------------------------------------------
+
+// rebuildImports rebuilds the import table of the original image, from the
+// compressed import info of UPX and the DLL names of the packed import table.
+//
+// The compressed import info consists of one entry per DLL: the offset of the
+// DLL name (relative to the packed import table) and the relative address of
+// the import address table (relative to rvamin), both 32-bit, followed by the
+// imported functions (terminated by a zero byte), each either
+//
+//	0x01 + NULL-terminated function name
+//	0xFF + 16-bit ordinal
+//	(other) + 32-bit offset of import lookup entry of packed import table
+//
+// The list of DLLs is terminated by a zero 32-bit DLL name offset.
+func (u *unpacker) rebuildImports() error {
+	dir := u.dataDir(dirImport)
+	if dir.RelAddr == 0 || dir.Size <= 20 {
+		return nil
+	}
+	idata, err := u.nextUint32()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	namesPos, err := u.nextUint32()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	imports, err := u.packedImports()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	thunkSize := uint32(4)
+	ordMask := uint64(0x80000000)
+	if u.is64() {
+		thunkSize = 8
+		ordMask = 1 << 63
+	}
+	// Compute size of DLL names.
+	var dllNamesSize uint32
+	for p := idata; ; p++ {
+		dllNameOff, err := u.uint32At(p)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if dllNameOff == 0 {
+			break
+		}
+		dllName, err := cstring(imports, dllNameOff)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dllNamesSize += uint32(len(dllName)) + 1
+		if p, err = u.skipImportFuncs(p + 8); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	dllNamesSize = alignUp(dllNamesSize, 2)
+	// Rebuild import descriptors, DLL names and import address tables.
+	dllNames := namesPos
+	importedNames := namesPos + dllNamesSize
+	importedNamesStart := importedNames
+	im := dir.RelAddr
+	for p := idata; ; p++ {
+		dllNameOff, err := u.uint32At(p)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if dllNameOff == 0 {
+			break
+		}
+		dllName, err := cstring(imports, dllNameOff)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		iatOff, err := u.uint32At(p + 4)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		iat := iatOff + u.rvamin
+		desc, err := u.image(im, 20)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dllNameAddr := binary.LittleEndian.Uint32(desc[12:])
+		if namesPos != 0 {
+			dllNameAddr = dllNames
+			dllNames += uint32(len(dllName)) + 1
+		}
+		if err := u.putString(dllNameAddr, dllName); err != nil {
+			return errors.WithStack(err)
+		}
+		binary.LittleEndian.PutUint32(desc[0:], iat)
+		binary.LittleEndian.PutUint32(desc[12:], dllNameAddr)
+		binary.LittleEndian.PutUint32(desc[16:], iat)
+		// Rebuild import address table.
+		thunkAddr := iat
+		for p += 8; ; thunkAddr += thunkSize {
+			kind, err := u.uint8At(p)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if kind == 0 {
+				break
+			}
+			thunk, err := u.image(thunkAddr, thunkSize)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			var v uint64
+			switch kind {
+			case 0x01:
+				// Import by name.
+				name, err := cstring(u.obuf, p+1)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				hintName := uint32(getThunk(thunk))
+				if namesPos != 0 {
+					// Align hint/name entries to 2 bytes.
+					if (importedNames-importedNamesStart)&1 != 0 {
+						importedNames--
+					}
+					hintName = importedNames
+					importedNames += 2 + uint32(len(name)) + 1
+				}
+				hint, err := u.image(hintName, 2)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				hint[0], hint[1] = 0, 0
+				if err := u.putString(hintName+2, name); err != nil {
+					return errors.WithStack(err)
+				}
+				v = uint64(hintName)
+				p += 1 + uint32(len(name)) + 1
+			case 0xFF:
+				// Import by ordinal.
+				ordinal, err := u.uint16At(p + 1)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				v = uint64(ordinal) | ordMask
+				p += 3
+			default:
+				// Import lookup entry of packed import table.
+				off, err := u.uint32At(p + 1)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				if uint64(off)+uint64(thunkSize) > uint64(len(imports)) {
+					return errors.Errorf("import lookup entry offset 0x%X out of bounds", off)
+				}
+				v = getThunk(imports[off : off+thunkSize])
+				p += 5
+			}
+			putThunk(thunk, v)
+		}
+		// Terminate import address table.
+		thunk, err := u.image(thunkAddr, thunkSize)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		putThunk(thunk, 0)
+		im += 20
+	}
+	// Terminate import directory.
+	if im+20 <= dir.RelAddr+dir.Size {
+		if desc, err := u.image(im, 20); err == nil {
+			for i := range desc {
+				desc[i] = 0
+			}
+		}
+	}
+	return nil
+}
+
+// skipImportFuncs skips the imported functions of a DLL in the compressed
+// import info starting at the given offset into obuf, returning the offset of
+// the terminating zero byte.
+func (u *unpacker) skipImportFuncs(p uint32) (uint32, error) {
+	for {
+		kind, err := u.uint8At(p)
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		switch kind {
+		case 0x00:
+			return p, nil
+		case 0x01:
+			name, err := cstring(u.obuf, p+1)
+			if err != nil {
+				return 0, errors.WithStack(err)
+			}
+			p += 1 + uint32(len(name)) + 1
+		case 0xFF:
+			p += 3
+		default:
+			p += 5
+		}
+	}
+}
+
+// packedImports returns the contents of the packed file starting at the import
+// table, up to the end of the third section (which holds the import table of
+// UPX packed files).
+func (u *unpacker) packedImports() ([]byte, error) {
+	if len(u.file.SectHdrs) <= 2 {
+		return nil, errors.Errorf("invalid number of sections of UPX packed file; expected >= 3, got %d", len(u.file.SectHdrs))
+	}
+	if len(u.file.DataDirs) <= dirImport {
+		return nil, errors.New("unable to locate import table of UPX packed file")
+	}
+	sectHdr := u.file.SectHdrs[2]
+	data, err := u.file.SectionData(sectHdr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	addr := u.file.DataDirs[dirImport].RelAddr
+	if addr < sectHdr.RelAddr || uint64(addr-sectHdr.RelAddr) > uint64(len(data)) {
+		return nil, errors.Errorf("packed import table at 0x%08X outside of section %q", addr, sectHdr.Name)
+	}
+	return data[addr-sectHdr.RelAddr:], nil
+}
+
+// packedData returns n bytes of the packed file at the given relative address.
+func (u *unpacker) packedData(relAddr, n uint32) ([]byte, error) {
+	for _, sectHdr := range u.file.SectHdrs {
+		if relAddr < sectHdr.RelAddr || uint64(relAddr)+uint64(n) > uint64(sectHdr.RelAddr)+uint64(sectHdr.DataSize) {
+			continue
+		}
+		data, err := u.file.SectionData(sectHdr)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		start := relAddr - sectHdr.RelAddr
+		if uint64(start)+uint64(n) > uint64(len(data)) {
+			break
+		}
+		return data[start : start+n], nil
+	}
+	return nil, errors.Errorf("unable to locate data of packed file at relative address 0x%08X (%d bytes)", relAddr, n)
+}
+
+// --- [ Base relocation table ] -----------------------------------------------
+
+// Base relocation types.
+const (
+	relocHigh    = 1
+	relocLow     = 2
+	relocHighLow = 3
+	relocDir64   = 10
+)
+
+// baseReloc is a base relocation of the original image.
+type baseReloc struct {
+	// Relative address of relocation.
+	relAddr uint32
+	// Relocation type.
+	typ uint16
+}
+
+// rebuildRelocs rebuilds the base relocation table of the original image, from
+// the compressed relocation info of UPX.
+//
+// The compressed relocation info consists of the deltas between consecutive
+// relocation offsets (relative to rvamin, starting at -4), terminated by a zero
+// byte; each either
+//
+//	0x01-0xEF                 delta
+//	0xF0-0xFF + 16-bit        delta (low nibble holds bits 16-19)
+//	0xF0 + 0x0000 + 32-bit    delta
+//
+// optionally followed by zero-terminated lists of 32-bit offsets of 16-bit
+// relocations. The relocated values of the image are stored in big-endian byte
+// order relative to image base + rvamin.
+func (u *unpacker) rebuildRelocs() error {
+	dir := u.dataDir(dirReloc)
+	flags := binary.LittleEndian.Uint16(u.oh[4+18:])
+	if dir.RelAddr == 0 || dir.Size == 0 || flags&uint16(enum.CharacteristicRelocsStripped) != 0 {
+		return nil
+	}
+	if dir.Size == 8 {
+		// Empty relocation block.
+		buf, err := u.image(dir.RelAddr, 8)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		copy(buf, []byte{0, 0, 0, 0, 8, 0, 0, 0})
+		return nil
+	}
+	p, err := u.nextUint32()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	big, err := u.next(1)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	// Decode relocation offsets.
+	var offs []uint32
+	off := uint32(0xFFFFFFFC) // -4
+	for ; ; p++ {
+		b, err := u.uint8At(p)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if b == 0 {
+			break
+		}
+		if b < 0xF0 {
+			off += uint32(b)
+		} else {
+			lo, err := u.uint16At(p + 1)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			delta := uint32(b&0x0F)<<16 | uint32(lo)
+			p += 2
+			if delta == 0 {
+				if delta, err = u.uint32At(p + 1); err != nil {
+					return errors.WithStack(err)
+				}
+				p += 4
+			}
+			off += delta
+		}
+		offs = append(offs, off)
+	}
+	p++
+	var relocs []baseReloc
+	// 16-bit relocations.
+	if big[0]&6 != 0 {
+		typ := uint16(relocHigh)
+		if big[0]&4 != 0 {
+			typ = relocLow
+		}
+		if relocs, p, err = u.relocs16(relocs, p, typ); err != nil {
+			return errors.WithStack(err)
+		}
+		if big[0]&6 == 6 {
+			if relocs, _, err = u.relocs16(relocs, p+4, relocHigh); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	// Restore relocated values.
+	ohImageBase := u.imageBase()
+	for _, off := range offs {
+		addr := u.rvamin + off
+		if u.is64() {
+			buf, err := u.image(addr, 8)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			binary.LittleEndian.PutUint64(buf, binary.BigEndian.Uint64(buf)+ohImageBase+uint64(u.rvamin))
+			relocs = append(relocs, baseReloc{relAddr: addr, typ: relocDir64})
+		} else {
+			buf, err := u.image(addr, 4)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			binary.LittleEndian.PutUint32(buf, binary.BigEndian.Uint32(buf)+uint32(ohImageBase)+u.rvamin)
+			relocs = append(relocs, baseReloc{relAddr: addr, typ: relocHighLow})
+		}
+	}
+	table := encodeRelocs(relocs)
+	buf, err := u.image(dir.RelAddr, uint32(len(table)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	copy(buf, table)
+	dir.Size = uint32(len(table))
+	u.setDataDir(dirReloc, dir)
+	return nil
+}
+
+// relocs16 appends the 16-bit relocations of the given type from the
+// zero-terminated list of 32-bit offsets at the given offset into obuf. The
+// offset of the terminating zero is returned.
+func (u *unpacker) relocs16(relocs []baseReloc, p uint32, typ uint16) ([]baseReloc, uint32, error) {
+	for ; ; p += 4 {
+		off, err := u.uint32At(p)
+		if err != nil {
+			return nil, 0, errors.WithStack(err)
+		}
+		if off == 0 {
+			return relocs, p, nil
+		}
+		relocs = append(relocs, baseReloc{relAddr: off + u.rvamin, typ: typ})
+	}
+}
+
+// encodeRelocs encodes the given base relocations as a base relocation table;
+// one block per 4K page, each padded to 32-bit alignment.
+func encodeRelocs(relocs []baseReloc) []byte {
+	sort.SliceStable(relocs, func(i, j int) bool {
+		return relocs[i].relAddr < relocs[j].relAddr
+	})
+	var table []byte
+	for i := 0; i < len(relocs); {
+		page := relocs[i].relAddr &^ 0xFFF
+		block := make([]byte, 8)
+		for ; i < len(relocs) && relocs[i].relAddr&^0xFFF == page; i++ {
+			entry := relocs[i].typ<<12 | uint16(relocs[i].relAddr&0xFFF)
+			block = append(block, byte(entry), byte(entry>>8))
+		}
+		if len(block)%4 != 0 {
+			// Pad with absolute relocation.
+			block = append(block, 0, 0)
+		}
+		binary.LittleEndian.PutUint32(block[0:], page)
+		binary.LittleEndian.PutUint32(block[4:], uint32(len(block)))
+		table = append(table, block...)
+	}
+	return table
+}
+
+// --- [ Export table ] --------------------------------------------------------
+
+// rebuildExports restores the export table of the original image, if it has
+// been moved to the packed file; relative addresses within the export table
+// are rebased to its original location.
+func (u *unpacker) rebuildExports() error {
+	dir := u.dataDir(dirExport)
+	if len(u.file.DataDirs) <= dirExport {
+		return nil
+	}
+	idir := u.file.DataDirs[dirExport]
+	if dir.Size == 0 || idir.Size == 0 || dir.RelAddr == idir.RelAddr {
+		return nil
+	}
+	if idir.Size < 40 {
+		return errors.Errorf("invalid size of packed export table; expected >= 40, got %d", idir.Size)
+	}
+	src, err := u.packedData(idir.RelAddr, idir.Size)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	buf, err := u.image(dir.RelAddr, idir.Size)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	copy(buf, src)
+	// rebase rebases the relative address at the given offset into the export
+	// table, if located within the export table.
+	rebase := func(off uint32) {
+		if uint64(off)+4 > uint64(len(buf)) {
+			return
+		}
+		addr := binary.LittleEndian.Uint32(buf[off:])
+		if idir.RelAddr <= addr && addr < idir.RelAddr+idir.Size {
+			binary.LittleEndian.PutUint32(buf[off:], addr-idir.RelAddr+dir.RelAddr)
+		}
+	}
+	nfuncs := binary.LittleEndian.Uint32(buf[20:])
+	nnames := binary.LittleEndian.Uint32(buf[24:])
+	funcs := binary.LittleEndian.Uint32(buf[28:]) - idir.RelAddr
+	names := binary.LittleEndian.Uint32(buf[32:]) - idir.RelAddr
+	for _, off := range []uint32{12, 28, 32, 36} {
+		rebase(off)
+	}
+	// Forwarder RVAs of export address table.
+	for i := uint32(0); i < nfuncs && uint64(funcs)+uint64(i)*4 < uint64(len(buf)); i++ {
+		rebase(funcs + i*4)
+	}
+	// Export name pointer table.
+	for i := uint32(0); i < nnames && uint64(names)+uint64(i)*4 < uint64(len(buf)); i++ {
+		rebase(names + i*4)
+	}
+	dir.Size = idir.Size
+	u.setDataDir(dirExport, dir)
+	return nil
+}
+
+// --- [ Resource table ] ------------------------------------------------------
+
+// Resource type of icon groups.
+const rtGroupIcon = 14
+
+// resourceLeaf is a resource data entry of the packed resource directory.
+type resourceLeaf struct {
+	// Offset of data entry (relative to the resource directory).
+	entryOff uint32
+	// Top-level resource type ID; or 0 if named.
+	typ uint32
+}
+
+// rebuildResources restores the resources of the original image.
+//
+// UPX leaves some resources (e.g. the first icon and version info)
+// uncompressed in the last section of the packed file, each preceded by the
+// 32-bit relative address of its original location. These resources are
+// copied back, and the resource directory is restored if it has been cleared
+// in the original image.
+func (u *unpacker) rebuildResources() error {
+	dir := u.dataDir(dirResource)
+	if len(u.file.DataDirs) <= dirResource {
+		return nil
+	}
+	idir := u.file.DataDirs[dirResource]
+	if dir.Size == 0 || idir.Size == 0 {
+		return nil
+	}
+	iconDirCount, err := u.nextUint16()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	last := u.file.SectHdrs[len(u.file.SectHdrs)-1]
+	data, err := u.file.SectionData(last)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if idir.RelAddr < last.RelAddr || uint64(idir.RelAddr-last.RelAddr) >= uint64(len(data)) {
+		return errors.Errorf("packed resource directory at 0x%08X outside of section %q", idir.RelAddr, last.Name)
+	}
+	rsrc := data[idir.RelAddr-last.RelAddr:]
+	var leaves []resourceLeaf
+	extent, err := walkResourceDir(rsrc, 0, 0, 0, &leaves)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	dirBuf := append([]byte(nil), rsrc[:extent]...)
+	for _, leaf := range leaves {
+		entry := dirBuf[leaf.entryOff:]
+		addr := binary.LittleEndian.Uint32(entry[0:])
+		size := binary.LittleEndian.Uint32(entry[4:])
+		if addr <= idir.RelAddr {
+			// Resource data compressed within the original image.
+			continue
+		}
+		off := uint64(addr) - uint64(last.RelAddr)
+		if off < 4 || off+uint64(size) > uint64(len(data)) {
+			return errors.Errorf("resource data at 0x%08X out of bounds", addr)
+		}
+		origAddr := binary.LittleEndian.Uint32(data[off-4:])
+		buf, err := u.image(origAddr, size)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		copy(buf, data[off:off+uint64(size)])
+		if iconDirCount != 0 && leaf.typ == rtGroupIcon && size >= 6 {
+			binary.LittleEndian.PutUint16(buf[4:], iconDirCount)
+			iconDirCount = 0
+		}
+		binary.LittleEndian.PutUint32(entry[0:], origAddr)
+	}
+	// Restore resource directory if cleared.
+	orig, err := u.image(dir.RelAddr, 16)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if binary.LittleEndian.Uint32(orig[12:]) == 0 {
+		buf, err := u.image(dir.RelAddr, uint32(len(dirBuf)))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		copy(buf, dirBuf)
+	}
+	return nil
+}
+
+// walkResourceDir walks the resource directory table at the given offset into
+// the resource directory, at the given depth (0 for the type level) and of the
+// given resource type, recording resource data entries. The end offset of the
+// furthest directory structure is returned.
+func walkResourceDir(rsrc []byte, off uint32, depth int, typ uint32, leaves *[]resourceLeaf) (uint32, error) {
+	if depth > 2 {
+		return 0, errors.New("resource directory nested too deeply")
+	}
+	if uint64(off)+16 > uint64(len(rsrc)) {
+		return 0, errors.Errorf("resource directory table at offset 0x%X out of bounds", off)
+	}
+	n := uint32(binary.LittleEndian.Uint16(rsrc[off+12:])) + uint32(binary.LittleEndian.Uint16(rsrc[off+14:]))
+	extent := off + 16 + n*8
+	if uint64(extent) > uint64(len(rsrc)) {
+		return 0, errors.Errorf("resource directory entries at offset 0x%X out of bounds", off)
+	}
+	for i := uint32(0); i < n; i++ {
+		entry := rsrc[off+16+i*8:]
+		name := binary.LittleEndian.Uint32(entry[0:])
+		child := binary.LittleEndian.Uint32(entry[4:])
+		if depth == 0 {
+			typ = name
+			if name&0x80000000 != 0 {
+				typ = 0
+			}
+		}
+		if name&0x80000000 != 0 {
+			// Resource name; 16-bit length followed by UTF-16 characters.
+			nameOff := name &^ 0x80000000
+			if uint64(nameOff)+2 > uint64(len(rsrc)) {
+				return 0, errors.Errorf("resource name at offset 0x%X out of bounds", nameOff)
+			}
+			end := nameOff + 2 + 2*uint32(binary.LittleEndian.Uint16(rsrc[nameOff:]))
+			extent = maxUint32(extent, end)
+		}
+		if child&0x80000000 != 0 {
+			end, err := walkResourceDir(rsrc, child&^0x80000000, depth+1, typ, leaves)
+			if err != nil {
+				return 0, errors.WithStack(err)
+			}
+			extent = maxUint32(extent, end)
+			continue
+		}
+		if uint64(child)+16 > uint64(len(rsrc)) {
+			return 0, errors.Errorf("resource data entry at offset 0x%X out of bounds", child)
+		}
+		*leaves = append(*leaves, resourceLeaf{entryOff: child, typ: typ})
+		extent = maxUint32(extent, child+16)
+	}
+	if uint64(extent) > uint64(len(rsrc)) {
+		return 0, errors.Errorf("resource directory extent 0x%X out of bounds", extent)
+	}
+	return extent, nil
+}
+
+// --- [ Output ] --------------------------------------------------------------
+
+// write returns the contents of the unpacked PE image; the MS-DOS header and
+// stub of the packed file, followed by the original PE header, section headers
+// and section contents, and the overlay of the packed file.
+func (u *unpacker) write() ([]byte, error) {
+	if u.file.DOSHdr == nil || u.file.OptHdr == nil {
+		return nil, errors.New("invalid UPX packed file; missing MS-DOS header or optional header of PE image")
+	}
+	content := u.file.Content
+	peOffset := u.file.DOSHdr.PEOffset
+	if uint64(peOffset) > uint64(len(content)) {
+		return nil, errors.Errorf("PE signature offset 0x%X out of bounds; expected <= %d", peOffset, len(content))
+	}
+	hdrsEnd := uint64(peOffset) + uint64(len(u.oh)) + uint64(len(u.osects))
+	fileAlign := u.ohUint32(24 + 36)
+	if fileAlign == 0 {
+		fileAlign = 1
+	}
+	nsects := len(u.osects) / sectHdrSize
+	size := hdrsEnd
+	for i := 0; i < nsects; i++ {
+		sect := u.osect(i)
+		if sect.dataOffset == 0 {
+			continue
+		}
+		if uint64(sect.dataOffset) < hdrsEnd {
+			return nil, errors.Errorf("raw data of section %d at offset 0x%X overlaps headers (end 0x%X)", i, sect.dataOffset, hdrsEnd)
+		}
+		end := uint64(sect.dataOffset) + uint64(alignUp(sect.dataSize, fileAlign))
+		if end > size {
+			size = end
+		}
+	}
+	if size > 1<<31 {
+		return nil, errors.Errorf("unpacked image too large (%d bytes)", size)
+	}
+	out := make([]byte, size)
+	copy(out, content[:peOffset])
+	copy(out[peOffset:], u.oh)
+	copy(out[uint64(peOffset)+uint64(len(u.oh)):], u.osects)
+	for i := 0; i < nsects; i++ {
+		sect := u.osect(i)
+		if sect.dataOffset == 0 {
+			continue
+		}
+		n := uint64(alignUp(sect.dataSize, fileAlign))
+		start := uint64(sect.relAddr) - uint64(u.rvamin)
+		if sect.relAddr < u.rvamin || start >= uint64(len(u.obuf)) {
+			continue
+		}
+		end := start + n
+		if end > uint64(len(u.obuf)) {
+			end = uint64(len(u.obuf))
+		}
+		copy(out[sect.dataOffset:], u.obuf[start:end])
+	}
+	// Append overlay of packed file.
+	last := u.file.SectHdrs[len(u.file.SectHdrs)-1]
+	overlay := uint64(last.DataOffset) + uint64(alignUp(last.DataSize, u.file.OptHdr.FileAlign))
+	if overlay < uint64(len(content)) {
+		out = append(out, content[overlay:]...)
+	}
+	if err := pe.UpdateChecksum(out); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return out, nil
+}
+
+// --- [ Helpers ] -------------------------------------------------------------
+
+// sectHdr is an original section header.
+type sectHdr struct {
+	// Relative address of section.
+	relAddr uint32
+	// Size of raw data.
+	dataSize uint32
+	// File offset of raw data.
+	dataOffset uint32
+}
+
+// osect returns the original section header with the given index.
+func (u *unpacker) osect(i int) sectHdr {
+	buf := u.osects[i*sectHdrSize:]
+	return sectHdr{
+		relAddr:    binary.LittleEndian.Uint32(buf[12:]),
+		dataSize:   binary.LittleEndian.Uint32(buf[16:]),
+		dataOffset: binary.LittleEndian.Uint32(buf[20:]),
+	}
+}
+
+// is64 reports whether the original image is PE32+.
+func (u *unpacker) is64() bool {
+	return len(u.oh) == peHeaderSize64
+}
+
+// ohUint32 returns the 32-bit value at the given offset into the original PE
+// header.
+func (u *unpacker) ohUint32(off int) uint32 {
+	return binary.LittleEndian.Uint32(u.oh[off:])
+}
+
+// imageBase returns the image base of the original image.
+func (u *unpacker) imageBase() uint64 {
+	if u.is64() {
+		return binary.LittleEndian.Uint64(u.oh[24+24:])
+	}
+	return uint64(u.ohUint32(24 + 28))
+}
+
+// dataDirOffset returns the offset into the original PE header of the data
+// directory with the given index.
+func (u *unpacker) dataDirOffset(idx int) int {
+	if u.is64() {
+		return 24 + 112 + idx*8
+	}
+	return 24 + 96 + idx*8
+}
+
+// dataDir returns the data directory with the given index of the original PE
+// header.
+func (u *unpacker) dataDir(idx int) pe.DataDirectory {
+	off := u.dataDirOffset(idx)
+	return pe.DataDirectory{
+		RelAddr: u.ohUint32(off),
+		Size:    u.ohUint32(off + 4),
+	}
+}
+
+// setDataDir sets the data directory with the given index of the original PE
+// header.
+func (u *unpacker) setDataDir(idx int, dir pe.DataDirectory) {
+	off := u.dataDirOffset(idx)
+	binary.LittleEndian.PutUint32(u.oh[off:], dir.RelAddr)
+	binary.LittleEndian.PutUint32(u.oh[off+4:], dir.Size)
+}
+
+// image returns n bytes of the original image at the given relative address.
+func (u *unpacker) image(relAddr, n uint32) ([]byte, error) {
+	start := uint64(relAddr) - uint64(u.rvamin)
+	if relAddr < u.rvamin || start+uint64(n) > uint64(len(u.obuf)) {
+		return nil, errors.Errorf("relative address 0x%08X (%d bytes) outside of unpacked image", relAddr, n)
+	}
+	return u.obuf[start : start+uint64(n)], nil
+}
+
+// putString stores the given NULL-terminated string at the given relative
+// address of the original image.
+func (u *unpacker) putString(relAddr uint32, s string) error {
+	buf, err := u.image(relAddr, uint32(len(s))+1)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	copy(buf, s)
+	buf[len(s)] = 0
+	return nil
+}
+
+// next returns the next n bytes of the extra info.
+func (u *unpacker) next(n uint32) ([]byte, error) {
+	if uint64(u.extra)+uint64(n) > uint64(len(u.obuf)) {
+		return nil, errors.Errorf("UPX extra info at offset 0x%X (%d bytes) out of bounds", u.extra, n)
+	}
+	buf := u.obuf[u.extra : u.extra+n]
+	u.extra += n
+	return buf, nil
+}
+
+// nextUint16 returns the next 16-bit value of the extra info.
+func (u *unpacker) nextUint16() (uint16, error) {
+	buf, err := u.next(2)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return binary.LittleEndian.Uint16(buf), nil
+}
+
+// nextUint32 returns the next 32-bit value of the extra info.
+func (u *unpacker) nextUint32() (uint32, error) {
+	buf, err := u.next(4)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return binary.LittleEndian.Uint32(buf), nil
+}
+
+// uint8At returns the 8-bit value at the given offset into obuf.
+func (u *unpacker) uint8At(off uint32) (uint8, error) {
+	if uint64(off) >= uint64(len(u.obuf)) {
+		return 0, errors.Errorf("offset 0x%X out of bounds of UPX decompressed data", off)
+	}
+	return u.obuf[off], nil
+}
+
+// uint16At returns the 16-bit value at the given offset into obuf.
+func (u *unpacker) uint16At(off uint32) (uint16, error) {
+	if uint64(off)+2 > uint64(len(u.obuf)) {
+		return 0, errors.Errorf("offset 0x%X out of bounds of UPX decompressed data", off)
+	}
+	return binary.LittleEndian.Uint16(u.obuf[off:]), nil
+}
+
+// uint32At returns the 32-bit value at the given offset into obuf.
+func (u *unpacker) uint32At(off uint32) (uint32, error) {
+	if uint64(off)+4 > uint64(len(u.obuf)) {
+		return 0, errors.Errorf("offset 0x%X out of bounds of UPX decompressed data", off)
+	}
+	return binary.LittleEndian.Uint32(u.obuf[off:]), nil
+}
+
+// cstring returns the NULL-terminated string at the given offset into buf.
+func cstring(buf []byte, off uint32) (string, error) {
+	if uint64(off) >= uint64(len(buf)) {
+		return "", errors.Errorf("string offset 0x%X out of bounds", off)
+	}
+	for i, b := range buf[off:] {
+		if b == 0 {
+			return string(buf[off : off+uint32(i)]), nil
+		}
+	}
+	return "", errors.Errorf("unterminated string at offset 0x%X", off)
+}
+
+// getThunk returns the 32- or 64-bit import thunk of the given buffer.
+func getThunk(buf []byte) uint64 {
+	if len(buf) == 8 {
+		return binary.LittleEndian.Uint64(buf)
+	}
+	return uint64(binary.LittleEndian.Uint32(buf))
+}
+
+// putThunk stores the 32- or 64-bit import thunk into the given buffer.
+func putThunk(buf []byte, v uint64) {
+	if len(buf) == 8 {
+		binary.LittleEndian.PutUint64(buf, v)
+		return
+	}
+	binary.LittleEndian.PutUint32(buf, uint32(v))
+}
+
+// alignUp rounds x up to the nearest multiple of align.
+func alignUp(x, align uint32) uint32 {
+	if align == 0 {
+		return x
+	}
+	return (x + align - 1) / align * align
+}
+
+// maxUint32 returns the larger of x and y.
+func maxUint32(x, y uint32) uint32 {
+	if x > y {
+		return x
+	}
+	return y
+}
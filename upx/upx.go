@@ -0,0 +1,275 @@
+// Package upx implements unpacking of PE files packed by UPX, without running
+// the UPX executable.
+//
+// The original PE image is restored from the pack header and the compressed
+// data of the packed file; section contents are decompressed (NRV2B, NRV2D,
+// NRV2E or LZMA) and unfiltered, after which the section table, import table,
+// base relocations, exports and resources are rebuilt. As the pack header is
+// located by structure rather than by name, samples with renamed sections or a
+// tampered "UPX!" magic are supported.
+//
+// ref: https://github.com/upx/upx/blob/devel/src/pefile.cpp
+package upx
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/mewmew/pe"
+	"github.com/pkg/errors"
+)
+
+// Unpack unpacks the given UPX packed PE file, returning the restored PE file.
+// Use UnpackBytes to access the restored PE image if it cannot be parsed.
+func Unpack(file *pe.File) (*pe.File, error) {
+	content, err := UnpackBytes(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	unpacked, err := pe.ParseBytes(content)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse unpacked PE image")
+	}
+	return unpacked, nil
+}
+
+// UnpackBytes unpacks the given UPX packed PE file, returning the contents of
+// the restored PE image.
+func UnpackBytes(file *pe.File) ([]byte, error) {
+	ph, err := FindPackHeader(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	u := &unpacker{file: file, ph: ph}
+	if err := u.unpack(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return u.write()
+}
+
+// --- [ Pack header ] ---------------------------------------------------------
+
+// packHeaderSize is the size in bytes of the pack header (version >= 10).
+const packHeaderSize = 32
+
+// packHeaderMagic is the magic number of the pack header.
+var packHeaderMagic = []byte("UPX!")
+
+//go:generate stringer -trimprefix Method -type Method
+
+// Method specifies the compression method of UPX.
+type Method uint8
+
+// Compression methods.
+const (
+	// NRV2B with 32-bit bit buffers.
+	MethodNRV2B32 Method = 2
+	// NRV2B with 8-bit bit buffers.
+	MethodNRV2B8 Method = 3
+	// NRV2B with 16-bit bit buffers.
+	MethodNRV2B16 Method = 4
+	// NRV2D with 32-bit bit buffers.
+	MethodNRV2D32 Method = 5
+	// NRV2D with 8-bit bit buffers.
+	MethodNRV2D8 Method = 6
+	// NRV2D with 16-bit bit buffers.
+	MethodNRV2D16 Method = 7
+	// NRV2E with 32-bit bit buffers.
+	MethodNRV2E32 Method = 8
+	// NRV2E with 8-bit bit buffers.
+	MethodNRV2E8 Method = 9
+	// NRV2E with 16-bit bit buffers.
+	MethodNRV2E16 Method = 10
+	// LZMA.
+	MethodLZMA Method = 14
+	// Deflate.
+	MethodDeflate Method = 15
+)
+
+// PackHeader is the pack header of a UPX packed file.
+type PackHeader struct {
+	// File offset of pack header.
+	Offset uint32
+	// Specifies whether the "UPX!" magic is present; false if the pack header
+	// was located by its checksum (e.g. tampered magic).
+	HasMagic bool
+	// Pack header version.
+	Version uint8
+	// Executable format (e.g. 9 for win32/pe, 36 for win64/pe).
+	Format uint8
+	// Compression method.
+	Method Method
+	// Compression level.
+	Level uint8
+	// Adler-32 checksum of uncompressed data.
+	UAdler uint32
+	// Adler-32 checksum of compressed data.
+	CAdler uint32
+	// Size in bytes of uncompressed data.
+	ULen uint32
+	// Size in bytes of compressed data.
+	CLen uint32
+	// Size in bytes of original file.
+	UFileSize uint32
+	// Filter ID; 0 if unfiltered.
+	Filter uint8
+	// Call trick offset of filter.
+	FilterCTO uint8
+	// Number of entries of move-to-front filter.
+	NMRU uint8
+	// Checksum of pack header.
+	Checksum uint8
+	// Computed checksum of pack header.
+	sum uint8
+}
+
+// Valid reports whether the checksum of the pack header is valid.
+func (ph *PackHeader) Valid() bool {
+	return ph.Checksum == ph.sum
+}
+
+// FindPackHeader locates and parses the pack header of the given UPX packed PE
+// file.
+//
+// The pack header is searched for near the start of the raw data of the second
+// section (and of the third section, as used by old versions of UPX). If the
+// "UPX!" magic has been tampered with, the pack header is located by its
+// checksum instead.
+func FindPackHeader(file *pe.File) (*PackHeader, error) {
+	if len(file.SectHdrs) < 3 {
+		return nil, errors.Errorf("invalid number of sections of UPX packed file; expected >= 3, got %d", len(file.SectHdrs))
+	}
+	var starts []uint32
+	if off := file.SectHdrs[1].DataOffset; off >= 64 {
+		starts = append(starts, off-64)
+	}
+	starts = append(starts, file.SectHdrs[2].DataOffset)
+	// Locate by magic.
+	for _, start := range starts {
+		buf := window(file.Content, start)
+		var candidate *PackHeader
+		for i := 0; i+packHeaderSize <= len(buf); i++ {
+			if !bytes.HasPrefix(buf[i:], packHeaderMagic) {
+				continue
+			}
+			ph := parsePackHeader(buf[i:], start+uint32(i))
+			if ph.Valid() {
+				return ph, nil
+			}
+			if candidate == nil {
+				candidate = ph
+			}
+		}
+		if candidate != nil {
+			return candidate, nil
+		}
+	}
+	// Locate by checksum.
+	for _, start := range starts {
+		buf := window(file.Content, start)
+		for i := 0; i+packHeaderSize <= len(buf); i++ {
+			ph := parsePackHeader(buf[i:], start+uint32(i))
+			if ph.Valid() && ph.plausible(file) {
+				return ph, nil
+			}
+		}
+	}
+	return nil, errors.New("unable to locate UPX pack header")
+}
+
+// window returns the 1024 bytes (or less at end of file) of content starting at
+// the given file offset.
+func window(content []byte, start uint32) []byte {
+	if uint64(start) >= uint64(len(content)) {
+		return nil
+	}
+	buf := content[start:]
+	if len(buf) > 1024 {
+		buf = buf[:1024]
+	}
+	return buf
+}
+
+// parsePackHeader parses the pack header at the start of the given buffer,
+// located at the given file offset.
+func parsePackHeader(buf []byte, offset uint32) *PackHeader {
+	ph := &PackHeader{
+		Offset:    offset,
+		HasMagic:  bytes.HasPrefix(buf, packHeaderMagic),
+		Version:   buf[4],
+		Format:    buf[5],
+		Method:    Method(buf[6]),
+		Level:     buf[7],
+		UAdler:    binary.LittleEndian.Uint32(buf[8:]),
+		CAdler:    binary.LittleEndian.Uint32(buf[12:]),
+		ULen:      binary.LittleEndian.Uint32(buf[16:]),
+		CLen:      binary.LittleEndian.Uint32(buf[20:]),
+		UFileSize: binary.LittleEndian.Uint32(buf[24:]),
+		Filter:    buf[28],
+		FilterCTO: buf[29],
+		NMRU:      buf[30],
+		Checksum:  buf[31],
+	}
+	// Sum of the bytes following the magic (excluding the checksum) modulo
+	// 251.
+	var sum uint32
+	for _, b := range buf[4 : packHeaderSize-1] {
+		sum += uint32(b)
+	}
+	ph.sum = uint8(sum % 251)
+	return ph
+}
+
+// plausible reports whether the fields of the pack header (located without
+// magic) are plausible for the given PE file.
+func (ph *PackHeader) plausible(file *pe.File) bool {
+	if ph.Version < 10 || ph.Version > 20 {
+		return false
+	}
+	if !ph.Method.supported() {
+		return false
+	}
+	end := uint64(ph.Offset) + packHeaderSize + uint64(ph.CLen)
+	return ph.CLen > 0 && ph.ULen > 0 && end <= uint64(len(file.Content))
+}
+
+// supported reports whether the compression method is supported.
+func (m Method) supported() bool {
+	switch m {
+	case MethodNRV2B32, MethodNRV2B8, MethodNRV2B16,
+		MethodNRV2D32, MethodNRV2D8, MethodNRV2D16,
+		MethodNRV2E32, MethodNRV2E8, MethodNRV2E16,
+		MethodLZMA:
+		return true
+	}
+	return false
+}
+
+// decompress decompresses the given data using the specified compression
+// method into dst. The length of dst specifies the size of the decompressed
+// data.
+func decompress(method Method, src, dst []byte) error {
+	var variant nrvVariant
+	var width uint
+	switch method {
+	case MethodNRV2B32, MethodNRV2D32, MethodNRV2E32:
+		width = 32
+	case MethodNRV2B8, MethodNRV2D8, MethodNRV2E8:
+		width = 8
+	case MethodNRV2B16, MethodNRV2D16, MethodNRV2E16:
+		width = 16
+	case MethodLZMA:
+		return decompressLZMA(src, dst)
+	default:
+		return errors.Errorf("support for UPX compression method %v not yet implemented", method)
+	}
+	switch method {
+	case MethodNRV2B32, MethodNRV2B8, MethodNRV2B16:
+		variant = nrv2b
+	case MethodNRV2D32, MethodNRV2D8, MethodNRV2D16:
+		variant = nrv2d
+	default:
+		variant = nrv2e
+	}
+	return decompressNRV(variant, width, src, dst)
+}
@@ -0,0 +1,213 @@
+package upx
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/mewmew/pe"
+)
+
+// The test files of the testdata directory were created as follows:
+//
+//    plain.bin          uncompressed data
+//    nrv2*_*.bin, lzma.bin
+//                       plain.bin compressed using the given method
+//    pe32.exe           PE32 image packed with NRV2B (32-bit bit buffers) and
+//                       x86 filter 0x26, with overlay
+//    pe64.exe           PE32+ image packed with LZMA, with tampered "UPX!"
+//                       magic and overlay
+//    pe64_tls.exe       PE32+ image with exception table, TLS directory and
+//                       load configuration, packed with NRV2E (32-bit bit
+//                       buffers), with overlay
+//    pe*.unpacked.exe   expected unpacked images
+//
+// The packed images use the UPX 3.x win32/pe and win64/pe pack format.
+
+func TestDecompress(t *testing.T) {
+	plain, err := ioutil.ReadFile("testdata/plain.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := []struct {
+		method Method
+		path   string
+	}{
+		{method: MethodNRV2B32, path: "testdata/nrv2b_32.bin"},
+		{method: MethodNRV2B8, path: "testdata/nrv2b_8.bin"},
+		{method: MethodNRV2B16, path: "testdata/nrv2b_16.bin"},
+		{method: MethodNRV2D32, path: "testdata/nrv2d_32.bin"},
+		{method: MethodNRV2D8, path: "testdata/nrv2d_8.bin"},
+		{method: MethodNRV2D16, path: "testdata/nrv2d_16.bin"},
+		{method: MethodNRV2E32, path: "testdata/nrv2e_32.bin"},
+		{method: MethodNRV2E8, path: "testdata/nrv2e_8.bin"},
+		{method: MethodNRV2E16, path: "testdata/nrv2e_16.bin"},
+		{method: MethodLZMA, path: "testdata/lzma.bin"},
+	}
+	for _, g := range golden {
+		src, err := ioutil.ReadFile(g.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst := make([]byte, len(plain))
+		if err := decompress(g.method, src, dst); err != nil {
+			t.Errorf("%v: unable to decompress %q; %+v", g.method, g.path, err)
+			continue
+		}
+		if !bytes.Equal(dst, plain) {
+			t.Errorf("%v: decompressed data mismatch of %q", g.method, g.path)
+		}
+		// Truncated compressed data.
+		if err := decompress(g.method, src[:len(src)/2], make([]byte, len(plain))); err == nil {
+			t.Errorf("%v: expected error for truncated compressed data", g.method)
+		}
+		// Decompressed data exceeding the output buffer.
+		if err := decompress(g.method, src, make([]byte, len(plain)-1)); err == nil {
+			t.Errorf("%v: expected error for decompressed data exceeding output buffer", g.method)
+		}
+		// Corrupted compressed data must not cause a panic.
+		corrupt := append([]byte(nil), src...)
+		for i := 2; i < len(corrupt); i += 97 {
+			corrupt[i] ^= 0x5A
+		}
+		decompress(g.method, corrupt, make([]byte, len(plain)))
+	}
+	if err := decompress(MethodDeflate, nil, nil); err == nil {
+		t.Error("expected error for unsupported compression method")
+	}
+}
+
+func TestUnpack(t *testing.T) {
+	golden := []struct {
+		path     string
+		want     string
+		method   Method
+		filter   uint8
+		hasMagic bool
+		// Comma-separated section names of unpacked image.
+		sects string
+		// Exception table, TLS directory and load configuration of unpacked
+		// image.
+		dirs map[int]pe.DataDirectory
+	}{
+		{
+			path:     "testdata/pe32.exe",
+			want:     "testdata/pe32.unpacked.exe",
+			method:   MethodNRV2B32,
+			filter:   0x26,
+			hasMagic: true,
+			sects:    ".text,.data,.edata,.idata,.rsrc,.reloc",
+		},
+		{
+			path:     "testdata/pe64.exe",
+			want:     "testdata/pe64.unpacked.exe",
+			method:   MethodLZMA,
+			filter:   0,
+			hasMagic: false,
+			sects:    ".text,.data,.edata,.idata,.rsrc,.reloc",
+		},
+		{
+			path:     "testdata/pe64_tls.exe",
+			want:     "testdata/pe64_tls.unpacked.exe",
+			method:   MethodNRV2E32,
+			filter:   0,
+			hasMagic: true,
+			sects:    ".text,.data,.edata,.idata,.rsrc,.rdata,.pdata,.reloc",
+			dirs: map[int]pe.DataDirectory{
+				3:  {RelAddr: 0x8000, Size: 36},
+				9:  {RelAddr: 0x7000, Size: 40},
+				10: {RelAddr: 0x7200, Size: 280},
+			},
+		},
+	}
+	for _, g := range golden {
+		file, err := pe.ParseFile(g.path)
+		if err != nil {
+			t.Fatalf("%q: unable to parse file; %+v", g.path, err)
+		}
+		ph, err := FindPackHeader(file)
+		if err != nil {
+			t.Errorf("%q: unable to locate pack header; %+v", g.path, err)
+			continue
+		}
+		if ph.Method != g.method || ph.Filter != g.filter || ph.HasMagic != g.hasMagic || !ph.Valid() {
+			t.Errorf("%q: pack header mismatch; expected method %v, filter 0x%02X, magic %v, got method %v, filter 0x%02X, magic %v (valid %v)", g.path, g.method, g.filter, g.hasMagic, ph.Method, ph.Filter, ph.HasMagic, ph.Valid())
+		}
+		content, err := UnpackBytes(file)
+		if err != nil {
+			t.Errorf("%q: unable to unpack file; %+v", g.path, err)
+			continue
+		}
+		want, err := ioutil.ReadFile(g.want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(content, want) {
+			t.Errorf("%q: unpacked image mismatch; expected %d bytes, got %d bytes", g.path, len(want), len(content))
+		}
+		unpacked, err := Unpack(file)
+		if err != nil {
+			t.Errorf("%q: unable to unpack file; %+v", g.path, err)
+			continue
+		}
+		var sects []string
+		for _, sectHdr := range unpacked.SectHdrs {
+			sects = append(sects, sectHdr.Name)
+		}
+		if got := strings.Join(sects, ","); got != g.sects {
+			t.Errorf("%q: sections mismatch; expected %q, got %q", g.path, g.sects, got)
+		}
+		// Data directories not rebuilt by the unpacker are restored from the
+		// original optional header.
+		for _, idx := range []int{3, 9, 10} {
+			if got, want := unpacked.DataDirs[idx], g.dirs[idx]; got != want {
+				t.Errorf("%q: data directory %d mismatch; expected %+v, got %+v", g.path, idx, want, got)
+			}
+		}
+		var imps []string
+		for _, imp := range unpacked.Imps {
+			for _, name := range imp.Names() {
+				imps = append(imps, imp.ImpDir.Name+"!"+name.Name)
+			}
+		}
+		if got, want := strings.Join(imps, ","), "KERNEL32.dll!ExitProcess,KERNEL32.dll!GetTickCount,KERNEL32.dll!Sleep,WS2_32.dll!WSAStartup,WS2_32.dll!closesocket,WS2_32.dll!select,USER32.dll!MessageBoxA"; got != want {
+			t.Errorf("%q: imports mismatch; expected %q, got %q", g.path, want, got)
+		}
+		if got, want := unpacked.ExpHash(), file.ExpHash(); got != want {
+			t.Errorf("%q: exports mismatch; expected export hash %q, got %q", g.path, want, got)
+		}
+		if !unpacked.ChecksumValid() {
+			t.Errorf("%q: invalid checksum of unpacked image", g.path)
+		}
+		if !bytes.HasSuffix(content, []byte("OVERLAY-DATA")) {
+			t.Errorf("%q: missing overlay of unpacked image", g.path)
+		}
+	}
+}
+
+func TestUnpackInvalid(t *testing.T) {
+	golden := []struct {
+		name   string
+		modify func(file *pe.File)
+	}{
+		// COFF object files have neither MS-DOS header nor optional header.
+		{name: "missing MS-DOS header", modify: func(file *pe.File) { file.DOSHdr = nil }},
+		{name: "missing optional header", modify: func(file *pe.File) { file.OptHdr = nil }},
+		{name: "missing data directories", modify: func(file *pe.File) { file.DataDirs = nil }},
+		{name: "missing sections", modify: func(file *pe.File) { file.SectHdrs = file.SectHdrs[:2] }},
+		{name: "truncated", modify: func(file *pe.File) { file.Content = file.Content[:len(file.Content)/2] }},
+	}
+	for _, path := range []string{"testdata/pe32.exe", "testdata/pe64.exe"} {
+		for _, g := range golden {
+			file, err := pe.ParseFile(path)
+			if err != nil {
+				t.Fatalf("%q: unable to parse file; %+v", path, err)
+			}
+			g.modify(file)
+			if _, err := UnpackBytes(file); err == nil {
+				t.Errorf("%q: %s: expected error", path, g.name)
+			}
+		}
+	}
+}